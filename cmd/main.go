@@ -1,13 +1,29 @@
 package main
 
 import (
+	"flag"
 	"log"
 
 	"study.com/v1/internal/app"
+	"study.com/v1/internal/cli"
+	"study.com/v1/internal/config"
 )
 
 func main() {
-	application, err := app.New()
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	// Any argument left after the flags is a maintenance subcommand, see package cli.
+	if args := flag.Args(); len(args) > 0 {
+		if err := cli.Run(cfg, args); err != nil {
+			log.Fatalf("%s failed: %v", args[0], err)
+		}
+		return
+	}
+
+	application, err := app.New(cfg)
 	if err != nil {
 		log.Fatalf("Failed to initialize application: %v", err)
 	}
@@ -5,6 +5,7 @@ import (
 	"log"
 
 	"github.com/gofiber/fiber/v2"
+	"study.com/v1/internal/config"
 	"study.com/v1/internal/router"
 )
 
@@ -17,8 +18,8 @@ type App struct {
 	Fiber     *fiber.App
 }
 
-func New() (*App, error) {
-	resources, err := InitResources()
+func New(cfg *config.Config) (*App, error) {
+	resources, err := InitResources(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize resources: %w", err)
 	}
@@ -27,14 +28,32 @@ func New() (*App, error) {
 
 	services := InitServices(resources, repos)
 
-	handlers := InitHandlers(services)
+	handlers := InitHandlers(resources, services)
 
-	fiberApp := fiber.New()
+	fiberApp := fiber.New(fiber.Config{
+		// Teacher applications upload a CV and credentials of up to 10 MB each
+		BodyLimit: 64 << 20,
+	})
 
 	router.SetupAllRoutes(
 		fiberApp,
 		resources.Config,
 		handlers.Auth,
+		handlers.Role,
+		handlers.UserRole,
+		handlers.Organization,
+		handlers.Category,
+		handlers.Application,
+		handlers.Parent,
+		handlers.Course,
+		handlers.CourseReview,
+		handlers.MediaUpload,
+		handlers.Catalog,
+		handlers.Search,
+		handlers.MediaAccess,
+		handlers.Article,
+		services.Authorization,
+		services.Parent,
 		resources.Redis,
 		resources.MinioClient,
 	)
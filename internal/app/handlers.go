@@ -4,12 +4,38 @@ import "study.com/v1/internal/handler"
 
 // Handlers holds all handler instances
 type Handlers struct {
-	Auth *handler.AuthHandler
+	Auth         *handler.AuthHandler
+	Role         *handler.RoleHandler
+	UserRole     *handler.UserRoleHandler
+	Organization *handler.OrganizationHandler
+	Category     *handler.CategoryHandler
+	Application  *handler.TeacherApplicationHandler
+	Parent       *handler.ParentHandler
+	Course       *handler.CourseHandler
+	CourseReview *handler.CourseReviewHandler
+	Catalog      *handler.CatalogHandler
+	Search       *handler.SearchHandler
+	MediaUpload  *handler.MediaUploadHandler
+	MediaAccess  *handler.MediaAccessHandler
+	Article      *handler.ArticleHandler
 }
 
 // InitHandlers initializes all handlers
-func InitHandlers(services *Services) *Handlers {
+func InitHandlers(resources *Resources, services *Services) *Handlers {
 	return &Handlers{
-		Auth: handler.NewAuthHandler(services.Auth),
+		Auth:         handler.NewAuthHandler(resources.Config, services.Auth),
+		Role:         handler.NewRoleHandler(services.Role),
+		UserRole:     handler.NewUserRoleHandler(services.UserRole),
+		Organization: handler.NewOrganizationHandler(services.Organization, services.OrganizationRole),
+		Category:     handler.NewCategoryHandler(services.Category),
+		Application:  handler.NewTeacherApplicationHandler(services.Application),
+		Parent:       handler.NewParentHandler(services.Parent),
+		Course:       handler.NewCourseHandler(services.Course),
+		CourseReview: handler.NewCourseReviewHandler(services.CourseReview),
+		Catalog:      handler.NewCatalogHandler(services.Catalog),
+		Search:       handler.NewSearchHandler(services.Search),
+		MediaUpload:  handler.NewMediaUploadHandler(services.MediaUpload),
+		MediaAccess:  handler.NewMediaAccessHandler(services.MediaAccess),
+		Article:      handler.NewArticleHandler(services.Article),
 	}
 }
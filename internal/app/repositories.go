@@ -6,11 +6,51 @@ import (
 )
 
 type Repositories struct {
-	User *repository.UserRepository
+	User         *repository.UserRepository
+	RefreshToken *repository.RefreshTokenRepository
+	Permission   *repository.PermissionRepository
+	Role         *repository.RoleRepository
+	UserRole     *repository.UserRoleRepository
+	Override     *repository.PermissionOverrideRepository
+	Organization *repository.OrganizationRepository
+	Invitation   *repository.OrganizationInvitationRepository
+	Category     *repository.CategoryRepository
+	Application  *repository.TeacherApplicationRepository
+	Parent       *repository.ParentRepository
+	Enrollment   *repository.EnrollmentRepository
+	Quiz         *repository.QuizRepository
+	Order        *repository.OrderRepository
+	Course       *repository.CourseRepository
+	Catalog      *repository.CatalogRepository
+	Search       *repository.SearchRepository
+	MediaUpload  *repository.MediaUploadRepository
+	Video        *repository.VideoRepository
+	Attachment   *repository.AttachmentRepository
+	Article      *repository.ArticleRepository
 }
 
 func InitRepositories(db *gorm.DB) *Repositories {
 	return &Repositories{
-		User: repository.NewUserRepository(db),
+		User:         repository.NewUserRepository(db),
+		RefreshToken: repository.NewRefreshTokenRepository(db),
+		Permission:   repository.NewPermissionRepository(db),
+		Role:         repository.NewRoleRepository(db),
+		UserRole:     repository.NewUserRoleRepository(db),
+		Override:     repository.NewPermissionOverrideRepository(db),
+		Organization: repository.NewOrganizationRepository(db),
+		Invitation:   repository.NewOrganizationInvitationRepository(db),
+		Category:     repository.NewCategoryRepository(db),
+		Application:  repository.NewTeacherApplicationRepository(db),
+		Parent:       repository.NewParentRepository(db),
+		Enrollment:   repository.NewEnrollmentRepository(db),
+		Quiz:         repository.NewQuizRepository(db),
+		Order:        repository.NewOrderRepository(db),
+		Course:       repository.NewCourseRepository(db),
+		Catalog:      repository.NewCatalogRepository(db),
+		Search:       repository.NewSearchRepository(db),
+		MediaUpload:  repository.NewMediaUploadRepository(db),
+		Video:        repository.NewVideoRepository(db),
+		Attachment:   repository.NewAttachmentRepository(db),
+		Article:      repository.NewArticleRepository(db),
 	}
 }
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"log"
 
 	"github.com/minio/minio-go/v7"
@@ -10,19 +11,21 @@ import (
 	"study.com/v1/internal/config"
 	"study.com/v1/internal/database"
 	"study.com/v1/internal/storage"
+	"study.com/v1/internal/utils"
 )
 
 type Resources struct {
 	DB          *gorm.DB
 	Redis       *redis.Client
 	MinioClient *minio.Client
-	Config      *config.Config
+	// MinioPresigner signs URLs for browsers, against MINIO_PUBLIC_URL
+	MinioPresigner *minio.Client
+	Config         *config.Config
 }
 
-func InitResources() (*Resources, error) {
-	cfg, err := config.LoadConfig()
-	if err != nil {
-		log.Fatalf("Failed to load config: %v", err)
+func InitResources(cfg *config.Config) (*Resources, error) {
+	if _, err := utils.LoadKeySet(cfg); err != nil {
+		log.Fatalf("Failed to load JWT signing keys: %v", err)
 		return nil, err
 	}
 
@@ -45,13 +48,26 @@ func InitResources() (*Resources, error) {
 	minioClient, err := storage.Connect(cfg)
 	if err != nil {
 		log.Printf("Warning: Failed to connect to minio: %v", err)
+	} else if err := storage.EnsureBuckets(context.Background(), minioClient,
+		cfg.MinioBucketImages, cfg.MinioBucketVideos, cfg.MinioBucketDocuments); err != nil {
+		log.Printf("Warning: Failed to prepare minio buckets: %v", err)
+	} else if err := storage.EnsurePublicRead(context.Background(), minioClient, cfg.MinioBucketImages); err != nil {
+		log.Printf("Warning: Failed to prepare minio buckets: %v", err)
+	}
+
+	var presigner *minio.Client
+	if minioClient != nil {
+		if presigner, err = storage.ConnectPresigner(cfg); err != nil {
+			log.Printf("Warning: Failed to create minio presign client: %v", err)
+		}
 	}
 
 	return &Resources{
-		DB:          db,
-		Redis:       rdb,
-		MinioClient: minioClient,
-		Config:      cfg,
+		DB:             db,
+		Redis:          rdb,
+		MinioClient:    minioClient,
+		MinioPresigner: presigner,
+		Config:         cfg,
 	}, nil
 }
 
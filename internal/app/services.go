@@ -3,12 +3,44 @@ package app
 import "study.com/v1/internal/service"
 
 type Services struct {
-	Auth *service.AuthService
+	Auth             *service.AuthService
+	Authorization    *service.AuthorizationService
+	UserRole         *service.UserRoleService
+	Role             *service.RoleService
+	Organization     *service.OrganizationService
+	OrganizationRole *service.OrganizationRoleService
+	Category         *service.CategoryService
+	Application      *service.TeacherApplicationService
+	Parent           *service.ParentService
+	Course           *service.CourseService
+	CourseReview     *service.CourseReviewService
+	Catalog          *service.CatalogService
+	Search           *service.SearchService
+	MediaUpload      *service.MediaUploadService
+	MediaAccess      *service.MediaAccessService
+	Article          *service.ArticleService
 }
 
 func InitServices(resources *Resources, repos *Repositories) *Services {
 
+	authorization := service.NewAuthorizationService(repos.User, repos.Permission, repos.Override, resources.Redis)
+
 	return &Services{
-		Auth: service.NewAuthService(resources.Config, repos.User, resources.Redis),
+		Auth:             service.NewAuthService(resources.Config, repos.User, repos.RefreshToken, resources.Redis),
+		Authorization:    authorization,
+		UserRole:         service.NewUserRoleService(repos.User, repos.Role, repos.UserRole, repos.Permission, repos.Override, authorization),
+		Role:             service.NewRoleService(repos.Role, repos.Permission, authorization),
+		Organization:     service.NewOrganizationService(resources.Config, repos.Organization, repos.Invitation, repos.Role, repos.User, authorization),
+		OrganizationRole: service.NewOrganizationRoleService(repos.Organization, repos.Role, repos.Permission, authorization),
+		Category:         service.NewCategoryService(repos.Organization, repos.Category, authorization),
+		Application:      service.NewTeacherApplicationService(resources.Config, repos.Application, repos.Role, repos.UserRole, authorization, resources.MinioClient),
+		Parent:           service.NewParentService(resources.Config, repos.Parent, repos.User, repos.Role, repos.Enrollment, repos.Quiz, repos.Order, authorization, resources.Redis),
+		Course:           service.NewCourseService(repos.Course, repos.Organization, repos.Category, authorization),
+		CourseReview:     service.NewCourseReviewService(resources.Config, repos.Course, repos.Organization, repos.User, authorization),
+		Catalog:          service.NewCatalogService(repos.Catalog),
+		Search:           service.NewSearchService(repos.Search),
+		MediaUpload:      service.NewMediaUploadService(resources.Config, repos.MediaUpload, repos.Course, authorization, resources.MinioClient, resources.MinioPresigner),
+		MediaAccess:      service.NewMediaAccessService(resources.Config, repos.Course, repos.Enrollment, repos.Video, repos.Attachment, repos.User, authorization, resources.MinioClient, resources.MinioPresigner),
+		Article:          service.NewArticleService(resources.Config, repos.Article, repos.Course, repos.Enrollment, authorization),
 	}
 }
@@ -0,0 +1,231 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrSessionNotFound is returned when the session of a device was deleted (logout,
+// revocation) or expired before an update could be applied.
+var ErrSessionNotFound = errors.New("session not found")
+
+// touchScript and extendScript only write to a session hash that still exists: a plain
+// HSET after a logout would recreate the hash without a TTL and sign the device back in
+// for good. HSET keeps the TTL of an existing key.
+var touchScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+redis.call('HSET', KEYS[1], 'last_seen_at', ARGV[1])
+return 1
+`)
+
+// extendScript: KEYS[1] the session, KEYS[2] the device index, ARGV[1] the TTL in
+// milliseconds, then field/value pairs.
+var extendScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+redis.call('HSET', KEYS[1], unpack(ARGV, 2))
+redis.call('PEXPIRE', KEYS[1], ARGV[1])
+redis.call('PEXPIRE', KEYS[2], ARGV[1])
+return 1
+`)
+
+// Session is the server-side record of one logged-in device.
+type Session struct {
+	UserID     uuid.UUID
+	DeviceID   uuid.UUID
+	DeviceName string
+	UserAgent  string
+	IPAddress  string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+}
+
+// SessionStore keeps one hash per (user, device) plus a set indexing the devices of a user.
+// The per-user session_version key is bumped to invalidate every access token at once.
+type SessionStore struct {
+	rdb *redis.Client
+	ttl time.Duration
+}
+
+func NewSessionStore(rdb *redis.Client, ttl time.Duration) *SessionStore {
+	return &SessionStore{rdb: rdb, ttl: ttl}
+}
+
+func sessionKey(userID, deviceID uuid.UUID) string {
+	return fmt.Sprintf("session:%s:%s", userID, deviceID)
+}
+
+func sessionIndexKey(userID uuid.UUID) string {
+	return fmt.Sprintf("sessions:%s", userID)
+}
+
+func SessionVersionKey(userID uuid.UUID) string {
+	return fmt.Sprintf("session_version:%s", userID)
+}
+
+func (s *SessionStore) Save(ctx context.Context, session *Session) error {
+	key := sessionKey(session.UserID, session.DeviceID)
+	indexKey := sessionIndexKey(session.UserID)
+
+	pipe := s.rdb.TxPipeline()
+	pipe.HSet(ctx, key, map[string]interface{}{
+		"device_name":  session.DeviceName,
+		"user_agent":   session.UserAgent,
+		"ip_address":   session.IPAddress,
+		"created_at":   session.CreatedAt.Unix(),
+		"last_seen_at": session.LastSeenAt.Unix(),
+	})
+	pipe.Expire(ctx, key, s.ttl)
+	pipe.SAdd(ctx, indexKey, session.DeviceID.String())
+	pipe.Expire(ctx, indexKey, s.ttl)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Get returns nil when the device has no live session.
+func (s *SessionStore) Get(ctx context.Context, userID, deviceID uuid.UUID) (*Session, error) {
+	values, err := s.rdb.HGetAll(ctx, sessionKey(userID, deviceID)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+	return parseSession(userID, deviceID, values), nil
+}
+
+func (s *SessionStore) Exists(ctx context.Context, userID, deviceID uuid.UUID) (bool, error) {
+	n, err := s.rdb.Exists(ctx, sessionKey(userID, deviceID)).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// Touch records activity on a device. Only the last-seen time is updated on every request,
+// the expiry is left as it is.
+func (s *SessionStore) Touch(ctx context.Context, userID, deviceID uuid.UUID, at time.Time) error {
+	touched, err := touchScript.Run(ctx, s.rdb, []string{sessionKey(userID, deviceID)}, at.Unix()).Int()
+	if err != nil {
+		return err
+	}
+	if touched == 0 {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+// Extend pushes the session expiry forward, used when a refresh token is rotated. A session
+// that is already gone is not recreated, ErrSessionNotFound is returned instead.
+func (s *SessionStore) Extend(ctx context.Context, userID, deviceID uuid.UUID, ipAddress, userAgent string) error {
+	args := []interface{}{s.ttl.Milliseconds(), "last_seen_at", time.Now().Unix()}
+	if ipAddress != "" {
+		args = append(args, "ip_address", ipAddress)
+	}
+	if userAgent != "" {
+		args = append(args, "user_agent", userAgent)
+	}
+
+	keys := []string{sessionKey(userID, deviceID), sessionIndexKey(userID)}
+	extended, err := extendScript.Run(ctx, s.rdb, keys, args...).Int()
+	if err != nil {
+		return err
+	}
+	if extended == 0 {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+func (s *SessionStore) List(ctx context.Context, userID uuid.UUID) ([]Session, error) {
+	deviceIDs, err := s.rdb.SMembers(ctx, sessionIndexKey(userID)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]Session, 0, len(deviceIDs))
+	for _, raw := range deviceIDs {
+		deviceID, err := uuid.Parse(raw)
+		if err != nil {
+			s.rdb.SRem(ctx, sessionIndexKey(userID), raw)
+			continue
+		}
+		session, err := s.Get(ctx, userID, deviceID)
+		if err != nil {
+			return nil, err
+		}
+		if session == nil {
+			// The hash expired on its own, drop the stale index entry.
+			s.rdb.SRem(ctx, sessionIndexKey(userID), raw)
+			continue
+		}
+		sessions = append(sessions, *session)
+	}
+	return sessions, nil
+}
+
+func (s *SessionStore) Delete(ctx context.Context, userID, deviceID uuid.UUID) error {
+	pipe := s.rdb.TxPipeline()
+	pipe.Del(ctx, sessionKey(userID, deviceID))
+	pipe.SRem(ctx, sessionIndexKey(userID), deviceID.String())
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (s *SessionStore) DeleteAll(ctx context.Context, userID uuid.UUID) error {
+	deviceIDs, err := s.rdb.SMembers(ctx, sessionIndexKey(userID)).Result()
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(deviceIDs)+1)
+	for _, raw := range deviceIDs {
+		keys = append(keys, fmt.Sprintf("session:%s:%s", userID, raw))
+	}
+	keys = append(keys, sessionIndexKey(userID))
+	return s.rdb.Del(ctx, keys...).Err()
+}
+
+// CurrentVersion returns the user's session version, initialising it to 1 on first login.
+func (s *SessionStore) CurrentVersion(ctx context.Context, userID uuid.UUID) (int64, error) {
+	key := SessionVersionKey(userID)
+	if err := s.rdb.SetNX(ctx, key, 1, 0).Err(); err != nil {
+		return 0, err
+	}
+	raw, err := s.rdb.Get(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(raw, 10, 64)
+}
+
+// BumpVersion invalidates every access token issued to the user.
+func (s *SessionStore) BumpVersion(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return s.rdb.Incr(ctx, SessionVersionKey(userID)).Result()
+}
+
+func parseSession(userID, deviceID uuid.UUID, values map[string]string) *Session {
+	session := &Session{
+		UserID:     userID,
+		DeviceID:   deviceID,
+		DeviceName: values["device_name"],
+		UserAgent:  values["user_agent"],
+		IPAddress:  values["ip_address"],
+	}
+	if ts, err := strconv.ParseInt(values["created_at"], 10, 64); err == nil {
+		session.CreatedAt = time.Unix(ts, 0)
+	}
+	if ts, err := strconv.ParseInt(values["last_seen_at"], 10, 64); err == nil {
+		session.LastSeenAt = time.Unix(ts, 0)
+	}
+	return session
+}
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+	"study.com/v1/internal/cache"
+	"study.com/v1/internal/config"
+	"study.com/v1/internal/model"
+	"study.com/v1/internal/repository"
+	"study.com/v1/internal/service"
+	"study.com/v1/internal/utils"
+)
+
+const generatedPasswordLength = 20
+
+// runCreateAdmin grants SYSTEM_ADMIN to the user with the given email, creating the
+// account first if it does not exist. A generated password is printed once.
+func runCreateAdmin(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("create-admin", flag.ContinueOnError)
+	email := fs.String("email", "", "Email of the admin account (required)")
+	userName := fs.String("username", "", "Username for a new account, defaults to the part of the email before @")
+	password := fs.String("password", "", "Password for a new account, generated when empty")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	normalized := strings.ToLower(strings.TrimSpace(*email))
+	if normalized == "" || !strings.Contains(normalized, "@") {
+		return errors.New("create-admin: --email is required")
+	}
+
+	db, closeDB, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	ctx := context.Background()
+	userRepo := repository.NewUserRepository(db)
+	roleRepo := repository.NewRoleRepository(db)
+	userRoleRepo := repository.NewUserRoleRepository(db)
+
+	role, err := roleRepo.FindByName(ctx, nil, model.RoleSystemAdmin)
+	if err != nil {
+		return err
+	}
+	if role == nil {
+		return fmt.Errorf("role %s does not exist, run the seed command first", model.RoleSystemAdmin)
+	}
+
+	user, err := userRepo.FindUserByEmail(ctx, normalized)
+	if err != nil {
+		return err
+	}
+
+	if user == nil {
+		name := strings.TrimSpace(*userName)
+		if name == "" {
+			name = strings.SplitN(normalized, "@", 2)[0]
+		}
+		existing, err := userRepo.FindUserByUserName(ctx, name)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return fmt.Errorf("username %s is already taken, pass --username", name)
+		}
+
+		plain := *password
+		generated := plain == ""
+		if generated {
+			plain = utils.GenerateShortCode(generatedPasswordLength)
+		}
+		hash, err := utils.HashPassword(plain)
+		if err != nil {
+			return err
+		}
+
+		user = &model.User{
+			Email:        normalized,
+			UserName:     name,
+			PasswordHash: hash,
+			IsVerified:   true,
+			IsActive:     true,
+		}
+		if err := userRepo.CreateUserWithRoles(ctx, user, []string{model.RoleSystemAdmin}); err != nil {
+			return err
+		}
+		fmt.Printf("Created admin %s (%s)\n", user.Email, user.UserName)
+		if generated {
+			fmt.Printf("Generated password: %s\n", plain)
+		}
+	} else {
+		if err := userRoleRepo.AssignRoles(ctx, user.ID, []uuid.UUID{role.ID}, nil); err != nil {
+			return err
+		}
+		fmt.Printf("Granted %s to %s\n", model.RoleSystemAdmin, user.Email)
+	}
+
+	rdb, err := cache.Connect(cfg)
+	if err != nil {
+		log.Printf("Warning: could not reach redis, cached permissions expire on their own: %v", err)
+		return nil
+	}
+	defer rdb.Close()
+	authz := service.NewAuthorizationService(userRepo, repository.NewPermissionRepository(db), repository.NewPermissionOverrideRepository(db), rdb)
+	return authz.InvalidateUserPermissions(ctx, user.ID)
+}
@@ -0,0 +1,68 @@
+// Package cli implements the maintenance subcommands of the server binary:
+//
+//	go run ./cmd [-env dev] migrate up|down [steps]|status|verify
+//	go run ./cmd [-env dev] seed [--data-dir data] [--dry-run] [--prune]
+//	go run ./cmd [-env dev] create-admin --email admin@example.com [--username admin] [--password secret]
+//	go run ./cmd [-env dev] search-reindex
+//	go run ./cmd [-env dev] cleanup-uploads
+//	go run ./cmd [-env dev] worker
+//
+// Without a subcommand the binary starts the HTTP server.
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"gorm.io/gorm"
+	"study.com/v1/internal/config"
+	"study.com/v1/internal/database"
+)
+
+var ErrUnknownCommand = errors.New("unknown command")
+
+type command struct {
+	name  string
+	usage string
+	run   func(cfg *config.Config, args []string) error
+}
+
+var commands = []command{
+	{name: "migrate", usage: "migrate up|down [steps]|status|verify", run: runMigrate},
+	{name: "seed", usage: "seed [--data-dir data] [--dry-run] [--prune]", run: runSeed},
+	{name: "create-admin", usage: "create-admin --email <email> [--username <name>] [--password <password>]", run: runCreateAdmin},
+	{name: "search-reindex", usage: "search-reindex", run: runSearchReindex},
+	{name: "cleanup-uploads", usage: "cleanup-uploads", run: runCleanupUploads},
+	{name: "worker", usage: "worker", run: runWorker},
+}
+
+// Run executes the subcommand named by args[0].
+func Run(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		printUsage()
+		return ErrUnknownCommand
+	}
+	for _, c := range commands {
+		if c.name == args[0] {
+			return c.run(cfg, args[1:])
+		}
+	}
+	printUsage()
+	return fmt.Errorf("%w: %s", ErrUnknownCommand, args[0])
+}
+
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "Usage: [-env dev|test|prod] <command>")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %s\n", c.usage)
+	}
+}
+
+func openDB(cfg *config.Config) (*gorm.DB, func(), error) {
+	db, err := database.Connect(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return db, func() { database.Close(db) }, nil
+}
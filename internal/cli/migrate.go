@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"study.com/v1/internal/config"
+	"study.com/v1/internal/database"
+)
+
+func runMigrate(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: migrate up|down [steps]|status|verify")
+	}
+
+	db, closeDB, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	switch args[0] {
+	case "up":
+		if err := database.Migrate(db); err != nil {
+			return err
+		}
+		fmt.Println("Migrations applied")
+		return nil
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+		}
+		return database.MigrateDown(db, steps)
+	case "status":
+		states, err := database.MigrationStatus(db)
+		if err != nil {
+			return err
+		}
+		for _, s := range states {
+			state := "pending"
+			if s.Applied {
+				state = "applied " + s.AppliedAt.Format(time.RFC3339)
+			}
+			if s.Modified {
+				state += " (file changed since)"
+			}
+			if s.Missing {
+				state += " (no file in this build)"
+			}
+			fmt.Fprintf(os.Stdout, "%04d %-32s %s\n", s.Version, s.Name, state)
+		}
+		return nil
+	case "verify":
+		mismatches, err := database.VerifySchema(db)
+		if err != nil {
+			return err
+		}
+		for _, m := range mismatches {
+			fmt.Println(m.String())
+		}
+		if len(mismatches) > 0 {
+			return fmt.Errorf("schema differs from the models in %d places", len(mismatches))
+		}
+		fmt.Println("Schema matches the models")
+		return nil
+	default:
+		return fmt.Errorf("%w: migrate %s", ErrUnknownCommand, args[0])
+	}
+}
@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"study.com/v1/internal/config"
+	"study.com/v1/internal/repository"
+)
+
+// runSearchReindex rebuilds the course search index. Triggers keep it current, so this is
+// only needed after the refresh function or the text search configuration changed.
+func runSearchReindex(cfg *config.Config, args []string) error {
+	if len(args) > 0 {
+		return errors.New("usage: search-reindex")
+	}
+
+	db, closeDB, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	count, err := repository.NewSearchRepository(db).Reindex(context.Background())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Reindexed %d courses\n", count)
+	return nil
+}
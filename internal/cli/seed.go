@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"study.com/v1/internal/cache"
+	"study.com/v1/internal/config"
+	"study.com/v1/internal/database/seeds"
+	"study.com/v1/internal/repository"
+	"study.com/v1/internal/service"
+)
+
+func runSeed(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	dataDir := fs.String("data-dir", "data", "Directory containing roles.json and permissions/")
+	dryRun := fs.Bool("dry-run", false, "Print the permission and role changes without writing them")
+	prune := fs.Bool("prune", false, "Delete permissions that are no longer listed in the data files")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	db, closeDB, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	plan, err := seeds.NewSeeder(db).SeedAll(*dataDir, seeds.Options{DryRun: *dryRun, Prune: *prune})
+	if err != nil {
+		return err
+	}
+	plan.Print(os.Stdout)
+
+	if *dryRun {
+		fmt.Println("Dry run, nothing was written")
+		return nil
+	}
+	if plan.Empty() {
+		return nil
+	}
+
+	// Role permissions changed underneath any running server, drop its cached policies.
+	rdb, err := cache.Connect(cfg)
+	if err != nil {
+		log.Printf("Warning: could not reach redis, cached permissions expire on their own: %v", err)
+		return nil
+	}
+	defer rdb.Close()
+	authz := service.NewAuthorizationService(
+		repository.NewUserRepository(db),
+		repository.NewPermissionRepository(db),
+		repository.NewPermissionOverrideRepository(db),
+		rdb,
+	)
+	return authz.InvalidateAllPermissions(context.Background())
+}
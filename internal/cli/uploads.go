@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"study.com/v1/internal/config"
+	"study.com/v1/internal/repository"
+	"study.com/v1/internal/service"
+	"study.com/v1/internal/storage"
+)
+
+// runCleanupUploads aborts the media uploads whose URLs expired before the client completed
+// them, freeing the parts MinIO keeps for unfinished multipart uploads. Run it periodically.
+func runCleanupUploads(cfg *config.Config, args []string) error {
+	if len(args) > 0 {
+		return errors.New("usage: cleanup-uploads")
+	}
+
+	db, closeDB, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+	client, err := storage.Connect(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Cleanup touches neither courses nor permissions, nor signs URLs
+	uploads := service.NewMediaUploadService(cfg, repository.NewMediaUploadRepository(db), nil, nil, client, nil)
+	count, err := uploads.CleanupExpired(context.Background())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Aborted %d expired uploads\n", count)
+	return nil
+}
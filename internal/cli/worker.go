@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"study.com/v1/internal/config"
+	"study.com/v1/internal/media"
+	"study.com/v1/internal/model"
+	"study.com/v1/internal/repository"
+	"study.com/v1/internal/service"
+	"study.com/v1/internal/storage"
+	"study.com/v1/internal/transcription"
+	"study.com/v1/internal/worker"
+)
+
+// runWorker processes background jobs until interrupted. Run as many as needed, on
+// machines with ffmpeg installed: jobs are shared out through Postgres, and a job whose
+// worker dies is picked up by another one once its lease expires. Only workers with a
+// TRANSCRIPTION_BACKEND take the transcription jobs, so they can run on separate machines.
+func runWorker(cfg *config.Config, args []string) error {
+	if len(args) > 0 {
+		return errors.New("usage: worker")
+	}
+
+	db, closeDB, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+	client, err := storage.Connect(cfg)
+	if err != nil {
+		return err
+	}
+
+	transcriber, err := newTranscriber(cfg)
+	if err != nil {
+		return err
+	}
+
+	videoRepo := repository.NewVideoRepository(db)
+	ffmpeg := media.NewFFmpeg(cfg.FFmpegPath, cfg.FFprobePath)
+	videos := service.NewVideoProcessingService(cfg, videoRepo, client, ffmpeg)
+
+	w := worker.New(repository.NewJobRepository(db), cfg.WorkerConcurrency)
+	w.Register(model.JobTypeVideoTranscode, videos.Transcode)
+	if transcriber != nil {
+		transcripts := service.NewTranscriptionService(cfg, repository.NewCourseRepository(db), videoRepo, client, ffmpeg, transcriber)
+		w.Register(model.JobTypeVideoTranscribe, transcripts.Transcribe)
+	} else {
+		log.Println("Transcription is disabled, set TRANSCRIPTION_BACKEND to enable it")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	w.Run(ctx)
+	return nil
+}
+
+// newTranscriber returns the backend named by TRANSCRIPTION_BACKEND, nil when it is empty.
+func newTranscriber(cfg *config.Config) (transcription.Transcriber, error) {
+	switch cfg.TranscriptionBackend {
+	case "":
+		return nil, nil
+	case "whisper_cpp":
+		if cfg.WhisperModelPath == "" {
+			return nil, errors.New("WHISPER_MODEL_PATH is required by the whisper_cpp transcription backend")
+		}
+		return transcription.NewWhisperCpp(cfg.WhisperCppPath, cfg.WhisperModelPath, cfg.WhisperThreads), nil
+	default:
+		return nil, fmt.Errorf("unknown TRANSCRIPTION_BACKEND %q", cfg.TranscriptionBackend)
+	}
+}
@@ -29,10 +29,16 @@ type Config struct {
 	MinioAccessKey string `mapstructure:"MINIO_ACCESS_KEY"`
 	MinioSecretKey string `mapstructure:"MINIO_SECRET_KEY"`
 	MinioUseSSL    bool   `mapstructure:"MINIO_USE_SSL"`
+	MinioRegion    string `mapstructure:"MINIO_REGION"`
+	// Base URL browsers reach MinIO at, e.g. https://files.example.com. Presigned URLs and
+	// public thumbnails use it; defaults to MINIO_HOST:MINIO_PORT.
+	MinioPublicURL string `mapstructure:"MINIO_PUBLIC_URL"`
 
 	// Minio Buckets
 	MinioBucketImages string `mapstructure:"MINIO_BUCKET_IMAGES"`
 	MinioBucketVideos string `mapstructure:"MINIO_BUCKET_VIDEOS"`
+	// Private files such as CVs and credentials, only served through presigned URLs
+	MinioBucketDocuments string `mapstructure:"MINIO_BUCKET_DOCUMENTS"`
 
 	// SMTP Configuration
 	SMTPHost     string `mapstructure:"SMTP_HOST"`
@@ -43,8 +49,47 @@ type Config struct {
 
 	// JWT Configuration
 	JWTSecret            string `mapstructure:"JWT_SECRET"`
+	JWTAccessSecret      string `mapstructure:"JWT_ACCESS_SECRET"`
+	JWTRefreshSecret     string `mapstructure:"JWT_REFRESH_SECRET"`
+	JWTIssuer            string `mapstructure:"JWT_ISSUER"`
+	JWTAudience          string `mapstructure:"JWT_AUDIENCE"`
+	JWTSigningAlgorithm  string `mapstructure:"JWT_SIGNING_ALGORITHM"` // HS256 (local dev), RS256 or EdDSA
+	JWTKeysDir           string `mapstructure:"JWT_KEYS_DIR"`
+	JWTActiveKeyID       string `mapstructure:"JWT_ACTIVE_KEY_ID"`
 	JWTAccessExpiration  time.Duration
 	JWTRefreshExpiration time.Duration
+
+	// Auth transport
+	AuthAllowBearer     bool   `mapstructure:"AUTH_ALLOW_BEARER"`
+	AuthTokenPrecedence string `mapstructure:"AUTH_TOKEN_PRECEDENCE"` // header or cookie, used when a request carries both
+
+	// Auth cookies
+	CookieDomain   string `mapstructure:"COOKIE_DOMAIN"`
+	CookieSecure   bool   `mapstructure:"COOKIE_SECURE"`
+	CookieSameSite string `mapstructure:"COOKIE_SAMESITE"` // Lax, Strict or None
+	CSRFEnabled    bool   `mapstructure:"CSRF_ENABLED"`
+
+	// Signs the URLs lesson videos are streamed from, see utils.SignMediaToken
+	MediaTokenSecret string `mapstructure:"MEDIA_TOKEN_SECRET"`
+
+	// Base URL of the web client, used for links sent by email
+	FrontendURL string `mapstructure:"FRONTEND_URL"`
+
+	// Students younger than this (from their date of birth) must have a linked parent
+	MinorAgeThreshold int `mapstructure:"MINOR_AGE_THRESHOLD"`
+
+	// Background worker, started with the worker subcommand
+	WorkerConcurrency int    `mapstructure:"WORKER_CONCURRENCY"`
+	WorkerTempDir     string `mapstructure:"WORKER_TEMP_DIR"` // scratch space for transcoding, defaults to the system temp dir
+	FFmpegPath        string `mapstructure:"FFMPEG_PATH"`
+	FFprobePath       string `mapstructure:"FFPROBE_PATH"`
+
+	// Transcription of lesson videos by the worker. Empty disables it: the jobs wait for a
+	// worker that has a backend configured.
+	TranscriptionBackend string `mapstructure:"TRANSCRIPTION_BACKEND"` // whisper_cpp
+	WhisperCppPath       string `mapstructure:"WHISPER_CPP_PATH"`
+	WhisperModelPath     string `mapstructure:"WHISPER_MODEL_PATH"` // ggml model file, e.g. ggml-large-v3-turbo.bin
+	WhisperThreads       int    `mapstructure:"WHISPER_THREADS"`    // 0 keeps the whisper.cpp default
 }
 
 func LoadConfig() (*Config, error) {
@@ -60,8 +105,10 @@ func LoadConfig() (*Config, error) {
 	viper.SetDefault("HOST", "localhost")
 	viper.SetDefault("REDIS_DB", 0)
 	viper.SetDefault("MINIO_USE_SSL", false)
+	viper.SetDefault("MINIO_REGION", "us-east-1")
 	viper.SetDefault("MINIO_BUCKET_IMAGES", "images")
 	viper.SetDefault("MINIO_BUCKET_VIDEOS", "videos")
+	viper.SetDefault("MINIO_BUCKET_DOCUMENTS", "documents")
 
 	viper.AutomaticEnv()
 
@@ -112,8 +159,48 @@ func LoadConfig() (*Config, error) {
 	viper.SetDefault("SMTP_HOST", "smtp.gmail.com")
 	viper.SetDefault("SMTP_PORT", 587)
 	viper.SetDefault("JWT_SECRET", "supersecretkey-change-in-production")
+	viper.SetDefault("JWT_ISSUER", "40study-api")
+	viper.SetDefault("JWT_AUDIENCE", "40study-app")
+	viper.SetDefault("JWT_SIGNING_ALGORITHM", "HS256")
 	viper.SetDefault("JWT_ACCESS_EXPIRATION_MINUTES", 15)
+	viper.SetDefault("AUTH_ALLOW_BEARER", true)
+	viper.SetDefault("AUTH_TOKEN_PRECEDENCE", "header")
+	// Local dev runs over plain http, everything else must only send cookies over TLS
+	viper.SetDefault("COOKIE_SECURE", env != "dev" && env != "test")
+	viper.SetDefault("COOKIE_SAMESITE", "Lax")
+	viper.SetDefault("CSRF_ENABLED", true)
 	viper.SetDefault("JWT_REFRESH_EXPIRATION_DAYS", 7)
+	viper.SetDefault("FRONTEND_URL", "http://localhost:5173")
+	viper.SetDefault("MINOR_AGE_THRESHOLD", 16)
+	viper.SetDefault("WORKER_CONCURRENCY", 1)
+	viper.SetDefault("FFMPEG_PATH", "ffmpeg")
+	viper.SetDefault("FFPROBE_PATH", "ffprobe")
+	viper.SetDefault("WHISPER_CPP_PATH", "whisper-cli")
+
+	// Unmarshal only sees keys viper knows about. Keys without a default, and the auth keys,
+	// are bound explicitly, otherwise they are lost when they come from the environment alone.
+	for _, key := range []string{
+		"JWT_ACCESS_SECRET",
+		"JWT_REFRESH_SECRET",
+		"JWT_KEYS_DIR",
+		"JWT_ACTIVE_KEY_ID",
+		"AUTH_ALLOW_BEARER",
+		"AUTH_TOKEN_PRECEDENCE",
+		"COOKIE_DOMAIN",
+		"COOKIE_SECURE",
+		"COOKIE_SAMESITE",
+		"CSRF_ENABLED",
+		"MEDIA_TOKEN_SECRET",
+		"TRANSCRIPTION_BACKEND",
+		"WHISPER_MODEL_PATH",
+		"WHISPER_THREADS",
+		"MINIO_PUBLIC_URL",
+		"WORKER_TEMP_DIR",
+	} {
+		if err := viper.BindEnv(key); err != nil {
+			return nil, fmt.Errorf("unable to bind %s: %w", key, err)
+		}
+	}
 
 	if err := viper.Unmarshal(config); err != nil {
 		return nil, fmt.Errorf("unable to decode into struct: %w", err)
@@ -125,5 +212,30 @@ func LoadConfig() (*Config, error) {
 	config.JWTAccessExpiration = time.Duration(accessMinutes) * time.Minute
 	config.JWTRefreshExpiration = time.Duration(refreshDays) * 24 * time.Hour
 
+	// Access and refresh tokens are signed with different keys so one can never
+	// be verified as the other. Deriving both from JWT_SECRET means one leaked secret
+	// forges either type, so it is only a fallback for local .env files.
+	if config.JWTAccessSecret == "" || config.JWTRefreshSecret == "" {
+		if env == "prod" {
+			return nil, fmt.Errorf("JWT_ACCESS_SECRET and JWT_REFRESH_SECRET are required in %s", env)
+		}
+		if config.JWTAccessSecret == "" {
+			config.JWTAccessSecret = config.JWTSecret
+		}
+		if config.JWTRefreshSecret == "" {
+			config.JWTRefreshSecret = config.JWTSecret + ":refresh"
+		}
+	}
+	if config.MediaTokenSecret == "" {
+		// Anyone who learns JWT_SECRET could mint media URLs, only acceptable locally
+		if env == "prod" {
+			return nil, fmt.Errorf("MEDIA_TOKEN_SECRET is required in %s", env)
+		}
+		if env != "dev" {
+			fmt.Println("Warning: MEDIA_TOKEN_SECRET is not set, deriving it from JWT_SECRET")
+		}
+		config.MediaTokenSecret = config.JWTSecret + ":media"
+	}
+
 	return config, nil
 }
@@ -0,0 +1,51 @@
+package database
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// migrateLegacyUserRoles copies the old single users.role value into user_roles and
+// drops the column once every user has at least one role. Until the roles have been
+// seeded there is nothing to map onto, so the column is kept and retried next start.
+func migrateLegacyUserRoles(db *gorm.DB) error {
+	if !db.Migrator().HasColumn("users", "role") {
+		return nil
+	}
+
+	err := db.Exec(`
+		INSERT INTO user_roles (user_id, role_id, assigned_at)
+		SELECT u.id, r.id, CURRENT_TIMESTAMP
+		FROM users u
+		JOIN roles r ON r.organization_id IS NULL AND r.name = CASE u.role
+			WHEN 'student' THEN 'STUDENT'
+			WHEN 'instructor' THEN 'TEACHER'
+			WHEN 'admin' THEN 'SYSTEM_ADMIN'
+		END
+		ON CONFLICT (user_id, role_id) WHERE organization_id IS NULL DO NOTHING
+	`).Error
+	if err != nil {
+		return fmt.Errorf("failed to copy legacy user roles: %w", err)
+	}
+
+	var unmigrated int64
+	err = db.Raw(`
+		SELECT COUNT(*) FROM users u
+		WHERE NOT EXISTS (SELECT 1 FROM user_roles ur WHERE ur.user_id = u.id)
+	`).Scan(&unmigrated).Error
+	if err != nil {
+		return fmt.Errorf("failed to count unmigrated users: %w", err)
+	}
+	if unmigrated > 0 {
+		log.Printf("Warning: %d users have no role yet, keeping legacy users.role column until roles are seeded", unmigrated)
+		return nil
+	}
+
+	if err := db.Migrator().DropColumn("users", "role"); err != nil {
+		return fmt.Errorf("failed to drop legacy users.role column: %w", err)
+	}
+	log.Println("Migrated legacy users.role column to user_roles")
+	return nil
+}
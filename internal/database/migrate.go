@@ -0,0 +1,330 @@
+package database
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"study.com/v1/internal/model"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLockID is the pg_advisory_lock key that serialises migrations across
+// instances starting at the same time.
+const migrationLockID int64 = 4017_0001
+
+// baselineVersion is the last migration whose tables the old AutoMigrate created.
+const baselineVersion int64 = 1
+
+var ErrNoMigrationToRollback = errors.New("no applied migration to roll back")
+
+// Migration is one pair of NNNN_name.up.sql / NNNN_name.down.sql files in migrations/.
+// Schema changes always go into a new pair with the next version; after changing a
+// model, `migrate verify` lists the columns a new migration still has to cover.
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+// MigrationState is a migration as reported by MigrationStatus.
+type MigrationState struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+	// Modified means the up file changed after it was applied.
+	Modified bool
+	// Missing means the database has the version but this binary has no file for it.
+	Missing bool
+}
+
+type schemaMigration struct {
+	Version   int64     `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"type:varchar(255);not null"`
+	Checksum  string    `gorm:"type:varchar(64);not null"`
+	AppliedAt time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Migrate applies every pending up migration, each in its own transaction.
+func Migrate(db *gorm.DB) error {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+
+	err = withMigrationLock(db, func(conn *gorm.DB) error {
+		applied, err := appliedMigrations(conn)
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			if err := baselineLegacySchema(conn, migrations, applied); err != nil {
+				return err
+			}
+		}
+
+		for _, m := range migrations {
+			if existing, ok := applied[m.Version]; ok {
+				if existing.Checksum != m.Checksum {
+					log.Printf("Warning: migration %04d_%s changed after it was applied", m.Version, m.Name)
+				}
+				continue
+			}
+			if err := applyMigration(conn, m); err != nil {
+				return err
+			}
+			log.Printf("Applied migration %04d_%s", m.Version, m.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return migrateLegacyUserRoles(db)
+}
+
+// MigrateDown rolls back the last steps applied migrations, newest first.
+func MigrateDown(db *gorm.DB, steps int) error {
+	if steps < 1 {
+		steps = 1
+	}
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+	byVersion := make(map[int64]Migration, len(migrations))
+	for _, m := range migrations {
+		byVersion[m.Version] = m
+	}
+
+	return withMigrationLock(db, func(conn *gorm.DB) error {
+		var applied []schemaMigration
+		err := conn.Order("version DESC").Limit(steps).Find(&applied).Error
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			return ErrNoMigrationToRollback
+		}
+
+		for _, a := range applied {
+			m, ok := byVersion[a.Version]
+			if !ok {
+				return fmt.Errorf("no down file for applied migration %04d_%s", a.Version, a.Name)
+			}
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(m.Down).Error; err != nil {
+					return fmt.Errorf("migration %04d_%s down: %w", m.Version, m.Name, err)
+				}
+				return tx.Where("version = ?", m.Version).Delete(&schemaMigration{}).Error
+			})
+			if err != nil {
+				return err
+			}
+			log.Printf("Rolled back migration %04d_%s", m.Version, m.Name)
+		}
+		return nil
+	})
+}
+
+// MigrationStatus lists every known or applied migration in version order.
+func MigrationStatus(db *gorm.DB) ([]MigrationState, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+	if err := db.AutoMigrate(&schemaMigration{}); err != nil {
+		return nil, err
+	}
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]MigrationState, 0, len(migrations))
+	for _, m := range migrations {
+		state := MigrationState{Version: m.Version, Name: m.Name}
+		if a, ok := applied[m.Version]; ok {
+			appliedAt := a.AppliedAt
+			state.Applied = true
+			state.AppliedAt = &appliedAt
+			state.Modified = a.Checksum != m.Checksum
+			delete(applied, m.Version)
+		}
+		states = append(states, state)
+	}
+	for _, a := range applied {
+		appliedAt := a.AppliedAt
+		states = append(states, MigrationState{
+			Version:   a.Version,
+			Name:      a.Name,
+			Applied:   true,
+			AppliedAt: &appliedAt,
+			Missing:   true,
+		})
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Version < states[j].Version })
+	return states, nil
+}
+
+// withMigrationLock runs fn on a single connection holding the advisory lock, so that
+// the lock and the migrations share one session.
+func withMigrationLock(db *gorm.DB, fn func(conn *gorm.DB) error) error {
+	return db.Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", migrationLockID).Error; err != nil {
+			return fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+		defer func() {
+			if err := conn.Exec("SELECT pg_advisory_unlock(?)", migrationLockID).Error; err != nil {
+				log.Printf("Warning: failed to release migration lock: %v", err)
+			}
+		}()
+
+		if err := conn.AutoMigrate(&schemaMigration{}); err != nil {
+			return fmt.Errorf("failed to create schema_migrations: %w", err)
+		}
+		return fn(conn)
+	})
+}
+
+func appliedMigrations(db *gorm.DB) (map[int64]schemaMigration, error) {
+	var rows []schemaMigration
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[int64]schemaMigration, len(rows))
+	for _, r := range rows {
+		applied[r.Version] = r
+	}
+	return applied, nil
+}
+
+func applyMigration(conn *gorm.DB, m Migration) error {
+	return conn.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(m.Up).Error; err != nil {
+			return fmt.Errorf("migration %04d_%s: %w", m.Version, m.Name, err)
+		}
+		return tx.Create(&schemaMigration{
+			Version:   m.Version,
+			Name:      m.Name,
+			Checksum:  m.Checksum,
+			AppliedAt: time.Now(),
+		}).Error
+	})
+}
+
+// baselineLegacySchema handles databases created before versioned migrations: their
+// users/RBAC tables came from AutoMigrate, so those are brought up to date the old way
+// and recorded as applied instead of being created again.
+func baselineLegacySchema(conn *gorm.DB, migrations []Migration, applied map[int64]schemaMigration) error {
+	if !conn.Migrator().HasTable(&model.User{}) {
+		return nil
+	}
+
+	err := conn.AutoMigrate(
+		&model.User{},
+		&model.UserPreference{},
+		&model.RefreshToken{},
+		&model.Permission{},
+		&model.Role{},
+		&model.RolePermission{},
+		&model.UserRole{},
+		&model.UserPermissionOverride{},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to bring legacy schema up to date: %w", err)
+	}
+
+	for _, m := range migrations {
+		if m.Version > baselineVersion {
+			break
+		}
+		row := schemaMigration{Version: m.Version, Name: m.Name, Checksum: m.Checksum, AppliedAt: time.Now()}
+		if err := conn.Create(&row).Error; err != nil {
+			return err
+		}
+		applied[m.Version] = row
+		log.Printf("Recorded existing schema as migration %04d_%s", m.Version, m.Name)
+	}
+	return nil
+}
+
+func loadMigrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, e := range entries {
+		name := e.Name()
+		var direction string
+		switch {
+		case strings.HasSuffix(name, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(name, ".down.sql"):
+			direction = "down"
+		default:
+			continue
+		}
+
+		base := strings.TrimSuffix(name, "."+direction+".sql")
+		prefix, label, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("migration file %s must be named NNNN_name.%s.sql", name, direction)
+		}
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration file %s has an invalid version: %w", name, err)
+		}
+
+		content, err := migrationFiles.ReadFile(path.Join("migrations", name))
+		if err != nil {
+			return nil, err
+		}
+
+		m, exists := byVersion[version]
+		if !exists {
+			m = &Migration{Version: version, Name: label}
+			byVersion[version] = m
+		} else if m.Name != label {
+			return nil, fmt.Errorf("migration version %d is used by both %s and %s", version, m.Name, label)
+		}
+		if direction == "up" {
+			m.Up = string(content)
+			sum := sha256.Sum256(content)
+			m.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s needs both an up and a down file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
@@ -0,0 +1,106 @@
+package database
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"gorm.io/gorm"
+	"study.com/v1/internal/config"
+	"study.com/v1/internal/model"
+)
+
+// testDB connects to the database named by the TEST_DB_* variables, the test is skipped
+// without them. The database must be empty: the tests below roll every migration back.
+func testDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	cfg := &config.Config{
+		DBHost:     os.Getenv("TEST_DB_HOST"),
+		DBPort:     os.Getenv("TEST_DB_PORT"),
+		DBUser:     os.Getenv("TEST_DB_USER"),
+		DBPassword: os.Getenv("TEST_DB_PASSWORD"),
+		DBName:     os.Getenv("TEST_DB_NAME"),
+	}
+	if cfg.DBHost == "" || cfg.DBUser == "" || cfg.DBName == "" {
+		t.Skip("TEST_DB_HOST, TEST_DB_USER and TEST_DB_NAME are not set")
+	}
+	if cfg.DBPort == "" {
+		cfg.DBPort = "5432"
+	}
+
+	db, err := Connect(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = Close(db) })
+
+	if db.Migrator().HasTable(&schemaMigration{}) || db.Migrator().HasTable(&model.User{}) {
+		t.Fatalf("database %s is not empty, the migration tests drop every table", cfg.DBName)
+	}
+	return db
+}
+
+// TestMigrationsRoundTrip applies every migration, checks that the result matches the
+// models, then rolls the migrations back one at a time down to an empty schema and
+// applies them again.
+func TestMigrationsRoundTrip(t *testing.T) {
+	db := testDB(t)
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := MigrateDown(db, len(migrations)); err != nil && !errors.Is(err, ErrNoMigrationToRollback) {
+			t.Errorf("cleanup: %v", err)
+		}
+	})
+
+	if err := Migrate(db); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	assertNoDrift(t, db)
+
+	// Applying again finds nothing to do
+	if err := Migrate(db); err != nil {
+		t.Fatalf("second Migrate: %v", err)
+	}
+
+	for i := len(migrations) - 1; i >= 0; i-- {
+		m := migrations[i]
+		if err := MigrateDown(db, 1); err != nil {
+			t.Fatalf("MigrateDown %04d_%s: %v", m.Version, m.Name, err)
+		}
+		var applied int64
+		if err := db.Model(&schemaMigration{}).Where("version >= ?", m.Version).Count(&applied).Error; err != nil {
+			t.Fatal(err)
+		}
+		if applied != 0 {
+			t.Fatalf("%04d_%s is still recorded as applied after rolling it back", m.Version, m.Name)
+		}
+	}
+	if err := MigrateDown(db, 1); !errors.Is(err, ErrNoMigrationToRollback) {
+		t.Fatalf("MigrateDown on an empty schema: got %v, want ErrNoMigrationToRollback", err)
+	}
+	for _, m := range model.AllModels() {
+		if db.Migrator().HasTable(m) {
+			t.Errorf("table of %T is left after rolling back every migration", m)
+		}
+	}
+
+	// The down files leave nothing behind that would break a fresh install
+	if err := Migrate(db); err != nil {
+		t.Fatalf("Migrate after rolling back: %v", err)
+	}
+	assertNoDrift(t, db)
+}
+
+func assertNoDrift(t *testing.T, db *gorm.DB) {
+	t.Helper()
+	mismatches, err := VerifySchema(db)
+	if err != nil {
+		t.Fatalf("VerifySchema: %v", err)
+	}
+	for _, m := range mismatches {
+		t.Errorf("schema drift: %s", m)
+	}
+}
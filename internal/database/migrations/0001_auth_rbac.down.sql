@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS "user_permission_overrides" CASCADE;
+DROP TABLE IF EXISTS "user_roles" CASCADE;
+DROP TABLE IF EXISTS "role_permissions" CASCADE;
+DROP TABLE IF EXISTS "roles" CASCADE;
+DROP TABLE IF EXISTS "permissions" CASCADE;
+DROP TABLE IF EXISTS "refresh_tokens" CASCADE;
+DROP TABLE IF EXISTS "user_preferences" CASCADE;
+DROP TABLE IF EXISTS "users" CASCADE;
@@ -0,0 +1,137 @@
+-- Users, sessions and role-based access control.
+-- Databases created by the old AutoMigrate already have these tables and are
+-- recorded at this version without running it, see database.baselineLegacySchema.
+
+CREATE TABLE "users" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "email" varchar(255) NOT NULL,
+    "password_hash" varchar(255) NOT NULL,
+    "user_name" varchar(100) NOT NULL,
+    "full_name" varchar(255),
+    "avatar_url" varchar(500),
+    "phone" varchar(20),
+    "date_of_birth" date,
+    "gender" varchar(10),
+    "bio" text,
+    "is_verified" boolean DEFAULT false,
+    "is_active" boolean DEFAULT true,
+    "last_login_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "chk_users_gender" CHECK (gender IN ('male', 'female', 'other'))
+);
+CREATE INDEX IF NOT EXISTS "idx_users_is_active" ON "users" ("is_active");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_user_name" ON "users" ("user_name");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_email" ON "users" ("email");
+CREATE INDEX IF NOT EXISTS "idx_users_deleted_at" ON "users" ("deleted_at");
+
+CREATE TABLE "user_preferences" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "user_id" uuid NOT NULL,
+    "daily_study_goal_minutes" bigint DEFAULT 30,
+    "weekly_lesson_goal" bigint DEFAULT 5,
+    "reminder_enabled" boolean DEFAULT true,
+    "reminder_time" varchar(5),
+    "timezone" varchar(50) DEFAULT 'Asia/Ho_Chi_Minh',
+    "language" varchar(10) DEFAULT 'vi',
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_users_preference" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_user_preferences_user_id" ON "user_preferences" ("user_id");
+
+CREATE TABLE "refresh_tokens" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "user_id" uuid NOT NULL,
+    "family_id" uuid NOT NULL,
+    "token_hash" varchar(255) NOT NULL,
+    "device_id" uuid NOT NULL,
+    "device_name" varchar(255),
+    "ip_address" varchar(45),
+    "user_agent" text,
+    "expires_at" timestamptz NOT NULL,
+    "is_revoked" boolean DEFAULT false,
+    "revoked_at" timestamptz,
+    "revoked_reason" varchar(100),
+    "replaced_by_id" uuid,
+    "last_used_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_refresh_tokens_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_refresh_tokens_is_revoked" ON "refresh_tokens" ("is_revoked");
+CREATE INDEX IF NOT EXISTS "idx_refresh_tokens_expires_at" ON "refresh_tokens" ("expires_at");
+CREATE INDEX IF NOT EXISTS "idx_refresh_tokens_device_id" ON "refresh_tokens" ("device_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_refresh_tokens_token_hash" ON "refresh_tokens" ("token_hash");
+CREATE INDEX IF NOT EXISTS "idx_refresh_tokens_family_id" ON "refresh_tokens" ("family_id");
+CREATE INDEX IF NOT EXISTS "idx_refresh_tokens_user_id" ON "refresh_tokens" ("user_id");
+
+CREATE TABLE "permissions" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "name" varchar(100) NOT NULL,
+    "description" text,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_permissions_name" ON "permissions" ("name");
+
+CREATE TABLE "roles" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "name" varchar(100) NOT NULL,
+    "description" text,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_roles_name" ON "roles" ("name");
+
+CREATE TABLE "role_permissions" (
+    "role_id" uuid,
+    "permission_id" uuid,
+    "is_granted" boolean NOT NULL DEFAULT true,
+    "conditions" jsonb NOT NULL DEFAULT '{}',
+    "created_at" timestamptz,
+    PRIMARY KEY ("role_id","permission_id"),
+    CONSTRAINT "fk_role_permissions_permission" FOREIGN KEY ("permission_id") REFERENCES "permissions"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_roles_role_permissions" FOREIGN KEY ("role_id") REFERENCES "roles"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_role_permissions_permission_id" ON "role_permissions" ("permission_id");
+
+CREATE TABLE "user_roles" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "role_id" uuid NOT NULL,
+    "assigned_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "assigned_by" uuid,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_user_roles_role" FOREIGN KEY ("role_id") REFERENCES "roles"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_users_user_roles" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_user_roles_role_id" ON "user_roles" ("role_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_user_role" ON "user_roles" ("user_id","role_id");
+CREATE INDEX IF NOT EXISTS "idx_user_roles_user_id" ON "user_roles" ("user_id");
+
+CREATE TABLE "user_permission_overrides" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "permission_id" uuid NOT NULL,
+    "organization_id" uuid,
+    "resource_type" varchar(50),
+    "resource_id" uuid,
+    "is_granted" boolean NOT NULL,
+    "reason" text,
+    "granted_by" uuid,
+    "granted_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "expires_at" timestamptz,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_user_permission_overrides_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_user_permission_overrides_permission" FOREIGN KEY ("permission_id") REFERENCES "permissions"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_user_permission_overrides_organization_id" ON "user_permission_overrides" ("organization_id");
+CREATE INDEX IF NOT EXISTS "idx_user_permission_overrides_permission_id" ON "user_permission_overrides" ("permission_id");
+CREATE INDEX IF NOT EXISTS "idx_user_permission_overrides_user_id" ON "user_permission_overrides" ("user_id");
@@ -0,0 +1,46 @@
+DROP TABLE IF EXISTS "learning_goals" CASCADE;
+DROP TABLE IF EXISTS "reward_redemptions" CASCADE;
+DROP TABLE IF EXISTS "rewards" CASCADE;
+DROP TABLE IF EXISTS "leaderboard_entries" CASCADE;
+DROP TABLE IF EXISTS "user_achievement_progress" CASCADE;
+DROP TABLE IF EXISTS "user_achievements" CASCADE;
+DROP TABLE IF EXISTS "achievements" CASCADE;
+DROP TABLE IF EXISTS "user_streaks" CASCADE;
+DROP TABLE IF EXISTS "daily_checkins" CASCADE;
+DROP TABLE IF EXISTS "point_rules" CASCADE;
+DROP TABLE IF EXISTS "point_transactions" CASCADE;
+DROP TABLE IF EXISTS "user_points" CASCADE;
+DROP TABLE IF EXISTS "reports" CASCADE;
+DROP TABLE IF EXISTS "cart_items" CASCADE;
+DROP TABLE IF EXISTS "wishlists" CASCADE;
+DROP TABLE IF EXISTS "notification_settings" CASCADE;
+DROP TABLE IF EXISTS "notifications" CASCADE;
+DROP TABLE IF EXISTS "instructor_payouts" CASCADE;
+DROP TABLE IF EXISTS "coupon_usages" CASCADE;
+DROP TABLE IF EXISTS "order_items" CASCADE;
+DROP TABLE IF EXISTS "orders" CASCADE;
+DROP TABLE IF EXISTS "coupons" CASCADE;
+DROP TABLE IF EXISTS "discussion_votes" CASCADE;
+DROP TABLE IF EXISTS "discussions" CASCADE;
+DROP TABLE IF EXISTS "review_reactions" CASCADE;
+DROP TABLE IF EXISTS "reviews" CASCADE;
+DROP TABLE IF EXISTS "user_notes" CASCADE;
+DROP TABLE IF EXISTS "lesson_progress" CASCADE;
+DROP TABLE IF EXISTS "enrollments" CASCADE;
+DROP TABLE IF EXISTS "certificates" CASCADE;
+DROP TABLE IF EXISTS "quiz_attempt_answers" CASCADE;
+DROP TABLE IF EXISTS "quiz_attempts" CASCADE;
+DROP TABLE IF EXISTS "question_answers" CASCADE;
+DROP TABLE IF EXISTS "questions" CASCADE;
+DROP TABLE IF EXISTS "quizzes" CASCADE;
+DROP TABLE IF EXISTS "lesson_attachments" CASCADE;
+DROP TABLE IF EXISTS "lesson_articles" CASCADE;
+DROP TABLE IF EXISTS "lesson_videos" CASCADE;
+DROP TABLE IF EXISTS "lessons" CASCADE;
+DROP TABLE IF EXISTS "sections" CASCADE;
+DROP TABLE IF EXISTS "course_tags" CASCADE;
+DROP TABLE IF EXISTS "courses" CASCADE;
+DROP TABLE IF EXISTS "tags" CASCADE;
+DROP TABLE IF EXISTS "categories" CASCADE;
+DROP TABLE IF EXISTS "user_oauth_providers" CASCADE;
+DROP TABLE IF EXISTS "verification_codes" CASCADE;
@@ -0,0 +1,826 @@
+-- Courses, learning progress, payments, notifications and gamification,
+-- generated from model.AllModels.
+
+CREATE TABLE "verification_codes" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "user_id" uuid NOT NULL,
+    "code" varchar(10) NOT NULL,
+    "type" varchar(20) NOT NULL,
+    "expires_at" timestamptz NOT NULL,
+    "is_used" boolean DEFAULT false,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_users_verification_codes" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE,
+    CONSTRAINT "chk_verification_codes_type" CHECK (type IN ('email', 'phone', 'password_reset'))
+);
+CREATE INDEX IF NOT EXISTS "idx_verification_codes_user_id" ON "verification_codes" ("user_id");
+
+CREATE TABLE "user_oauth_providers" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "user_id" uuid NOT NULL,
+    "provider" varchar(50) NOT NULL,
+    "provider_user_id" varchar(255) NOT NULL,
+    "access_token" text,
+    "refresh_token" text,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_users_o_auth_providers" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE,
+    CONSTRAINT "chk_user_oauth_providers_provider" CHECK (provider IN ('google', 'facebook', 'github'))
+);
+CREATE INDEX IF NOT EXISTS "idx_user_oauth_providers_user_id" ON "user_oauth_providers" ("user_id");
+
+CREATE TABLE "categories" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "parent_id" uuid,
+    "name" varchar(100) NOT NULL,
+    "slug" varchar(100) NOT NULL,
+    "description" text,
+    "icon_url" varchar(500),
+    "display_order" bigint DEFAULT 0,
+    "is_active" boolean DEFAULT true,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_categories_children" FOREIGN KEY ("parent_id") REFERENCES "categories"("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_categories_slug" ON "categories" ("slug");
+CREATE INDEX IF NOT EXISTS "idx_categories_parent_id" ON "categories" ("parent_id");
+CREATE INDEX IF NOT EXISTS "idx_categories_deleted_at" ON "categories" ("deleted_at");
+
+CREATE TABLE "tags" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "name" varchar(50) NOT NULL,
+    "slug" varchar(50) NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_tags_slug" ON "tags" ("slug");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_tags_name" ON "tags" ("name");
+
+CREATE TABLE "courses" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "instructor_id" uuid NOT NULL,
+    "category_id" uuid,
+    "title" varchar(255) NOT NULL,
+    "slug" varchar(255) NOT NULL,
+    "short_description" varchar(500),
+    "description" text,
+    "thumbnail_url" varchar(500),
+    "preview_video_url" varchar(500),
+    "level" varchar(20) DEFAULT 'beginner',
+    "language" varchar(10) DEFAULT 'vi',
+    "price" decimal(12,2) DEFAULT '0',
+    "discount_price" decimal(12,2),
+    "discount_expires_at" timestamptz,
+    "total_duration_minutes" bigint DEFAULT 0,
+    "total_lessons" bigint DEFAULT 0,
+    "total_students" bigint DEFAULT 0,
+    "average_rating" decimal(2,1) DEFAULT '0',
+    "total_reviews" bigint DEFAULT 0,
+    "requirements" text[],
+    "objectives" text[],
+    "target_audience" text[],
+    "status" varchar(20) DEFAULT 'draft',
+    "published_at" timestamptz,
+    "is_featured" boolean DEFAULT false,
+    "is_free" boolean DEFAULT false,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_categories_courses" FOREIGN KEY ("category_id") REFERENCES "categories"("id"),
+    CONSTRAINT "fk_users_courses" FOREIGN KEY ("instructor_id") REFERENCES "users"("id"),
+    CONSTRAINT "chk_courses_level" CHECK (level IN ('beginner', 'intermediate', 'advanced', 'all_levels')),
+    CONSTRAINT "chk_courses_status" CHECK (status IN ('draft', 'pending_review', 'published', 'archived'))
+);
+CREATE INDEX IF NOT EXISTS "idx_courses_status" ON "courses" ("status");
+CREATE INDEX IF NOT EXISTS "idx_courses_average_rating" ON "courses" ("average_rating");
+CREATE INDEX IF NOT EXISTS "idx_courses_price" ON "courses" ("price");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_courses_slug" ON "courses" ("slug");
+CREATE INDEX IF NOT EXISTS "idx_courses_category_id" ON "courses" ("category_id");
+CREATE INDEX IF NOT EXISTS "idx_courses_instructor_id" ON "courses" ("instructor_id");
+CREATE INDEX IF NOT EXISTS "idx_courses_deleted_at" ON "courses" ("deleted_at");
+
+CREATE TABLE "course_tags" (
+    "course_id" uuid DEFAULT gen_random_uuid(),
+    "tag_id" uuid DEFAULT gen_random_uuid(),
+    PRIMARY KEY ("course_id","tag_id"),
+    CONSTRAINT "fk_course_tags_course" FOREIGN KEY ("course_id") REFERENCES "courses"("id"),
+    CONSTRAINT "fk_course_tags_tag" FOREIGN KEY ("tag_id") REFERENCES "tags"("id")
+);
+
+CREATE TABLE "sections" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "course_id" uuid NOT NULL,
+    "title" varchar(255) NOT NULL,
+    "description" text,
+    "display_order" bigint NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_courses_sections" FOREIGN KEY ("course_id") REFERENCES "courses"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_sections_course_id" ON "sections" ("course_id");
+CREATE INDEX IF NOT EXISTS "idx_sections_deleted_at" ON "sections" ("deleted_at");
+
+CREATE TABLE "lessons" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "section_id" uuid NOT NULL,
+    "title" varchar(255) NOT NULL,
+    "description" text,
+    "content_type" varchar(20) NOT NULL,
+    "display_order" bigint NOT NULL,
+    "duration_minutes" bigint DEFAULT 0,
+    "is_preview" boolean DEFAULT false,
+    "is_mandatory" boolean DEFAULT true,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_sections_lessons" FOREIGN KEY ("section_id") REFERENCES "sections"("id") ON DELETE CASCADE,
+    CONSTRAINT "chk_lessons_content_type" CHECK (content_type IN ('video', 'article', 'quiz', 'assignment'))
+);
+CREATE INDEX IF NOT EXISTS "idx_lessons_section_id" ON "lessons" ("section_id");
+
+CREATE TABLE "lesson_videos" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "lesson_id" uuid NOT NULL,
+    "video_url" varchar(500) NOT NULL,
+    "video_hls_url" varchar(500),
+    "thumbnail_url" varchar(500),
+    "duration_seconds" bigint NOT NULL,
+    "resolution" varchar(20),
+    "file_size_bytes" bigint,
+    "transcription" text,
+    "transcription_status" varchar(20) DEFAULT 'pending',
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_lessons_video" FOREIGN KEY ("lesson_id") REFERENCES "lessons"("id") ON DELETE CASCADE,
+    CONSTRAINT "chk_lesson_videos_transcription_status" CHECK (transcription_status IN ('pending', 'processing', 'completed', 'failed'))
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_lesson_videos_lesson_id" ON "lesson_videos" ("lesson_id");
+CREATE INDEX IF NOT EXISTS "idx_lesson_videos_deleted_at" ON "lesson_videos" ("deleted_at");
+
+CREATE TABLE "lesson_articles" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "lesson_id" uuid NOT NULL,
+    "content" text NOT NULL,
+    "reading_time_minutes" bigint DEFAULT 5,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_lessons_article" FOREIGN KEY ("lesson_id") REFERENCES "lessons"("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_lesson_articles_lesson_id" ON "lesson_articles" ("lesson_id");
+CREATE INDEX IF NOT EXISTS "idx_lesson_articles_deleted_at" ON "lesson_articles" ("deleted_at");
+
+CREATE TABLE "lesson_attachments" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "lesson_id" uuid NOT NULL,
+    "file_name" varchar(255) NOT NULL,
+    "file_url" varchar(500) NOT NULL,
+    "file_type" varchar(50),
+    "file_size_bytes" bigint,
+    "download_count" bigint DEFAULT 0,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_lessons_attachments" FOREIGN KEY ("lesson_id") REFERENCES "lessons"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_lesson_attachments_lesson_id" ON "lesson_attachments" ("lesson_id");
+
+CREATE TABLE "quizzes" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "lesson_id" uuid,
+    "course_id" uuid,
+    "title" varchar(255) NOT NULL,
+    "description" text,
+    "time_limit_minutes" bigint,
+    "pass_percentage" decimal(5,2) DEFAULT '70.00',
+    "max_attempts" bigint DEFAULT 3,
+    "shuffle_questions" boolean DEFAULT true,
+    "shuffle_answers" boolean DEFAULT true,
+    "show_correct_answers" boolean DEFAULT true,
+    "is_ai_generated" boolean DEFAULT false,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_lessons_quiz" FOREIGN KEY ("lesson_id") REFERENCES "lessons"("id"),
+    CONSTRAINT "fk_courses_quizzes" FOREIGN KEY ("course_id") REFERENCES "courses"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_quizzes_course_id" ON "quizzes" ("course_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_quizzes_lesson_id" ON "quizzes" ("lesson_id");
+CREATE INDEX IF NOT EXISTS "idx_quizzes_deleted_at" ON "quizzes" ("deleted_at");
+
+CREATE TABLE "questions" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "quiz_id" uuid NOT NULL,
+    "question_text" text NOT NULL,
+    "question_type" varchar(20) NOT NULL,
+    "explanation" text,
+    "points" decimal(5,2) DEFAULT '1.00',
+    "display_order" bigint NOT NULL,
+    "image_url" varchar(500),
+    "is_ai_generated" boolean DEFAULT false,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_quizzes_questions" FOREIGN KEY ("quiz_id") REFERENCES "quizzes"("id") ON DELETE CASCADE,
+    CONSTRAINT "chk_questions_question_type" CHECK (question_type IN ('single_choice', 'multiple_choice', 'true_false', 'fill_blank', 'essay'))
+);
+CREATE INDEX IF NOT EXISTS "idx_questions_quiz_id" ON "questions" ("quiz_id");
+CREATE INDEX IF NOT EXISTS "idx_questions_deleted_at" ON "questions" ("deleted_at");
+
+CREATE TABLE "question_answers" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "question_id" uuid NOT NULL,
+    "answer_text" text NOT NULL,
+    "is_correct" boolean DEFAULT false,
+    "display_order" bigint NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_questions_answers" FOREIGN KEY ("question_id") REFERENCES "questions"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_question_answers_question_id" ON "question_answers" ("question_id");
+
+CREATE TABLE "quiz_attempts" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "user_id" uuid NOT NULL,
+    "quiz_id" uuid NOT NULL,
+    "score" decimal(5,2),
+    "total_points" decimal(5,2),
+    "percentage" decimal(5,2),
+    "is_passed" boolean,
+    "time_spent_seconds" bigint,
+    "started_at" timestamptz DEFAULT CURRENT_TIMESTAMP,
+    "completed_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_quizzes_attempts" FOREIGN KEY ("quiz_id") REFERENCES "quizzes"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_quiz_attempts_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_quiz_attempts_quiz_id" ON "quiz_attempts" ("quiz_id");
+CREATE INDEX IF NOT EXISTS "idx_quiz_attempts_user_id" ON "quiz_attempts" ("user_id");
+
+CREATE TABLE "quiz_attempt_answers" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "attempt_id" uuid NOT NULL,
+    "question_id" uuid NOT NULL,
+    "selected_answer_ids" uuid[],
+    "text_answer" text,
+    "is_correct" boolean,
+    "points_earned" decimal(5,2) DEFAULT '0',
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_quiz_attempt_answers_question" FOREIGN KEY ("question_id") REFERENCES "questions"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_quiz_attempts_answers" FOREIGN KEY ("attempt_id") REFERENCES "quiz_attempts"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_quiz_attempt_answers_question_id" ON "quiz_attempt_answers" ("question_id");
+CREATE INDEX IF NOT EXISTS "idx_quiz_attempt_answers_attempt_id" ON "quiz_attempt_answers" ("attempt_id");
+
+CREATE TABLE "certificates" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "user_id" uuid NOT NULL,
+    "course_id" uuid NOT NULL,
+    "enrollment_id" uuid NOT NULL,
+    "certificate_number" varchar(50) NOT NULL,
+    "certificate_url" varchar(500),
+    "issued_at" timestamptz DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_certificates_course" FOREIGN KEY ("course_id") REFERENCES "courses"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_certificates_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_certificates_certificate_number" ON "certificates" ("certificate_number");
+CREATE INDEX IF NOT EXISTS "idx_certificates_enrollment_id" ON "certificates" ("enrollment_id");
+CREATE INDEX IF NOT EXISTS "idx_certificates_course_id" ON "certificates" ("course_id");
+CREATE INDEX IF NOT EXISTS "idx_certificates_user_id" ON "certificates" ("user_id");
+
+CREATE TABLE "enrollments" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "user_id" uuid NOT NULL,
+    "course_id" uuid NOT NULL,
+    "enrolled_at" timestamptz DEFAULT CURRENT_TIMESTAMP,
+    "expires_at" timestamptz,
+    "progress_percentage" decimal(5,2) DEFAULT '0',
+    "completed_at" timestamptz,
+    "last_accessed_at" timestamptz,
+    "certificate_id" uuid,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_enrollments_certificate" FOREIGN KEY ("certificate_id") REFERENCES "certificates"("id"),
+    CONSTRAINT "fk_courses_enrollments" FOREIGN KEY ("course_id") REFERENCES "courses"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_users_enrollments" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_enrollments_course_id" ON "enrollments" ("course_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_user_course" ON "enrollments" ("user_id","course_id");
+CREATE INDEX IF NOT EXISTS "idx_enrollments_user_id" ON "enrollments" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_enrollments_deleted_at" ON "enrollments" ("deleted_at");
+-- certificates and enrollments reference each other, so this key is added once both exist.
+ALTER TABLE "certificates" ADD CONSTRAINT "fk_certificates_enrollment" FOREIGN KEY ("enrollment_id") REFERENCES "enrollments"("id") ON DELETE CASCADE;
+
+CREATE TABLE "lesson_progress" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "user_id" uuid NOT NULL,
+    "lesson_id" uuid NOT NULL,
+    "enrollment_id" uuid NOT NULL,
+    "status" varchar(20) DEFAULT 'not_started',
+    "progress_percentage" decimal(5,2) DEFAULT '0',
+    "video_watched_seconds" bigint DEFAULT 0,
+    "completed_at" timestamptz,
+    "last_accessed_at" timestamptz DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_lessons_lesson_progress" FOREIGN KEY ("lesson_id") REFERENCES "lessons"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_lesson_progress_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_enrollments_lesson_progress" FOREIGN KEY ("enrollment_id") REFERENCES "enrollments"("id") ON DELETE CASCADE,
+    CONSTRAINT "chk_lesson_progress_status" CHECK (status IN ('not_started', 'in_progress', 'completed'))
+);
+CREATE INDEX IF NOT EXISTS "idx_lesson_progress_status" ON "lesson_progress" ("status");
+CREATE INDEX IF NOT EXISTS "idx_lesson_progress_enrollment_id" ON "lesson_progress" ("enrollment_id");
+CREATE INDEX IF NOT EXISTS "idx_lesson_progress_lesson_id" ON "lesson_progress" ("lesson_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_user_lesson" ON "lesson_progress" ("user_id","lesson_id");
+CREATE INDEX IF NOT EXISTS "idx_lesson_progress_user_id" ON "lesson_progress" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_lesson_progress_deleted_at" ON "lesson_progress" ("deleted_at");
+
+CREATE TABLE "user_notes" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "user_id" uuid NOT NULL,
+    "lesson_id" uuid NOT NULL,
+    "content" text NOT NULL,
+    "video_timestamp_seconds" bigint,
+    "is_bookmarked" boolean DEFAULT false,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_user_notes_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_lessons_user_notes" FOREIGN KEY ("lesson_id") REFERENCES "lessons"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_user_notes_lesson_id" ON "user_notes" ("lesson_id");
+CREATE INDEX IF NOT EXISTS "idx_user_notes_user_id" ON "user_notes" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_user_notes_deleted_at" ON "user_notes" ("deleted_at");
+
+CREATE TABLE "reviews" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "user_id" uuid NOT NULL,
+    "course_id" uuid NOT NULL,
+    "rating" smallint NOT NULL,
+    "comment" text,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_reviews_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_reviews_course" FOREIGN KEY ("course_id") REFERENCES "courses"("id") ON DELETE CASCADE,
+    CONSTRAINT "chk_reviews_rating" CHECK (rating >= 1 AND rating <= 5)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_user_course_review" ON "reviews" ("course_id");
+CREATE INDEX IF NOT EXISTS "idx_reviews_course_id" ON "reviews" ("course_id");
+CREATE INDEX IF NOT EXISTS "idx_reviews_user_id" ON "reviews" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_reviews_deleted_at" ON "reviews" ("deleted_at");
+
+CREATE TABLE "review_reactions" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "review_id" uuid NOT NULL,
+    "user_id" uuid NOT NULL,
+    "reaction_type" varchar(20) NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_review_reactions_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_reviews_reactions" FOREIGN KEY ("review_id") REFERENCES "reviews"("id"),
+    CONSTRAINT "chk_review_reactions_reaction_type" CHECK (reaction_type IN ('helpful', 'not_helpful'))
+);
+CREATE INDEX IF NOT EXISTS "idx_review_reactions_user_id" ON "review_reactions" ("user_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_review_user_reaction" ON "review_reactions" ("review_id","user_id");
+CREATE INDEX IF NOT EXISTS "idx_review_reactions_review_id" ON "review_reactions" ("review_id");
+
+CREATE TABLE "discussions" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "user_id" uuid NOT NULL,
+    "lesson_id" uuid NOT NULL,
+    "parent_id" uuid,
+    "content" text NOT NULL,
+    "video_timestamp_seconds" bigint,
+    "upvote_count" bigint DEFAULT 0,
+    "is_pinned" boolean DEFAULT false,
+    "is_instructor_answer" boolean DEFAULT false,
+    "is_hidden" boolean DEFAULT false,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_lessons_discussions" FOREIGN KEY ("lesson_id") REFERENCES "lessons"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_discussions_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_discussions_replies" FOREIGN KEY ("parent_id") REFERENCES "discussions"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_discussions_parent_id" ON "discussions" ("parent_id");
+CREATE INDEX IF NOT EXISTS "idx_discussions_lesson_id" ON "discussions" ("lesson_id");
+CREATE INDEX IF NOT EXISTS "idx_discussions_user_id" ON "discussions" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_discussions_deleted_at" ON "discussions" ("deleted_at");
+
+CREATE TABLE "discussion_votes" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "user_id" uuid NOT NULL,
+    "discussion_id" uuid NOT NULL,
+    "vote_type" varchar(10) NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_discussions_votes" FOREIGN KEY ("discussion_id") REFERENCES "discussions"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_discussion_votes_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE,
+    CONSTRAINT "chk_discussion_votes_vote_type" CHECK (vote_type IN ('upvote', 'downvote'))
+);
+CREATE INDEX IF NOT EXISTS "idx_discussion_votes_discussion_id" ON "discussion_votes" ("discussion_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_user_discussion_vote" ON "discussion_votes" ("user_id","discussion_id");
+CREATE INDEX IF NOT EXISTS "idx_discussion_votes_user_id" ON "discussion_votes" ("user_id");
+
+CREATE TABLE "coupons" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "code" varchar(50) NOT NULL,
+    "description" text,
+    "discount_type" varchar(20) NOT NULL,
+    "discount_value" decimal(12,2) NOT NULL,
+    "min_purchase_amount" decimal(12,2),
+    "max_discount_amount" decimal(12,2),
+    "usage_limit" bigint,
+    "usage_count" bigint DEFAULT 0,
+    "per_user_limit" bigint DEFAULT 1,
+    "applicable_course_ids" uuid[],
+    "starts_at" timestamptz,
+    "expires_at" timestamptz,
+    "is_active" boolean DEFAULT true,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "chk_coupons_discount_type" CHECK (discount_type IN ('percentage', 'fixed_amount'))
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_coupons_code" ON "coupons" ("code");
+CREATE INDEX IF NOT EXISTS "idx_coupons_deleted_at" ON "coupons" ("deleted_at");
+
+CREATE TABLE "orders" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "order_number" varchar(50) NOT NULL,
+    "subtotal" decimal(12,2) NOT NULL,
+    "discount_amount" decimal(12,2) DEFAULT '0',
+    "tax_amount" decimal(12,2) DEFAULT '0',
+    "total_amount" decimal(12,2) NOT NULL,
+    "currency" varchar(3) DEFAULT 'VND',
+    "status" varchar(20) DEFAULT 'pending',
+    "payment_method" varchar(30),
+    "payment_gateway" varchar(30),
+    "payment_transaction_id" varchar(255),
+    "paid_at" timestamptz,
+    "coupon_id" uuid,
+    "notes" text,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_users_orders" FOREIGN KEY ("user_id") REFERENCES "users"("id"),
+    CONSTRAINT "fk_orders_coupon" FOREIGN KEY ("coupon_id") REFERENCES "coupons"("id"),
+    CONSTRAINT "chk_orders_status" CHECK (status IN ('pending', 'processing', 'completed', 'failed', 'refunded', 'cancelled'))
+);
+CREATE INDEX IF NOT EXISTS "idx_orders_status" ON "orders" ("status");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_orders_order_number" ON "orders" ("order_number");
+CREATE INDEX IF NOT EXISTS "idx_orders_user_id" ON "orders" ("user_id");
+
+CREATE TABLE "order_items" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "order_id" uuid NOT NULL,
+    "course_id" uuid NOT NULL,
+    "price" decimal(12,2) NOT NULL,
+    "discount_amount" decimal(12,2) DEFAULT '0',
+    "final_price" decimal(12,2) NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_order_items_course" FOREIGN KEY ("course_id") REFERENCES "courses"("id"),
+    CONSTRAINT "fk_orders_items" FOREIGN KEY ("order_id") REFERENCES "orders"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_order_items_order_id" ON "order_items" ("order_id");
+
+CREATE TABLE "coupon_usages" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "coupon_id" uuid NOT NULL,
+    "user_id" uuid NOT NULL,
+    "order_id" uuid NOT NULL,
+    "discount_amount" decimal(12,2) NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_coupon_usages_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_coupons_usages" FOREIGN KEY ("coupon_id") REFERENCES "coupons"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_orders_coupon_usage" FOREIGN KEY ("order_id") REFERENCES "orders"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_coupon_usages_order_id" ON "coupon_usages" ("order_id");
+CREATE INDEX IF NOT EXISTS "idx_coupon_usages_user_id" ON "coupon_usages" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_coupon_usages_coupon_id" ON "coupon_usages" ("coupon_id");
+
+CREATE TABLE "instructor_payouts" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "instructor_id" uuid NOT NULL,
+    "amount" decimal(12,2) NOT NULL,
+    "currency" varchar(3) DEFAULT 'VND',
+    "status" varchar(20) DEFAULT 'pending',
+    "payment_method" varchar(50),
+    "bank_name" varchar(100),
+    "bank_account_number" varchar(50),
+    "bank_account_name" varchar(255),
+    "transaction_id" varchar(255),
+    "period_start" date,
+    "period_end" date,
+    "processed_at" timestamptz,
+    "notes" text,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_instructor_payouts_instructor" FOREIGN KEY ("instructor_id") REFERENCES "users"("id"),
+    CONSTRAINT "chk_instructor_payouts_status" CHECK (status IN ('pending', 'processing', 'completed', 'failed'))
+);
+CREATE INDEX IF NOT EXISTS "idx_instructor_payouts_instructor_id" ON "instructor_payouts" ("instructor_id");
+CREATE INDEX IF NOT EXISTS "idx_instructor_payouts_deleted_at" ON "instructor_payouts" ("deleted_at");
+
+CREATE TABLE "notifications" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "user_id" uuid NOT NULL,
+    "title" varchar(255) NOT NULL,
+    "content" text NOT NULL,
+    "notification_type" varchar(30) NOT NULL,
+    "reference_type" varchar(30),
+    "reference_id" uuid,
+    "is_read" boolean DEFAULT false,
+    "read_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_notifications_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE,
+    CONSTRAINT "chk_notifications_notification_type" CHECK (notification_type IN ('course_update', 'new_lesson', 'quiz_reminder', 'certificate_earned', 'payment_success', 'payment_failed', 'promotion', 'system', 'achievement', 'streak', 'point_earned'))
+);
+CREATE INDEX IF NOT EXISTS "idx_notifications_is_read" ON "notifications" ("is_read");
+CREATE INDEX IF NOT EXISTS "idx_notifications_user_id" ON "notifications" ("user_id");
+
+CREATE TABLE "notification_settings" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "user_id" uuid NOT NULL,
+    "email_course_updates" boolean DEFAULT true,
+    "email_promotions" boolean DEFAULT true,
+    "email_recommendations" boolean DEFAULT true,
+    "push_course_updates" boolean DEFAULT true,
+    "push_quiz_reminders" boolean DEFAULT true,
+    "push_promotions" boolean DEFAULT false,
+    "push_achievements" boolean DEFAULT true,
+    "push_streak_reminders" boolean DEFAULT true,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_notification_settings_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_notification_settings_user_id" ON "notification_settings" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_notification_settings_deleted_at" ON "notification_settings" ("deleted_at");
+
+CREATE TABLE "wishlists" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "user_id" uuid NOT NULL,
+    "course_id" uuid NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_wishlists_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_wishlists_course" FOREIGN KEY ("course_id") REFERENCES "courses"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_wishlists_course_id" ON "wishlists" ("course_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_user_course_wishlist" ON "wishlists" ("user_id","course_id");
+CREATE INDEX IF NOT EXISTS "idx_wishlists_user_id" ON "wishlists" ("user_id");
+
+CREATE TABLE "cart_items" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "user_id" uuid NOT NULL,
+    "course_id" uuid NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_cart_items_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_cart_items_course" FOREIGN KEY ("course_id") REFERENCES "courses"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_cart_items_course_id" ON "cart_items" ("course_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_user_course_cart" ON "cart_items" ("user_id","course_id");
+CREATE INDEX IF NOT EXISTS "idx_cart_items_user_id" ON "cart_items" ("user_id");
+
+CREATE TABLE "reports" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "reporter_id" uuid NOT NULL,
+    "reported_type" varchar(30) NOT NULL,
+    "reported_id" uuid NOT NULL,
+    "reason" varchar(50) NOT NULL,
+    "description" text,
+    "status" varchar(20) DEFAULT 'pending',
+    "admin_notes" text,
+    "resolved_by" uuid,
+    "resolved_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_reports_reporter" FOREIGN KEY ("reporter_id") REFERENCES "users"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_reports_resolver" FOREIGN KEY ("resolved_by") REFERENCES "users"("id"),
+    CONSTRAINT "chk_reports_reported_type" CHECK (reported_type IN ('course', 'review', 'discussion', 'user')),
+    CONSTRAINT "chk_reports_reason" CHECK (reason IN ('spam', 'inappropriate', 'copyright', 'harassment', 'other')),
+    CONSTRAINT "chk_reports_status" CHECK (status IN ('pending', 'reviewing', 'resolved', 'dismissed'))
+);
+CREATE INDEX IF NOT EXISTS "idx_reports_reported_id" ON "reports" ("reported_id");
+CREATE INDEX IF NOT EXISTS "idx_reports_reporter_id" ON "reports" ("reporter_id");
+
+CREATE TABLE "user_points" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "user_id" uuid NOT NULL,
+    "total_points" bigint DEFAULT 0,
+    "current_points" bigint DEFAULT 0,
+    "lifetime_points" bigint DEFAULT 0,
+    "level" bigint DEFAULT 1,
+    "level_progress" bigint DEFAULT 0,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_users_points" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_user_points_user_id" ON "user_points" ("user_id");
+
+CREATE TABLE "point_transactions" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "user_id" uuid NOT NULL,
+    "points" bigint NOT NULL,
+    "type" varchar(30) NOT NULL,
+    "description" varchar(255),
+    "reference_id" uuid,
+    "balance" bigint NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_point_transactions_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_user_points_transactions" FOREIGN KEY ("user_id") REFERENCES "user_points"("id"),
+    CONSTRAINT "chk_point_transactions_type" CHECK (type IN ('lesson_complete', 'quiz_pass', 'course_complete', 'daily_checkin', 'streak_bonus', 'first_login', 'review_write', 'referral', 'redemption', 'admin_adjust'))
+);
+CREATE INDEX IF NOT EXISTS "idx_point_transactions_user_id" ON "point_transactions" ("user_id");
+
+CREATE TABLE "point_rules" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "action_type" varchar(30) NOT NULL,
+    "points" bigint NOT NULL,
+    "description" varchar(255),
+    "is_active" boolean DEFAULT true,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_point_rules_action_type" ON "point_rules" ("action_type");
+
+CREATE TABLE "daily_checkins" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "user_id" uuid NOT NULL,
+    "checkin_date" date NOT NULL,
+    "streak_count" bigint DEFAULT 1,
+    "points_earned" bigint DEFAULT 0,
+    "bonus_earned" bigint DEFAULT 0,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_daily_checkins_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_user_checkin_date" ON "daily_checkins" ("user_id","checkin_date");
+CREATE INDEX IF NOT EXISTS "idx_daily_checkins_checkin_date" ON "daily_checkins" ("checkin_date");
+CREATE INDEX IF NOT EXISTS "idx_daily_checkins_user_id" ON "daily_checkins" ("user_id");
+
+CREATE TABLE "user_streaks" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "user_id" uuid NOT NULL,
+    "current_streak" bigint DEFAULT 0,
+    "longest_streak" bigint DEFAULT 0,
+    "last_checkin_date" date,
+    "total_checkins" bigint DEFAULT 0,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_users_streak" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_user_streaks_user_id" ON "user_streaks" ("user_id");
+
+CREATE TABLE "achievements" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "name" varchar(100) NOT NULL,
+    "slug" varchar(100) NOT NULL,
+    "description" text,
+    "icon_url" varchar(500),
+    "badge_url" varchar(500),
+    "category" varchar(30) NOT NULL,
+    "points" bigint DEFAULT 0,
+    "requirement" varchar(50),
+    "threshold" bigint DEFAULT 1,
+    "is_hidden" boolean DEFAULT false,
+    "is_active" boolean DEFAULT true,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "chk_achievements_category" CHECK (category IN ('learning', 'streak', 'social', 'milestone', 'special'))
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_achievements_slug" ON "achievements" ("slug");
+
+CREATE TABLE "user_achievements" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "user_id" uuid NOT NULL,
+    "achievement_id" uuid NOT NULL,
+    "earned_at" timestamptz NOT NULL,
+    "progress" bigint DEFAULT 100,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_users_achievements" FOREIGN KEY ("user_id") REFERENCES "users"("id"),
+    CONSTRAINT "fk_achievements_user_achievements" FOREIGN KEY ("achievement_id") REFERENCES "achievements"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_user_achievements_achievement_id" ON "user_achievements" ("achievement_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_user_achievement" ON "user_achievements" ("user_id","achievement_id");
+CREATE INDEX IF NOT EXISTS "idx_user_achievements_user_id" ON "user_achievements" ("user_id");
+
+CREATE TABLE "user_achievement_progress" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "user_id" uuid NOT NULL,
+    "achievement_id" uuid NOT NULL,
+    "current_value" bigint DEFAULT 0,
+    "target_value" bigint NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_user_achievement_progress_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_user_achievement_progress_achievement" FOREIGN KEY ("achievement_id") REFERENCES "achievements"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_user_achievement_progress_achievement_id" ON "user_achievement_progress" ("achievement_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_user_achievement_progress" ON "user_achievement_progress" ("user_id","achievement_id");
+CREATE INDEX IF NOT EXISTS "idx_user_achievement_progress_user_id" ON "user_achievement_progress" ("user_id");
+
+CREATE TABLE "leaderboard_entries" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "user_id" uuid NOT NULL,
+    "period" varchar(20) NOT NULL,
+    "period_type" varchar(10) NOT NULL,
+    "points" bigint DEFAULT 0,
+    "rank" bigint DEFAULT 0,
+    "lessons_completed" bigint DEFAULT 0,
+    "quizzes_completed" bigint DEFAULT 0,
+    "study_minutes" bigint DEFAULT 0,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_leaderboard_entries_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE,
+    CONSTRAINT "chk_leaderboard_entries_period_type" CHECK (period_type IN ('weekly', 'monthly', 'all_time'))
+);
+CREATE INDEX IF NOT EXISTS "idx_leaderboard_entries_points" ON "leaderboard_entries" ("points");
+CREATE INDEX IF NOT EXISTS "idx_leaderboard_entries_period" ON "leaderboard_entries" ("period");
+CREATE INDEX IF NOT EXISTS "idx_leaderboard_entries_user_id" ON "leaderboard_entries" ("user_id");
+
+CREATE TABLE "rewards" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "name" varchar(100) NOT NULL,
+    "description" text,
+    "image_url" varchar(500),
+    "points_cost" bigint NOT NULL,
+    "reward_type" varchar(30) NOT NULL,
+    "reward_value" varchar(255),
+    "stock" bigint,
+    "is_active" boolean DEFAULT true,
+    "expires_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "chk_rewards_reward_type" CHECK (reward_type IN ('course_discount', 'free_course', 'certificate_badge', 'custom'))
+);
+
+CREATE TABLE "reward_redemptions" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "user_id" uuid NOT NULL,
+    "reward_id" uuid NOT NULL,
+    "points_used" bigint NOT NULL,
+    "status" varchar(20) DEFAULT 'pending',
+    "code" varchar(50),
+    "used_at" timestamptz,
+    "notes" text,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_reward_redemptions_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_rewards_redemptions" FOREIGN KEY ("reward_id") REFERENCES "rewards"("id"),
+    CONSTRAINT "chk_reward_redemptions_status" CHECK (status IN ('pending', 'approved', 'delivered', 'cancelled'))
+);
+CREATE INDEX IF NOT EXISTS "idx_reward_redemptions_reward_id" ON "reward_redemptions" ("reward_id");
+CREATE INDEX IF NOT EXISTS "idx_reward_redemptions_user_id" ON "reward_redemptions" ("user_id");
+
+CREATE TABLE "learning_goals" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "user_id" uuid NOT NULL,
+    "goal_type" varchar(30) NOT NULL,
+    "target_value" bigint NOT NULL,
+    "current_value" bigint DEFAULT 0,
+    "period" varchar(20),
+    "is_completed" boolean DEFAULT false,
+    "completed_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_learning_goals_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE,
+    CONSTRAINT "chk_learning_goals_goal_type" CHECK (goal_type IN ('daily_study_time', 'weekly_lessons', 'monthly_courses', 'streak_days', 'quiz_score'))
+);
+CREATE INDEX IF NOT EXISTS "idx_learning_goals_user_id" ON "learning_goals" ("user_id");
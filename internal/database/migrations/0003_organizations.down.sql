@@ -0,0 +1,31 @@
+DROP INDEX IF EXISTS "idx_courses_organization_id";
+ALTER TABLE "courses" DROP CONSTRAINT IF EXISTS "fk_courses_organization";
+ALTER TABLE "courses" DROP COLUMN IF EXISTS "organization_id";
+
+DELETE FROM "categories" WHERE organization_id IS NOT NULL;
+DROP INDEX IF EXISTS "idx_categories_organization_id";
+DROP INDEX IF EXISTS "idx_categories_org_slug";
+DROP INDEX IF EXISTS "idx_categories_slug";
+ALTER TABLE "categories" DROP CONSTRAINT IF EXISTS "fk_categories_organization";
+ALTER TABLE "categories" DROP COLUMN IF EXISTS "organization_id";
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_categories_slug" ON "categories" ("slug");
+
+DELETE FROM "user_roles" WHERE organization_id IS NOT NULL;
+DROP INDEX IF EXISTS "idx_user_roles_organization_id";
+DROP INDEX IF EXISTS "idx_user_role_org";
+DROP INDEX IF EXISTS "idx_user_role";
+ALTER TABLE "user_roles" DROP CONSTRAINT IF EXISTS "fk_user_roles_organization";
+ALTER TABLE "user_roles" DROP COLUMN IF EXISTS "organization_id";
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_user_role" ON "user_roles" ("user_id","role_id");
+
+DELETE FROM "roles" WHERE organization_id IS NOT NULL;
+DROP INDEX IF EXISTS "idx_roles_organization_id";
+DROP INDEX IF EXISTS "idx_roles_org_name";
+DROP INDEX IF EXISTS "idx_roles_name";
+ALTER TABLE "roles" DROP CONSTRAINT IF EXISTS "fk_roles_organization";
+ALTER TABLE "roles" DROP COLUMN IF EXISTS "organization_id";
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_roles_name" ON "roles" ("name");
+
+DROP TABLE IF EXISTS "organization_invitations" CASCADE;
+DROP TABLE IF EXISTS "organization_members" CASCADE;
+DROP TABLE IF EXISTS "organizations" CASCADE;
@@ -0,0 +1,102 @@
+-- Organizations (schools, training centers) as tenants. Roles, role assignments,
+-- categories and courses gain an optional organization_id; NULL keeps the old,
+-- platform-wide meaning.
+
+CREATE TABLE IF NOT EXISTS "organizations" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "owner_id" uuid NOT NULL,
+    "name" varchar(255) NOT NULL,
+    "slug" varchar(100) NOT NULL,
+    "description" text,
+    "logo_url" varchar(500),
+    "website" varchar(255),
+    "org_type" varchar(20) NOT NULL DEFAULT 'school',
+    "email" varchar(255),
+    "phone" varchar(20),
+    "address" text,
+    "city" varchar(100),
+    "country" varchar(100) NOT NULL DEFAULT 'Vietnam',
+    "max_members" bigint NOT NULL DEFAULT 100,
+    "max_courses" bigint NOT NULL DEFAULT 10,
+    "is_verified" boolean NOT NULL DEFAULT false,
+    "is_active" boolean NOT NULL DEFAULT true,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_organizations_owner" FOREIGN KEY ("owner_id") REFERENCES "users"("id"),
+    CONSTRAINT "chk_organizations_org_type" CHECK (org_type IN ('school', 'center', 'company', 'individual'))
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_organizations_slug" ON "organizations" ("slug");
+CREATE INDEX IF NOT EXISTS "idx_organizations_owner_id" ON "organizations" ("owner_id");
+CREATE INDEX IF NOT EXISTS "idx_organizations_deleted_at" ON "organizations" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "organization_members" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "organization_id" uuid NOT NULL,
+    "user_id" uuid NOT NULL,
+    "member_role" varchar(50) NOT NULL DEFAULT 'member',
+    "department" varchar(100),
+    "student_id" varchar(50),
+    "status" varchar(20) NOT NULL DEFAULT 'active',
+    "joined_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "invited_by" uuid,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_organization_members_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_organizations_members" FOREIGN KEY ("organization_id") REFERENCES "organizations"("id") ON DELETE CASCADE,
+    CONSTRAINT "chk_organization_members_member_role" CHECK (member_role IN ('owner', 'admin', 'manager', 'teacher', 'ta', 'student', 'member')),
+    CONSTRAINT "chk_organization_members_status" CHECK (status IN ('active', 'suspended'))
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_org_member" ON "organization_members" ("organization_id","user_id");
+CREATE INDEX IF NOT EXISTS "idx_organization_members_organization_id" ON "organization_members" ("organization_id");
+CREATE INDEX IF NOT EXISTS "idx_organization_members_user_id" ON "organization_members" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "organization_invitations" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "organization_id" uuid NOT NULL,
+    "email" varchar(255) NOT NULL,
+    "member_role" varchar(50) NOT NULL DEFAULT 'member',
+    "role_ids" uuid[] NOT NULL DEFAULT '{}',
+    "token_hash" varchar(64) NOT NULL,
+    "invited_by" uuid NOT NULL,
+    "expires_at" timestamptz NOT NULL,
+    "accepted_at" timestamptz,
+    "accepted_by" uuid,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_organization_invitations_organization" FOREIGN KEY ("organization_id") REFERENCES "organizations"("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_organization_invitations_token_hash" ON "organization_invitations" ("token_hash");
+CREATE INDEX IF NOT EXISTS "idx_organization_invitations_organization_id" ON "organization_invitations" ("organization_id");
+CREATE INDEX IF NOT EXISTS "idx_organization_invitations_email" ON "organization_invitations" ("email");
+
+-- Org-scoped custom roles: global names stay unique, org role names are unique per org.
+ALTER TABLE "roles" ADD COLUMN IF NOT EXISTS "organization_id" uuid;
+ALTER TABLE "roles" ADD CONSTRAINT "fk_roles_organization" FOREIGN KEY ("organization_id") REFERENCES "organizations"("id") ON DELETE CASCADE;
+DROP INDEX IF EXISTS "idx_roles_name";
+CREATE UNIQUE INDEX "idx_roles_name" ON "roles" ("name") WHERE organization_id IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_roles_org_name" ON "roles" ("organization_id","name") WHERE organization_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS "idx_roles_organization_id" ON "roles" ("organization_id");
+
+-- Role assignments that only apply inside one organization.
+ALTER TABLE "user_roles" ADD COLUMN IF NOT EXISTS "organization_id" uuid;
+ALTER TABLE "user_roles" ADD CONSTRAINT "fk_user_roles_organization" FOREIGN KEY ("organization_id") REFERENCES "organizations"("id") ON DELETE CASCADE;
+DROP INDEX IF EXISTS "idx_user_role";
+CREATE UNIQUE INDEX "idx_user_role" ON "user_roles" ("user_id","role_id") WHERE organization_id IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_user_role_org" ON "user_roles" ("user_id","role_id","organization_id") WHERE organization_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS "idx_user_roles_organization_id" ON "user_roles" ("organization_id");
+
+-- Org-scoped categories.
+ALTER TABLE "categories" ADD COLUMN IF NOT EXISTS "organization_id" uuid;
+ALTER TABLE "categories" ADD CONSTRAINT "fk_categories_organization" FOREIGN KEY ("organization_id") REFERENCES "organizations"("id") ON DELETE CASCADE;
+DROP INDEX IF EXISTS "idx_categories_slug";
+CREATE UNIQUE INDEX "idx_categories_slug" ON "categories" ("slug") WHERE organization_id IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_categories_org_slug" ON "categories" ("organization_id","slug") WHERE organization_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS "idx_categories_organization_id" ON "categories" ("organization_id");
+
+-- Courses owned by an organization.
+ALTER TABLE "courses" ADD COLUMN IF NOT EXISTS "organization_id" uuid;
+ALTER TABLE "courses" ADD CONSTRAINT "fk_courses_organization" FOREIGN KEY ("organization_id") REFERENCES "organizations"("id") ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS "idx_courses_organization_id" ON "courses" ("organization_id");
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "teacher_application_documents";
+DROP TABLE IF EXISTS "teacher_applications";
@@ -0,0 +1,42 @@
+-- Applications from users who want to become teachers, reviewed by platform staff.
+-- The CV and credential files live in the private MinIO documents bucket.
+
+CREATE TABLE IF NOT EXISTS "teacher_applications" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "user_id" uuid NOT NULL,
+    "status" varchar(20) NOT NULL DEFAULT 'pending',
+    "headline" varchar(255) NOT NULL,
+    "bio" text NOT NULL,
+    "expertise" text[] NOT NULL DEFAULT '{}',
+    "years_of_experience" bigint NOT NULL DEFAULT 0,
+    "education" text,
+    "phone" varchar(20),
+    "website_url" varchar(500),
+    "reviewed_by" uuid,
+    "review_note" text,
+    "reviewed_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_teacher_applications_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE,
+    CONSTRAINT "chk_teacher_applications_status" CHECK (status IN ('pending', 'approved', 'rejected'))
+);
+CREATE INDEX IF NOT EXISTS "idx_teacher_applications_user_id" ON "teacher_applications" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_teacher_applications_status" ON "teacher_applications" ("status");
+-- At most one pending application per user
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_teacher_applications_user_pending" ON "teacher_applications" ("user_id") WHERE status = 'pending';
+
+CREATE TABLE IF NOT EXISTS "teacher_application_documents" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "application_id" uuid NOT NULL,
+    "doc_type" varchar(20) NOT NULL,
+    "file_name" varchar(255) NOT NULL,
+    "object_key" varchar(500) NOT NULL,
+    "content_type" varchar(100) NOT NULL,
+    "size_bytes" bigint NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_teacher_applications_documents" FOREIGN KEY ("application_id") REFERENCES "teacher_applications"("id") ON DELETE CASCADE,
+    CONSTRAINT "chk_teacher_application_documents_doc_type" CHECK (doc_type IN ('cv', 'credential'))
+);
+CREATE INDEX IF NOT EXISTS "idx_teacher_application_documents_application_id" ON "teacher_application_documents" ("application_id");
@@ -0,0 +1,8 @@
+ALTER TABLE "orders" DROP CONSTRAINT IF EXISTS "chk_orders_parent_approval_status";
+ALTER TABLE "orders" DROP COLUMN IF EXISTS "parent_approval_note";
+ALTER TABLE "orders" DROP COLUMN IF EXISTS "parent_approved_at";
+ALTER TABLE "orders" DROP COLUMN IF EXISTS "parent_approved_by";
+ALTER TABLE "orders" DROP COLUMN IF EXISTS "parent_approval_status";
+
+DROP TABLE IF EXISTS "age_verifications" CASCADE;
+DROP TABLE IF EXISTS "parent_student_relations" CASCADE;
@@ -0,0 +1,56 @@
+-- Parent accounts linked to students. A link becomes active once the student (or the
+-- parent, with the code the student read out) confirms the OTP sent to the student.
+
+CREATE TABLE IF NOT EXISTS "parent_student_relations" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "parent_user_id" uuid NOT NULL,
+    "student_user_id" uuid NOT NULL,
+    "relationship" varchar(20) NOT NULL DEFAULT 'parent',
+    "status" varchar(20) NOT NULL DEFAULT 'pending',
+    "can_view_progress" boolean NOT NULL DEFAULT true,
+    "can_view_grades" boolean NOT NULL DEFAULT true,
+    "can_approve_purchases" boolean NOT NULL DEFAULT true,
+    "confirmed_at" timestamptz,
+    "confirmed_by" varchar(20),
+    "revoked_at" timestamptz,
+    "revoked_by" uuid,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_parent_student_relations_parent" FOREIGN KEY ("parent_user_id") REFERENCES "users"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_parent_student_relations_student" FOREIGN KEY ("student_user_id") REFERENCES "users"("id") ON DELETE CASCADE,
+    CONSTRAINT "chk_parent_student_relations_relationship" CHECK (relationship IN ('parent', 'guardian', 'grandparent', 'sibling')),
+    CONSTRAINT "chk_parent_student_relations_status" CHECK (status IN ('pending', 'active', 'revoked')),
+    CONSTRAINT "chk_parent_student_relations_confirmed_by" CHECK (confirmed_by IN ('student', 'parent'))
+);
+CREATE INDEX IF NOT EXISTS "idx_parent_student_relations_parent_user_id" ON "parent_student_relations" ("parent_user_id");
+CREATE INDEX IF NOT EXISTS "idx_parent_student_relations_student_user_id" ON "parent_student_relations" ("student_user_id");
+CREATE INDEX IF NOT EXISTS "idx_parent_student_relations_status" ON "parent_student_relations" ("status");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_parent_student" ON "parent_student_relations" ("parent_user_id","student_user_id");
+
+-- Consent records for students under the minor age threshold
+CREATE TABLE IF NOT EXISTS "age_verifications" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "user_id" uuid NOT NULL,
+    "verification_method" varchar(30) NOT NULL,
+    "status" varchar(20) NOT NULL DEFAULT 'pending',
+    "parent_user_id" uuid,
+    "parent_consent_at" timestamptz,
+    "verified_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_age_verifications_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_age_verifications_parent" FOREIGN KEY ("parent_user_id") REFERENCES "users"("id") ON DELETE SET NULL,
+    CONSTRAINT "chk_age_verifications_verification_method" CHECK (verification_method IN ('document', 'parent_consent', 'school_verification', 'self_declaration')),
+    CONSTRAINT "chk_age_verifications_status" CHECK (status IN ('pending', 'reviewing', 'approved', 'rejected', 'expired'))
+);
+CREATE INDEX IF NOT EXISTS "idx_age_verifications_user_id" ON "age_verifications" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_age_verifications_parent_user_id" ON "age_verifications" ("parent_user_id");
+
+-- Purchases by minors wait for a linked parent to approve them
+ALTER TABLE "orders" ADD COLUMN IF NOT EXISTS "parent_approval_status" varchar(20) NOT NULL DEFAULT 'not_required';
+ALTER TABLE "orders" ADD COLUMN IF NOT EXISTS "parent_approved_by" uuid;
+ALTER TABLE "orders" ADD COLUMN IF NOT EXISTS "parent_approved_at" timestamptz;
+ALTER TABLE "orders" ADD COLUMN IF NOT EXISTS "parent_approval_note" text;
+ALTER TABLE "orders" ADD CONSTRAINT "chk_orders_parent_approval_status" CHECK (parent_approval_status IN ('not_required', 'pending', 'approved', 'rejected'));
@@ -0,0 +1,18 @@
+-- Review workflow for courses: every status transition (submit, approve, reject,
+-- archive...) is kept here together with the reviewer's comment.
+
+CREATE TABLE IF NOT EXISTS "course_status_changes" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "course_id" uuid NOT NULL,
+    "actor_id" uuid,
+    "action" varchar(20) NOT NULL,
+    "from_status" varchar(20) NOT NULL,
+    "to_status" varchar(20) NOT NULL,
+    "comment" text,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_course_status_changes_course" FOREIGN KEY ("course_id") REFERENCES "courses"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_course_status_changes_actor" FOREIGN KEY ("actor_id") REFERENCES "users"("id") ON DELETE SET NULL,
+    CONSTRAINT "chk_course_status_changes_action" CHECK (action IN ('submit', 'withdraw', 'approve', 'reject', 'archive', 'restore'))
+);
+CREATE INDEX IF NOT EXISTS "idx_course_status_changes_course_id" ON "course_status_changes" ("course_id");
@@ -0,0 +1,18 @@
+DROP TRIGGER IF EXISTS course_search_lesson ON lessons;
+DROP TRIGGER IF EXISTS course_search_section ON sections;
+DROP TRIGGER IF EXISTS course_search_tag ON tags;
+DROP TRIGGER IF EXISTS course_search_course_tag ON course_tags;
+DROP TRIGGER IF EXISTS course_search_course ON courses;
+
+DROP FUNCTION IF EXISTS course_search_on_lesson();
+DROP FUNCTION IF EXISTS course_search_on_section();
+DROP FUNCTION IF EXISTS course_search_on_tag();
+DROP FUNCTION IF EXISTS course_search_on_course_tag();
+DROP FUNCTION IF EXISTS course_search_on_course();
+DROP FUNCTION IF EXISTS refresh_course_search_document(uuid);
+
+DROP TABLE IF EXISTS "course_search_documents" CASCADE;
+DROP TEXT SEARCH CONFIGURATION IF EXISTS vietnamese_unaccent;
+DROP FUNCTION IF EXISTS immutable_unaccent(text);
+
+-- The unaccent and pg_trgm extensions are left installed, other objects may use them.
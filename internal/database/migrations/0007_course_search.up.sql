@@ -0,0 +1,165 @@
+-- Full-text search over the catalog. Vietnamese has no stemmer in Postgres, so words are
+-- only lowercased and stripped of diacritics: "lap trinh" and "Lập trình" give the same
+-- lexemes. pg_trgm backs the typo tolerant fallback and the suggestions.
+--
+-- course_search_documents holds one row per course and is kept up to date by the triggers
+-- below, whatever code path changes a course, its tags or its lessons.
+
+CREATE EXTENSION IF NOT EXISTS unaccent;
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- unaccent() is only STABLE because its dictionary can be swapped; pinning the dictionary
+-- makes it usable in indexes. Input is NFC normalized first so decomposed diacritics are
+-- removed as well.
+CREATE OR REPLACE FUNCTION immutable_unaccent(value text) RETURNS text
+    LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT
+    AS $$ SELECT public.unaccent('public.unaccent'::regdictionary, normalize(value, NFC)) $$;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = 'vietnamese_unaccent') THEN
+        CREATE TEXT SEARCH CONFIGURATION vietnamese_unaccent (COPY = simple);
+        ALTER TEXT SEARCH CONFIGURATION vietnamese_unaccent
+            ALTER MAPPING FOR hword, hword_part, word WITH unaccent, simple;
+    END IF;
+END
+$$;
+
+CREATE TABLE IF NOT EXISTS "course_search_documents" (
+    "course_id" uuid,
+    "document" tsvector NOT NULL,
+    "search_text" text NOT NULL,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("course_id"),
+    CONSTRAINT "fk_course_search_documents_course" FOREIGN KEY ("course_id") REFERENCES "courses"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_course_search_documents_document" ON "course_search_documents" USING gin ("document");
+CREATE INDEX IF NOT EXISTS "idx_course_search_documents_search_text" ON "course_search_documents" USING gin ("search_text" gin_trgm_ops);
+
+-- Weights: title A, tags B, short description and lesson titles C, description D.
+-- search_text is the unaccented title and tags, matched by trigram similarity.
+CREATE OR REPLACE FUNCTION refresh_course_search_document(target uuid) RETURNS void
+    LANGUAGE plpgsql AS $$
+BEGIN
+    IF target IS NULL THEN
+        RETURN;
+    END IF;
+
+    INSERT INTO course_search_documents (course_id, document, search_text, updated_at)
+    SELECT c.id,
+        setweight(to_tsvector('vietnamese_unaccent', normalize(c.title, NFC)), 'A') ||
+        setweight(to_tsvector('vietnamese_unaccent', normalize(coalesce(t.names, ''), NFC)), 'B') ||
+        setweight(to_tsvector('vietnamese_unaccent', normalize(concat_ws(' ', c.short_description, l.titles), NFC)), 'C') ||
+        setweight(to_tsvector('vietnamese_unaccent', normalize(coalesce(c.description, ''), NFC)), 'D'),
+        lower(immutable_unaccent(concat_ws(' ', c.title, t.names))),
+        now()
+    FROM courses c
+    LEFT JOIN LATERAL (
+        SELECT string_agg(tags.name, ' ') AS names
+        FROM course_tags
+        JOIN tags ON tags.id = course_tags.tag_id
+        WHERE course_tags.course_id = c.id
+    ) t ON true
+    LEFT JOIN LATERAL (
+        SELECT string_agg(lessons.title, ' ') AS titles
+        FROM sections
+        JOIN lessons ON lessons.section_id = sections.id
+        WHERE sections.course_id = c.id AND sections.deleted_at IS NULL
+    ) l ON true
+    WHERE c.id = target AND c.deleted_at IS NULL
+    ON CONFLICT (course_id) DO UPDATE
+        SET document = EXCLUDED.document, search_text = EXCLUDED.search_text, updated_at = EXCLUDED.updated_at;
+
+    IF NOT FOUND THEN
+        DELETE FROM course_search_documents WHERE course_id = target;
+    END IF;
+END
+$$;
+
+CREATE OR REPLACE FUNCTION course_search_on_course() RETURNS trigger
+    LANGUAGE plpgsql AS $$
+BEGIN
+    PERFORM refresh_course_search_document(NEW.id);
+    RETURN NULL;
+END
+$$;
+
+CREATE OR REPLACE FUNCTION course_search_on_course_tag() RETURNS trigger
+    LANGUAGE plpgsql AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        PERFORM refresh_course_search_document(OLD.course_id);
+    ELSE
+        PERFORM refresh_course_search_document(NEW.course_id);
+    END IF;
+    RETURN NULL;
+END
+$$;
+
+CREATE OR REPLACE FUNCTION course_search_on_tag() RETURNS trigger
+    LANGUAGE plpgsql AS $$
+BEGIN
+    PERFORM refresh_course_search_document(course_tags.course_id)
+    FROM course_tags
+    WHERE course_tags.tag_id = NEW.id;
+    RETURN NULL;
+END
+$$;
+
+CREATE OR REPLACE FUNCTION course_search_on_section() RETURNS trigger
+    LANGUAGE plpgsql AS $$
+BEGIN
+    PERFORM refresh_course_search_document(OLD.course_id);
+    IF TG_OP = 'UPDATE' AND NEW.course_id <> OLD.course_id THEN
+        PERFORM refresh_course_search_document(NEW.course_id);
+    END IF;
+    RETURN NULL;
+END
+$$;
+
+-- A lesson whose section was just deleted finds no course here; the section trigger
+-- refreshes that course instead.
+CREATE OR REPLACE FUNCTION course_search_on_lesson() RETURNS trigger
+    LANGUAGE plpgsql AS $$
+BEGIN
+    IF TG_OP <> 'INSERT' THEN
+        PERFORM refresh_course_search_document(sections.course_id)
+        FROM sections
+        WHERE sections.id = OLD.section_id;
+    END IF;
+    IF TG_OP = 'INSERT' OR (TG_OP = 'UPDATE' AND NEW.section_id <> OLD.section_id) THEN
+        PERFORM refresh_course_search_document(sections.course_id)
+        FROM sections
+        WHERE sections.id = NEW.section_id;
+    END IF;
+    RETURN NULL;
+END
+$$;
+
+DROP TRIGGER IF EXISTS course_search_course ON courses;
+CREATE TRIGGER course_search_course
+    AFTER INSERT OR UPDATE OF title, short_description, description, deleted_at ON courses
+    FOR EACH ROW EXECUTE FUNCTION course_search_on_course();
+
+DROP TRIGGER IF EXISTS course_search_course_tag ON course_tags;
+CREATE TRIGGER course_search_course_tag
+    AFTER INSERT OR DELETE ON course_tags
+    FOR EACH ROW EXECUTE FUNCTION course_search_on_course_tag();
+
+DROP TRIGGER IF EXISTS course_search_tag ON tags;
+CREATE TRIGGER course_search_tag
+    AFTER UPDATE OF name ON tags
+    FOR EACH ROW EXECUTE FUNCTION course_search_on_tag();
+
+DROP TRIGGER IF EXISTS course_search_section ON sections;
+CREATE TRIGGER course_search_section
+    AFTER UPDATE OF course_id, deleted_at OR DELETE ON sections
+    FOR EACH ROW EXECUTE FUNCTION course_search_on_section();
+
+DROP TRIGGER IF EXISTS course_search_lesson ON lessons;
+CREATE TRIGGER course_search_lesson
+    AFTER INSERT OR UPDATE OF title, section_id OR DELETE ON lessons
+    FOR EACH ROW EXECUTE FUNCTION course_search_on_lesson();
+
+-- Index the courses that already exist
+SELECT refresh_course_search_document(id) FROM courses WHERE deleted_at IS NULL;
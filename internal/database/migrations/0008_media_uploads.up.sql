@@ -0,0 +1,31 @@
+-- Direct uploads to MinIO: the server hands out presigned URLs and records what the client
+-- announced, then checks the stored object against it before attaching it to a lesson or
+-- a course.
+
+CREATE TABLE IF NOT EXISTS "media_uploads" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "course_id" uuid NOT NULL,
+    "lesson_id" uuid,
+    "uploader_id" uuid,
+    "kind" varchar(20) NOT NULL,
+    "status" varchar(20) NOT NULL,
+    "bucket" varchar(63) NOT NULL,
+    "object_key" varchar(500) NOT NULL,
+    "file_name" varchar(255) NOT NULL,
+    "content_type" varchar(100) NOT NULL,
+    "size_bytes" bigint NOT NULL,
+    "multipart_upload_id" varchar(255),
+    "part_size" bigint NOT NULL DEFAULT 0,
+    "expires_at" timestamptz NOT NULL,
+    "completed_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_media_uploads_course" FOREIGN KEY ("course_id") REFERENCES "courses"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_media_uploads_lesson" FOREIGN KEY ("lesson_id") REFERENCES "lessons"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_media_uploads_uploader" FOREIGN KEY ("uploader_id") REFERENCES "users"("id") ON DELETE SET NULL,
+    CONSTRAINT "chk_media_uploads_kind" CHECK (kind IN ('video', 'thumbnail', 'attachment')),
+    CONSTRAINT "chk_media_uploads_status" CHECK (status IN ('pending', 'completed', 'failed', 'aborted'))
+);
+CREATE INDEX IF NOT EXISTS "idx_media_uploads_course_id" ON "media_uploads" ("course_id");
+CREATE INDEX IF NOT EXISTS "idx_media_uploads_lesson_id" ON "media_uploads" ("lesson_id");
+CREATE INDEX IF NOT EXISTS "idx_media_uploads_status" ON "media_uploads" ("status");
@@ -0,0 +1,23 @@
+-- Background jobs, claimed by workers with SELECT ... FOR UPDATE SKIP LOCKED. A running job
+-- is leased until locked_until; a worker that dies stops renewing the lease and another
+-- worker picks the job back up.
+
+CREATE TABLE IF NOT EXISTS "jobs" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "type" varchar(50) NOT NULL,
+    "payload" jsonb NOT NULL DEFAULT '{}',
+    "status" varchar(20) NOT NULL DEFAULT 'queued',
+    "run_at" timestamptz NOT NULL,
+    "attempts" bigint NOT NULL DEFAULT 0,
+    "max_attempts" bigint NOT NULL DEFAULT 5,
+    "locked_by" varchar(100),
+    "locked_until" timestamptz,
+    "last_error" text,
+    "completed_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "chk_jobs_status" CHECK (status IN ('queued', 'running', 'succeeded', 'failed'))
+);
+CREATE INDEX IF NOT EXISTS "idx_jobs_status_run_at" ON "jobs" ("status","run_at");
+CREATE INDEX IF NOT EXISTS "idx_jobs_type" ON "jobs" ("type");
@@ -0,0 +1,30 @@
+-- Transcripts of lesson videos, written by the video_transcribe jobs. Each video gets a
+-- WebVTT caption track per language, stored in MinIO, and its time-coded segments so that
+-- students can search inside the lectures and jump to the moment something was said.
+
+CREATE TABLE IF NOT EXISTS "video_captions" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "lesson_video_id" uuid NOT NULL,
+    "language" varchar(10) NOT NULL,
+    "object_key" varchar(500) NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_video_captions_lesson_video" FOREIGN KEY ("lesson_video_id") REFERENCES "lesson_videos"("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_video_captions_video_language" ON "video_captions" ("lesson_video_id","language");
+
+CREATE TABLE IF NOT EXISTS "transcript_segments" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "lesson_video_id" uuid NOT NULL,
+    "start_ms" bigint NOT NULL,
+    "end_ms" bigint NOT NULL,
+    "text" text NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_transcript_segments_lesson_video" FOREIGN KEY ("lesson_video_id") REFERENCES "lesson_videos"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_transcript_segments_video_start" ON "transcript_segments" ("lesson_video_id","start_ms");
+
+-- Same text search configuration as the catalog (migration 0007). Queries must repeat this
+-- expression exactly for the index to be used, see repository.transcriptMatchSQL.
+CREATE INDEX IF NOT EXISTS "idx_transcript_segments_text" ON "transcript_segments"
+    USING gin (to_tsvector('vietnamese_unaccent', normalize("text", NFC)));
@@ -0,0 +1,7 @@
+DELETE FROM "media_uploads" WHERE kind = 'article_image';
+ALTER TABLE "media_uploads" DROP CONSTRAINT IF EXISTS "chk_media_uploads_kind";
+ALTER TABLE "media_uploads" ADD CONSTRAINT "chk_media_uploads_kind" CHECK (kind IN ('video', 'thumbnail', 'attachment'));
+
+DROP TABLE IF EXISTS "lesson_article_revisions" CASCADE;
+ALTER TABLE "lesson_articles" DROP COLUMN IF EXISTS "version";
+ALTER TABLE "lesson_articles" DROP COLUMN IF EXISTS "content_html";
@@ -0,0 +1,32 @@
+-- Markdown articles: the sanitized HTML rendered from the Markdown is stored next to it,
+-- and every save is kept as a numbered revision that authors can compare or restore.
+
+ALTER TABLE "lesson_articles" ADD COLUMN IF NOT EXISTS "content_html" text NOT NULL DEFAULT '';
+ALTER TABLE "lesson_articles" ADD COLUMN IF NOT EXISTS "version" bigint NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS "lesson_article_revisions" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "article_id" uuid NOT NULL,
+    "version" bigint NOT NULL,
+    "author_id" uuid,
+    "content" text NOT NULL,
+    "reading_time_minutes" bigint NOT NULL,
+    "restored_from" bigint,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_lesson_article_revisions_article" FOREIGN KEY ("article_id") REFERENCES "lesson_articles"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_lesson_article_revisions_author" FOREIGN KEY ("author_id") REFERENCES "users"("id") ON DELETE SET NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_lesson_article_revisions_article_version" ON "lesson_article_revisions" ("article_id","version");
+
+-- Existing articles become their version 1. Their HTML is rendered when they are read
+-- until the next save stores it.
+INSERT INTO "lesson_article_revisions" ("created_at", "article_id", "version", "content", "reading_time_minutes")
+SELECT coalesce("updated_at", "created_at", now()), "id", 1, "content", coalesce("reading_time_minutes", 5)
+FROM "lesson_articles"
+WHERE "version" = 0;
+UPDATE "lesson_articles" SET "version" = 1 WHERE "version" = 0;
+
+-- Images embedded in articles are uploaded like the other course media.
+ALTER TABLE "media_uploads" DROP CONSTRAINT IF EXISTS "chk_media_uploads_kind";
+ALTER TABLE "media_uploads" ADD CONSTRAINT "chk_media_uploads_kind" CHECK (kind IN ('video', 'thumbnail', 'attachment', 'article_image'));
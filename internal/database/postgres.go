@@ -9,7 +9,6 @@ import (
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 	"study.com/v1/internal/config"
-	"study.com/v1/internal/model"
 )
 
 func Connect(cfg *config.Config) (*gorm.DB, error) {
@@ -41,13 +40,6 @@ func Connect(cfg *config.Config) (*gorm.DB, error) {
 	return db, nil
 }
 
-func Migrate(db *gorm.DB) error {
-	return db.AutoMigrate(
-		&model.User{},
-		&model.UserPreference{},
-	)
-}
-
 func Close(db *gorm.DB) error {
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -0,0 +1,123 @@
+package database
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+	"study.com/v1/internal/model"
+)
+
+// SchemaMismatch is one difference between a GORM model and the migrated schema.
+type SchemaMismatch struct {
+	Table   string
+	Column  string
+	Problem string
+}
+
+func (m SchemaMismatch) String() string {
+	if m.Column == "" {
+		return fmt.Sprintf("%s: %s", m.Table, m.Problem)
+	}
+	return fmt.Sprintf("%s.%s: %s", m.Table, m.Column, m.Problem)
+}
+
+// VerifySchema compares every model in model.AllModels with the database and reports
+// missing tables, missing or extra columns, and columns whose type family differs.
+// An empty result means a new migration is not needed for the current models.
+func VerifySchema(db *gorm.DB) ([]SchemaMismatch, error) {
+	migrator := db.Migrator()
+	var mismatches []SchemaMismatch
+
+	for _, m := range model.AllModels() {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(m); err != nil {
+			return nil, fmt.Errorf("failed to parse model %T: %w", m, err)
+		}
+		table := stmt.Schema.Table
+
+		if !migrator.HasTable(table) {
+			mismatches = append(mismatches, SchemaMismatch{Table: table, Problem: "table is missing"})
+			continue
+		}
+
+		columnTypes, err := migrator.ColumnTypes(m)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read columns of %s: %w", table, err)
+		}
+		actual := make(map[string]gorm.ColumnType, len(columnTypes))
+		for _, ct := range columnTypes {
+			actual[ct.Name()] = ct
+		}
+
+		for _, name := range stmt.Schema.DBNames {
+			field := stmt.Schema.FieldsByDBName[name]
+			ct, ok := actual[name]
+			if !ok {
+				mismatches = append(mismatches, SchemaMismatch{Table: table, Column: name, Problem: "column is missing"})
+				continue
+			}
+			delete(actual, name)
+
+			want := typeFamily(migrator.FullDataTypeOf(field).SQL)
+			got := typeFamily(ct.DatabaseTypeName())
+			if want != "" && got != "" && want != got {
+				mismatches = append(mismatches, SchemaMismatch{
+					Table:   table,
+					Column:  name,
+					Problem: fmt.Sprintf("model type %s, database type %s", want, got),
+				})
+			}
+		}
+
+		extra := make([]string, 0, len(actual))
+		for name := range actual {
+			extra = append(extra, name)
+		}
+		sort.Strings(extra)
+		for _, name := range extra {
+			mismatches = append(mismatches, SchemaMismatch{Table: table, Column: name, Problem: "column is not in the model"})
+		}
+	}
+	return mismatches, nil
+}
+
+// typeFamily reduces a column type to a name comparable between GORM's DDL and
+// information_schema, e.g. "varchar(255) NOT NULL" and "varchar" both become "varchar".
+func typeFamily(sqlType string) string {
+	t := strings.ToLower(strings.TrimSpace(sqlType))
+	if i := strings.IndexAny(t, "( "); i >= 0 {
+		switch {
+		case strings.HasPrefix(t, "double precision"):
+			t = "double precision"
+		case strings.HasPrefix(t, "timestamp with time zone"):
+			t = "timestamptz"
+		case strings.HasPrefix(t, "timestamp without time zone"):
+			t = "timestamp"
+		case strings.HasPrefix(t, "character varying"):
+			t = "varchar"
+		default:
+			t = t[:i]
+		}
+	}
+	switch t {
+	case "boolean":
+		return "bool"
+	case "bigint", "bigserial":
+		return "int8"
+	case "integer", "serial", "int":
+		return "int4"
+	case "smallint", "smallserial":
+		return "int2"
+	case "double precision":
+		return "float8"
+	case "real":
+		return "float4"
+	case "decimal":
+		return "numeric"
+	case "character":
+		return "bpchar"
+	}
+	return t
+}
@@ -1,15 +1,19 @@
 package seeds
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"gorm.io/gorm"
 	"study.com/v1/internal/model"
+	"study.com/v1/internal/repository"
 )
 
 type PermissionSeed struct {
@@ -18,9 +22,98 @@ type PermissionSeed struct {
 }
 
 type RoleSeed struct {
-	Role        string   `json:"role"`
-	Description string   `json:"description"`
-	Permissions []string `json:"permissions"`
+	Role        string               `json:"role"`
+	Description string               `json:"description"`
+	Permissions []RolePermissionSeed `json:"permissions"`
+}
+
+// RolePermissionSeed is either a plain permission name, which is an unconditional grant,
+// or an object such as {"name": "COURSES_UPDATE_OWN", "conditions": {"own_resource_only": true}}
+// or {"name": "COURSES_DELETE_ORG", "granted": false} for an explicit deny.
+type RolePermissionSeed struct {
+	Name       string                     `json:"name"`
+	Granted    *bool                      `json:"granted"`
+	Conditions model.PermissionConditions `json:"conditions"`
+}
+
+func (p *RolePermissionSeed) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*p = RolePermissionSeed{Name: name}
+		return nil
+	}
+	type plain RolePermissionSeed
+	var seed plain
+	if err := json.Unmarshal(data, &seed); err != nil {
+		return err
+	}
+	if seed.Name == "" {
+		return fmt.Errorf("role permission entry %s has no name", string(data))
+	}
+	*p = RolePermissionSeed(seed)
+	return nil
+}
+
+func (p RolePermissionSeed) isGranted() bool {
+	return p.Granted == nil || *p.Granted
+}
+
+// Options controls how SeedAll applies the data files.
+type Options struct {
+	// DryRun only computes the plan and never writes.
+	DryRun bool
+	// Prune deletes permissions that are no longer listed in the permission files,
+	// together with their role links and user overrides.
+	Prune bool
+}
+
+// Plan is the difference between the data files and the database.
+type Plan struct {
+	CreatePermissions   []string
+	UpdatePermissions   []string
+	PrunePermissions    []string
+	UnlistedPermissions []string // not in the data files, kept because Prune is off
+	CreateRoles         []string
+	UpdateRoles         []string
+	RoleChanges         []RoleChange
+}
+
+// RoleChange lists the permission links of one role that will be added, removed,
+// or rewritten because is_granted or conditions differ.
+type RoleChange struct {
+	Role    string
+	Added   []string
+	Removed []string
+	Changed []string
+}
+
+func (p *Plan) Empty() bool {
+	return len(p.CreatePermissions) == 0 && len(p.UpdatePermissions) == 0 &&
+		len(p.PrunePermissions) == 0 && len(p.CreateRoles) == 0 &&
+		len(p.UpdateRoles) == 0 && len(p.RoleChanges) == 0
+}
+
+func (p *Plan) Print(w io.Writer) {
+	if p.Empty() {
+		fmt.Fprintln(w, "Database already matches the seed data")
+	}
+	printList(w, "+ permission", p.CreatePermissions)
+	printList(w, "~ permission", p.UpdatePermissions)
+	printList(w, "- permission", p.PrunePermissions)
+	printList(w, "? permission not in data files (use --prune to delete)", p.UnlistedPermissions)
+	printList(w, "+ role", p.CreateRoles)
+	printList(w, "~ role", p.UpdateRoles)
+	for _, c := range p.RoleChanges {
+		printList(w, "  "+c.Role+" +", c.Added)
+		printList(w, "  "+c.Role+" -", c.Removed)
+		printList(w, "  "+c.Role+" ~", c.Changed)
+	}
+}
+
+func printList(w io.Writer, prefix string, names []string) {
+	for _, n := range names {
+		fmt.Fprintf(w, "%s %s\n", prefix, n)
+	}
 }
 
 type Seeder struct {
@@ -31,128 +124,285 @@ func NewSeeder(db *gorm.DB) *Seeder {
 	return &Seeder{db: db}
 }
 
-func (s *Seeder) SeedPermissions(filePath string) error {
-	log.Println("Seeding permissions...")
+// SeedAll syncs data/permissions/*.json and data/roles.json into the database in a
+// single transaction and returns what was (or, with DryRun, would be) changed.
+func (s *Seeder) SeedAll(dataDir string, opts Options) (*Plan, error) {
+	permissions, roles, err := LoadData(dataDir)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := s.plan(permissions, roles, opts.Prune)
+	if err != nil {
+		return nil, err
+	}
+	if opts.DryRun || plan.Empty() {
+		return plan, nil
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := applyPermissions(tx, permissions, plan); err != nil {
+			return err
+		}
+		return applyRoles(tx, roles)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Println("All seeds completed successfully!")
+	return plan, nil
+}
 
-	data, err := os.ReadFile(filePath)
+// LoadData reads the seed files and rejects roles that reference a permission
+// which is not defined in any permission file.
+func LoadData(dataDir string) ([]PermissionSeed, []RoleSeed, error) {
+	permissionsDir := filepath.Join(dataDir, "permissions")
+	files, err := os.ReadDir(permissionsDir)
 	if err != nil {
-		return fmt.Errorf("failed to read permissions file: %w", err)
+		return nil, nil, fmt.Errorf("failed to read permissions directory: %w", err)
 	}
 
 	var permissions []PermissionSeed
-	if err := json.Unmarshal(data, &permissions); err != nil {
-		return fmt.Errorf("failed to parse permissions JSON: %w", err)
+	defined := map[string]string{}
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(permissionsDir, file.Name()))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read permissions file %s: %w", file.Name(), err)
+		}
+		var seeds []PermissionSeed
+		if err := json.Unmarshal(data, &seeds); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse permissions JSON %s: %w", file.Name(), err)
+		}
+		for _, p := range seeds {
+			if p.Name == "" {
+				return nil, nil, fmt.Errorf("permission without name in %s", file.Name())
+			}
+			if other, exists := defined[p.Name]; exists {
+				return nil, nil, fmt.Errorf("permission %s is defined in both %s and %s", p.Name, other, file.Name())
+			}
+			defined[p.Name] = file.Name()
+			permissions = append(permissions, p)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dataDir, "roles.json"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read roles file: %w", err)
+	}
+	var roles []RoleSeed
+	if err := json.Unmarshal(data, &roles); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse roles JSON: %w", err)
+	}
+
+	var unknown []string
+	for _, r := range roles {
+		for _, p := range r.Permissions {
+			if _, ok := defined[p.Name]; !ok && p.Name != model.PermissionWildcard {
+				unknown = append(unknown, fmt.Sprintf("%s (role %s)", p.Name, r.Role))
+			}
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, nil, fmt.Errorf("roles.json references unknown permissions: %s", strings.Join(unknown, ", "))
+	}
+
+	if rolesUseWildcard(roles) {
+		permissions = append(permissions, PermissionSeed{
+			Name:        model.PermissionWildcard,
+			Description: "Toàn quyền hệ thống",
+		})
+	}
+	return permissions, roles, nil
+}
+
+func (s *Seeder) plan(permissions []PermissionSeed, roles []RoleSeed, prune bool) (*Plan, error) {
+	plan := &Plan{}
+
+	var existingPermissions []model.Permission
+	if err := s.db.Find(&existingPermissions).Error; err != nil {
+		return nil, fmt.Errorf("failed to load permissions: %w", err)
+	}
+	current := make(map[string]model.Permission, len(existingPermissions))
+	for _, p := range existingPermissions {
+		current[p.Name] = p
 	}
 
+	wanted := make(map[string]struct{}, len(permissions))
 	for _, p := range permissions {
-		permission := model.Permission{
-			Name: p.Name,
+		wanted[p.Name] = struct{}{}
+		existing, ok := current[p.Name]
+		switch {
+		case !ok:
+			plan.CreatePermissions = append(plan.CreatePermissions, p.Name)
+		case existing.Description.String != p.Description:
+			plan.UpdatePermissions = append(plan.UpdatePermissions, p.Name)
 		}
-		permission.Description.String = p.Description
-		permission.Description.Valid = p.Description != ""
+	}
+	for name := range current {
+		if _, ok := wanted[name]; ok {
+			continue
+		}
+		if prune {
+			plan.PrunePermissions = append(plan.PrunePermissions, name)
+		} else {
+			plan.UnlistedPermissions = append(plan.UnlistedPermissions, name)
+		}
+	}
+	sort.Strings(plan.PrunePermissions)
+	sort.Strings(plan.UnlistedPermissions)
 
-		result := s.db.Where("name = ?", p.Name).FirstOrCreate(&permission)
-		if result.Error != nil {
-			return fmt.Errorf("failed to seed permission %s: %w", p.Name, result.Error)
+	var existingRoles []model.Role
+	if err := s.db.Preload("RolePermissions.Permission").Where("organization_id IS NULL").Find(&existingRoles).Error; err != nil {
+		return nil, fmt.Errorf("failed to load roles: %w", err)
+	}
+	currentRoles := make(map[string]model.Role, len(existingRoles))
+	for _, r := range existingRoles {
+		currentRoles[r.Name] = r
+	}
+
+	for _, r := range roles {
+		existing, ok := currentRoles[r.Role]
+		if !ok {
+			plan.CreateRoles = append(plan.CreateRoles, r.Role)
+		} else if existing.Description.String != r.Description {
+			plan.UpdateRoles = append(plan.UpdateRoles, r.Role)
 		}
+		if change := diffRolePermissions(r, existing.RolePermissions); change != nil {
+			plan.RoleChanges = append(plan.RoleChanges, *change)
+		}
+	}
+	return plan, nil
+}
 
-		if result.RowsAffected == 0 {
-			s.db.Model(&permission).Where("name = ?", p.Name).Update("description", p.Description)
+func diffRolePermissions(seed RoleSeed, current []model.RolePermission) *RoleChange {
+	change := RoleChange{Role: seed.Role}
+	have := make(map[string]model.RolePermission, len(current))
+	for _, rp := range current {
+		have[rp.Permission.Name] = rp
+	}
+
+	want := make(map[string]struct{}, len(seed.Permissions))
+	for _, p := range seed.Permissions {
+		want[p.Name] = struct{}{}
+		rp, ok := have[p.Name]
+		switch {
+		case !ok:
+			change.Added = append(change.Added, p.Name)
+		case rp.IsGranted != p.isGranted() || !sameConditions(rp.Conditions, p.Conditions):
+			change.Changed = append(change.Changed, p.Name)
 		}
 	}
+	for name := range have {
+		if _, ok := want[name]; !ok {
+			change.Removed = append(change.Removed, name)
+		}
+	}
+	sort.Strings(change.Removed)
 
-	log.Printf("Successfully seeded %d permissions\n", len(permissions))
-	return nil
+	if len(change.Added) == 0 && len(change.Removed) == 0 && len(change.Changed) == 0 {
+		return nil
+	}
+	return &change
 }
 
-func (s *Seeder) SeedRoles(filePath string) error {
-	log.Println("Seeding roles...")
+func sameConditions(a, b model.PermissionConditions) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(ja) == string(jb)
+}
 
-	data, err := os.ReadFile(filePath)
-	if err != nil {
-		return fmt.Errorf("failed to read roles file: %w", err)
+func applyPermissions(tx *gorm.DB, permissions []PermissionSeed, plan *Plan) error {
+	for _, p := range permissions {
+		permission := model.Permission{Name: p.Name}
+		permission.Description.String = p.Description
+		permission.Description.Valid = p.Description != ""
+
+		result := tx.Where("name = ?", p.Name).FirstOrCreate(&permission)
+		if result.Error != nil {
+			return fmt.Errorf("failed to seed permission %s: %w", p.Name, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			if err := tx.Model(&permission).Update("description", p.Description).Error; err != nil {
+				return fmt.Errorf("failed to update permission %s: %w", p.Name, err)
+			}
+		}
 	}
+	log.Printf("Seeded %d permissions\n", len(permissions))
 
-	var roles []RoleSeed
-	if err := json.Unmarshal(data, &roles); err != nil {
-		return fmt.Errorf("failed to parse roles JSON: %w", err)
+	if len(plan.PrunePermissions) == 0 {
+		return nil
+	}
+	stale := tx.Model(&model.Permission{}).Select("id").Where("name IN ?", plan.PrunePermissions)
+	if err := tx.Where("permission_id IN (?)", stale).Delete(&model.RolePermission{}).Error; err != nil {
+		return fmt.Errorf("failed to prune role permissions: %w", err)
 	}
+	if err := tx.Where("permission_id IN (?)", stale).Delete(&model.UserPermissionOverride{}).Error; err != nil {
+		return fmt.Errorf("failed to prune permission overrides: %w", err)
+	}
+	if err := tx.Where("name IN ?", plan.PrunePermissions).Delete(&model.Permission{}).Error; err != nil {
+		return fmt.Errorf("failed to prune permissions: %w", err)
+	}
+	log.Printf("Pruned %d permissions\n", len(plan.PrunePermissions))
+	return nil
+}
 
+func applyRoles(tx *gorm.DB, roles []RoleSeed) error {
 	var allPermissions []model.Permission
-	if err := s.db.Find(&allPermissions).Error; err != nil {
+	if err := tx.Find(&allPermissions).Error; err != nil {
 		return fmt.Errorf("failed to load permissions: %w", err)
 	}
-
-	permissionMap := make(map[string]model.Permission)
+	permissionMap := make(map[string]model.Permission, len(allPermissions))
 	for _, p := range allPermissions {
 		permissionMap[p.Name] = p
 	}
 
+	roleRepo := repository.NewRoleRepository(tx)
 	for _, r := range roles {
-		role := model.Role{
-			Name: r.Role,
-		}
+		role := model.Role{Name: r.Role}
 		role.Description.String = r.Description
 		role.Description.Valid = r.Description != ""
 
-		// Use FirstOrCreate to insert new role or get existing
-		result := s.db.Where("name = ?", r.Role).FirstOrCreate(&role)
-		if result.Error != nil {
-			return fmt.Errorf("failed to seed role %s: %w", r.Role, result.Error)
+		if err := tx.Where("name = ? AND organization_id IS NULL", r.Role).FirstOrCreate(&role).Error; err != nil {
+			return fmt.Errorf("failed to seed role %s: %w", r.Role, err)
 		}
-
-		// Always update description
-		if err := s.db.Model(&role).Update("description", r.Description).Error; err != nil {
+		if err := tx.Model(&role).Update("description", r.Description).Error; err != nil {
 			return fmt.Errorf("failed to update role %s: %w", r.Role, err)
 		}
 
-		// Refresh role to get updated data
-		s.db.Where("name = ?", r.Role).First(&role)
-
-		var rolePermissions []model.Permission
-		for _, permKey := range r.Permissions {
-			if perm, exists := permissionMap[permKey]; exists {
-				rolePermissions = append(rolePermissions, perm)
-			} else {
-				log.Printf("Warning: Permission %s not found for role %s\n", permKey, r.Role)
-			}
+		rolePermissions := make([]model.RolePermission, 0, len(r.Permissions))
+		for _, seed := range r.Permissions {
+			// LoadData has already rejected unknown names.
+			perm := permissionMap[seed.Name]
+			rolePermissions = append(rolePermissions, model.RolePermission{
+				RoleID:       role.ID,
+				PermissionID: perm.ID,
+				IsGranted:    seed.isGranted(),
+				Conditions:   seed.Conditions,
+			})
 		}
-
-		// Replace permissions for this role
-		if err := s.db.Model(&role).Association("Permissions").Replace(rolePermissions); err != nil {
+		if err := roleRepo.ReplacePermissions(context.Background(), role.ID, rolePermissions); err != nil {
 			return fmt.Errorf("failed to assign permissions to role %s: %w", r.Role, err)
 		}
 
 		log.Printf("Seeded role: %s with %d permissions\n", r.Role, len(rolePermissions))
 	}
-
-	log.Printf("Successfully seeded %d roles\n", len(roles))
 	return nil
 }
 
-func (s *Seeder) SeedAll(dataDir string) error {
-	// Seed all permission files from permissions folder
-	permissionsDir := filepath.Join(dataDir, "permissions")
-	files, err := ioutil.ReadDir(permissionsDir)
-	if err != nil {
-		return fmt.Errorf("failed to read permissions directory: %w", err)
-	}
-
-	for _, file := range files {
-		if filepath.Ext(file.Name()) == ".json" {
-			filePath := filepath.Join(permissionsDir, file.Name())
-			if err := s.SeedPermissions(filePath); err != nil {
-				return fmt.Errorf("failed to seed permissions from %s: %w", file.Name(), err)
+func rolesUseWildcard(roles []RoleSeed) bool {
+	for _, r := range roles {
+		for _, p := range r.Permissions {
+			if p.Name == model.PermissionWildcard {
+				return true
 			}
 		}
 	}
-
-	// Seed roles
-	rolesFilePath := filepath.Join(dataDir, "roles.json")
-	if err := s.SeedRoles(rolesFilePath); err != nil {
-		return err
-	}
-
-	log.Println("All seeds completed successfully!")
-	return nil
+	return false
 }
@@ -0,0 +1,60 @@
+package dto
+
+import "github.com/google/uuid"
+
+// SaveArticleDTO replaces the Markdown of an article lesson. BaseVersion is the version the
+// editor was opened on (0 for a new article): when set, the save is refused if someone
+// saved in between rather than silently overwriting their work.
+type SaveArticleDTO struct {
+	Content     string `json:"content"`
+	BaseVersion *int   `json:"base_version"`
+}
+
+// ArticleDTO is an article lesson. HTML is sanitized and ready to insert in the page, see
+// markdown.Document for the markup of code blocks and formulas. Content, the Markdown
+// source, is only returned to authors.
+type ArticleDTO struct {
+	LessonID           uuid.UUID `json:"lesson_id"`
+	Content            string    `json:"content,omitempty"`
+	HTML               string    `json:"html"`
+	ReadingTimeMinutes int       `json:"reading_time_minutes"`
+	Version            int       `json:"version"`
+	UpdatedAt          string    `json:"updated_at"`
+}
+
+// ArticleRevisionDTO is a saved version of an article. The list of revisions leaves out
+// Content and HTML, which come with a single revision.
+type ArticleRevisionDTO struct {
+	Version            int        `json:"version"`
+	AuthorID           *uuid.UUID `json:"author_id,omitempty"`
+	AuthorName         string     `json:"author_name,omitempty"`
+	ReadingTimeMinutes int        `json:"reading_time_minutes"`
+	RestoredFrom       *int       `json:"restored_from,omitempty"`
+	Content            string     `json:"content,omitempty"`
+	HTML               string     `json:"html,omitempty"`
+	CreatedAt          string     `json:"created_at"`
+}
+
+// ArticleDiffQueryDTO compares two versions, by default the current one with the one
+// before it.
+type ArticleDiffQueryDTO struct {
+	From int `query:"from"`
+	To   int `query:"to"`
+}
+
+// ArticleDiffLineDTO is a line of the Markdown: op is equal, insert or delete. OldLine and
+// NewLine number it in the From and To versions, when it is in them.
+type ArticleDiffLineDTO struct {
+	Op      string `json:"op"`
+	Text    string `json:"text"`
+	OldLine *int   `json:"old_line,omitempty"`
+	NewLine *int   `json:"new_line,omitempty"`
+}
+
+type ArticleDiffDTO struct {
+	From    int                  `json:"from"`
+	To      int                  `json:"to"`
+	Added   int                  `json:"added"`
+	Removed int                  `json:"removed"`
+	Lines   []ArticleDiffLineDTO `json:"lines"`
+}
@@ -58,17 +58,37 @@ type UserResponseDto struct {
 	CreatedAt   string    `json:"created_at"`
 }
 
+// LoginResponseDto carries the tokens only when they are delivered in the body, cookie
+// clients get the expiry times alone.
 type LoginResponseDto struct {
-	AccessToken  string          `json:"access_token"`
-	RefreshToken string          `json:"refresh_token"`
-	User         UserResponseDto `json:"user"`
+	AccessToken           string          `json:"access_token,omitempty"`
+	RefreshToken          string          `json:"refresh_token,omitempty"`
+	AccessTokenExpiresAt  string          `json:"access_token_expires_at"`
+	RefreshTokenExpiresAt string          `json:"refresh_token_expires_at"`
+	User                  UserResponseDto `json:"user"`
 }
 
 type RegisterResponseDto struct {
 	User UserResponseDto `json:"user"`
 }
 
-type RefreshTokenResponseDto struct {
-	AccessToken  string `json:"access_token"`
+type RefreshTokenRequestDto struct {
 	RefreshToken string `json:"refresh_token"`
 }
+
+type RefreshTokenResponseDto struct {
+	AccessToken           string `json:"access_token,omitempty"`
+	RefreshToken          string `json:"refresh_token,omitempty"`
+	AccessTokenExpiresAt  string `json:"access_token_expires_at"`
+	RefreshTokenExpiresAt string `json:"refresh_token_expires_at"`
+}
+
+type SessionResponseDto struct {
+	DeviceID   uuid.UUID `json:"device_id"`
+	DeviceName string    `json:"device_name"`
+	UserAgent  string    `json:"user_agent"`
+	IpAddress  string    `json:"ip"`
+	CreatedAt  string    `json:"created_at"`
+	LastSeenAt string    `json:"last_seen_at"`
+	IsCurrent  bool      `json:"is_current"`
+}
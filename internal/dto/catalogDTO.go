@@ -0,0 +1,141 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// CatalogQueryDTO holds the raw query string of GET /api/courses, it is validated by the service.
+type CatalogQueryDTO struct {
+	// Q searches titles, descriptions, tags and lesson titles, with or without diacritics
+	Q string `query:"q"`
+	// Category is the slug of a platform-wide category, CategoryID any category; both include subcategories
+	Category   string `query:"category"`
+	CategoryID string `query:"category_id"`
+	Tag        string `query:"tag"`
+	Level      string `query:"level"`
+	Language   string `query:"language"`
+	MinPrice   string `query:"min_price"`
+	MaxPrice   string `query:"max_price"`
+	IsFree     string `query:"is_free"`
+	MinRating  string `query:"min_rating"`
+	// Sort is relevance (default with q), popular (default without q), rating, newest,
+	// price_asc or price_desc
+	Sort   string `query:"sort"`
+	Cursor string `query:"cursor"`
+	Limit  int    `query:"limit" default:"20"`
+}
+
+type CatalogInstructorDTO struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	AvatarURL *string   `json:"avatar_url,omitempty"`
+	Bio       *string   `json:"bio,omitempty"`
+}
+
+type CatalogCategoryDTO struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	Slug string    `json:"slug"`
+}
+
+type CatalogTagDTO struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+type CatalogCourseDTO struct {
+	ID               uuid.UUID       `json:"id"`
+	Slug             string          `json:"slug"`
+	Title            string          `json:"title"`
+	ShortDescription *string         `json:"short_description,omitempty"`
+	ThumbnailURL     *string         `json:"thumbnail_url,omitempty"`
+	Level            string          `json:"level"`
+	Language         string          `json:"language"`
+	Price            decimal.Decimal `json:"price"`
+	// DiscountPrice is only present while the discount is running
+	DiscountPrice     *decimal.Decimal     `json:"discount_price,omitempty"`
+	DiscountExpiresAt *string              `json:"discount_expires_at,omitempty"`
+	IsFree            bool                 `json:"is_free"`
+	AverageRating     decimal.Decimal      `json:"average_rating"`
+	TotalReviews      int                  `json:"total_reviews"`
+	TotalStudents     int                  `json:"total_students"`
+	TotalLessons      int                  `json:"total_lessons"`
+	TotalDurationMins int                  `json:"total_duration_minutes"`
+	Instructor        CatalogInstructorDTO `json:"instructor"`
+	Category          *CatalogCategoryDTO  `json:"category,omitempty"`
+	PublishedAt       *string              `json:"published_at,omitempty"`
+}
+
+type CatalogLessonDTO struct {
+	ID           uuid.UUID `json:"id"`
+	Title        string    `json:"title"`
+	ContentType  string    `json:"content_type"`
+	DisplayOrder int       `json:"display_order"`
+	DurationMins int       `json:"duration_minutes"`
+	// IsPreview lessons can be watched without enrolling
+	IsPreview bool `json:"is_preview"`
+}
+
+type CatalogSectionDTO struct {
+	ID           uuid.UUID          `json:"id"`
+	Title        string             `json:"title"`
+	Description  *string            `json:"description,omitempty"`
+	DisplayOrder int                `json:"display_order"`
+	DurationMins int                `json:"duration_minutes"`
+	Lessons      []CatalogLessonDTO `json:"lessons"`
+}
+
+type CatalogCourseDetailDTO struct {
+	CatalogCourseDTO
+	Description     *string             `json:"description,omitempty"`
+	PreviewVideoURL *string             `json:"preview_video_url,omitempty"`
+	Requirements    []string            `json:"requirements"`
+	Objectives      []string            `json:"objectives"`
+	TargetAudience  []string            `json:"target_audience"`
+	Tags            []CatalogTagDTO     `json:"tags"`
+	TotalPreviews   int                 `json:"total_preview_lessons"`
+	Sections        []CatalogSectionDTO `json:"sections"`
+}
+
+type FacetCountDTO struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+type CategoryFacetDTO struct {
+	ID    uuid.UUID `json:"id"`
+	Name  string    `json:"name"`
+	Slug  string    `json:"slug"`
+	Count int64     `json:"count"`
+}
+
+type PriceFacetDTO struct {
+	Free int64 `json:"free"`
+	Paid int64 `json:"paid"`
+}
+
+type RatingFacetDTO struct {
+	MinRating string `json:"min_rating"`
+	Count     int64  `json:"count"`
+}
+
+// CatalogFacetsDTO counts the matching courses per filter value. Each dimension ignores its
+// own filter, so the counts show what switching to another value would return.
+type CatalogFacetsDTO struct {
+	Categories []CategoryFacetDTO `json:"categories"`
+	Levels     []FacetCountDTO    `json:"levels"`
+	Languages  []FacetCountDTO    `json:"languages"`
+	Price      PriceFacetDTO      `json:"price"`
+	Ratings    []RatingFacetDTO   `json:"ratings"`
+}
+
+type CatalogListResponseDTO struct {
+	Courses []CatalogCourseDTO `json:"courses"`
+	// Facets are only computed for the first page, when no cursor is sent
+	Facets     *CatalogFacetsDTO `json:"facets,omitempty"`
+	NextCursor *string           `json:"next_cursor,omitempty"`
+	HasMore    bool              `json:"has_more"`
+	// SearchMode is fuzzy when q matched no whole word and typo tolerant matching was used
+	SearchMode string `json:"search_mode,omitempty"`
+}
@@ -0,0 +1,35 @@
+package dto
+
+import "github.com/google/uuid"
+
+type CreateCategoryDTO struct {
+	Name string `json:"name" binding:"required,min=2,max=100"`
+	// Slug is generated from Name when empty
+	Slug         string     `json:"slug" binding:"omitempty,max=100"`
+	ParentID     *uuid.UUID `json:"parent_id"`
+	Description  *string    `json:"description"`
+	IconURL      *string    `json:"icon_url" binding:"omitempty,max=500"`
+	DisplayOrder int        `json:"display_order"`
+}
+
+type UpdateCategoryDTO struct {
+	Name         *string    `json:"name" binding:"omitempty,min=2,max=100"`
+	Slug         *string    `json:"slug" binding:"omitempty,max=100"`
+	ParentID     *uuid.UUID `json:"parent_id"`
+	Description  *string    `json:"description"`
+	IconURL      *string    `json:"icon_url" binding:"omitempty,max=500"`
+	DisplayOrder *int       `json:"display_order"`
+	IsActive     *bool      `json:"is_active"`
+}
+
+type CategoryResponseDTO struct {
+	ID             uuid.UUID  `json:"id"`
+	OrganizationID *uuid.UUID `json:"organization_id,omitempty"`
+	ParentID       *uuid.UUID `json:"parent_id,omitempty"`
+	Name           string     `json:"name"`
+	Slug           string     `json:"slug"`
+	Description    *string    `json:"description,omitempty"`
+	IconURL        *string    `json:"icon_url,omitempty"`
+	DisplayOrder   int        `json:"display_order"`
+	IsActive       bool       `json:"is_active"`
+}
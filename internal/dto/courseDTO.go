@@ -0,0 +1,174 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type CreateCourseDTO struct {
+	// OrganizationID makes it a course of that organization, NULL is a personal course
+	OrganizationID    *uuid.UUID       `json:"organization_id"`
+	CategoryID        *uuid.UUID       `json:"category_id"`
+	Title             string           `json:"title" binding:"required,min=5,max=255"`
+	Slug              string           `json:"slug" binding:"omitempty,max=100"`
+	ShortDescription  string           `json:"short_description" binding:"max=500"`
+	Description       string           `json:"description"`
+	ThumbnailURL      string           `json:"thumbnail_url" binding:"max=500"`
+	PreviewVideoURL   string           `json:"preview_video_url" binding:"max=500"`
+	Level             string           `json:"level" binding:"omitempty,oneof=beginner intermediate advanced all_levels"`
+	Language          string           `json:"language" binding:"omitempty,max=10"`
+	Price             decimal.Decimal  `json:"price"`
+	DiscountPrice     *decimal.Decimal `json:"discount_price"`
+	DiscountExpiresAt *time.Time       `json:"discount_expires_at"`
+	Requirements      []string         `json:"requirements"`
+	Objectives        []string         `json:"objectives"`
+	TargetAudience    []string         `json:"target_audience"`
+	// Tags are free text, unknown tags are created
+	Tags []string `json:"tags"`
+	// Sections are created together with the course, in the given order
+	Sections []CreateSectionDTO `json:"sections"`
+}
+
+type UpdateCourseDTO struct {
+	CategoryID        *uuid.UUID       `json:"category_id"`
+	Title             *string          `json:"title" binding:"omitempty,min=5,max=255"`
+	Slug              *string          `json:"slug" binding:"omitempty,max=100"`
+	ShortDescription  *string          `json:"short_description" binding:"omitempty,max=500"`
+	Description       *string          `json:"description"`
+	ThumbnailURL      *string          `json:"thumbnail_url" binding:"omitempty,max=500"`
+	PreviewVideoURL   *string          `json:"preview_video_url" binding:"omitempty,max=500"`
+	Level             *string          `json:"level" binding:"omitempty,oneof=beginner intermediate advanced all_levels"`
+	Language          *string          `json:"language" binding:"omitempty,max=10"`
+	Price             *decimal.Decimal `json:"price"`
+	DiscountPrice     *decimal.Decimal `json:"discount_price"`
+	DiscountExpiresAt *time.Time       `json:"discount_expires_at"`
+	Requirements      *[]string        `json:"requirements"`
+	Objectives        *[]string        `json:"objectives"`
+	TargetAudience    *[]string        `json:"target_audience"`
+	// Tags replaces all tags of the course when present
+	Tags *[]string `json:"tags"`
+}
+
+type CreateSectionDTO struct {
+	Title       string `json:"title" binding:"required,max=255"`
+	Description string `json:"description"`
+	// Lessons are created together with the section, in the given order
+	Lessons []CreateLessonDTO `json:"lessons"`
+}
+
+type UpdateSectionDTO struct {
+	Title       *string `json:"title" binding:"omitempty,max=255"`
+	Description *string `json:"description"`
+}
+
+type CreateLessonDTO struct {
+	Title        string `json:"title" binding:"required,max=255"`
+	Description  string `json:"description"`
+	ContentType  string `json:"content_type" binding:"required,oneof=video article quiz assignment"`
+	DurationMins int    `json:"duration_minutes" binding:"min=0"`
+	IsPreview    bool   `json:"is_preview"`
+	// IsMandatory defaults to true
+	IsMandatory *bool `json:"is_mandatory"`
+}
+
+type UpdateLessonDTO struct {
+	Title        *string `json:"title" binding:"omitempty,max=255"`
+	Description  *string `json:"description"`
+	ContentType  *string `json:"content_type" binding:"omitempty,oneof=video article quiz assignment"`
+	DurationMins *int    `json:"duration_minutes" binding:"omitempty,min=0"`
+	IsPreview    *bool   `json:"is_preview"`
+	IsMandatory  *bool   `json:"is_mandatory"`
+}
+
+// ReorderCourseDTO is the full outline of the course: every section in its new order, each
+// with every lesson it should hold in order. Lessons may move between sections.
+type ReorderCourseDTO struct {
+	Sections []ReorderSectionDTO `json:"sections" binding:"required"`
+}
+
+type ReorderSectionDTO struct {
+	ID        uuid.UUID   `json:"id" binding:"required"`
+	LessonIDs []uuid.UUID `json:"lesson_ids"`
+}
+
+type CourseQueryDTO struct {
+	Status   string `query:"status"`
+	Search   string `query:"search"`
+	Page     int    `query:"page" default:"1"`
+	PageSize int    `query:"page_size" default:"20"`
+}
+
+type LessonResponseDTO struct {
+	ID           uuid.UUID `json:"id"`
+	SectionID    uuid.UUID `json:"section_id"`
+	Title        string    `json:"title"`
+	Description  *string   `json:"description,omitempty"`
+	ContentType  string    `json:"content_type"`
+	DisplayOrder int       `json:"display_order"`
+	DurationMins int       `json:"duration_minutes"`
+	IsPreview    bool      `json:"is_preview"`
+	IsMandatory  bool      `json:"is_mandatory"`
+}
+
+type SectionResponseDTO struct {
+	ID           uuid.UUID           `json:"id"`
+	Title        string              `json:"title"`
+	Description  *string             `json:"description,omitempty"`
+	DisplayOrder int                 `json:"display_order"`
+	Lessons      []LessonResponseDTO `json:"lessons"`
+}
+
+type CourseResponseDTO struct {
+	ID                uuid.UUID            `json:"id"`
+	InstructorID      uuid.UUID            `json:"instructor_id"`
+	OrganizationID    *uuid.UUID           `json:"organization_id,omitempty"`
+	CategoryID        *uuid.UUID           `json:"category_id,omitempty"`
+	Title             string               `json:"title"`
+	Slug              string               `json:"slug"`
+	ShortDescription  *string              `json:"short_description,omitempty"`
+	Description       *string              `json:"description,omitempty"`
+	ThumbnailURL      *string              `json:"thumbnail_url,omitempty"`
+	PreviewVideoURL   *string              `json:"preview_video_url,omitempty"`
+	Level             string               `json:"level"`
+	Language          string               `json:"language"`
+	Price             decimal.Decimal      `json:"price"`
+	DiscountPrice     *decimal.Decimal     `json:"discount_price,omitempty"`
+	DiscountExpiresAt *string              `json:"discount_expires_at,omitempty"`
+	IsFree            bool                 `json:"is_free"`
+	TotalLessons      int                  `json:"total_lessons"`
+	TotalDurationMins int                  `json:"total_duration_minutes"`
+	Requirements      []string             `json:"requirements"`
+	Objectives        []string             `json:"objectives"`
+	TargetAudience    []string             `json:"target_audience"`
+	Tags              []string             `json:"tags,omitempty"`
+	Status            string               `json:"status"`
+	PublishedAt       *string              `json:"published_at,omitempty"`
+	Sections          []SectionResponseDTO `json:"sections,omitempty"`
+	CreatedAt         string               `json:"created_at"`
+	UpdatedAt         string               `json:"updated_at"`
+}
+
+type CourseListResponseDTO struct {
+	Courses  []CourseResponseDTO `json:"courses"`
+	Total    int64               `json:"total"`
+	Page     int                 `json:"page"`
+	PageSize int                 `json:"page_size"`
+}
+
+type CourseTransitionDTO struct {
+	// Comment is required when rejecting, it is sent to the instructor
+	Comment string `json:"comment" binding:"max=2000"`
+}
+
+type CourseStatusChangeDTO struct {
+	ID         uuid.UUID  `json:"id"`
+	Action     string     `json:"action"`
+	FromStatus string     `json:"from_status"`
+	ToStatus   string     `json:"to_status"`
+	Comment    *string    `json:"comment,omitempty"`
+	ActorID    *uuid.UUID `json:"actor_id,omitempty"`
+	ActorName  string     `json:"actor_name,omitempty"`
+	CreatedAt  string     `json:"created_at"`
+}
@@ -0,0 +1,80 @@
+package dto
+
+import "github.com/google/uuid"
+
+// Video delivery formats of PlaybackDTO
+const (
+	PlaybackTypeHLS  = "hls"  // URL is a master playlist, play it with hls.js or natively
+	PlaybackTypeFile = "file" // the video has not been transcoded yet, URL is the source file
+)
+
+// WatermarkDTO is drawn over the player so a screen recording can be traced back to the
+// account and the playback it came from.
+type WatermarkDTO struct {
+	Text      string    `json:"text"`
+	UserID    uuid.UUID `json:"user_id"`
+	SessionID string    `json:"session_id"`
+	IssuedAt  string    `json:"issued_at"`
+}
+
+type PlaybackDTO struct {
+	LessonID        uuid.UUID    `json:"lesson_id"`
+	Type            string       `json:"type"`
+	URL             string       `json:"url"`
+	ExpiresAt       string       `json:"expires_at"`
+	DurationSeconds int          `json:"duration_seconds"`
+	Resolution      *string      `json:"resolution,omitempty"`
+	ThumbnailURL    *string      `json:"thumbnail_url,omitempty"`
+	Watermark       WatermarkDTO `json:"watermark"`
+	// Captions expire with the playback
+	Captions []CaptionTrackDTO `json:"captions"`
+	// Token authenticates the playlist URLs, which players fetch without credentials
+	Token string `json:"-"`
+}
+
+type AttachmentDownloadDTO struct {
+	ID        uuid.UUID `json:"id"`
+	FileName  string    `json:"file_name"`
+	URL       string    `json:"url"`
+	ExpiresAt string    `json:"expires_at"`
+}
+
+// CaptionTrackDTO is a WebVTT subtitle file, for a <track kind="subtitles"> element.
+type CaptionTrackDTO struct {
+	Language string `json:"language"`
+	URL      string `json:"url"`
+}
+
+type TranscriptSegmentDTO struct {
+	StartSeconds float64 `json:"start_seconds"`
+	EndSeconds   float64 `json:"end_seconds"`
+	Text         string  `json:"text"`
+}
+
+// TranscriptDTO is the transcript of a lesson video. Segments is empty until Status is
+// completed.
+type TranscriptDTO struct {
+	LessonID uuid.UUID              `json:"lesson_id"`
+	Status   string                 `json:"status"`
+	Segments []TranscriptSegmentDTO `json:"segments"`
+}
+
+type TranscriptSearchQueryDTO struct {
+	Q     string `query:"q"`
+	Limit int    `query:"limit"`
+}
+
+// TranscriptSearchHitDTO is a moment of a lecture matching the search, the player seeks
+// to StartSeconds.
+type TranscriptSearchHitDTO struct {
+	LessonID     uuid.UUID `json:"lesson_id"`
+	LessonTitle  string    `json:"lesson_title"`
+	StartSeconds float64   `json:"start_seconds"`
+	EndSeconds   float64   `json:"end_seconds"`
+	Text         string    `json:"text"`
+}
+
+type TranscriptSearchDTO struct {
+	Query string                   `json:"query"`
+	Hits  []TranscriptSearchHitDTO `json:"hits"`
+}
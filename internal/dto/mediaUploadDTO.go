@@ -0,0 +1,50 @@
+package dto
+
+import "github.com/google/uuid"
+
+type CreateMediaUploadDTO struct {
+	// Kind is video, attachment or article_image (all need lesson_id) or thumbnail (the
+	// course cover)
+	Kind        string     `json:"kind" binding:"required,oneof=video thumbnail attachment article_image"`
+	LessonID    *uuid.UUID `json:"lesson_id"`
+	FileName    string     `json:"file_name" binding:"required,max=255"`
+	ContentType string     `json:"content_type" binding:"required"`
+	SizeBytes   int64      `json:"size_bytes" binding:"required,min=1"`
+}
+
+type UploadPartDTO struct {
+	PartNumber int    `json:"part_number"`
+	URL        string `json:"url"`
+}
+
+// MediaUploadSessionDTO tells the client where to send the file. Small files get one URL
+// to PUT the whole file to, large ones are cut into part_size chunks (the last one
+// shorter), each PUT to the URL of its part number. Headers must be sent as given.
+// POST .../complete once every byte is uploaded.
+type MediaUploadSessionDTO struct {
+	ID        uuid.UUID         `json:"id"`
+	Kind      string            `json:"kind"`
+	Method    string            `json:"method"`
+	URL       string            `json:"url,omitempty"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	PartSize  int64             `json:"part_size,omitempty"`
+	Parts     []UploadPartDTO   `json:"parts,omitempty"`
+	ExpiresAt string            `json:"expires_at"`
+}
+
+type MediaUploadResponseDTO struct {
+	ID          uuid.UUID  `json:"id"`
+	CourseID    uuid.UUID  `json:"course_id"`
+	LessonID    *uuid.UUID `json:"lesson_id,omitempty"`
+	Kind        string     `json:"kind"`
+	Status      string     `json:"status"`
+	FileName    string     `json:"file_name"`
+	ContentType string     `json:"content_type"`
+	SizeBytes   int64      `json:"size_bytes"`
+	CompletedAt *string    `json:"completed_at,omitempty"`
+	// What the upload was attached to, depending on the kind
+	VideoID      *uuid.UUID `json:"video_id,omitempty"`
+	AttachmentID *uuid.UUID `json:"attachment_id,omitempty"`
+	ThumbnailURL *string    `json:"thumbnail_url,omitempty"`
+	ImageURL     *string    `json:"image_url,omitempty"`
+}
@@ -0,0 +1,118 @@
+package dto
+
+import "github.com/google/uuid"
+
+type CreateOrganizationDTO struct {
+	Name string `json:"name" binding:"required,min=2,max=255"`
+	// Slug is generated from Name when empty
+	Slug        string  `json:"slug" binding:"omitempty,max=100"`
+	Description *string `json:"description"`
+	OrgType     string  `json:"org_type" binding:"omitempty,oneof=school center company individual"`
+	LogoURL     *string `json:"logo_url" binding:"omitempty,max=500"`
+	Website     *string `json:"website" binding:"omitempty,max=255"`
+	Email       *string `json:"email" binding:"omitempty,email"`
+	Phone       *string `json:"phone" binding:"omitempty,max=20"`
+	Address     *string `json:"address"`
+	City        *string `json:"city" binding:"omitempty,max=100"`
+	Country     string  `json:"country" binding:"omitempty,max=100"`
+}
+
+type UpdateOrganizationDTO struct {
+	Name        *string `json:"name" binding:"omitempty,min=2,max=255"`
+	Description *string `json:"description"`
+	OrgType     *string `json:"org_type" binding:"omitempty,oneof=school center company individual"`
+	LogoURL     *string `json:"logo_url" binding:"omitempty,max=500"`
+	Website     *string `json:"website" binding:"omitempty,max=255"`
+	Email       *string `json:"email" binding:"omitempty,email"`
+	Phone       *string `json:"phone" binding:"omitempty,max=20"`
+	Address     *string `json:"address"`
+	City        *string `json:"city" binding:"omitempty,max=100"`
+	Country     *string `json:"country" binding:"omitempty,max=100"`
+}
+
+type OrganizationResponseDTO struct {
+	ID          uuid.UUID `json:"id"`
+	OwnerID     uuid.UUID `json:"owner_id"`
+	Name        string    `json:"name"`
+	Slug        string    `json:"slug"`
+	Description *string   `json:"description,omitempty"`
+	OrgType     string    `json:"org_type"`
+	LogoURL     *string   `json:"logo_url,omitempty"`
+	Website     *string   `json:"website,omitempty"`
+	Email       *string   `json:"email,omitempty"`
+	Phone       *string   `json:"phone,omitempty"`
+	Address     *string   `json:"address,omitempty"`
+	City        *string   `json:"city,omitempty"`
+	Country     string    `json:"country"`
+	MaxMembers  int       `json:"max_members"`
+	MaxCourses  int       `json:"max_courses"`
+	IsVerified  bool      `json:"is_verified"`
+	IsActive    bool      `json:"is_active"`
+	CreatedAt   string    `json:"created_at"`
+	UpdatedAt   string    `json:"updated_at"`
+}
+
+type OrganizationListResponseDTO struct {
+	Organizations []OrganizationResponseDTO `json:"organizations"`
+	Total         int64                     `json:"total"`
+	Page          int                       `json:"page"`
+	PageSize      int                       `json:"page_size"`
+}
+
+type OrganizationMemberQueryDTO struct {
+	Search     string `query:"search"`
+	MemberRole string `query:"member_role"`
+	Status     string `query:"status"`
+	Page       int    `query:"page" default:"1"`
+	PageSize   int    `query:"page_size" default:"20"`
+}
+
+type UpdateOrganizationMemberDTO struct {
+	MemberRole *string `json:"member_role" binding:"omitempty,oneof=admin manager teacher ta student member"`
+	Status     *string `json:"status" binding:"omitempty,oneof=active suspended"`
+	Department *string `json:"department" binding:"omitempty,max=100"`
+	StudentID  *string `json:"student_id" binding:"omitempty,max=50"`
+	// RoleIDs replaces the roles the member holds inside the organization when set
+	RoleIDs *[]uuid.UUID `json:"role_ids"`
+}
+
+type OrganizationMemberResponseDTO struct {
+	ID         uuid.UUID         `json:"id"`
+	UserID     uuid.UUID         `json:"user_id"`
+	Username   string            `json:"username"`
+	Email      string            `json:"email"`
+	FullName   *string           `json:"full_name,omitempty"`
+	MemberRole string            `json:"member_role"`
+	Department *string           `json:"department,omitempty"`
+	StudentID  *string           `json:"student_id,omitempty"`
+	Status     string            `json:"status"`
+	Roles      []RoleResponseDTO `json:"roles"`
+	JoinedAt   string            `json:"joined_at"`
+}
+
+type OrganizationMemberListResponseDTO struct {
+	Members  []OrganizationMemberResponseDTO `json:"members"`
+	Total    int64                           `json:"total"`
+	Page     int                             `json:"page"`
+	PageSize int                             `json:"page_size"`
+}
+
+type CreateOrganizationInvitationDTO struct {
+	Email      string      `json:"email" binding:"required,email"`
+	MemberRole string      `json:"member_role" binding:"omitempty,oneof=admin manager teacher ta student member"`
+	RoleIDs    []uuid.UUID `json:"role_ids"`
+}
+
+type AcceptOrganizationInvitationDTO struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type OrganizationInvitationResponseDTO struct {
+	ID         uuid.UUID   `json:"id"`
+	Email      string      `json:"email"`
+	MemberRole string      `json:"member_role"`
+	RoleIDs    []uuid.UUID `json:"role_ids"`
+	InvitedBy  uuid.UUID   `json:"invited_by"`
+	ExpiresAt  string      `json:"expires_at"`
+	CreatedAt  string      `json:"created_at"`
+}
@@ -0,0 +1,118 @@
+package dto
+
+import "github.com/google/uuid"
+
+type CreateParentLinkDTO struct {
+	// StudentEmail is the email of the student account to link, the OTP is sent there
+	StudentEmail string `json:"student_email" binding:"required,email"`
+	Relationship string `json:"relationship" binding:"omitempty,oneof=parent guardian grandparent sibling"`
+}
+
+type ConfirmParentLinkDTO struct {
+	OTP string `json:"otp" binding:"required,len=6"`
+}
+
+// UpdateParentLinkDTO lets an adult student limit what a linked parent can see.
+type UpdateParentLinkDTO struct {
+	CanViewProgress     *bool `json:"can_view_progress"`
+	CanViewGrades       *bool `json:"can_view_grades"`
+	CanApprovePurchases *bool `json:"can_approve_purchases"`
+}
+
+type ParentApprovalDTO struct {
+	Note string `json:"note" binding:"max=1000"`
+}
+
+type LinkedUserDTO struct {
+	ID       uuid.UUID `json:"id"`
+	Username string    `json:"username"`
+	Email    string    `json:"email"`
+	FullName *string   `json:"full_name,omitempty"`
+}
+
+type ParentLinkResponseDTO struct {
+	ID                  uuid.UUID     `json:"id"`
+	Parent              LinkedUserDTO `json:"parent"`
+	Student             LinkedUserDTO `json:"student"`
+	Relationship        string        `json:"relationship"`
+	Status              string        `json:"status"`
+	CanViewProgress     bool          `json:"can_view_progress"`
+	CanViewGrades       bool          `json:"can_view_grades"`
+	CanApprovePurchases bool          `json:"can_approve_purchases"`
+	ConfirmedAt         *string       `json:"confirmed_at,omitempty"`
+	ConfirmedBy         *string       `json:"confirmed_by,omitempty"`
+	CreatedAt           string        `json:"created_at"`
+}
+
+// GuardianStatusDTO tells a student whether they still need a linked parent.
+type GuardianStatusDTO struct {
+	IsMinor           bool  `json:"is_minor"`
+	MinorAgeThreshold int   `json:"minor_age_threshold"`
+	ActiveParents     int64 `json:"active_parents"`
+	ParentRequired    bool  `json:"parent_required"`
+}
+
+type ChildEnrollmentDTO struct {
+	ID                 uuid.UUID `json:"id"`
+	CourseID           uuid.UUID `json:"course_id"`
+	CourseTitle        string    `json:"course_title"`
+	ProgressPercentage string    `json:"progress_percentage"`
+	EnrolledAt         string    `json:"enrolled_at"`
+	LastAccessedAt     *string   `json:"last_accessed_at,omitempty"`
+	CompletedAt        *string   `json:"completed_at,omitempty"`
+}
+
+type ChildQuizAttemptDTO struct {
+	ID            uuid.UUID `json:"id"`
+	QuizID        uuid.UUID `json:"quiz_id"`
+	QuizTitle     string    `json:"quiz_title"`
+	Score         *string   `json:"score,omitempty"`
+	TotalPoints   *string   `json:"total_points,omitempty"`
+	Percentage    *string   `json:"percentage,omitempty"`
+	IsPassed      *bool     `json:"is_passed,omitempty"`
+	TimeSpentSecs *int      `json:"time_spent_seconds,omitempty"`
+	StartedAt     string    `json:"started_at"`
+	CompletedAt   *string   `json:"completed_at,omitempty"`
+}
+
+type ChildOrderItemDTO struct {
+	CourseID    uuid.UUID `json:"course_id"`
+	CourseTitle string    `json:"course_title"`
+	Price       string    `json:"price"`
+	FinalPrice  string    `json:"final_price"`
+}
+
+type ChildOrderDTO struct {
+	ID                   uuid.UUID           `json:"id"`
+	OrderNumber          string              `json:"order_number"`
+	Status               string              `json:"status"`
+	TotalAmount          string              `json:"total_amount"`
+	Currency             string              `json:"currency"`
+	PaidAt               *string             `json:"paid_at,omitempty"`
+	ParentApprovalStatus string              `json:"parent_approval_status"`
+	ParentApprovedBy     *uuid.UUID          `json:"parent_approved_by,omitempty"`
+	ParentApprovedAt     *string             `json:"parent_approved_at,omitempty"`
+	ParentApprovalNote   *string             `json:"parent_approval_note,omitempty"`
+	Items                []ChildOrderItemDTO `json:"items"`
+}
+
+type ChildEnrollmentListResponseDTO struct {
+	Enrollments []ChildEnrollmentDTO `json:"enrollments"`
+	Total       int64                `json:"total"`
+	Page        int                  `json:"page"`
+	PageSize    int                  `json:"page_size"`
+}
+
+type ChildQuizAttemptListResponseDTO struct {
+	Attempts []ChildQuizAttemptDTO `json:"attempts"`
+	Total    int64                 `json:"total"`
+	Page     int                   `json:"page"`
+	PageSize int                   `json:"page_size"`
+}
+
+type ChildOrderListResponseDTO struct {
+	Orders   []ChildOrderDTO `json:"orders"`
+	Total    int64           `json:"total"`
+	Page     int             `json:"page"`
+	PageSize int             `json:"page_size"`
+}
@@ -1,6 +1,10 @@
 package dto
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type UpdatePermissionDTO struct {
 	Description string `json:"description" binding:"required,min=1,max=500"`
@@ -10,8 +14,11 @@ type PermissionResponseDTO struct {
 	ID          uuid.UUID `json:"id"`
 	Name        string    `json:"name"`
 	Description *string   `json:"description,omitempty"`
-	CreatedAt   string    `json:"created_at"`
-	UpdatedAt   string    `json:"updated_at"`
+	// IsGranted and Conditions are only set when the permission is listed under a role
+	IsGranted  *bool                  `json:"is_granted,omitempty"`
+	Conditions map[string]interface{} `json:"conditions,omitempty"`
+	CreatedAt  string                 `json:"created_at"`
+	UpdatedAt  string                 `json:"updated_at"`
 }
 
 type PermissionListResponseDTO struct {
@@ -20,3 +27,27 @@ type PermissionListResponseDTO struct {
 	Page        int                     `json:"page"`
 	PageSize    int                     `json:"page_size"`
 }
+
+type CreatePermissionOverrideDTO struct {
+	PermissionID   uuid.UUID  `json:"permission_id" binding:"required"`
+	IsGranted      *bool      `json:"is_granted" binding:"required"`
+	OrganizationID *uuid.UUID `json:"organization_id"`
+	ResourceType   *string    `json:"resource_type" binding:"omitempty,max=50"`
+	ResourceID     *uuid.UUID `json:"resource_id"`
+	Reason         *string    `json:"reason"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+}
+
+type PermissionOverrideResponseDTO struct {
+	ID             uuid.UUID             `json:"id"`
+	UserID         uuid.UUID             `json:"user_id"`
+	Permission     PermissionResponseDTO `json:"permission"`
+	IsGranted      bool                  `json:"is_granted"`
+	OrganizationID *uuid.UUID            `json:"organization_id,omitempty"`
+	ResourceType   *string               `json:"resource_type,omitempty"`
+	ResourceID     *uuid.UUID            `json:"resource_id,omitempty"`
+	Reason         *string               `json:"reason,omitempty"`
+	GrantedBy      *uuid.UUID            `json:"granted_by,omitempty"`
+	GrantedAt      string                `json:"granted_at"`
+	ExpiresAt      *string               `json:"expires_at,omitempty"`
+}
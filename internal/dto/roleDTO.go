@@ -14,6 +14,9 @@ type UpdateRoleDTO struct {
 
 type AddPermissionsToRoleDTO struct {
 	PermissionIDs []uuid.UUID `json:"permission_ids" binding:"required,min=1,dive,required"`
+	// IsGranted defaults to true; false stores an explicit deny that overrides grants from other roles
+	IsGranted  *bool                  `json:"is_granted"`
+	Conditions map[string]interface{} `json:"conditions"`
 }
 
 type RemovePermissionsFromRoleDTO struct {
@@ -21,20 +24,22 @@ type RemovePermissionsFromRoleDTO struct {
 }
 
 type RoleResponseDTO struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"name"`
-	Description *string   `json:"description,omitempty"`
-	CreatedAt   string    `json:"created_at"`
-	UpdatedAt   string    `json:"updated_at"`
+	ID             uuid.UUID  `json:"id"`
+	OrganizationID *uuid.UUID `json:"organization_id,omitempty"`
+	Name           string     `json:"name"`
+	Description    *string    `json:"description,omitempty"`
+	CreatedAt      string     `json:"created_at"`
+	UpdatedAt      string     `json:"updated_at"`
 }
 
 type RoleDetailResponseDTO struct {
-	ID          uuid.UUID               `json:"id"`
-	Name        string                  `json:"name"`
-	Description *string                 `json:"description,omitempty"`
-	Permissions []PermissionResponseDTO `json:"permissions"`
-	CreatedAt   string                  `json:"created_at"`
-	UpdatedAt   string                  `json:"updated_at"`
+	ID             uuid.UUID               `json:"id"`
+	OrganizationID *uuid.UUID              `json:"organization_id,omitempty"`
+	Name           string                  `json:"name"`
+	Description    *string                 `json:"description,omitempty"`
+	Permissions    []PermissionResponseDTO `json:"permissions"`
+	CreatedAt      string                  `json:"created_at"`
+	UpdatedAt      string                  `json:"updated_at"`
 }
 
 type RoleListResponseDTO struct {
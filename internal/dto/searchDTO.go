@@ -0,0 +1,20 @@
+package dto
+
+import "github.com/google/uuid"
+
+type CourseSuggestionDTO struct {
+	ID    uuid.UUID `json:"id"`
+	Slug  string    `json:"slug"`
+	Title string    `json:"title"`
+}
+
+type TagSuggestionDTO struct {
+	Name        string `json:"name"`
+	Slug        string `json:"slug"`
+	CourseCount int64  `json:"course_count"`
+}
+
+type SearchSuggestionsDTO struct {
+	Courses []CourseSuggestionDTO `json:"courses"`
+	Tags    []TagSuggestionDTO    `json:"tags"`
+}
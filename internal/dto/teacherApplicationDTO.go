@@ -0,0 +1,77 @@
+package dto
+
+import "github.com/google/uuid"
+
+// CreateTeacherApplicationDTO is sent as multipart/form-data together with the
+// "cv" file and any number of "credentials" files.
+type CreateTeacherApplicationDTO struct {
+	Headline          string   `form:"headline" binding:"required,min=5,max=255"`
+	Bio               string   `form:"bio" binding:"required,min=50,max=5000"`
+	Expertise         []string `form:"expertise" binding:"max=10"`
+	YearsOfExperience int      `form:"years_of_experience" binding:"min=0,max=60"`
+	Education         string   `form:"education" binding:"max=2000"`
+	Phone             string   `form:"phone" binding:"max=20"`
+	WebsiteURL        string   `form:"website_url" binding:"max=500"`
+}
+
+type UpdateTeacherApplicationDTO struct {
+	Headline          *string   `json:"headline" binding:"omitempty,min=5,max=255"`
+	Bio               *string   `json:"bio" binding:"omitempty,min=50,max=5000"`
+	Expertise         *[]string `json:"expertise" binding:"omitempty,max=10"`
+	YearsOfExperience *int      `json:"years_of_experience" binding:"omitempty,min=0,max=60"`
+	Education         *string   `json:"education" binding:"omitempty,max=2000"`
+	Phone             *string   `json:"phone" binding:"omitempty,max=20"`
+	WebsiteURL        *string   `json:"website_url" binding:"omitempty,max=500"`
+}
+
+type ReviewTeacherApplicationDTO struct {
+	// Note is required when rejecting, it is sent to the applicant
+	Note string `json:"note" binding:"max=2000"`
+}
+
+type TeacherApplicationQueryDTO struct {
+	Status   string `query:"status"`
+	Search   string `query:"search"`
+	Page     int    `query:"page" default:"1"`
+	PageSize int    `query:"page_size" default:"20"`
+}
+
+type TeacherApplicationDocumentResponseDTO struct {
+	ID          uuid.UUID `json:"id"`
+	DocType     string    `json:"doc_type"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	SizeBytes   int64     `json:"size_bytes"`
+	// DownloadURL is a short-lived presigned link
+	DownloadURL string `json:"download_url,omitempty"`
+	CreatedAt   string `json:"created_at"`
+}
+
+type TeacherApplicationResponseDTO struct {
+	ID                uuid.UUID                               `json:"id"`
+	UserID            uuid.UUID                               `json:"user_id"`
+	Username          string                                  `json:"username"`
+	Email             string                                  `json:"email"`
+	FullName          *string                                 `json:"full_name,omitempty"`
+	Status            string                                  `json:"status"`
+	Headline          string                                  `json:"headline"`
+	Bio               string                                  `json:"bio"`
+	Expertise         []string                                `json:"expertise"`
+	YearsOfExperience int                                     `json:"years_of_experience"`
+	Education         *string                                 `json:"education,omitempty"`
+	Phone             *string                                 `json:"phone,omitempty"`
+	WebsiteURL        *string                                 `json:"website_url,omitempty"`
+	ReviewedBy        *uuid.UUID                              `json:"reviewed_by,omitempty"`
+	ReviewNote        *string                                 `json:"review_note,omitempty"`
+	ReviewedAt        *string                                 `json:"reviewed_at,omitempty"`
+	Documents         []TeacherApplicationDocumentResponseDTO `json:"documents,omitempty"`
+	CreatedAt         string                                  `json:"created_at"`
+	UpdatedAt         string                                  `json:"updated_at"`
+}
+
+type TeacherApplicationListResponseDTO struct {
+	Applications []TeacherApplicationResponseDTO `json:"applications"`
+	Total        int64                           `json:"total"`
+	Page         int                             `json:"page"`
+	PageSize     int                             `json:"page_size"`
+}
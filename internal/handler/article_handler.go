@@ -0,0 +1,238 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"study.com/v1/internal/dto"
+	"study.com/v1/internal/service"
+)
+
+type ArticleHandlerInterface interface {
+	GetArticle(c *fiber.Ctx) error
+	SaveArticle(c *fiber.Ctx) error
+	ListRevisions(c *fiber.Ctx) error
+	GetRevision(c *fiber.Ctx) error
+	DiffRevisions(c *fiber.Ctx) error
+	RestoreRevision(c *fiber.Ctx) error
+	GetLessonArticle(c *fiber.Ctx) error
+}
+
+type ArticleHandler struct {
+	articleService service.ArticleServiceInterface
+}
+
+func NewArticleHandler(articleService service.ArticleServiceInterface) *ArticleHandler {
+	return &ArticleHandler{articleService: articleService}
+}
+
+// GetArticle returns the Markdown of an article lesson to its authors.
+func (h *ArticleHandler) GetArticle(c *fiber.Ctx) error {
+	courseID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "course")
+	}
+	lessonID, err := uuid.Parse(c.Params("lesson_id"))
+	if err != nil {
+		return invalidIDResponse(c, "lesson")
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	article, err := h.articleService.GetArticle(c.Context(), actorID, courseID, lessonID)
+	if err != nil {
+		return c.Status(articleErrorStatus(err)).JSON(fiber.Map{
+			"message": "Get article failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Get article successfully",
+		"data":    article,
+	})
+}
+
+// SaveArticle stores the Markdown as a new version of the article.
+func (h *ArticleHandler) SaveArticle(c *fiber.Ctx) error {
+	courseID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "course")
+	}
+	lessonID, err := uuid.Parse(c.Params("lesson_id"))
+	if err != nil {
+		return invalidIDResponse(c, "lesson")
+	}
+	var req dto.SaveArticleDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	article, err := h.articleService.SaveArticle(c.Context(), actorID, courseID, lessonID, req)
+	if err != nil {
+		return c.Status(articleErrorStatus(err)).JSON(fiber.Map{
+			"message": "Save article failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Article saved",
+		"data":    article,
+	})
+}
+
+func (h *ArticleHandler) ListRevisions(c *fiber.Ctx) error {
+	courseID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "course")
+	}
+	lessonID, err := uuid.Parse(c.Params("lesson_id"))
+	if err != nil {
+		return invalidIDResponse(c, "lesson")
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	revisions, err := h.articleService.ListRevisions(c.Context(), actorID, courseID, lessonID)
+	if err != nil {
+		return c.Status(articleErrorStatus(err)).JSON(fiber.Map{
+			"message": "List revisions failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "List revisions successfully",
+		"data":    revisions,
+	})
+}
+
+func (h *ArticleHandler) GetRevision(c *fiber.Ctx) error {
+	courseID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "course")
+	}
+	lessonID, err := uuid.Parse(c.Params("lesson_id"))
+	if err != nil {
+		return invalidIDResponse(c, "lesson")
+	}
+	version, err := strconv.Atoi(c.Params("version"))
+	if err != nil {
+		return invalidVersionResponse(c)
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	revision, err := h.articleService.GetRevision(c.Context(), actorID, courseID, lessonID, version)
+	if err != nil {
+		return c.Status(articleErrorStatus(err)).JSON(fiber.Map{
+			"message": "Get revision failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Get revision successfully",
+		"data":    revision,
+	})
+}
+
+// DiffRevisions compares the versions from and to, by default the last two.
+func (h *ArticleHandler) DiffRevisions(c *fiber.Ctx) error {
+	courseID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "course")
+	}
+	lessonID, err := uuid.Parse(c.Params("lesson_id"))
+	if err != nil {
+		return invalidIDResponse(c, "lesson")
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+	query := dto.ArticleDiffQueryDTO{
+		From: c.QueryInt("from"),
+		To:   c.QueryInt("to"),
+	}
+
+	diff, err := h.articleService.DiffRevisions(c.Context(), actorID, courseID, lessonID, query)
+	if err != nil {
+		return c.Status(articleErrorStatus(err)).JSON(fiber.Map{
+			"message": "Diff revisions failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Diff revisions successfully",
+		"data":    diff,
+	})
+}
+
+// RestoreRevision saves an earlier revision again as the newest version.
+func (h *ArticleHandler) RestoreRevision(c *fiber.Ctx) error {
+	courseID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "course")
+	}
+	lessonID, err := uuid.Parse(c.Params("lesson_id"))
+	if err != nil {
+		return invalidIDResponse(c, "lesson")
+	}
+	version, err := strconv.Atoi(c.Params("version"))
+	if err != nil {
+		return invalidVersionResponse(c)
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	article, err := h.articleService.RestoreRevision(c.Context(), actorID, courseID, lessonID, version)
+	if err != nil {
+		return c.Status(articleErrorStatus(err)).JSON(fiber.Map{
+			"message": "Restore revision failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Revision restored",
+		"data":    article,
+	})
+}
+
+// GetLessonArticle returns the rendered article of a lesson to the students taking it.
+func (h *ArticleHandler) GetLessonArticle(c *fiber.Ctx) error {
+	lessonID, err := uuid.Parse(c.Params("lesson_id"))
+	if err != nil {
+		return invalidIDResponse(c, "lesson")
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	article, err := h.articleService.GetLessonArticle(c.Context(), actorID, lessonID)
+	if err != nil {
+		return c.Status(articleErrorStatus(err)).JSON(fiber.Map{
+			"message": "Get article failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Get article successfully",
+		"data":    article,
+	})
+}
+
+func invalidVersionResponse(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		"message": "Invalid revision version",
+	})
+}
+
+func articleErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrArticleNotFound), errors.Is(err, service.ErrArticleRevisionNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, service.ErrInvalidArticle), errors.Is(err, service.ErrArticleLessonRequired):
+		return fiber.StatusBadRequest
+	case errors.Is(err, service.ErrArticleVersionConflict):
+		return fiber.StatusConflict
+	case errors.Is(err, service.ErrMediaForbidden):
+		return fiber.StatusForbidden
+	default:
+		return courseErrorStatus(err)
+	}
+}
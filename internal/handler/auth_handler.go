@@ -1,13 +1,16 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
-	"time"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
+	"study.com/v1/internal/config"
 	"study.com/v1/internal/dto"
 	"study.com/v1/internal/service"
+	"study.com/v1/internal/utils"
 )
 
 type AuthHandlerInterface interface {
@@ -21,18 +24,37 @@ type AuthHandlerInterface interface {
 	LogoutOneDevice(c *fiber.Ctx) error
 	LogoutAll(c *fiber.Ctx) error
 	ChangePassword(c *fiber.Ctx) error
+	ListSessions(c *fiber.Ctx) error
+	RevokeSession(c *fiber.Ctx) error
 }
 
 type AuthHandler struct {
+	cfg         *config.Config
+	cookies     *utils.CookiePolicy
 	authService service.AuthServiceInterface
 }
 
-func NewAuthHandler(authService service.AuthServiceInterface) *AuthHandler {
+func NewAuthHandler(cfg *config.Config, authService service.AuthServiceInterface) *AuthHandler {
 	return &AuthHandler{
+		cfg:         cfg,
+		cookies:     utils.NewCookiePolicy(cfg),
 		authService: authService,
 	}
 }
 
+// wantsBodyTokens reports whether the client asked for tokens in the response body
+// instead of cookies, which native apps and scripted clients do via X-Token-Delivery.
+func (h *AuthHandler) wantsBodyTokens(c *fiber.Ctx) bool {
+	if !h.cfg.AuthAllowBearer {
+		return false
+	}
+	delivery := c.Get("X-Token-Delivery")
+	if delivery == "" {
+		delivery = c.Query("token_delivery")
+	}
+	return strings.EqualFold(delivery, "body")
+}
+
 func (h *AuthHandler) Register(c *fiber.Ctx) error {
 	var req dto.RegisterDto
 	if err := c.BodyParser(&req); err != nil {
@@ -43,7 +65,14 @@ func (h *AuthHandler) Register(c *fiber.Ctx) error {
 	}
 	response, err := h.authService.Register(c.Context(), req)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		status := fiber.StatusBadRequest
+		switch {
+		case errors.Is(err, service.ErrEmailAlreadyExists), errors.Is(err, service.ErrUserNameAlreadyExists):
+			status = fiber.StatusConflict
+		case errors.Is(err, service.ErrOTPTooManyAttempts):
+			status = fiber.StatusTooManyRequests
+		}
+		return c.Status(status).JSON(fiber.Map{
 			"message": "Register failed",
 			"error":   err.Error(),
 		})
@@ -64,7 +93,11 @@ func (h *AuthHandler) RequestRegister(c *fiber.Ctx) error {
 	}
 	err := h.authService.RequestRegister(c.Context(), req)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		status := fiber.StatusBadRequest
+		if errors.Is(err, service.ErrOTPResendCooldown) {
+			status = fiber.StatusTooManyRequests
+		}
+		return c.Status(status).JSON(fiber.Map{
 			"message": "Request register failed",
 			"error":   err.Error(),
 		})
@@ -82,6 +115,11 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 			"error":   err.Error(),
 		})
 	}
+	// The address seen by the server is more trustworthy than the one in the body.
+	req.IpAddress = c.IP()
+	if req.UserAgent == "" {
+		req.UserAgent = c.Get(fiber.HeaderUserAgent)
+	}
 	response, err := h.authService.Login(c.Context(), req)
 	if err != nil {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
@@ -89,21 +127,25 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 			"error":   err.Error(),
 		})
 	}
-	c.Cookie(&fiber.Cookie{
-		Name:     "accessToken",
-		Value:    response.AccessToken,
-		Expires:  time.Now().Add(15 * time.Minute),
-		HTTPOnly: true,
-	})
-	c.Cookie(&fiber.Cookie{
-		Name:     "rfToken",
-		Value:    response.RefreshToken,
-		Expires:  time.Now().Add(24 * time.Hour),
-		HTTPOnly: true,
-	})
+	if h.wantsBodyTokens(c) {
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"message": "Login successful",
+			"data":    response,
+		})
+	}
+	csrfToken, err := h.cookies.SetAuthCookies(c, response.AccessToken, response.RefreshToken)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": "Login failed",
+			"error":   err.Error(),
+		})
+	}
+	// The cookies are HttpOnly, the tokens must not reach page scripts through the body.
+	response.AccessToken, response.RefreshToken = "", ""
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"message": "Login successful",
-		"data":    response,
+		"message":    "Login successful",
+		"data":       response,
+		"csrf_token": csrfToken,
 	})
 }
 
@@ -141,18 +183,7 @@ func (h *AuthHandler) LogoutOneDevice(c *fiber.Ctx) error {
 			"error":   err.Error(),
 		})
 	}
-	c.Cookie(&fiber.Cookie{
-		Name:     "accessToken",
-		Value:    "",
-		Expires:  time.Now().Add(-1 * time.Hour),
-		HTTPOnly: true,
-	})
-	c.Cookie(&fiber.Cookie{
-		Name:     "rfToken",
-		Value:    "",
-		Expires:  time.Now().Add(-1 * time.Hour),
-		HTTPOnly: true,
-	})
+	h.cookies.ClearAuthCookies(c)
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"message": "Logout successfully",
 	})
@@ -171,6 +202,7 @@ func (h *AuthHandler) LogoutAll(c *fiber.Ctx) error {
 			"message": "logout all device false",
 		})
 	}
+	h.cookies.ClearAuthCookies(c)
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"message": "Logout on all devices successfully",
@@ -178,28 +210,49 @@ func (h *AuthHandler) LogoutAll(c *fiber.Ctx) error {
 }
 
 func (h *AuthHandler) RefreshToken(c *fiber.Ctx) error {
-	old_rfToken := c.Cookies("rfToken")
+	// Body-delivery clients send the refresh token back in the body, browsers use the cookie.
+	var req dto.RefreshTokenRequestDto
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"message": "Invalid request body",
+				"error":   err.Error(),
+			})
+		}
+	}
+	old_rfToken := req.RefreshToken
+	if old_rfToken == "" {
+		old_rfToken = c.Cookies(utils.RefreshTokenCookie)
+	}
 	response, err := h.authService.RefreshToken(c.Context(), old_rfToken)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		status := fiber.StatusBadRequest
+		if errors.Is(err, service.ErrInvalidRefreshToken) || errors.Is(err, service.ErrRefreshTokenReused) {
+			status = fiber.StatusUnauthorized
+		}
+		return c.Status(status).JSON(fiber.Map{
 			"message": "refresh token service false",
+			"error":   err.Error(),
 		})
 	}
-	c.Cookie(&fiber.Cookie{
-		Name:     "accessToken",
-		Value:    response.AccessToken,
-		Expires:  time.Now().Add(15 * time.Minute),
-		HTTPOnly: true,
-	})
-	c.Cookie(&fiber.Cookie{
-		Name:     "rfToken",
-		Value:    response.RefreshToken,
-		Expires:  time.Now().Add(24 * time.Hour),
-		HTTPOnly: true,
-	})
+	if h.wantsBodyTokens(c) {
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"message": "refresh token successfully",
+			"data":    response,
+		})
+	}
+	csrfToken, err := h.cookies.SetAuthCookies(c, response.AccessToken, response.RefreshToken)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": "refresh token service false",
+			"error":   err.Error(),
+		})
+	}
+	response.AccessToken, response.RefreshToken = "", ""
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"message": "refresh token successfully",
-		"data":    response,
+		"message":    "refresh token successfully",
+		"data":       response,
+		"csrf_token": csrfToken,
 	})
 }
 
@@ -286,3 +339,55 @@ func (h *AuthHandler) ResetPassword(c *fiber.Ctx) error {
 		"message": "Password reset successfully",
 	})
 }
+
+func (h *AuthHandler) ListSessions(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uuid.UUID)
+	if !ok || userID == uuid.Nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Empty userId",
+		})
+	}
+	deviceID, _ := c.Locals("device_id").(uuid.UUID)
+
+	sessions, err := h.authService.ListSessions(c.Context(), userID, deviceID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Get sessions failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Get sessions successfully",
+		"data":    sessions,
+	})
+}
+
+func (h *AuthHandler) RevokeSession(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uuid.UUID)
+	if !ok || userID == uuid.Nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Empty userId",
+		})
+	}
+	deviceID, err := uuid.Parse(c.Params("device_id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid device id",
+			"error":   err.Error(),
+		})
+	}
+
+	if err := h.authService.RevokeSession(c.Context(), userID, deviceID); err != nil {
+		status := fiber.StatusBadRequest
+		if errors.Is(err, service.ErrSessionNotFound) {
+			status = fiber.StatusNotFound
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"message": "Revoke session failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Session revoked successfully",
+	})
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"study.com/v1/internal/dto"
+	"study.com/v1/internal/service"
+)
+
+type CatalogHandlerInterface interface {
+	ListCourses(c *fiber.Ctx) error
+	GetCourse(c *fiber.Ctx) error
+}
+
+type CatalogHandler struct {
+	catalogService service.CatalogServiceInterface
+}
+
+func NewCatalogHandler(catalogService service.CatalogServiceInterface) *CatalogHandler {
+	return &CatalogHandler{catalogService: catalogService}
+}
+
+// ListCourses pages through published courses. Pass next_cursor back as ?cursor= with the
+// same filters and sort to get the following page.
+func (h *CatalogHandler) ListCourses(c *fiber.Ctx) error {
+	query := dto.CatalogQueryDTO{
+		Q:          c.Query("q"),
+		Category:   c.Query("category"),
+		CategoryID: c.Query("category_id"),
+		Tag:        c.Query("tag"),
+		Level:      c.Query("level"),
+		Language:   c.Query("language"),
+		MinPrice:   c.Query("min_price"),
+		MaxPrice:   c.Query("max_price"),
+		IsFree:     c.Query("is_free"),
+		MinRating:  c.Query("min_rating"),
+		Sort:       c.Query("sort"),
+		Cursor:     c.Query("cursor"),
+		Limit:      c.QueryInt("limit", 20),
+	}
+
+	courses, err := h.catalogService.SearchCourses(c.Context(), query)
+	if err != nil {
+		return c.Status(catalogErrorStatus(err)).JSON(fiber.Map{
+			"message": "List courses failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "List courses successfully",
+		"data":    courses,
+	})
+}
+
+func (h *CatalogHandler) GetCourse(c *fiber.Ctx) error {
+	course, err := h.catalogService.GetCourse(c.Context(), c.Params("slug"))
+	if err != nil {
+		return c.Status(catalogErrorStatus(err)).JSON(fiber.Map{
+			"message": "Get course failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Get course successfully",
+		"data":    course,
+	})
+}
+
+func catalogErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrCourseNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, service.ErrInvalidCatalogQuery), errors.Is(err, service.ErrInvalidCursor),
+		errors.Is(err, service.ErrInvalidCourseLevel):
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
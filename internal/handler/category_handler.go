@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"study.com/v1/internal/dto"
+	"study.com/v1/internal/service"
+)
+
+type CategoryHandlerInterface interface {
+	ListCategories(c *fiber.Ctx) error
+	CreateCategory(c *fiber.Ctx) error
+	UpdateCategory(c *fiber.Ctx) error
+	DeleteCategory(c *fiber.Ctx) error
+}
+
+type CategoryHandler struct {
+	categoryService service.CategoryServiceInterface
+}
+
+func NewCategoryHandler(categoryService service.CategoryServiceInterface) *CategoryHandler {
+	return &CategoryHandler{categoryService: categoryService}
+}
+
+func (h *CategoryHandler) ListCategories(c *fiber.Ctx) error {
+	orgID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "organization")
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	categories, err := h.categoryService.ListCategories(c.Context(), actorID, orgID)
+	if err != nil {
+		return c.Status(organizationErrorStatus(err)).JSON(fiber.Map{
+			"message": "List categories failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "List categories successfully",
+		"data":    categories,
+	})
+}
+
+func (h *CategoryHandler) CreateCategory(c *fiber.Ctx) error {
+	orgID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "organization")
+	}
+	var req dto.CreateCategoryDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	category, err := h.categoryService.CreateCategory(c.Context(), actorID, orgID, req)
+	if err != nil {
+		return c.Status(organizationErrorStatus(err)).JSON(fiber.Map{
+			"message": "Create category failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Category created",
+		"data":    category,
+	})
+}
+
+func (h *CategoryHandler) UpdateCategory(c *fiber.Ctx) error {
+	orgID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "organization")
+	}
+	categoryID, err := uuid.Parse(c.Params("category_id"))
+	if err != nil {
+		return invalidIDResponse(c, "category")
+	}
+	var req dto.UpdateCategoryDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	category, err := h.categoryService.UpdateCategory(c.Context(), actorID, orgID, categoryID, req)
+	if err != nil {
+		return c.Status(organizationErrorStatus(err)).JSON(fiber.Map{
+			"message": "Update category failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Category updated",
+		"data":    category,
+	})
+}
+
+func (h *CategoryHandler) DeleteCategory(c *fiber.Ctx) error {
+	orgID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "organization")
+	}
+	categoryID, err := uuid.Parse(c.Params("category_id"))
+	if err != nil {
+		return invalidIDResponse(c, "category")
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	if err := h.categoryService.DeleteCategory(c.Context(), actorID, orgID, categoryID); err != nil {
+		return c.Status(organizationErrorStatus(err)).JSON(fiber.Map{
+			"message": "Delete category failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Category deleted",
+	})
+}
@@ -0,0 +1,360 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"study.com/v1/internal/dto"
+	"study.com/v1/internal/service"
+)
+
+type CourseHandlerInterface interface {
+	ListMyCourses(c *fiber.Ctx) error
+	CreateCourse(c *fiber.Ctx) error
+	GetCourse(c *fiber.Ctx) error
+	UpdateCourse(c *fiber.Ctx) error
+	DeleteCourse(c *fiber.Ctx) error
+	ReorderCourse(c *fiber.Ctx) error
+	CreateSection(c *fiber.Ctx) error
+	UpdateSection(c *fiber.Ctx) error
+	DeleteSection(c *fiber.Ctx) error
+	CreateLesson(c *fiber.Ctx) error
+	UpdateLesson(c *fiber.Ctx) error
+	DeleteLesson(c *fiber.Ctx) error
+}
+
+type CourseHandler struct {
+	courseService service.CourseServiceInterface
+}
+
+func NewCourseHandler(courseService service.CourseServiceInterface) *CourseHandler {
+	return &CourseHandler{courseService: courseService}
+}
+
+// ListMyCourses lists the courses the current user instructs, filtered by ?status= and ?search=.
+func (h *CourseHandler) ListMyCourses(c *fiber.Ctx) error {
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+	query := dto.CourseQueryDTO{
+		Status:   c.Query("status"),
+		Search:   c.Query("search"),
+		Page:     c.QueryInt("page", 1),
+		PageSize: c.QueryInt("page_size", 20),
+	}
+
+	courses, err := h.courseService.ListMyCourses(c.Context(), actorID, query)
+	if err != nil {
+		return c.Status(courseErrorStatus(err)).JSON(fiber.Map{
+			"message": "List courses failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "List courses successfully",
+		"data":    courses,
+	})
+}
+
+func (h *CourseHandler) CreateCourse(c *fiber.Ctx) error {
+	var req dto.CreateCourseDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	course, err := h.courseService.CreateCourse(c.Context(), actorID, req)
+	if err != nil {
+		return c.Status(courseErrorStatus(err)).JSON(fiber.Map{
+			"message": "Create course failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Course created",
+		"data":    course,
+	})
+}
+
+func (h *CourseHandler) GetCourse(c *fiber.Ctx) error {
+	courseID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "course")
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	course, err := h.courseService.GetCourse(c.Context(), actorID, courseID)
+	if err != nil {
+		return c.Status(courseErrorStatus(err)).JSON(fiber.Map{
+			"message": "Get course failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Get course successfully",
+		"data":    course,
+	})
+}
+
+func (h *CourseHandler) UpdateCourse(c *fiber.Ctx) error {
+	courseID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "course")
+	}
+	var req dto.UpdateCourseDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	course, err := h.courseService.UpdateCourse(c.Context(), actorID, courseID, req)
+	if err != nil {
+		return c.Status(courseErrorStatus(err)).JSON(fiber.Map{
+			"message": "Update course failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Course updated",
+		"data":    course,
+	})
+}
+
+func (h *CourseHandler) DeleteCourse(c *fiber.Ctx) error {
+	courseID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "course")
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	if err := h.courseService.DeleteCourse(c.Context(), actorID, courseID); err != nil {
+		return c.Status(courseErrorStatus(err)).JSON(fiber.Map{
+			"message": "Delete course failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Course deleted",
+	})
+}
+
+func (h *CourseHandler) ReorderCourse(c *fiber.Ctx) error {
+	courseID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "course")
+	}
+	var req dto.ReorderCourseDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	course, err := h.courseService.ReorderCourse(c.Context(), actorID, courseID, req)
+	if err != nil {
+		return c.Status(courseErrorStatus(err)).JSON(fiber.Map{
+			"message": "Reorder course failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Course reordered",
+		"data":    course,
+	})
+}
+
+func (h *CourseHandler) CreateSection(c *fiber.Ctx) error {
+	courseID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "course")
+	}
+	var req dto.CreateSectionDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	course, err := h.courseService.CreateSection(c.Context(), actorID, courseID, req)
+	if err != nil {
+		return c.Status(courseErrorStatus(err)).JSON(fiber.Map{
+			"message": "Create section failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Section created",
+		"data":    course,
+	})
+}
+
+func (h *CourseHandler) UpdateSection(c *fiber.Ctx) error {
+	courseID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "course")
+	}
+	sectionID, err := uuid.Parse(c.Params("section_id"))
+	if err != nil {
+		return invalidIDResponse(c, "section")
+	}
+	var req dto.UpdateSectionDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	course, err := h.courseService.UpdateSection(c.Context(), actorID, courseID, sectionID, req)
+	if err != nil {
+		return c.Status(courseErrorStatus(err)).JSON(fiber.Map{
+			"message": "Update section failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Section updated",
+		"data":    course,
+	})
+}
+
+func (h *CourseHandler) DeleteSection(c *fiber.Ctx) error {
+	courseID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "course")
+	}
+	sectionID, err := uuid.Parse(c.Params("section_id"))
+	if err != nil {
+		return invalidIDResponse(c, "section")
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	course, err := h.courseService.DeleteSection(c.Context(), actorID, courseID, sectionID)
+	if err != nil {
+		return c.Status(courseErrorStatus(err)).JSON(fiber.Map{
+			"message": "Delete section failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Section deleted",
+		"data":    course,
+	})
+}
+
+func (h *CourseHandler) CreateLesson(c *fiber.Ctx) error {
+	courseID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "course")
+	}
+	sectionID, err := uuid.Parse(c.Params("section_id"))
+	if err != nil {
+		return invalidIDResponse(c, "section")
+	}
+	var req dto.CreateLessonDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	course, err := h.courseService.CreateLesson(c.Context(), actorID, courseID, sectionID, req)
+	if err != nil {
+		return c.Status(courseErrorStatus(err)).JSON(fiber.Map{
+			"message": "Create lesson failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Lesson created",
+		"data":    course,
+	})
+}
+
+func (h *CourseHandler) UpdateLesson(c *fiber.Ctx) error {
+	courseID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "course")
+	}
+	lessonID, err := uuid.Parse(c.Params("lesson_id"))
+	if err != nil {
+		return invalidIDResponse(c, "lesson")
+	}
+	var req dto.UpdateLessonDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	course, err := h.courseService.UpdateLesson(c.Context(), actorID, courseID, lessonID, req)
+	if err != nil {
+		return c.Status(courseErrorStatus(err)).JSON(fiber.Map{
+			"message": "Update lesson failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Lesson updated",
+		"data":    course,
+	})
+}
+
+func (h *CourseHandler) DeleteLesson(c *fiber.Ctx) error {
+	courseID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "course")
+	}
+	lessonID, err := uuid.Parse(c.Params("lesson_id"))
+	if err != nil {
+		return invalidIDResponse(c, "lesson")
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	course, err := h.courseService.DeleteLesson(c.Context(), actorID, courseID, lessonID)
+	if err != nil {
+		return c.Status(courseErrorStatus(err)).JSON(fiber.Map{
+			"message": "Delete lesson failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Lesson deleted",
+		"data":    course,
+	})
+}
+
+func courseErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrCourseNotFound), errors.Is(err, service.ErrSectionNotFound),
+		errors.Is(err, service.ErrLessonNotFound), errors.Is(err, service.ErrCategoryNotFound),
+		errors.Is(err, service.ErrOrganizationNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, service.ErrCourseForbidden), errors.Is(err, service.ErrOrganizationForbidden):
+		return fiber.StatusForbidden
+	case errors.Is(err, service.ErrCourseSlugExists), errors.Is(err, service.ErrCourseUnderReview):
+		return fiber.StatusConflict
+	case errors.Is(err, service.ErrInvalidCourse), errors.Is(err, service.ErrInvalidCourseLevel),
+		errors.Is(err, service.ErrInvalidCoursePrice), errors.Is(err, service.ErrInvalidSection),
+		errors.Is(err, service.ErrInvalidLesson), errors.Is(err, service.ErrInvalidLessonType),
+		errors.Is(err, service.ErrInvalidCourseOutline), errors.Is(err, service.ErrInvalidCourseStatusArg),
+		errors.Is(err, service.ErrInvalidSlug), errors.Is(err, service.ErrInvalidCourseTags):
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
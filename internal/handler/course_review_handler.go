@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"study.com/v1/internal/dto"
+	"study.com/v1/internal/service"
+)
+
+type CourseReviewHandlerInterface interface {
+	SubmitForReview(c *fiber.Ctx) error
+	WithdrawReview(c *fiber.Ctx) error
+	ArchiveCourse(c *fiber.Ctx) error
+	RestoreCourse(c *fiber.Ctx) error
+	ListStatusHistory(c *fiber.Ctx) error
+	ListPendingReviews(c *fiber.Ctx) error
+	GetCourseForReview(c *fiber.Ctx) error
+	ApproveCourse(c *fiber.Ctx) error
+	RejectCourse(c *fiber.Ctx) error
+}
+
+type CourseReviewHandler struct {
+	reviewService service.CourseReviewServiceInterface
+}
+
+func NewCourseReviewHandler(reviewService service.CourseReviewServiceInterface) *CourseReviewHandler {
+	return &CourseReviewHandler{reviewService: reviewService}
+}
+
+type courseTransitionFunc func(ctx context.Context, actorID, courseID uuid.UUID, req dto.CourseTransitionDTO) (*dto.CourseResponseDTO, error)
+
+func (h *CourseReviewHandler) SubmitForReview(c *fiber.Ctx) error {
+	return h.transition(c, "Course submitted for review", "Submit course failed", h.reviewService.SubmitForReview)
+}
+
+func (h *CourseReviewHandler) WithdrawReview(c *fiber.Ctx) error {
+	return h.transition(c, "Course withdrawn from review", "Withdraw course failed", h.reviewService.WithdrawReview)
+}
+
+func (h *CourseReviewHandler) ArchiveCourse(c *fiber.Ctx) error {
+	return h.transition(c, "Course archived", "Archive course failed", h.reviewService.ArchiveCourse)
+}
+
+func (h *CourseReviewHandler) RestoreCourse(c *fiber.Ctx) error {
+	return h.transition(c, "Course restored as draft", "Restore course failed", h.reviewService.RestoreCourse)
+}
+
+func (h *CourseReviewHandler) ApproveCourse(c *fiber.Ctx) error {
+	return h.transition(c, "Course approved and published", "Approve course failed", h.reviewService.ApproveCourse)
+}
+
+func (h *CourseReviewHandler) RejectCourse(c *fiber.Ctx) error {
+	return h.transition(c, "Course rejected", "Reject course failed", h.reviewService.RejectCourse)
+}
+
+func (h *CourseReviewHandler) ListStatusHistory(c *fiber.Ctx) error {
+	courseID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "course")
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	history, err := h.reviewService.ListStatusHistory(c.Context(), actorID, courseID)
+	if err != nil {
+		return c.Status(courseReviewErrorStatus(err)).JSON(fiber.Map{
+			"message": "List course history failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "List course history successfully",
+		"data":    history,
+	})
+}
+
+// ListPendingReviews shows the queue of ?organization_id=, or the personal courses without it.
+func (h *CourseReviewHandler) ListPendingReviews(c *fiber.Ctx) error {
+	var organizationID *uuid.UUID
+	if raw := c.Query("organization_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return invalidIDResponse(c, "organization")
+		}
+		organizationID = &id
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	courses, err := h.reviewService.ListPendingReviews(c.Context(), actorID, organizationID,
+		c.QueryInt("page", 1), c.QueryInt("page_size", 20))
+	if err != nil {
+		return c.Status(courseReviewErrorStatus(err)).JSON(fiber.Map{
+			"message": "List pending reviews failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "List pending reviews successfully",
+		"data":    courses,
+	})
+}
+
+func (h *CourseReviewHandler) GetCourseForReview(c *fiber.Ctx) error {
+	courseID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "course")
+	}
+	reviewerID, _ := c.Locals("user_id").(uuid.UUID)
+
+	course, err := h.reviewService.GetCourseForReview(c.Context(), reviewerID, courseID)
+	if err != nil {
+		return c.Status(courseReviewErrorStatus(err)).JSON(fiber.Map{
+			"message": "Get course failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Get course successfully",
+		"data":    course,
+	})
+}
+
+// transition parses the course id and the optional comment body shared by every workflow action.
+func (h *CourseReviewHandler) transition(c *fiber.Ctx, successMessage, failureMessage string, apply courseTransitionFunc) error {
+	courseID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "course")
+	}
+	var req dto.CourseTransitionDTO
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"message": "Invalid request body",
+				"error":   err.Error(),
+			})
+		}
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	course, err := apply(c.Context(), actorID, courseID, req)
+	if err != nil {
+		return c.Status(courseReviewErrorStatus(err)).JSON(fiber.Map{
+			"message": failureMessage,
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": successMessage,
+		"data":    course,
+	})
+}
+
+func courseReviewErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrCourseForbidden), errors.Is(err, service.ErrCourseReviewForbidden),
+		errors.Is(err, service.ErrReviewQueueForbidden):
+		return fiber.StatusForbidden
+	case errors.Is(err, service.ErrInvalidCourseStatus), errors.Is(err, service.ErrCourseStatusConflict):
+		return fiber.StatusConflict
+	case errors.Is(err, service.ErrCourseIncomplete):
+		return fiber.StatusUnprocessableEntity
+	case errors.Is(err, service.ErrReviewCommentRequired):
+		return fiber.StatusBadRequest
+	default:
+		return courseErrorStatus(err)
+	}
+}
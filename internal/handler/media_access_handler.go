@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"study.com/v1/internal/dto"
+	"study.com/v1/internal/service"
+)
+
+const hlsPlaylistContentType = "application/vnd.apple.mpegurl"
+
+type MediaAccessHandlerInterface interface {
+	GetPlayback(c *fiber.Ctx) error
+	GetMasterPlaylist(c *fiber.Ctx) error
+	GetRenditionPlaylist(c *fiber.Ctx) error
+	DownloadAttachment(c *fiber.Ctx) error
+	GetTranscript(c *fiber.Ctx) error
+	SearchTranscripts(c *fiber.Ctx) error
+}
+
+type MediaAccessHandler struct {
+	mediaService service.MediaAccessServiceInterface
+}
+
+func NewMediaAccessHandler(mediaService service.MediaAccessServiceInterface) *MediaAccessHandler {
+	return &MediaAccessHandler{mediaService: mediaService}
+}
+
+// GetPlayback returns the stream URL of a lesson video and the watermark to draw over it.
+func (h *MediaAccessHandler) GetPlayback(c *fiber.Ctx) error {
+	lessonID, err := uuid.Parse(c.Params("lesson_id"))
+	if err != nil {
+		return invalidIDResponse(c, "lesson")
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	playback, err := h.mediaService.GetPlayback(c.Context(), actorID, lessonID)
+	if err != nil {
+		return c.Status(mediaAccessErrorStatus(err)).JSON(fiber.Map{
+			"message": "Get playback failed",
+			"error":   err.Error(),
+		})
+	}
+	if playback.Type == dto.PlaybackTypeHLS {
+		playback.URL = c.BaseURL() + "/api/media/hls/" + playback.Token + "/master.m3u8"
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Get playback successfully",
+		"data":    playback,
+	})
+}
+
+func (h *MediaAccessHandler) GetMasterPlaylist(c *fiber.Ctx) error {
+	return h.playlist(c, "")
+}
+
+func (h *MediaAccessHandler) GetRenditionPlaylist(c *fiber.Ctx) error {
+	return h.playlist(c, c.Params("rendition"))
+}
+
+func (h *MediaAccessHandler) playlist(c *fiber.Ctx, rendition string) error {
+	playlist, err := h.mediaService.GetPlaylist(c.Context(), c.Params("token"), rendition)
+	if err != nil {
+		return c.Status(mediaAccessErrorStatus(err)).JSON(fiber.Map{
+			"message": "Get playlist failed",
+			"error":   err.Error(),
+		})
+	}
+	// Segment URLs inside expire, and they are personal: no shared cache may keep them
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+	c.Set(fiber.HeaderContentType, hlsPlaylistContentType)
+	return c.Status(fiber.StatusOK).SendString(playlist)
+}
+
+// DownloadAttachment returns a short-lived download link for a lesson attachment.
+func (h *MediaAccessHandler) DownloadAttachment(c *fiber.Ctx) error {
+	lessonID, err := uuid.Parse(c.Params("lesson_id"))
+	if err != nil {
+		return invalidIDResponse(c, "lesson")
+	}
+	attachmentID, err := uuid.Parse(c.Params("attachment_id"))
+	if err != nil {
+		return invalidIDResponse(c, "attachment")
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	download, err := h.mediaService.DownloadAttachment(c.Context(), actorID, lessonID, attachmentID)
+	if err != nil {
+		return c.Status(mediaAccessErrorStatus(err)).JSON(fiber.Map{
+			"message": "Download attachment failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Download link created",
+		"data":    download,
+	})
+}
+
+// GetTranscript returns the time-coded transcript of a lesson video.
+func (h *MediaAccessHandler) GetTranscript(c *fiber.Ctx) error {
+	lessonID, err := uuid.Parse(c.Params("lesson_id"))
+	if err != nil {
+		return invalidIDResponse(c, "lesson")
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	transcript, err := h.mediaService.GetTranscript(c.Context(), actorID, lessonID)
+	if err != nil {
+		return c.Status(mediaAccessErrorStatus(err)).JSON(fiber.Map{
+			"message": "Get transcript failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Get transcript successfully",
+		"data":    transcript,
+	})
+}
+
+// SearchTranscripts finds the moments of the course's videos where the words of q are said.
+func (h *MediaAccessHandler) SearchTranscripts(c *fiber.Ctx) error {
+	courseID, err := uuid.Parse(c.Params("course_id"))
+	if err != nil {
+		return invalidIDResponse(c, "course")
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+	query := dto.TranscriptSearchQueryDTO{
+		Q:     c.Query("q"),
+		Limit: c.QueryInt("limit", 20),
+	}
+
+	result, err := h.mediaService.SearchTranscripts(c.Context(), actorID, courseID, query)
+	if err != nil {
+		return c.Status(mediaAccessErrorStatus(err)).JSON(fiber.Map{
+			"message": "Search transcripts failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Search transcripts successfully",
+		"data":    result,
+	})
+}
+
+func mediaAccessErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrLessonNotFound), errors.Is(err, service.ErrAttachmentNotFound),
+		errors.Is(err, service.ErrMediaNotReady), errors.Is(err, service.ErrPlaylistNotFound),
+		errors.Is(err, service.ErrUserNotFound), errors.Is(err, service.ErrCourseNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, service.ErrInvalidTranscriptQuery):
+		return fiber.StatusBadRequest
+	case errors.Is(err, service.ErrMediaForbidden):
+		return fiber.StatusForbidden
+	case errors.Is(err, service.ErrInvalidMediaToken):
+		return fiber.StatusUnauthorized
+	case errors.Is(err, service.ErrStorageUnavailable):
+		return fiber.StatusServiceUnavailable
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"study.com/v1/internal/dto"
+	"study.com/v1/internal/service"
+)
+
+type MediaUploadHandlerInterface interface {
+	CreateUpload(c *fiber.Ctx) error
+	CompleteUpload(c *fiber.Ctx) error
+	AbortUpload(c *fiber.Ctx) error
+}
+
+type MediaUploadHandler struct {
+	uploadService service.MediaUploadServiceInterface
+}
+
+func NewMediaUploadHandler(uploadService service.MediaUploadServiceInterface) *MediaUploadHandler {
+	return &MediaUploadHandler{uploadService: uploadService}
+}
+
+// CreateUpload returns the presigned URLs the client uploads the file to, directly to storage.
+func (h *MediaUploadHandler) CreateUpload(c *fiber.Ctx) error {
+	courseID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "course")
+	}
+	var req dto.CreateMediaUploadDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	session, err := h.uploadService.CreateUpload(c.Context(), actorID, courseID, req)
+	if err != nil {
+		return c.Status(mediaUploadErrorStatus(err)).JSON(fiber.Map{
+			"message": "Create upload failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Upload created",
+		"data":    session,
+	})
+}
+
+func (h *MediaUploadHandler) CompleteUpload(c *fiber.Ctx) error {
+	courseID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "course")
+	}
+	uploadID, err := uuid.Parse(c.Params("upload_id"))
+	if err != nil {
+		return invalidIDResponse(c, "upload")
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	upload, err := h.uploadService.CompleteUpload(c.Context(), actorID, courseID, uploadID)
+	if err != nil {
+		return c.Status(mediaUploadErrorStatus(err)).JSON(fiber.Map{
+			"message": "Complete upload failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Upload completed",
+		"data":    upload,
+	})
+}
+
+func (h *MediaUploadHandler) AbortUpload(c *fiber.Ctx) error {
+	courseID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "course")
+	}
+	uploadID, err := uuid.Parse(c.Params("upload_id"))
+	if err != nil {
+		return invalidIDResponse(c, "upload")
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	if err := h.uploadService.AbortUpload(c.Context(), actorID, courseID, uploadID); err != nil {
+		return c.Status(mediaUploadErrorStatus(err)).JSON(fiber.Map{
+			"message": "Abort upload failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Upload aborted",
+	})
+}
+
+func mediaUploadErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrMediaUploadNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, service.ErrInvalidMediaUpload), errors.Is(err, service.ErrUnsupportedMediaType),
+		errors.Is(err, service.ErrMediaUploadTooLarge), errors.Is(err, service.ErrVideoLessonRequired),
+		errors.Is(err, service.ErrArticleLessonRequired):
+		return fiber.StatusBadRequest
+	case errors.Is(err, service.ErrMediaUploadNotPending), errors.Is(err, service.ErrMediaUploadIncomplete):
+		return fiber.StatusConflict
+	case errors.Is(err, service.ErrMediaUploadExpired):
+		return fiber.StatusGone
+	case errors.Is(err, service.ErrMediaUploadMismatch):
+		return fiber.StatusUnprocessableEntity
+	case errors.Is(err, service.ErrStorageUnavailable):
+		return fiber.StatusServiceUnavailable
+	default:
+		return courseErrorStatus(err)
+	}
+}
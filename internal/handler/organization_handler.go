@@ -0,0 +1,552 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"study.com/v1/internal/dto"
+	"study.com/v1/internal/service"
+)
+
+type OrganizationHandlerInterface interface {
+	CreateOrganization(c *fiber.Ctx) error
+	ListMyOrganizations(c *fiber.Ctx) error
+	GetOrganization(c *fiber.Ctx) error
+	UpdateOrganization(c *fiber.Ctx) error
+	DeleteOrganization(c *fiber.Ctx) error
+	ListMembers(c *fiber.Ctx) error
+	UpdateMember(c *fiber.Ctx) error
+	RemoveMember(c *fiber.Ctx) error
+	LeaveOrganization(c *fiber.Ctx) error
+	InviteMember(c *fiber.Ctx) error
+	ListInvitations(c *fiber.Ctx) error
+	RevokeInvitation(c *fiber.Ctx) error
+	AcceptInvitation(c *fiber.Ctx) error
+	ListRoles(c *fiber.Ctx) error
+	GetRole(c *fiber.Ctx) error
+	CreateRole(c *fiber.Ctx) error
+	UpdateRole(c *fiber.Ctx) error
+	DeleteRole(c *fiber.Ctx) error
+	AddRolePermissions(c *fiber.Ctx) error
+	RemoveRolePermissions(c *fiber.Ctx) error
+}
+
+type OrganizationHandler struct {
+	organizationService     service.OrganizationServiceInterface
+	organizationRoleService service.OrganizationRoleServiceInterface
+}
+
+func NewOrganizationHandler(
+	organizationService service.OrganizationServiceInterface,
+	organizationRoleService service.OrganizationRoleServiceInterface,
+) *OrganizationHandler {
+	return &OrganizationHandler{
+		organizationService:     organizationService,
+		organizationRoleService: organizationRoleService,
+	}
+}
+
+func (h *OrganizationHandler) CreateOrganization(c *fiber.Ctx) error {
+	var req dto.CreateOrganizationDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	org, err := h.organizationService.CreateOrganization(c.Context(), actorID, req)
+	if err != nil {
+		return c.Status(organizationErrorStatus(err)).JSON(fiber.Map{
+			"message": "Create organization failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Organization created",
+		"data":    org,
+	})
+}
+
+func (h *OrganizationHandler) ListMyOrganizations(c *fiber.Ctx) error {
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	orgs, err := h.organizationService.ListMyOrganizations(c.Context(), actorID, c.QueryInt("page", 1), c.QueryInt("page_size", 20))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": "List organizations failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "List organizations successfully",
+		"data":    orgs,
+	})
+}
+
+func (h *OrganizationHandler) GetOrganization(c *fiber.Ctx) error {
+	orgID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "organization")
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	org, err := h.organizationService.GetOrganization(c.Context(), actorID, orgID)
+	if err != nil {
+		return c.Status(organizationErrorStatus(err)).JSON(fiber.Map{
+			"message": "Get organization failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Get organization successfully",
+		"data":    org,
+	})
+}
+
+func (h *OrganizationHandler) UpdateOrganization(c *fiber.Ctx) error {
+	orgID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "organization")
+	}
+	var req dto.UpdateOrganizationDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	org, err := h.organizationService.UpdateOrganization(c.Context(), actorID, orgID, req)
+	if err != nil {
+		return c.Status(organizationErrorStatus(err)).JSON(fiber.Map{
+			"message": "Update organization failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Organization updated",
+		"data":    org,
+	})
+}
+
+func (h *OrganizationHandler) DeleteOrganization(c *fiber.Ctx) error {
+	orgID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "organization")
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	if err := h.organizationService.DeleteOrganization(c.Context(), actorID, orgID); err != nil {
+		return c.Status(organizationErrorStatus(err)).JSON(fiber.Map{
+			"message": "Delete organization failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Organization deleted",
+	})
+}
+
+func (h *OrganizationHandler) ListMembers(c *fiber.Ctx) error {
+	orgID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "organization")
+	}
+	query := dto.OrganizationMemberQueryDTO{
+		Search:     c.Query("search"),
+		MemberRole: c.Query("member_role"),
+		Status:     c.Query("status"),
+		Page:       c.QueryInt("page", 1),
+		PageSize:   c.QueryInt("page_size", 20),
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	members, err := h.organizationService.ListMembers(c.Context(), actorID, orgID, query)
+	if err != nil {
+		return c.Status(organizationErrorStatus(err)).JSON(fiber.Map{
+			"message": "List members failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "List members successfully",
+		"data":    members,
+	})
+}
+
+func (h *OrganizationHandler) UpdateMember(c *fiber.Ctx) error {
+	orgID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "organization")
+	}
+	userID, err := uuid.Parse(c.Params("user_id"))
+	if err != nil {
+		return invalidIDResponse(c, "user")
+	}
+	var req dto.UpdateOrganizationMemberDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	member, err := h.organizationService.UpdateMember(c.Context(), actorID, orgID, userID, req)
+	if err != nil {
+		return c.Status(organizationErrorStatus(err)).JSON(fiber.Map{
+			"message": "Update member failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Member updated",
+		"data":    member,
+	})
+}
+
+func (h *OrganizationHandler) RemoveMember(c *fiber.Ctx) error {
+	orgID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "organization")
+	}
+	userID, err := uuid.Parse(c.Params("user_id"))
+	if err != nil {
+		return invalidIDResponse(c, "user")
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	if err := h.organizationService.RemoveMember(c.Context(), actorID, orgID, userID); err != nil {
+		return c.Status(organizationErrorStatus(err)).JSON(fiber.Map{
+			"message": "Remove member failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Member removed",
+	})
+}
+
+func (h *OrganizationHandler) LeaveOrganization(c *fiber.Ctx) error {
+	orgID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "organization")
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	if err := h.organizationService.LeaveOrganization(c.Context(), actorID, orgID); err != nil {
+		return c.Status(organizationErrorStatus(err)).JSON(fiber.Map{
+			"message": "Leave organization failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Left organization",
+	})
+}
+
+func (h *OrganizationHandler) InviteMember(c *fiber.Ctx) error {
+	orgID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "organization")
+	}
+	var req dto.CreateOrganizationInvitationDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	invitation, err := h.organizationService.InviteMember(c.Context(), actorID, orgID, req)
+	if err != nil {
+		return c.Status(organizationErrorStatus(err)).JSON(fiber.Map{
+			"message": "Invite member failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Invitation sent",
+		"data":    invitation,
+	})
+}
+
+func (h *OrganizationHandler) ListInvitations(c *fiber.Ctx) error {
+	orgID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "organization")
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	invitations, err := h.organizationService.ListInvitations(c.Context(), actorID, orgID)
+	if err != nil {
+		return c.Status(organizationErrorStatus(err)).JSON(fiber.Map{
+			"message": "List invitations failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "List invitations successfully",
+		"data":    invitations,
+	})
+}
+
+func (h *OrganizationHandler) RevokeInvitation(c *fiber.Ctx) error {
+	orgID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "organization")
+	}
+	invitationID, err := uuid.Parse(c.Params("invitation_id"))
+	if err != nil {
+		return invalidIDResponse(c, "invitation")
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	if err := h.organizationService.RevokeInvitation(c.Context(), actorID, orgID, invitationID); err != nil {
+		return c.Status(organizationErrorStatus(err)).JSON(fiber.Map{
+			"message": "Revoke invitation failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Invitation revoked",
+	})
+}
+
+func (h *OrganizationHandler) AcceptInvitation(c *fiber.Ctx) error {
+	var req dto.AcceptOrganizationInvitationDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	org, err := h.organizationService.AcceptInvitation(c.Context(), actorID, req)
+	if err != nil {
+		return c.Status(organizationErrorStatus(err)).JSON(fiber.Map{
+			"message": "Accept invitation failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Invitation accepted",
+		"data":    org,
+	})
+}
+
+func (h *OrganizationHandler) ListRoles(c *fiber.Ctx) error {
+	orgID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "organization")
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	roles, err := h.organizationRoleService.ListRoles(c.Context(), actorID, orgID, c.Query("search"), c.QueryInt("page", 1), c.QueryInt("page_size", 20))
+	if err != nil {
+		return c.Status(organizationErrorStatus(err)).JSON(fiber.Map{
+			"message": "List roles failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "List roles successfully",
+		"data":    roles,
+	})
+}
+
+func (h *OrganizationHandler) GetRole(c *fiber.Ctx) error {
+	orgID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "organization")
+	}
+	roleID, err := uuid.Parse(c.Params("role_id"))
+	if err != nil {
+		return invalidIDResponse(c, "role")
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	role, err := h.organizationRoleService.GetRole(c.Context(), actorID, orgID, roleID)
+	if err != nil {
+		return c.Status(organizationErrorStatus(err)).JSON(fiber.Map{
+			"message": "Get role failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Get role successfully",
+		"data":    role,
+	})
+}
+
+func (h *OrganizationHandler) CreateRole(c *fiber.Ctx) error {
+	orgID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "organization")
+	}
+	var req dto.CreateRoleDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	role, err := h.organizationRoleService.CreateRole(c.Context(), actorID, orgID, req)
+	if err != nil {
+		return c.Status(organizationErrorStatus(err)).JSON(fiber.Map{
+			"message": "Create role failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Role created",
+		"data":    role,
+	})
+}
+
+func (h *OrganizationHandler) UpdateRole(c *fiber.Ctx) error {
+	orgID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "organization")
+	}
+	roleID, err := uuid.Parse(c.Params("role_id"))
+	if err != nil {
+		return invalidIDResponse(c, "role")
+	}
+	var req dto.UpdateRoleDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	role, err := h.organizationRoleService.UpdateRole(c.Context(), actorID, orgID, roleID, req)
+	if err != nil {
+		return c.Status(organizationErrorStatus(err)).JSON(fiber.Map{
+			"message": "Update role failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Role updated",
+		"data":    role,
+	})
+}
+
+func (h *OrganizationHandler) DeleteRole(c *fiber.Ctx) error {
+	orgID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "organization")
+	}
+	roleID, err := uuid.Parse(c.Params("role_id"))
+	if err != nil {
+		return invalidIDResponse(c, "role")
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	if err := h.organizationRoleService.DeleteRole(c.Context(), actorID, orgID, roleID); err != nil {
+		return c.Status(organizationErrorStatus(err)).JSON(fiber.Map{
+			"message": "Delete role failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Role deleted",
+	})
+}
+
+func (h *OrganizationHandler) AddRolePermissions(c *fiber.Ctx) error {
+	orgID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "organization")
+	}
+	roleID, err := uuid.Parse(c.Params("role_id"))
+	if err != nil {
+		return invalidIDResponse(c, "role")
+	}
+	var req dto.AddPermissionsToRoleDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	role, err := h.organizationRoleService.AddPermissionsToRole(c.Context(), actorID, orgID, roleID, req)
+	if err != nil {
+		return c.Status(organizationErrorStatus(err)).JSON(fiber.Map{
+			"message": "Add permissions failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Permissions added",
+		"data":    role,
+	})
+}
+
+func (h *OrganizationHandler) RemoveRolePermissions(c *fiber.Ctx) error {
+	orgID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "organization")
+	}
+	roleID, err := uuid.Parse(c.Params("role_id"))
+	if err != nil {
+		return invalidIDResponse(c, "role")
+	}
+	var req dto.RemovePermissionsFromRoleDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	role, err := h.organizationRoleService.RemovePermissionsFromRole(c.Context(), actorID, orgID, roleID, req)
+	if err != nil {
+		return c.Status(organizationErrorStatus(err)).JSON(fiber.Map{
+			"message": "Remove permissions failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Permissions removed",
+		"data":    role,
+	})
+}
+
+// organizationErrorStatus also covers the role and category errors returned by the
+// services nested under an organization.
+func organizationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrOrganizationNotFound), errors.Is(err, service.ErrMemberNotFound),
+		errors.Is(err, service.ErrInvitationNotFound), errors.Is(err, service.ErrCategoryNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, service.ErrOrganizationForbidden), errors.Is(err, service.ErrOwnerMembershipProtected),
+		errors.Is(err, service.ErrRoleNotDelegable), errors.Is(err, service.ErrInvitationEmailMismatch):
+		return fiber.StatusForbidden
+	case errors.Is(err, service.ErrOrganizationSlugExists), errors.Is(err, service.ErrAlreadyMember),
+		errors.Is(err, service.ErrMemberLimitReached), errors.Is(err, service.ErrCategorySlugExists):
+		return fiber.StatusConflict
+	case errors.Is(err, service.ErrInvalidOrganizationName), errors.Is(err, service.ErrInvalidSlug),
+		errors.Is(err, service.ErrInvalidOrgType), errors.Is(err, service.ErrInvalidMemberRole),
+		errors.Is(err, service.ErrInvalidMemberStatus), errors.Is(err, service.ErrInvalidEmail),
+		errors.Is(err, service.ErrInvitationInvalid), errors.Is(err, service.ErrInvalidCategoryName),
+		errors.Is(err, service.ErrInvalidCategoryParent):
+		return fiber.StatusBadRequest
+	default:
+		return roleErrorStatus(err)
+	}
+}
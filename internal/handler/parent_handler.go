@@ -0,0 +1,332 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"study.com/v1/internal/dto"
+	"study.com/v1/internal/service"
+)
+
+type ParentHandlerInterface interface {
+	GetGuardianStatus(c *fiber.Ctx) error
+	RequestLink(c *fiber.Ctx) error
+	ListMyLinks(c *fiber.Ctx) error
+	ResendLinkOTP(c *fiber.Ctx) error
+	ConfirmLink(c *fiber.Ctx) error
+	UpdateLink(c *fiber.Ctx) error
+	RevokeLink(c *fiber.Ctx) error
+	ListChildren(c *fiber.Ctx) error
+	ListChildEnrollments(c *fiber.Ctx) error
+	ListChildQuizAttempts(c *fiber.Ctx) error
+	ListChildOrders(c *fiber.Ctx) error
+	ApproveChildOrder(c *fiber.Ctx) error
+	RejectChildOrder(c *fiber.Ctx) error
+}
+
+type ParentHandler struct {
+	parentService service.ParentServiceInterface
+}
+
+func NewParentHandler(parentService service.ParentServiceInterface) *ParentHandler {
+	return &ParentHandler{parentService: parentService}
+}
+
+func (h *ParentHandler) GetGuardianStatus(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(uuid.UUID)
+
+	status, err := h.parentService.GetGuardianStatus(c.Context(), userID)
+	if err != nil {
+		return c.Status(parentErrorStatus(err)).JSON(fiber.Map{
+			"message": "Get guardian status failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Get guardian status successfully",
+		"data":    status,
+	})
+}
+
+// RequestLink creates a pending link to the student and emails the student a confirmation code.
+func (h *ParentHandler) RequestLink(c *fiber.Ctx) error {
+	var req dto.CreateParentLinkDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	parentID, _ := c.Locals("user_id").(uuid.UUID)
+
+	link, err := h.parentService.RequestLink(c.Context(), parentID, req)
+	if err != nil {
+		return c.Status(parentErrorStatus(err)).JSON(fiber.Map{
+			"message": "Request parent link failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "OTP sent to the student's email",
+		"data":    link,
+	})
+}
+
+func (h *ParentHandler) ListMyLinks(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(uuid.UUID)
+
+	links, err := h.parentService.ListMyLinks(c.Context(), userID)
+	if err != nil {
+		return c.Status(parentErrorStatus(err)).JSON(fiber.Map{
+			"message": "List parent links failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "List parent links successfully",
+		"data":    links,
+	})
+}
+
+func (h *ParentHandler) ResendLinkOTP(c *fiber.Ctx) error {
+	linkID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "link")
+	}
+	parentID, _ := c.Locals("user_id").(uuid.UUID)
+
+	if err := h.parentService.ResendLinkOTP(c.Context(), parentID, linkID); err != nil {
+		return c.Status(parentErrorStatus(err)).JSON(fiber.Map{
+			"message": "Resend OTP failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "OTP sent to the student's email",
+	})
+}
+
+func (h *ParentHandler) ConfirmLink(c *fiber.Ctx) error {
+	linkID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "link")
+	}
+	var req dto.ConfirmParentLinkDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	link, err := h.parentService.ConfirmLink(c.Context(), actorID, linkID, req)
+	if err != nil {
+		return c.Status(parentErrorStatus(err)).JSON(fiber.Map{
+			"message": "Confirm parent link failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Parent link confirmed",
+		"data":    link,
+	})
+}
+
+func (h *ParentHandler) UpdateLink(c *fiber.Ctx) error {
+	linkID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "link")
+	}
+	var req dto.UpdateParentLinkDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	studentID, _ := c.Locals("user_id").(uuid.UUID)
+
+	link, err := h.parentService.UpdateLink(c.Context(), studentID, linkID, req)
+	if err != nil {
+		return c.Status(parentErrorStatus(err)).JSON(fiber.Map{
+			"message": "Update parent link failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Parent link updated",
+		"data":    link,
+	})
+}
+
+func (h *ParentHandler) RevokeLink(c *fiber.Ctx) error {
+	linkID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "link")
+	}
+	actorID, _ := c.Locals("user_id").(uuid.UUID)
+
+	if err := h.parentService.RevokeLink(c.Context(), actorID, linkID); err != nil {
+		return c.Status(parentErrorStatus(err)).JSON(fiber.Map{
+			"message": "Remove parent link failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Parent link removed",
+	})
+}
+
+func (h *ParentHandler) ListChildren(c *fiber.Ctx) error {
+	parentID, _ := c.Locals("user_id").(uuid.UUID)
+
+	children, err := h.parentService.ListChildren(c.Context(), parentID)
+	if err != nil {
+		return c.Status(parentErrorStatus(err)).JSON(fiber.Map{
+			"message": "List children failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "List children successfully",
+		"data":    children,
+	})
+}
+
+func (h *ParentHandler) ListChildEnrollments(c *fiber.Ctx) error {
+	studentID, err := uuid.Parse(c.Params("student_id"))
+	if err != nil {
+		return invalidIDResponse(c, "student")
+	}
+	parentID, _ := c.Locals("user_id").(uuid.UUID)
+
+	enrollments, err := h.parentService.ListChildEnrollments(c.Context(), parentID, studentID,
+		c.QueryInt("page", 1), c.QueryInt("page_size", 20))
+	if err != nil {
+		return c.Status(parentErrorStatus(err)).JSON(fiber.Map{
+			"message": "List enrollments failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "List enrollments successfully",
+		"data":    enrollments,
+	})
+}
+
+func (h *ParentHandler) ListChildQuizAttempts(c *fiber.Ctx) error {
+	studentID, err := uuid.Parse(c.Params("student_id"))
+	if err != nil {
+		return invalidIDResponse(c, "student")
+	}
+	parentID, _ := c.Locals("user_id").(uuid.UUID)
+
+	attempts, err := h.parentService.ListChildQuizAttempts(c.Context(), parentID, studentID,
+		c.QueryInt("page", 1), c.QueryInt("page_size", 20))
+	if err != nil {
+		return c.Status(parentErrorStatus(err)).JSON(fiber.Map{
+			"message": "List quiz attempts failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "List quiz attempts successfully",
+		"data":    attempts,
+	})
+}
+
+// ListChildOrders accepts ?approval=pending to show only the orders waiting for the parent.
+func (h *ParentHandler) ListChildOrders(c *fiber.Ctx) error {
+	studentID, err := uuid.Parse(c.Params("student_id"))
+	if err != nil {
+		return invalidIDResponse(c, "student")
+	}
+	parentID, _ := c.Locals("user_id").(uuid.UUID)
+
+	orders, err := h.parentService.ListChildOrders(c.Context(), parentID, studentID, c.Query("approval"),
+		c.QueryInt("page", 1), c.QueryInt("page_size", 20))
+	if err != nil {
+		return c.Status(parentErrorStatus(err)).JSON(fiber.Map{
+			"message": "List orders failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "List orders successfully",
+		"data":    orders,
+	})
+}
+
+func (h *ParentHandler) ApproveChildOrder(c *fiber.Ctx) error {
+	return h.decideChildOrder(c, true)
+}
+
+func (h *ParentHandler) RejectChildOrder(c *fiber.Ctx) error {
+	return h.decideChildOrder(c, false)
+}
+
+func (h *ParentHandler) decideChildOrder(c *fiber.Ctx, approve bool) error {
+	studentID, err := uuid.Parse(c.Params("student_id"))
+	if err != nil {
+		return invalidIDResponse(c, "student")
+	}
+	orderID, err := uuid.Parse(c.Params("order_id"))
+	if err != nil {
+		return invalidIDResponse(c, "order")
+	}
+	var req dto.ParentApprovalDTO
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"message": "Invalid request body",
+				"error":   err.Error(),
+			})
+		}
+	}
+	parentID, _ := c.Locals("user_id").(uuid.UUID)
+
+	var order *dto.ChildOrderDTO
+	if approve {
+		order, err = h.parentService.ApproveChildOrder(c.Context(), parentID, studentID, orderID, req)
+	} else {
+		order, err = h.parentService.RejectChildOrder(c.Context(), parentID, studentID, orderID, req)
+	}
+	if err != nil {
+		return c.Status(parentErrorStatus(err)).JSON(fiber.Map{
+			"message": "Review order failed",
+			"error":   err.Error(),
+		})
+	}
+	message := "Order approved"
+	if !approve {
+		message = "Order rejected"
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": message,
+		"data":    order,
+	})
+}
+
+func parentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrParentLinkNotFound), errors.Is(err, service.ErrChildNotFound),
+		errors.Is(err, service.ErrOrderNotFound), errors.Is(err, service.ErrUserNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, service.ErrParentLinkExists), errors.Is(err, service.ErrParentLinkNotPending),
+		errors.Is(err, service.ErrOrderNotAwaitingParent):
+		return fiber.StatusConflict
+	case errors.Is(err, service.ErrParentAccessDenied), errors.Is(err, service.ErrParentIsMinor),
+		errors.Is(err, service.ErrMinorCannotUnlink), errors.Is(err, service.ErrMinorCannotChangeLink):
+		return fiber.StatusForbidden
+	case errors.Is(err, service.ErrOTPResendCooldown), errors.Is(err, service.ErrOTPTooManyAttempts):
+		return fiber.StatusTooManyRequests
+	case errors.Is(err, service.ErrOTPExpired), errors.Is(err, service.ErrOTPInvalid),
+		errors.Is(err, service.ErrCannotLinkSelf), errors.Is(err, service.ErrInvalidRelationship),
+		errors.Is(err, service.ErrInvalidEmail):
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
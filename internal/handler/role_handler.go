@@ -0,0 +1,258 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"study.com/v1/internal/dto"
+	"study.com/v1/internal/service"
+)
+
+type RoleHandlerInterface interface {
+	CreateRole(c *fiber.Ctx) error
+	UpdateRole(c *fiber.Ctx) error
+	DeleteRole(c *fiber.Ctx) error
+	GetRole(c *fiber.Ctx) error
+	ListRoles(c *fiber.Ctx) error
+	AddPermissions(c *fiber.Ctx) error
+	RemovePermissions(c *fiber.Ctx) error
+	GetPermission(c *fiber.Ctx) error
+	ListPermissions(c *fiber.Ctx) error
+	UpdatePermission(c *fiber.Ctx) error
+}
+
+type RoleHandler struct {
+	roleService service.RoleServiceInterface
+}
+
+func NewRoleHandler(roleService service.RoleServiceInterface) *RoleHandler {
+	return &RoleHandler{roleService: roleService}
+}
+
+func (h *RoleHandler) CreateRole(c *fiber.Ctx) error {
+	var req dto.CreateRoleDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	role, err := h.roleService.CreateRole(c.Context(), req)
+	if err != nil {
+		return c.Status(roleErrorStatus(err)).JSON(fiber.Map{
+			"message": "Create role failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Role created",
+		"data":    role,
+	})
+}
+
+func (h *RoleHandler) UpdateRole(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "role")
+	}
+	var req dto.UpdateRoleDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	role, err := h.roleService.UpdateRole(c.Context(), id, req)
+	if err != nil {
+		return c.Status(roleErrorStatus(err)).JSON(fiber.Map{
+			"message": "Update role failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Role updated",
+		"data":    role,
+	})
+}
+
+func (h *RoleHandler) DeleteRole(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "role")
+	}
+	if err := h.roleService.DeleteRole(c.Context(), id); err != nil {
+		return c.Status(roleErrorStatus(err)).JSON(fiber.Map{
+			"message": "Delete role failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Role deleted",
+	})
+}
+
+func (h *RoleHandler) GetRole(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "role")
+	}
+	role, err := h.roleService.GetRole(c.Context(), id)
+	if err != nil {
+		return c.Status(roleErrorStatus(err)).JSON(fiber.Map{
+			"message": "Get role failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Get role successfully",
+		"data":    role,
+	})
+}
+
+func (h *RoleHandler) ListRoles(c *fiber.Ctx) error {
+	roles, err := h.roleService.ListRoles(c.Context(), c.Query("search"), c.QueryInt("page", 1), c.QueryInt("page_size", 20))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": "List roles failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "List roles successfully",
+		"data":    roles,
+	})
+}
+
+func (h *RoleHandler) AddPermissions(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "role")
+	}
+	var req dto.AddPermissionsToRoleDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	role, err := h.roleService.AddPermissionsToRole(c.Context(), id, req)
+	if err != nil {
+		return c.Status(roleErrorStatus(err)).JSON(fiber.Map{
+			"message": "Add permissions failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Permissions added",
+		"data":    role,
+	})
+}
+
+func (h *RoleHandler) RemovePermissions(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "role")
+	}
+	var req dto.RemovePermissionsFromRoleDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	role, err := h.roleService.RemovePermissionsFromRole(c.Context(), id, req)
+	if err != nil {
+		return c.Status(roleErrorStatus(err)).JSON(fiber.Map{
+			"message": "Remove permissions failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Permissions removed",
+		"data":    role,
+	})
+}
+
+func (h *RoleHandler) GetPermission(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "permission")
+	}
+	permission, err := h.roleService.GetPermission(c.Context(), id)
+	if err != nil {
+		return c.Status(roleErrorStatus(err)).JSON(fiber.Map{
+			"message": "Get permission failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Get permission successfully",
+		"data":    permission,
+	})
+}
+
+func (h *RoleHandler) ListPermissions(c *fiber.Ctx) error {
+	permissions, err := h.roleService.ListPermissions(c.Context(), c.Query("search"), c.QueryInt("page", 1), c.QueryInt("page_size", 20))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": "List permissions failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "List permissions successfully",
+		"data":    permissions,
+	})
+}
+
+func (h *RoleHandler) UpdatePermission(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "permission")
+	}
+	var req dto.UpdatePermissionDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	permission, err := h.roleService.UpdatePermission(c.Context(), id, req)
+	if err != nil {
+		return c.Status(roleErrorStatus(err)).JSON(fiber.Map{
+			"message": "Update permission failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Permission updated",
+		"data":    permission,
+	})
+}
+
+func roleErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrRoleNotFound), errors.Is(err, service.ErrPermissionNotFound), errors.Is(err, service.ErrUserNotFound),
+		errors.Is(err, service.ErrOverrideNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, service.ErrRoleNameExists):
+		return fiber.StatusConflict
+	case errors.Is(err, service.ErrSystemRoleProtected), errors.Is(err, service.ErrWildcardNotAssignable):
+		return fiber.StatusForbidden
+	case errors.Is(err, service.ErrInvalidRoleName), errors.Is(err, service.ErrDescriptionTooLong),
+		errors.Is(err, service.ErrEmptyPermissionList), errors.Is(err, service.ErrEmptyDescription),
+		errors.Is(err, service.ErrEmptyRoleList), errors.Is(err, service.ErrLastRoleRemove),
+		errors.Is(err, service.ErrInvalidConditions), errors.Is(err, service.ErrOverrideGrantRequired),
+		errors.Is(err, service.ErrOverrideResourceScope), errors.Is(err, service.ErrOverrideAlreadyExpired):
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+func invalidIDResponse(c *fiber.Ctx, resource string) error {
+	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		"message": "Invalid " + resource + " id",
+	})
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"study.com/v1/internal/service"
+)
+
+type SearchHandlerInterface interface {
+	Suggest(c *fiber.Ctx) error
+}
+
+type SearchHandler struct {
+	searchService service.SearchServiceInterface
+}
+
+func NewSearchHandler(searchService service.SearchServiceInterface) *SearchHandler {
+	return &SearchHandler{searchService: searchService}
+}
+
+// Suggest is called on every keystroke of the search box with ?q= and an optional ?limit=.
+// Searching itself is GET /api/courses?q=.
+func (h *SearchHandler) Suggest(c *fiber.Ctx) error {
+	suggestions, err := h.searchService.Suggest(c.Context(), c.Query("q"), c.QueryInt("limit", 8))
+	if err != nil {
+		return c.Status(searchErrorStatus(err)).JSON(fiber.Map{
+			"message": "Search suggestions failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Search suggestions successfully",
+		"data":    suggestions,
+	})
+}
+
+func searchErrorStatus(err error) int {
+	if errors.Is(err, service.ErrInvalidCatalogQuery) {
+		return fiber.StatusBadRequest
+	}
+	return fiber.StatusInternalServerError
+}
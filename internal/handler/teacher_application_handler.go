@@ -0,0 +1,247 @@
+package handler
+
+import (
+	"errors"
+	"mime/multipart"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"study.com/v1/internal/dto"
+	"study.com/v1/internal/service"
+)
+
+type TeacherApplicationHandlerInterface interface {
+	SubmitApplication(c *fiber.Ctx) error
+	ListMyApplications(c *fiber.Ctx) error
+	UpdateMyApplication(c *fiber.Ctx) error
+	AddMyDocument(c *fiber.Ctx) error
+	ListApplications(c *fiber.Ctx) error
+	GetApplication(c *fiber.Ctx) error
+	ApproveApplication(c *fiber.Ctx) error
+	RejectApplication(c *fiber.Ctx) error
+}
+
+type TeacherApplicationHandler struct {
+	applicationService service.TeacherApplicationServiceInterface
+}
+
+func NewTeacherApplicationHandler(applicationService service.TeacherApplicationServiceInterface) *TeacherApplicationHandler {
+	return &TeacherApplicationHandler{applicationService: applicationService}
+}
+
+// SubmitApplication expects multipart/form-data with the profile fields, one "cv" file
+// and optional "credentials" files.
+func (h *TeacherApplicationHandler) SubmitApplication(c *fiber.Ctx) error {
+	var req dto.CreateTeacherApplicationDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	form, err := c.MultipartForm()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	var cv *multipart.FileHeader
+	if files := form.File["cv"]; len(files) > 0 {
+		cv = files[0]
+	}
+	userID, _ := c.Locals("user_id").(uuid.UUID)
+
+	application, err := h.applicationService.SubmitApplication(c.Context(), userID, req, cv, form.File["credentials"])
+	if err != nil {
+		return c.Status(teacherApplicationErrorStatus(err)).JSON(fiber.Map{
+			"message": "Submit application failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Application submitted",
+		"data":    application,
+	})
+}
+
+func (h *TeacherApplicationHandler) ListMyApplications(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(uuid.UUID)
+
+	applications, err := h.applicationService.ListMyApplications(c.Context(), userID)
+	if err != nil {
+		return c.Status(teacherApplicationErrorStatus(err)).JSON(fiber.Map{
+			"message": "List applications failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "List applications successfully",
+		"data":    applications,
+	})
+}
+
+func (h *TeacherApplicationHandler) UpdateMyApplication(c *fiber.Ctx) error {
+	var req dto.UpdateTeacherApplicationDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	userID, _ := c.Locals("user_id").(uuid.UUID)
+
+	application, err := h.applicationService.UpdateMyApplication(c.Context(), userID, req)
+	if err != nil {
+		return c.Status(teacherApplicationErrorStatus(err)).JSON(fiber.Map{
+			"message": "Update application failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Application updated",
+		"data":    application,
+	})
+}
+
+// AddMyDocument expects multipart/form-data with a "file" and its "doc_type" (cv or credential).
+func (h *TeacherApplicationHandler) AddMyDocument(c *fiber.Ctx) error {
+	file, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	userID, _ := c.Locals("user_id").(uuid.UUID)
+
+	application, err := h.applicationService.AddMyDocument(c.Context(), userID, c.FormValue("doc_type"), file)
+	if err != nil {
+		return c.Status(teacherApplicationErrorStatus(err)).JSON(fiber.Map{
+			"message": "Upload document failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Document uploaded",
+		"data":    application,
+	})
+}
+
+func (h *TeacherApplicationHandler) ListApplications(c *fiber.Ctx) error {
+	query := dto.TeacherApplicationQueryDTO{
+		Status:   c.Query("status", "pending"),
+		Search:   c.Query("search"),
+		Page:     c.QueryInt("page", 1),
+		PageSize: c.QueryInt("page_size", 20),
+	}
+	applications, err := h.applicationService.ListApplications(c.Context(), query)
+	if err != nil {
+		return c.Status(teacherApplicationErrorStatus(err)).JSON(fiber.Map{
+			"message": "List applications failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "List applications successfully",
+		"data":    applications,
+	})
+}
+
+func (h *TeacherApplicationHandler) GetApplication(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "application")
+	}
+	application, err := h.applicationService.GetApplication(c.Context(), id)
+	if err != nil {
+		return c.Status(teacherApplicationErrorStatus(err)).JSON(fiber.Map{
+			"message": "Get application failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Get application successfully",
+		"data":    application,
+	})
+}
+
+func (h *TeacherApplicationHandler) ApproveApplication(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "application")
+	}
+	var req dto.ReviewTeacherApplicationDTO
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"message": "Invalid request body",
+				"error":   err.Error(),
+			})
+		}
+	}
+	reviewerID, _ := c.Locals("user_id").(uuid.UUID)
+
+	application, err := h.applicationService.ApproveApplication(c.Context(), reviewerID, id, req)
+	if err != nil {
+		return c.Status(teacherApplicationErrorStatus(err)).JSON(fiber.Map{
+			"message": "Approve application failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Application approved",
+		"data":    application,
+	})
+}
+
+func (h *TeacherApplicationHandler) RejectApplication(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "application")
+	}
+	var req dto.ReviewTeacherApplicationDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	reviewerID, _ := c.Locals("user_id").(uuid.UUID)
+
+	application, err := h.applicationService.RejectApplication(c.Context(), reviewerID, id, req)
+	if err != nil {
+		return c.Status(teacherApplicationErrorStatus(err)).JSON(fiber.Map{
+			"message": "Reject application failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Application rejected",
+		"data":    application,
+	})
+}
+
+func teacherApplicationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrApplicationNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, service.ErrApplicationPending), errors.Is(err, service.ErrApplicationNotPending),
+		errors.Is(err, service.ErrAlreadyTeacher):
+		return fiber.StatusConflict
+	case errors.Is(err, service.ErrCannotReviewOwnApplication):
+		return fiber.StatusForbidden
+	case errors.Is(err, service.ErrDocumentTooLarge):
+		return fiber.StatusRequestEntityTooLarge
+	case errors.Is(err, service.ErrUnsupportedDocumentType):
+		return fiber.StatusUnsupportedMediaType
+	case errors.Is(err, service.ErrStorageUnavailable):
+		return fiber.StatusServiceUnavailable
+	case errors.Is(err, service.ErrReviewNoteRequired), errors.Is(err, service.ErrInvalidApplication),
+		errors.Is(err, service.ErrInvalidApplicationStatus), errors.Is(err, service.ErrCVRequired),
+		errors.Is(err, service.ErrTooManyDocuments), errors.Is(err, service.ErrInvalidDocumentType):
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"study.com/v1/internal/dto"
+	"study.com/v1/internal/service"
+)
+
+type UserRoleHandlerInterface interface {
+	ListUsers(c *fiber.Ctx) error
+	GetUserRoles(c *fiber.Ctx) error
+	AssignRoles(c *fiber.Ctx) error
+	RemoveRoles(c *fiber.Ctx) error
+	ListPermissionOverrides(c *fiber.Ctx) error
+	CreatePermissionOverride(c *fiber.Ctx) error
+	DeletePermissionOverride(c *fiber.Ctx) error
+}
+
+type UserRoleHandler struct {
+	userRoleService service.UserRoleServiceInterface
+}
+
+func NewUserRoleHandler(userRoleService service.UserRoleServiceInterface) *UserRoleHandler {
+	return &UserRoleHandler{userRoleService: userRoleService}
+}
+
+func (h *UserRoleHandler) ListUsers(c *fiber.Ctx) error {
+	query := dto.UserRoleQueryDTO{
+		Search:   c.Query("search"),
+		Page:     c.QueryInt("page", 1),
+		PageSize: c.QueryInt("page_size", 20),
+	}
+	if raw := c.Query("role_id"); raw != "" {
+		roleID, err := uuid.Parse(raw)
+		if err != nil {
+			return invalidIDResponse(c, "role")
+		}
+		query.RoleID = &roleID
+	}
+
+	users, err := h.userRoleService.ListUsersWithRoles(c.Context(), query)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": "List users failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "List users successfully",
+		"data":    users,
+	})
+}
+
+func (h *UserRoleHandler) GetUserRoles(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "user")
+	}
+	user, err := h.userRoleService.GetUserRoles(c.Context(), userID)
+	if err != nil {
+		return c.Status(roleErrorStatus(err)).JSON(fiber.Map{
+			"message": "Get user roles failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Get user roles successfully",
+		"data":    user,
+	})
+}
+
+func (h *UserRoleHandler) AssignRoles(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "user")
+	}
+	var req dto.AssignRolesToUserDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	assignedBy, _ := c.Locals("user_id").(uuid.UUID)
+
+	roles, err := h.userRoleService.AssignRoles(c.Context(), userID, req, assignedBy)
+	if err != nil {
+		return c.Status(roleErrorStatus(err)).JSON(fiber.Map{
+			"message": "Assign roles failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Roles assigned",
+		"data":    roles,
+	})
+}
+
+func (h *UserRoleHandler) RemoveRoles(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "user")
+	}
+	var req dto.RemoveRolesFromUserDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	if err := h.userRoleService.RemoveRoles(c.Context(), userID, req); err != nil {
+		return c.Status(roleErrorStatus(err)).JSON(fiber.Map{
+			"message": "Remove roles failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Roles removed",
+	})
+}
+
+func (h *UserRoleHandler) ListPermissionOverrides(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "user")
+	}
+	overrides, err := h.userRoleService.ListPermissionOverrides(c.Context(), userID)
+	if err != nil {
+		return c.Status(roleErrorStatus(err)).JSON(fiber.Map{
+			"message": "List permission overrides failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "List permission overrides successfully",
+		"data":    overrides,
+	})
+}
+
+func (h *UserRoleHandler) CreatePermissionOverride(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "user")
+	}
+	var req dto.CreatePermissionOverrideDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	grantedBy, _ := c.Locals("user_id").(uuid.UUID)
+
+	override, err := h.userRoleService.CreatePermissionOverride(c.Context(), userID, req, grantedBy)
+	if err != nil {
+		return c.Status(roleErrorStatus(err)).JSON(fiber.Map{
+			"message": "Create permission override failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Permission override created",
+		"data":    override,
+	})
+}
+
+func (h *UserRoleHandler) DeletePermissionOverride(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c, "user")
+	}
+	overrideID, err := uuid.Parse(c.Params("override_id"))
+	if err != nil {
+		return invalidIDResponse(c, "override")
+	}
+	if err := h.userRoleService.DeletePermissionOverride(c.Context(), userID, overrideID); err != nil {
+		return c.Status(roleErrorStatus(err)).JSON(fiber.Map{
+			"message": "Delete permission override failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Permission override deleted",
+	})
+}
@@ -0,0 +1,158 @@
+// Package markdown renders the Markdown of article lessons to HTML that is safe to insert
+// in the page of any student, and measures it for the reading time.
+package markdown
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"golang.org/x/text/unicode/norm"
+	"study.com/v1/internal/utils"
+)
+
+// Document is a rendered article. In HTML, code blocks are <pre><code class="language-x">
+// for a client side highlighter, and formulas are <span class="math math-inline">,
+// <span class="math math-display"> or <div class="math math-display"> holding the TeX
+// source, for katex.render(el.textContent, el, {displayMode}).
+type Document struct {
+	HTML string
+	// Words of prose and of code. Vietnamese is written one syllable per space separated
+	// token, so for Vietnamese text these are syllables.
+	Words     int
+	CodeWords int
+	Images    int
+}
+
+// Renderer turns Markdown into a Document. Raw HTML in the source is dropped, and the
+// output still goes through an allowlist: no scripts, styles, event handlers, iframes or
+// javascript: URLs survive whatever the parser lets through.
+type Renderer struct {
+	md             goldmark.Markdown
+	policy         *bluemonday.Policy
+	imageURLPrefix string
+}
+
+// NewRenderer returns a renderer that keeps only the images under imageURLPrefix, the
+// public URL of the images bucket. Other images are replaced by their alt text.
+func NewRenderer(imageURLPrefix string) *Renderer {
+	md := goldmark.New(
+		goldmark.WithExtensions(extension.GFM, extension.Footnote, mathExtension{}),
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+	)
+
+	policy := bluemonday.UGCPolicy()
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#.-]+$`)).OnElements("code")
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^math math-(inline|display)$`)).OnElements("span", "div")
+	// GFM task lists
+	policy.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	policy.AllowAttrs("checked", "disabled").OnElements("input")
+	policy.AddTargetBlankToFullyQualifiedLinks(true)
+
+	return &Renderer{md: md, policy: policy, imageURLPrefix: imageURLPrefix}
+}
+
+func (r *Renderer) Render(source string) (*Document, error) {
+	src := []byte(norm.NFC.String(source))
+	pc := parser.NewContext(parser.WithIDs(newHeadingIDs()))
+	root := r.md.Parser().Parse(text.NewReader(src), parser.WithContext(pc))
+
+	doc := &Document{}
+	r.measure(root, src, doc)
+
+	var buf bytes.Buffer
+	if err := r.md.Renderer().Render(&buf, src, root); err != nil {
+		return nil, err
+	}
+	doc.HTML = r.policy.Sanitize(buf.String())
+	return doc, nil
+}
+
+// measure counts words and images, and unwraps the images from other hosts so that their
+// alt text stays in the article.
+func (r *Renderer) measure(root ast.Node, src []byte, doc *Document) {
+	var foreign []*ast.Image
+	_ = ast.Walk(root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := n.(type) {
+		case *ast.Image:
+			if strings.HasPrefix(string(n.Destination), r.imageURLPrefix) {
+				// Images are timed as a whole, their alt text is not read
+				doc.Images++
+				return ast.WalkSkipChildren, nil
+			}
+			foreign = append(foreign, n)
+		case *ast.Text:
+			doc.Words += CountWords(string(n.Segment.Value(src)))
+		case *ast.FencedCodeBlock, *ast.CodeBlock:
+			lines := n.Lines()
+			for i := 0; i < lines.Len(); i++ {
+				segment := lines.At(i)
+				doc.CodeWords += CountWords(string(segment.Value(src)))
+			}
+			return ast.WalkSkipChildren, nil
+		case *Math, *MathBlock:
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+
+	for _, img := range foreign {
+		parent := img.Parent()
+		for child := img.FirstChild(); child != nil; {
+			next := child.NextSibling()
+			parent.InsertBefore(parent, img, child)
+			child = next
+		}
+		parent.RemoveChild(parent, img)
+	}
+}
+
+// CountWords counts the space separated tokens that hold a letter or a digit, so
+// punctuation on its own is not a word.
+func CountWords(s string) int {
+	count := 0
+	for _, token := range strings.FieldsFunc(s, unicode.IsSpace) {
+		if strings.IndexFunc(token, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) >= 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// headingIDs gives headings ASCII anchors, "Giới thiệu" becomes #gioi-thieu, which the
+// sanitizer keeps. Repeated headings get -1, -2...
+type headingIDs struct {
+	used map[string]bool
+}
+
+func newHeadingIDs() *headingIDs {
+	return &headingIDs{used: map[string]bool{}}
+}
+
+func (h *headingIDs) Generate(value []byte, kind ast.NodeKind) []byte {
+	base := utils.Slugify(string(value))
+	if base == "" {
+		base = "section"
+	}
+	id := base
+	for i := 1; h.used[id]; i++ {
+		id = base + "-" + strconv.Itoa(i)
+	}
+	h.used[id] = true
+	return []byte(id)
+}
+
+func (h *headingIDs) Put(value []byte) {
+	h.used[string(value)] = true
+}
@@ -0,0 +1,144 @@
+package markdown_test
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/text/unicode/norm"
+	"study.com/v1/internal/markdown"
+)
+
+const imagePrefix = "https://cdn.example.com/images/"
+
+func render(t *testing.T, source string) *markdown.Document {
+	t.Helper()
+	doc, err := markdown.NewRenderer(imagePrefix).Render(source)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	return doc
+}
+
+func TestRenderSanitizes(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "script tag",
+			source:  "<script>alert(1)</script>\n\nhi <script>alert(2)</script>",
+			want:    []string{"hi"},
+			notWant: []string{"<script"},
+		},
+		{
+			name:    "javascript link",
+			source:  "[click](javascript:alert(1)) [upper](JaVaScRiPt:alert(1))",
+			want:    []string{"click", "upper"},
+			notWant: []string{"href", "javascript:"},
+		},
+		{
+			name:    "javascript autolink and reference",
+			source:  "<javascript:alert(1)>\n\n[ref][x]\n\n[x]: javascript:alert(2)",
+			want:    []string{"ref"},
+			notWant: []string{"href"},
+		},
+		{
+			name:    "data link",
+			source:  "[click](data:text/html;base64,PHNjcmlwdD5hbGVydCgxKTwvc2NyaXB0Pg==)",
+			want:    []string{"click"},
+			notWant: []string{"href", "data:"},
+		},
+		{
+			name:    "raw img with onerror",
+			source:  "before <img src=x onerror=alert(1)> after\n\n<img src=\"" + imagePrefix + "a.png\" onerror=\"alert(1)\">",
+			want:    []string{"before", "after"},
+			notWant: []string{"onerror", "alert", "<img"},
+		},
+		{
+			name:    "raw math span with extra attributes",
+			source:  `<span class="math math-inline" onclick="alert(1)" style="color:red">x</span>`,
+			notWant: []string{"onclick", "style", "alert", "<span"},
+		},
+		{
+			name:    "math source breaking out of its span",
+			source:  `$x" onmouseover="alert(1)$ and $$</span><script>alert(2)</script>$$`,
+			want:    []string{`<span class="math math-inline">`, `<span class="math math-display">&lt;/span&gt;`},
+			notWant: []string{`" onmouseover`, "<script"},
+		},
+		{
+			name:    "foreign image",
+			source:  "![mèo con](https://evil.example.org/cat.png)",
+			want:    []string{"mèo con"},
+			notWant: []string{"<img", "evil.example.org"},
+		},
+		{
+			name:    "foreign host sharing the prefix",
+			source:  "![ảnh](https://cdn.example.com/images.evil.org/a.png)",
+			want:    []string{"ảnh"},
+			notWant: []string{"<img", "evil.org"},
+		},
+		{
+			name:   "image of the bucket",
+			source: "![sơ đồ](" + imagePrefix + "diagram.png)",
+			want:   []string{`<img src="` + imagePrefix + `diagram.png" alt="sơ đồ">`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			html := render(t, tt.source).HTML
+			for _, s := range tt.want {
+				if !strings.Contains(html, s) {
+					t.Errorf("%q is missing from %q", s, html)
+				}
+			}
+			lower := strings.ToLower(html)
+			for _, s := range tt.notWant {
+				if strings.Contains(lower, strings.ToLower(s)) {
+					t.Errorf("%q survived in %q", s, html)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderCountsVietnamese(t *testing.T) {
+	tests := []struct {
+		name      string
+		source    string
+		words     int
+		codeWords int
+		images    int
+	}{
+		{name: "syllables", source: "Xin chào các bạn, hôm nay chúng ta học lập trình Go!", words: 12},
+		{name: "punctuation alone", source: "Đúng vậy — thế thôi … !", words: 4},
+		{name: "decomposed diacritics", source: norm.NFD.String("Tiếng Việt có dấu"), words: 4},
+		{name: "code is counted apart", source: "Chạy lệnh:\n\n```go\nfmt.Println(\"xin chào\")\n```", words: 2, codeWords: 2},
+		{name: "formulas are not read", source: "Công thức $a^2 + b^2 = c^2$ nổi tiếng", words: 4},
+		{
+			name:   "bucket images count, not their alt",
+			source: "Hình vẽ ![sơ đồ lớp học](" + imagePrefix + "a.png) và ![ảnh ngoài](https://evil.example.org/b.png)",
+			words:  5,
+			images: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := render(t, tt.source)
+			if doc.Words != tt.words || doc.CodeWords != tt.codeWords || doc.Images != tt.images {
+				t.Errorf("got words=%d code=%d images=%d, want words=%d code=%d images=%d",
+					doc.Words, doc.CodeWords, doc.Images, tt.words, tt.codeWords, tt.images)
+			}
+		})
+	}
+}
+
+// The Markdown is normalized first, so text typed with combining marks renders the same.
+func TestRenderNormalizesToNFC(t *testing.T) {
+	composed := render(t, "Tiếng Việt").HTML
+	decomposed := render(t, norm.NFD.String("Tiếng Việt")).HTML
+	if composed != decomposed {
+		t.Errorf("got %q for decomposed input, want %q", decomposed, composed)
+	}
+}
@@ -0,0 +1,178 @@
+package markdown
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// KindMath is the node of a TeX formula: $...$ inline, $$...$$ or a ```math block for
+// display.
+var KindMath = ast.NewNodeKind("Math")
+
+// Math holds the TeX source of a formula. It is rendered as an element the client typesets
+// with KaTeX, see Document.
+type Math struct {
+	ast.BaseInline
+	Display bool
+	Value   []byte
+}
+
+func (n *Math) Kind() ast.NodeKind { return KindMath }
+
+func (n *Math) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Value": string(n.Value)}, nil)
+}
+
+// KindMathBlock is a ```math fenced block.
+var KindMathBlock = ast.NewNodeKind("MathBlock")
+
+type MathBlock struct {
+	ast.BaseBlock
+	Value []byte
+}
+
+func (n *MathBlock) Kind() ast.NodeKind { return KindMathBlock }
+
+func (n *MathBlock) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Value": string(n.Value)}, nil)
+}
+
+// mathExtension adds the formulas to goldmark. Inline dollar signs follow Pandoc, and the
+// next $ must be the closing one: the opening $ is not followed by a space and the closing
+// one is neither preceded by a space nor followed by a digit, so "từ $5 đến $10" stays
+// text. \$ is a literal dollar.
+type mathExtension struct{}
+
+func (mathExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithInlineParsers(util.Prioritized(&mathInlineParser{}, 500)),
+		parser.WithASTTransformers(util.Prioritized(&mathBlockTransformer{}, 500)),
+	)
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(util.Prioritized(&mathRenderer{}, 500)))
+}
+
+type mathInlineParser struct{}
+
+func (p *mathInlineParser) Trigger() []byte {
+	return []byte{'$'}
+}
+
+func (p *mathInlineParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	line, _ := block.PeekLine()
+	delim := 1
+	if len(line) > 1 && line[1] == '$' {
+		delim = 2
+	}
+	if len(line) <= delim || (delim == 1 && util.IsSpace(line[1])) {
+		return nil
+	}
+	block.Advance(delim)
+
+	// Display formulas may span the lines of a paragraph, inline ones may not
+	var value []byte
+	for {
+		line, _ := block.PeekLine()
+		if line == nil {
+			return nil
+		}
+		if end := closingDollars(line, delim); end >= 0 {
+			value = append(value, line[:end]...)
+			block.Advance(end + delim)
+			break
+		}
+		if delim == 1 {
+			return nil
+		}
+		value = append(value, line...)
+		block.AdvanceLine()
+	}
+	if len(bytes.TrimSpace(value)) == 0 {
+		return nil
+	}
+	return &Math{Display: delim == 2, Value: value}
+}
+
+// closingDollars returns where the closing delimiter starts in line, or -1.
+func closingDollars(line []byte, delim int) int {
+	for i := 0; i+delim <= len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '$':
+			if delim == 2 && (i+1 >= len(line) || line[i+1] != '$') {
+				continue
+			}
+			if delim == 1 {
+				if i == 0 || util.IsSpace(line[i-1]) {
+					return -1
+				}
+				if i+1 < len(line) && line[i+1] >= '0' && line[i+1] <= '9' {
+					return -1
+				}
+			}
+			return i
+		}
+	}
+	return -1
+}
+
+// mathBlockTransformer turns ```math fenced blocks into MathBlock nodes.
+type mathBlockTransformer struct{}
+
+func (t *mathBlockTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+	var blocks []*ast.FencedCodeBlock
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if code, ok := n.(*ast.FencedCodeBlock); ok && entering && string(code.Language(source)) == "math" {
+			blocks = append(blocks, code)
+		}
+		return ast.WalkContinue, nil
+	})
+	for _, code := range blocks {
+		math := &MathBlock{}
+		lines := code.Lines()
+		for i := 0; i < lines.Len(); i++ {
+			segment := lines.At(i)
+			math.Value = append(math.Value, segment.Value(source)...)
+		}
+		code.Parent().ReplaceChild(code.Parent(), code, math)
+	}
+}
+
+type mathRenderer struct{}
+
+func (r *mathRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindMath, r.renderMath)
+	reg.Register(KindMathBlock, r.renderMathBlock)
+}
+
+func (r *mathRenderer) renderMath(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	math := n.(*Math)
+	class := "math math-inline"
+	if math.Display {
+		class = "math math-display"
+	}
+	_, _ = w.WriteString(`<span class="` + class + `">`)
+	_, _ = w.Write(util.EscapeHTML(bytes.TrimSpace(math.Value)))
+	_, _ = w.WriteString("</span>")
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *mathRenderer) renderMathBlock(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	_, _ = w.WriteString(`<div class="math math-display">`)
+	_, _ = w.Write(util.EscapeHTML(bytes.TrimSpace(n.(*MathBlock).Value)))
+	_, _ = w.WriteString("</div>\n")
+	return ast.WalkSkipChildren, nil
+}
@@ -0,0 +1,265 @@
+// Package media wraps the ffmpeg and ffprobe binaries that turn uploaded lesson videos into
+// HLS renditions, and extract the audio that transcription reads.
+package media
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ErrNoVideoStream is returned by Probe for files ffprobe can read but that hold no video.
+var ErrNoVideoStream = errors.New("the file has no video stream")
+
+type FFmpeg struct {
+	ffmpegPath  string
+	ffprobePath string
+}
+
+func NewFFmpeg(ffmpegPath, ffprobePath string) *FFmpeg {
+	return &FFmpeg{ffmpegPath: ffmpegPath, ffprobePath: ffprobePath}
+}
+
+// ProbeResult describes the source video. Width and Height are as displayed, after the
+// rotation phones record in the metadata.
+type ProbeResult struct {
+	DurationSeconds float64
+	Width           int
+	Height          int
+	HasAudio        bool
+}
+
+// Resolution is the display size, e.g. "1920x1080".
+func (p *ProbeResult) Resolution() string {
+	return fmt.Sprintf("%dx%d", p.Width, p.Height)
+}
+
+type probeOutput struct {
+	Format struct {
+		Duration string `json:"duration"`
+	} `json:"format"`
+	Streams []struct {
+		CodecType string            `json:"codec_type"`
+		Width     int               `json:"width"`
+		Height    int               `json:"height"`
+		Tags      map[string]string `json:"tags"`
+		SideData  []struct {
+			Rotation int `json:"rotation"`
+		} `json:"side_data_list"`
+	} `json:"streams"`
+}
+
+func (f *FFmpeg) Probe(ctx context.Context, input string) (*ProbeResult, error) {
+	out, err := run(ctx, f.ffprobePath, "-v", "error", "-print_format", "json", "-show_format", "-show_streams", input)
+	if err != nil {
+		return nil, fmt.Errorf("ffprobe: %w", err)
+	}
+	var probe probeOutput
+	if err := json.Unmarshal(out, &probe); err != nil {
+		return nil, fmt.Errorf("ffprobe: %w", err)
+	}
+
+	result := &ProbeResult{}
+	result.DurationSeconds, _ = strconv.ParseFloat(probe.Format.Duration, 64)
+	for _, s := range probe.Streams {
+		switch s.CodecType {
+		case "video":
+			if result.Width > 0 {
+				continue
+			}
+			result.Width, result.Height = s.Width, s.Height
+			rotation, _ := strconv.Atoi(s.Tags["rotate"])
+			for _, d := range s.SideData {
+				if d.Rotation != 0 {
+					rotation = d.Rotation
+				}
+			}
+			if rotation%180 != 0 {
+				result.Width, result.Height = result.Height, result.Width
+			}
+		case "audio":
+			result.HasAudio = true
+		}
+	}
+	if result.Width == 0 || result.Height == 0 || result.DurationSeconds <= 0 {
+		return nil, ErrNoVideoStream
+	}
+	return result, nil
+}
+
+// Rendition is one rung of the HLS ladder. Size is the shorter side of the picture, so
+// portrait videos get the same quality steps as landscape ones.
+type Rendition struct {
+	Name         string
+	Size         int
+	VideoBitrate int // kbit/s
+	AudioBitrate int // kbit/s
+}
+
+// DefaultLadder is ordered from the best rendition down.
+var DefaultLadder = []Rendition{
+	{Name: "1080p", Size: 1080, VideoBitrate: 5000, AudioBitrate: 192},
+	{Name: "720p", Size: 720, VideoBitrate: 2800, AudioBitrate: 128},
+	{Name: "480p", Size: 480, VideoBitrate: 1400, AudioBitrate: 128},
+	{Name: "360p", Size: 360, VideoBitrate: 800, AudioBitrate: 96},
+}
+
+// LadderFor keeps the renditions that do not upscale the source. A source smaller than the
+// lowest rung is kept at its own size.
+func LadderFor(source *ProbeResult, ladder []Rendition) []Rendition {
+	short := min(source.Width, source.Height)
+	var renditions []Rendition
+	for _, r := range ladder {
+		if r.Size <= short {
+			renditions = append(renditions, r)
+		}
+	}
+	if len(renditions) == 0 && len(ladder) > 0 {
+		lowest := ladder[len(ladder)-1]
+		lowest.Name = strconv.Itoa(short) + "p"
+		lowest.Size = short - short%2
+		renditions = append(renditions, lowest)
+	}
+	return renditions
+}
+
+// Variant is a rendition as transcoded, listed in the master playlist.
+type Variant struct {
+	Rendition
+	Width    int
+	Height   int
+	HasAudio bool
+	Playlist string // relative to the output directory, e.g. "720p/index.m3u8"
+}
+
+// TranscodeHLS encodes one VOD playlist per rendition under outputDir/<name>/. Keyframes
+// are forced on segment boundaries so players can switch renditions between segments.
+func (f *FFmpeg) TranscodeHLS(ctx context.Context, input string, source *ProbeResult, renditions []Rendition, outputDir string, segmentSeconds int) ([]Variant, error) {
+	variants := make([]Variant, 0, len(renditions))
+	for _, r := range renditions {
+		width, height := scaledSize(source, r.Size)
+		dir := filepath.Join(outputDir, r.Name)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+
+		args := []string{
+			"-hide_banner", "-loglevel", "error", "-y",
+			"-i", input,
+			"-map", "0:v:0", "-map", "0:a:0?",
+			"-vf", fmt.Sprintf("scale=%d:%d", width, height),
+			"-c:v", "libx264", "-preset", "veryfast", "-profile:v", "main", "-pix_fmt", "yuv420p",
+			"-b:v", fmt.Sprintf("%dk", r.VideoBitrate),
+			"-maxrate", fmt.Sprintf("%dk", r.VideoBitrate*107/100),
+			"-bufsize", fmt.Sprintf("%dk", r.VideoBitrate*3/2),
+			"-force_key_frames", fmt.Sprintf("expr:gte(t,n_forced*%d)", segmentSeconds),
+			"-sc_threshold", "0",
+			"-c:a", "aac", "-b:a", fmt.Sprintf("%dk", r.AudioBitrate), "-ac", "2",
+			"-f", "hls",
+			"-hls_time", strconv.Itoa(segmentSeconds),
+			"-hls_playlist_type", "vod",
+			"-hls_segment_filename", filepath.Join(dir, "segment_%05d.ts"),
+			filepath.Join(dir, "index.m3u8"),
+		}
+		if _, err := run(ctx, f.ffmpegPath, args...); err != nil {
+			return nil, fmt.Errorf("ffmpeg %s: %w", r.Name, err)
+		}
+		variants = append(variants, Variant{
+			Rendition: r,
+			Width:     width,
+			Height:    height,
+			HasAudio:  source.HasAudio,
+			Playlist:  r.Name + "/index.m3u8",
+		})
+	}
+	return variants, nil
+}
+
+// WriteMasterPlaylist writes the playlist players open first, listing every variant.
+func WriteMasterPlaylist(path string, variants []Variant) error {
+	var b strings.Builder
+	b.WriteString("#EXTM3U\n#EXT-X-VERSION:3\n")
+	for _, v := range variants {
+		bitrate := v.VideoBitrate
+		codecs := "avc1.4d4028"
+		if v.HasAudio {
+			bitrate += v.AudioBitrate
+			codecs += ",mp4a.40.2"
+		}
+		fmt.Fprintf(&b, "#EXT-X-STREAM-INF:BANDWIDTH=%d,AVERAGE-BANDWIDTH=%d,RESOLUTION=%dx%d,CODECS=\"%s\"\n%s\n",
+			bitrate*1070, bitrate*1000, v.Width, v.Height, codecs, v.Playlist)
+	}
+	return os.WriteFile(path, []byte(b.String()), 0o644)
+}
+
+// Thumbnail grabs one frame at the given time as a JPEG whose shorter side is size pixels.
+func (f *FFmpeg) Thumbnail(ctx context.Context, input string, source *ProbeResult, atSeconds float64, size int, output string) error {
+	width, height := scaledSize(source, min(size, min(source.Width, source.Height)))
+	_, err := run(ctx, f.ffmpegPath,
+		"-hide_banner", "-loglevel", "error", "-y",
+		"-ss", strconv.FormatFloat(atSeconds, 'f', 2, 64),
+		"-i", input,
+		"-frames:v", "1",
+		"-vf", fmt.Sprintf("scale=%d:%d", width, height),
+		"-q:v", "3",
+		output,
+	)
+	if err != nil {
+		return fmt.Errorf("ffmpeg thumbnail: %w", err)
+	}
+	return nil
+}
+
+// ExtractAudio writes the first audio track as 16 kHz mono 16-bit PCM WAV, the input
+// speech recognition models expect.
+func (f *FFmpeg) ExtractAudio(ctx context.Context, input, output string) error {
+	_, err := run(ctx, f.ffmpegPath,
+		"-hide_banner", "-loglevel", "error", "-y",
+		"-i", input,
+		"-map", "0:a:0", "-vn",
+		"-ar", "16000", "-ac", "1", "-c:a", "pcm_s16le",
+		output,
+	)
+	if err != nil {
+		return fmt.Errorf("ffmpeg audio: %w", err)
+	}
+	return nil
+}
+
+// scaledSize scales the source so its shorter side is size, keeping the aspect ratio and
+// both sides even as H.264 requires.
+func scaledSize(source *ProbeResult, size int) (int, int) {
+	even := func(v float64) int { return int(math.Round(v/2)) * 2 }
+	if source.Width >= source.Height {
+		return even(float64(source.Width) * float64(size) / float64(source.Height)), even(float64(size))
+	}
+	return even(float64(size)), even(float64(source.Height) * float64(size) / float64(source.Width))
+}
+
+// run executes a binary and returns its stdout. On failure the end of stderr, where ffmpeg
+// explains what went wrong, is part of the error.
+func run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if len(msg) > 1000 {
+			msg = "..." + msg[len(msg)-1000:]
+		}
+		if msg == "" {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %s", err, msg)
+	}
+	return stdout.Bytes(), nil
+}
@@ -1,33 +1,44 @@
 package middleware
 
 import (
-	"fmt"
+	"context"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
+	"study.com/v1/internal/cache"
 	"study.com/v1/internal/config"
 	"study.com/v1/internal/utils"
 )
 
+const (
+	AuthSourceHeader = "header"
+	AuthSourceCookie = "cookie"
+)
+
 func AuthMiddleware(cfg *config.Config, rdb *redis.Client) fiber.Handler {
+	sessions := cache.NewSessionStore(rdb, cfg.JWTRefreshExpiration)
+
 	return func(c *fiber.Ctx) error {
 
-		accessToken := c.Cookies("accessToken")
+		accessToken, source := extractAccessToken(c, cfg)
 		if accessToken == "" {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 				"error": "Missing access token",
 			})
 		}
 
-		claims, err := utils.ParseToken(cfg, accessToken)
+		claims, err := utils.ParseToken(cfg, accessToken, utils.TokenTypeAccess)
 		if err != nil {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 				"error": "Invalid or expired token",
 			})
 		}
 
-		key := fmt.Sprintf("session_version:%s", claims.UserID)
+		key := cache.SessionVersionKey(claims.UserID)
 
 		versionStr, err := rdb.Get(c.Context(), key).Result()
 		if err == redis.Nil {
@@ -58,16 +69,102 @@ func AuthMiddleware(cfg *config.Config, rdb *redis.Client) fiber.Handler {
 			})
 		}
 
+		// Logging out one device removes only its session record, so the token
+		// version alone is not enough to tell whether this device is still signed in.
+		exists, err := sessions.Exists(c.Context(), claims.UserID, claims.DeviceID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"message": "Redis connection error",
+				"error":   err.Error(),
+			})
+		}
+		if !exists {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"message": "Session revoked",
+				"error":   "Please login again",
+			})
+		}
+		_ = sessions.Touch(c.Context(), claims.UserID, claims.DeviceID, time.Now())
+
 		c.Locals("user_id", claims.UserID)
 		c.Locals("device_id", claims.DeviceID)
 		c.Locals("version", claims.Version)
+		c.Locals("auth_source", source)
 
 		return c.Next()
 	}
 }
 
-func RequirePermissions(permissions ...string) fiber.Handler {
+// extractAccessToken reads the access token from the Authorization header or the
+// accessToken cookie. When both are present cfg.AuthTokenPrecedence decides which wins.
+func extractAccessToken(c *fiber.Ctx, cfg *config.Config) (string, string) {
+	cookieToken := c.Cookies(utils.AccessTokenCookie)
+
+	var headerToken string
+	if cfg.AuthAllowBearer {
+		header := c.Get(fiber.HeaderAuthorization)
+		if len(header) > 7 && strings.EqualFold(header[:7], "Bearer ") {
+			headerToken = strings.TrimSpace(header[7:])
+		}
+	}
+
+	switch {
+	case headerToken != "" && cookieToken != "":
+		if cfg.AuthTokenPrecedence == AuthSourceCookie {
+			return cookieToken, AuthSourceCookie
+		}
+		return headerToken, AuthSourceHeader
+	case headerToken != "":
+		return headerToken, AuthSourceHeader
+	default:
+		return cookieToken, AuthSourceCookie
+	}
+}
+
+// PermissionChecker is implemented by service.AuthorizationService.
+type PermissionChecker interface {
+	HasAllPermissions(ctx context.Context, userID uuid.UUID, permissions ...string) (bool, error)
+	HasAnyPermission(ctx context.Context, userID uuid.UUID, permissions ...string) (bool, error)
+}
+
+// RequirePermissions lets the request through only if the user holds every listed permission.
+// It must be mounted after AuthMiddleware.
+func RequirePermissions(checker PermissionChecker, permissions ...string) fiber.Handler {
+	return requirePermissions(checker.HasAllPermissions, permissions)
+}
+
+// RequireAnyPermission lets the request through if the user holds at least one listed permission.
+// It must be mounted after AuthMiddleware.
+func RequireAnyPermission(checker PermissionChecker, permissions ...string) fiber.Handler {
+	return requirePermissions(checker.HasAnyPermission, permissions)
+}
+
+func requirePermissions(
+	check func(ctx context.Context, userID uuid.UUID, permissions ...string) (bool, error),
+	permissions []string,
+) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		return nil
+		userID, ok := c.Locals("user_id").(uuid.UUID)
+		if !ok || userID == uuid.Nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Missing access token",
+			})
+		}
+
+		allowed, err := check(c.Context(), userID, permissions...)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"message": "Failed to check permissions",
+				"error":   err.Error(),
+			})
+		}
+		if !allowed {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"message": "Permission denied",
+				"error":   "You do not have permission to perform this action",
+			})
+		}
+
+		return c.Next()
 	}
 }
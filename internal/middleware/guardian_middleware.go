@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// GuardianChecker is implemented by service.ParentService.
+type GuardianChecker interface {
+	IsParentLinkRequired(ctx context.Context, userID uuid.UUID) (bool, error)
+}
+
+// RequireParentLink blocks students under the minor age until they have an active parent link.
+// It must be mounted after AuthMiddleware.
+func RequireParentLink(checker GuardianChecker) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, ok := c.Locals("user_id").(uuid.UUID)
+		if !ok || userID == uuid.Nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Missing access token",
+			})
+		}
+
+		required, err := checker.IsParentLinkRequired(c.Context(), userID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"message": "Failed to check parent link",
+				"error":   err.Error(),
+			})
+		}
+		if required {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"message": "Parent link required",
+				"error":   "Students under the minor age must link a parent account first",
+			})
+		}
+
+		return c.Next()
+	}
+}
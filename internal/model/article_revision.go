@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LessonArticleRevision là một phiên bản Markdown của bài đọc, ghi lại mỗi lần tác giả lưu
+// để có thể so sánh hoặc khôi phục. Phiên bản mới nhất trùng với LessonArticle.Content.
+type LessonArticleRevision struct {
+	ID              uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	CreatedAt       time.Time  `json:"created_at"`
+	ArticleID       uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_lesson_article_revisions_article_version" json:"article_id"`
+	Version         int        `gorm:"not null;uniqueIndex:idx_lesson_article_revisions_article_version" json:"version"`
+	AuthorID        *uuid.UUID `gorm:"type:uuid" json:"author_id,omitempty"`
+	Content         string     `gorm:"type:text;not null" json:"content"`
+	ReadingTimeMins int        `gorm:"not null;column:reading_time_minutes" json:"reading_time_minutes"`
+	RestoredFrom    *int       `json:"restored_from,omitempty"` // phiên bản được khôi phục, NULL nếu là bản sửa thường
+
+	// Relationships
+	Article LessonArticle `gorm:"foreignKey:ArticleID;constraint:OnDelete:CASCADE" json:"-"`
+	Author  *User         `gorm:"foreignKey:AuthorID;constraint:OnDelete:SET NULL" json:"-"`
+}
+
+func (LessonArticleRevision) TableName() string {
+	return "lesson_article_revisions"
+}
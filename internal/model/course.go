@@ -11,19 +11,21 @@ import (
 
 type Category struct {
 	gorm.Model
-	ID           uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
-	ParentID     *uuid.UUID `gorm:"type:uuid;index" json:"parent_id,omitempty"`
-	Name         string     `gorm:"type:varchar(100);not null" json:"name"`
-	Slug         string     `gorm:"type:varchar(100);uniqueIndex;not null" json:"slug"`
-	Description  *string    `gorm:"type:text" json:"description,omitempty"`
-	IconURL      *string    `gorm:"type:varchar(500);column:icon_url" json:"icon_url,omitempty"`
-	DisplayOrder int        `gorm:"default:0" json:"display_order"`
-	IsActive     bool       `gorm:"default:true" json:"is_active"`
+	ID             uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	OrganizationID *uuid.UUID `gorm:"type:uuid;index;uniqueIndex:idx_categories_org_slug,priority:1,where:organization_id IS NOT NULL" json:"organization_id,omitempty"` // NULL = danh mục toàn hệ thống
+	ParentID       *uuid.UUID `gorm:"type:uuid;index" json:"parent_id,omitempty"`
+	Name           string     `gorm:"type:varchar(100);not null" json:"name"`
+	Slug           string     `gorm:"type:varchar(100);not null;uniqueIndex:idx_categories_slug,where:organization_id IS NULL;uniqueIndex:idx_categories_org_slug,priority:2" json:"slug"`
+	Description    *string    `gorm:"type:text" json:"description,omitempty"`
+	IconURL        *string    `gorm:"type:varchar(500);column:icon_url" json:"icon_url,omitempty"`
+	DisplayOrder   int        `gorm:"default:0" json:"display_order"`
+	IsActive       bool       `gorm:"default:true" json:"is_active"`
 
 	// Relationships
-	Parent   *Category  `gorm:"foreignKey:ParentID" json:"-"`
-	Children []Category `gorm:"foreignKey:ParentID" json:"-"`
-	Courses  []Course   `gorm:"foreignKey:CategoryID" json:"-"`
+	Organization *Organization `gorm:"foreignKey:OrganizationID;constraint:OnDelete:CASCADE" json:"-"`
+	Parent       *Category     `gorm:"foreignKey:ParentID" json:"-"`
+	Children     []Category    `gorm:"foreignKey:ParentID" json:"-"`
+	Courses      []Course      `gorm:"foreignKey:CategoryID" json:"-"`
 }
 
 func (Category) TableName() string {
@@ -44,10 +46,35 @@ func (Tag) TableName() string {
 	return "tags"
 }
 
+// Trạng thái khoá học
+const (
+	CourseStatusDraft         = "draft"
+	CourseStatusPendingReview = "pending_review"
+	CourseStatusPublished     = "published"
+	CourseStatusArchived      = "archived"
+)
+
+// Trình độ khoá học
+const (
+	CourseLevelBeginner     = "beginner"
+	CourseLevelIntermediate = "intermediate"
+	CourseLevelAdvanced     = "advanced"
+	CourseLevelAllLevels    = "all_levels"
+)
+
+// Loại nội dung bài học
+const (
+	LessonContentVideo      = "video"
+	LessonContentArticle    = "article"
+	LessonContentQuiz       = "quiz"
+	LessonContentAssignment = "assignment"
+)
+
 type Course struct {
 	gorm.Model
 	ID                uuid.UUID        `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
 	InstructorID      uuid.UUID        `gorm:"type:uuid;not null;index" json:"instructor_id"`
+	OrganizationID    *uuid.UUID       `gorm:"type:uuid;index" json:"organization_id,omitempty"` // NULL = khoá học cá nhân của giảng viên
 	CategoryID        *uuid.UUID       `gorm:"type:uuid;index" json:"category_id,omitempty"`
 	Title             string           `gorm:"type:varchar(255);not null" json:"title"`
 	Slug              string           `gorm:"type:varchar(255);uniqueIndex;not null" json:"slug"`
@@ -74,12 +101,13 @@ type Course struct {
 	IsFree            bool             `gorm:"default:false" json:"is_free"`
 
 	// Relationships
-	Instructor  User         `gorm:"foreignKey:InstructorID" json:"-"`
-	Category    *Category    `gorm:"foreignKey:CategoryID" json:"-"`
-	Tags        []Tag        `gorm:"many2many:course_tags" json:"-"`
-	Sections    []Section    `gorm:"foreignKey:CourseID;constraint:OnDelete:CASCADE" json:"-"`
-	Enrollments []Enrollment `gorm:"foreignKey:CourseID;constraint:OnDelete:CASCADE" json:"-"`
-	Quizzes     []Quiz       `gorm:"foreignKey:CourseID" json:"-"`
+	Instructor   User          `gorm:"foreignKey:InstructorID" json:"-"`
+	Organization *Organization `gorm:"foreignKey:OrganizationID;constraint:OnDelete:SET NULL" json:"-"`
+	Category     *Category     `gorm:"foreignKey:CategoryID" json:"-"`
+	Tags         []Tag         `gorm:"many2many:course_tags" json:"-"`
+	Sections     []Section     `gorm:"foreignKey:CourseID;constraint:OnDelete:CASCADE" json:"-"`
+	Enrollments  []Enrollment  `gorm:"foreignKey:CourseID;constraint:OnDelete:CASCADE" json:"-"`
+	Quizzes      []Quiz        `gorm:"foreignKey:CourseID" json:"-"`
 }
 
 func (Course) TableName() string {
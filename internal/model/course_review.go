@@ -0,0 +1,37 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Các thao tác chuyển trạng thái khoá học
+const (
+	CourseActionSubmit   = "submit"   // draft -> pending_review, giảng viên gửi duyệt
+	CourseActionWithdraw = "withdraw" // pending_review -> draft, giảng viên rút lại
+	CourseActionApprove  = "approve"  // pending_review -> published
+	CourseActionReject   = "reject"   // pending_review -> draft, kèm nhận xét
+	CourseActionArchive  = "archive"  // published -> archived, học viên đã ghi danh vẫn học được
+	CourseActionRestore  = "restore"  // archived -> draft, phải gửi duyệt lại
+)
+
+// CourseStatusChange là lịch sử chuyển trạng thái của khoá học, mỗi lần chuyển ghi một dòng.
+type CourseStatusChange struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	CreatedAt  time.Time  `json:"created_at"`
+	CourseID   uuid.UUID  `gorm:"type:uuid;not null;index" json:"course_id"`
+	ActorID    *uuid.UUID `gorm:"type:uuid" json:"actor_id,omitempty"` // NULL khi tài khoản đã bị xoá
+	Action     string     `gorm:"type:varchar(20);not null;check:action IN ('submit', 'withdraw', 'approve', 'reject', 'archive', 'restore')" json:"action"`
+	FromStatus string     `gorm:"type:varchar(20);not null" json:"from_status"`
+	ToStatus   string     `gorm:"type:varchar(20);not null" json:"to_status"`
+	Comment    *string    `gorm:"type:text" json:"comment,omitempty"`
+
+	// Relationships
+	Course Course `gorm:"foreignKey:CourseID;constraint:OnDelete:CASCADE" json:"-"`
+	Actor  *User  `gorm:"foreignKey:ActorID;constraint:OnDelete:SET NULL" json:"-"`
+}
+
+func (CourseStatusChange) TableName() string {
+	return "course_status_changes"
+}
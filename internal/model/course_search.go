@@ -0,0 +1,24 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CourseSearchDocument là chỉ mục tìm kiếm toàn văn của một khoá học. Bảng này do trigger
+// trong database (migration 0007) cập nhật mỗi khi khoá học, tag hoặc bài học thay đổi,
+// code Go chỉ đọc.
+type CourseSearchDocument struct {
+	CourseID   uuid.UUID `gorm:"type:uuid;primaryKey" json:"course_id"`
+	Document   string    `gorm:"type:tsvector;not null" json:"-"` // tiêu đề (A), tag (B), mô tả ngắn và tên bài học (C), mô tả (D)
+	SearchText string    `gorm:"type:text;not null" json:"-"`     // tiêu đề và tag đã bỏ dấu, dùng cho tìm kiếm gần đúng (trigram)
+	UpdatedAt  time.Time `json:"updated_at"`
+
+	// Relationships
+	Course Course `gorm:"foreignKey:CourseID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+func (CourseSearchDocument) TableName() string {
+	return "course_search_documents"
+}
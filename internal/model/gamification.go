@@ -73,14 +73,14 @@ func (PointRule) TableName() string {
 type DailyCheckin struct {
 	ID           uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
 	CreatedAt    time.Time `json:"created_at"`
-	UserID       uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
+	UserID       uuid.UUID `gorm:"type:uuid;not null;index;uniqueIndex:idx_user_checkin_date,priority:1" json:"user_id"`
 	CheckinDate  time.Time `gorm:"type:date;not null;index;uniqueIndex:idx_user_checkin_date,priority:2" json:"checkin_date"`
 	StreakCount  int       `gorm:"default:1" json:"streak_count"` // Số ngày liên tiếp tính đến ngày này
 	PointsEarned int       `gorm:"default:0" json:"points_earned"`
 	BonusEarned  int       `gorm:"default:0" json:"bonus_earned"` // Bonus từ streak
 
 	// Relationships
-	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;uniqueIndex:idx_user_checkin_date,priority:1" json:"-"`
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
 }
 
 func (DailyCheckin) TableName() string {
@@ -0,0 +1,85 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Loại công việc nền, mỗi loại có một handler đăng ký trong worker
+const (
+	JobTypeVideoTranscode  = "video_transcode"  // chuyển video gốc thành HLS nhiều chất lượng
+	JobTypeVideoTranscribe = "video_transcribe" // chép lời video gốc thành phụ đề và bản chép lời tìm kiếm được
+)
+
+// Trạng thái công việc nền
+const (
+	JobStatusQueued    = "queued"    // chờ đến RunAt, kể cả khi chờ chạy lại sau lỗi
+	JobStatusRunning   = "running"   // đang được một worker giữ đến LockedUntil
+	JobStatusSucceeded = "succeeded" // đã xong
+	JobStatusFailed    = "failed"    // lỗi không thể thử lại hoặc đã hết MaxAttempts
+)
+
+// DefaultJobMaxAttempts là số lần chạy tối đa khi job không đặt MaxAttempts
+const DefaultJobMaxAttempts = 5
+
+// JobPayload là tham số của công việc, lưu dạng JSONB, VD: {"lesson_id": "...", "object_key": "..."}
+type JobPayload map[string]string
+
+func (p JobPayload) Value() (driver.Value, error) {
+	if len(p) == 0 {
+		return "{}", nil
+	}
+	b, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (p *JobPayload) Scan(value interface{}) error {
+	var raw []byte
+	switch v := value.(type) {
+	case nil:
+		*p = nil
+		return nil
+	case []byte:
+		raw = v
+	case string:
+		raw = []byte(v)
+	default:
+		return fmt.Errorf("unsupported type %T for JobPayload", value)
+	}
+	result := JobPayload{}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return err
+	}
+	*p = result
+	return nil
+}
+
+// Job là một công việc nền lưu trong Postgres. Worker nhận job bằng cách giữ nó đến
+// LockedUntil và gia hạn trong lúc chạy; nếu worker chết, hết hạn giữ thì worker khác
+// nhận lại job. Lỗi được thử lại với thời gian chờ tăng dần cho đến MaxAttempts.
+type Job struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Type        string     `gorm:"type:varchar(50);not null;index" json:"type"`
+	Payload     JobPayload `gorm:"type:jsonb;not null;default:'{}'" json:"payload"`
+	Status      string     `gorm:"type:varchar(20);not null;default:'queued';index:idx_jobs_status_run_at,priority:1;check:status IN ('queued', 'running', 'succeeded', 'failed')" json:"status"`
+	RunAt       time.Time  `gorm:"not null;index:idx_jobs_status_run_at,priority:2" json:"run_at"`
+	Attempts    int        `gorm:"not null;default:0" json:"attempts"`
+	MaxAttempts int        `gorm:"not null;default:5" json:"max_attempts"`
+	LockedBy    *string    `gorm:"type:varchar(100)" json:"locked_by,omitempty"`
+	LockedUntil *time.Time `json:"locked_until,omitempty"`
+	LastError   *string    `gorm:"type:text" json:"last_error,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+func (Job) TableName() string {
+	return "jobs"
+}
@@ -7,6 +7,14 @@ import (
 	"gorm.io/gorm"
 )
 
+// Trạng thái phụ đề (transcription) của video
+const (
+	TranscriptionStatusPending    = "pending"
+	TranscriptionStatusProcessing = "processing"
+	TranscriptionStatusCompleted  = "completed"
+	TranscriptionStatusFailed     = "failed"
+)
+
 type LessonVideo struct {
 	gorm.Model
 	ID                  uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
@@ -32,8 +40,10 @@ type LessonArticle struct {
 	gorm.Model
 	ID              uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
 	LessonID        uuid.UUID `gorm:"type:uuid;uniqueIndex;not null" json:"lesson_id"`
-	Content         string    `gorm:"type:text;not null" json:"content"`
+	Content         string    `gorm:"type:text;not null" json:"content"`                 // Markdown tác giả viết
+	ContentHTML     string    `gorm:"type:text;not null;default:''" json:"content_html"` // HTML đã lọc, render từ Content khi lưu
 	ReadingTimeMins int       `gorm:"default:5;column:reading_time_minutes" json:"reading_time_minutes"`
+	Version         int       `gorm:"not null;default:0" json:"version"` // số phiên bản của Content, tăng mỗi lần lưu
 
 	// Relationships
 	Lesson Lesson `gorm:"foreignKey:LessonID;constraint:OnDelete:CASCADE" json:"-"`
@@ -0,0 +1,55 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Loại file tải lên của khoá học
+const (
+	MediaUploadKindVideo        = "video"         // video bài học, bucket videos
+	MediaUploadKindThumbnail    = "thumbnail"     // ảnh bìa khoá học, bucket images (công khai)
+	MediaUploadKindAttachment   = "attachment"    // tài liệu đính kèm bài học, bucket documents
+	MediaUploadKindArticleImage = "article_image" // ảnh chèn trong bài đọc, bucket images (công khai)
+)
+
+// Trạng thái của một lượt tải lên
+const (
+	MediaUploadStatusPending   = "pending"   // đã cấp URL, chờ client tải lên rồi gọi complete
+	MediaUploadStatusCompleted = "completed" // đã kiểm tra và gắn vào bài học / khoá học
+	MediaUploadStatusFailed    = "failed"    // file không khớp kích thước hoặc định dạng đã khai báo
+	MediaUploadStatusAborted   = "aborted"   // bị huỷ hoặc hết hạn
+)
+
+// MediaUpload là một lượt tải file trực tiếp lên MinIO bằng presigned URL. File không đi
+// qua server: client khai báo trước tên, định dạng và kích thước, server kiểm tra lại khi
+// client gọi complete.
+type MediaUpload struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CourseID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"course_id"`
+	LessonID    *uuid.UUID `gorm:"type:uuid;index" json:"lesson_id,omitempty"` // NULL với ảnh bìa khoá học
+	UploaderID  *uuid.UUID `gorm:"type:uuid" json:"uploader_id,omitempty"`
+	Kind        string     `gorm:"type:varchar(20);not null;check:kind IN ('video', 'thumbnail', 'attachment', 'article_image')" json:"kind"`
+	Status      string     `gorm:"type:varchar(20);not null;index;check:status IN ('pending', 'completed', 'failed', 'aborted')" json:"status"`
+	Bucket      string     `gorm:"type:varchar(63);not null" json:"bucket"`
+	ObjectKey   string     `gorm:"type:varchar(500);not null" json:"object_key"`
+	FileName    string     `gorm:"type:varchar(255);not null" json:"file_name"`
+	ContentType string     `gorm:"type:varchar(100);not null" json:"content_type"`
+	SizeBytes   int64      `gorm:"not null" json:"size_bytes"`
+	// MultipartUploadID của S3 khi file được chia thành nhiều phần, NULL khi tải bằng một PUT
+	MultipartUploadID *string    `gorm:"type:varchar(255)" json:"-"`
+	PartSize          int64      `gorm:"not null;default:0" json:"part_size"`
+	ExpiresAt         time.Time  `gorm:"not null" json:"expires_at"`
+	CompletedAt       *time.Time `json:"completed_at,omitempty"`
+
+	// Relationships
+	Course   Course  `gorm:"foreignKey:CourseID;constraint:OnDelete:CASCADE" json:"-"`
+	Lesson   *Lesson `gorm:"foreignKey:LessonID;constraint:OnDelete:CASCADE" json:"-"`
+	Uploader *User   `gorm:"foreignKey:UploaderID;constraint:OnDelete:SET NULL" json:"-"`
+}
+
+func (MediaUpload) TableName() string {
+	return "media_uploads"
+}
@@ -7,6 +7,25 @@ func AllModels() []interface{} {
 		&User{},
 		&VerificationCode{},
 		&UserOAuthProvider{},
+		&RefreshToken{},
+		&Permission{},
+		&Role{},
+		&RolePermission{},
+		&UserRole{},
+		&UserPermissionOverride{},
+
+		// Organizations
+		&Organization{},
+		&OrganizationMember{},
+		&OrganizationInvitation{},
+
+		// Teacher Applications
+		&TeacherApplication{},
+		&TeacherApplicationDocument{},
+
+		// Parents
+		&ParentStudentRelation{},
+		&AgeVerification{},
 
 		// Course Management
 		&Category{},
@@ -16,7 +35,14 @@ func AllModels() []interface{} {
 		&Lesson{},
 		&LessonVideo{},
 		&LessonArticle{},
+		&LessonArticleRevision{},
 		&LessonAttachment{},
+		&CourseStatusChange{},
+		&CourseSearchDocument{},
+		&MediaUpload{},
+		&Job{},
+		&VideoCaption{},
+		&TranscriptSegment{},
 
 		// Quiz & Assessment
 		&Quiz{},
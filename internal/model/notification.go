@@ -7,6 +7,12 @@ import (
 	"gorm.io/gorm"
 )
 
+// Loại thông báo
+const (
+	NotificationTypeCourseUpdate = "course_update"
+	NotificationTypeSystem       = "system"
+)
+
 type Notification struct {
 	ID               uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
 	CreatedAt        time.Time  `json:"created_at"`
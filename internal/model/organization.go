@@ -0,0 +1,113 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// Loại tổ chức
+const (
+	OrgTypeSchool     = "school"
+	OrgTypeCenter     = "center"
+	OrgTypeCompany    = "company"
+	OrgTypeIndividual = "individual"
+)
+
+// Vai trò của thành viên trong tổ chức (khác với system role).
+// Quyền thực tế đến từ các role được gán kèm organization_id trong user_roles.
+const (
+	OrgMemberOwner   = "owner"
+	OrgMemberAdmin   = "admin"
+	OrgMemberManager = "manager"
+	OrgMemberTeacher = "teacher"
+	OrgMemberTA      = "ta"
+	OrgMemberStudent = "student"
+	OrgMemberMember  = "member"
+)
+
+// Trạng thái thành viên
+const (
+	OrgMemberStatusActive    = "active"
+	OrgMemberStatusSuspended = "suspended"
+)
+
+// Organization là một tenant (trường học, trung tâm đào tạo, ...).
+// Mọi dữ liệu thuộc tổ chức (thành viên, danh mục, role nội bộ, khoá học) đều mang organization_id.
+type Organization struct {
+	ID          uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+	OwnerID     uuid.UUID      `gorm:"type:uuid;not null;index" json:"owner_id"`
+	Name        string         `gorm:"type:varchar(255);not null" json:"name"`
+	Slug        string         `gorm:"type:varchar(100);uniqueIndex;not null" json:"slug"`
+	Description *string        `gorm:"type:text" json:"description,omitempty"`
+	LogoURL     *string        `gorm:"type:varchar(500);column:logo_url" json:"logo_url,omitempty"`
+	Website     *string        `gorm:"type:varchar(255)" json:"website,omitempty"`
+	OrgType     string         `gorm:"type:varchar(20);not null;default:'school';check:org_type IN ('school', 'center', 'company', 'individual')" json:"org_type"`
+	Email       *string        `gorm:"type:varchar(255)" json:"email,omitempty"`
+	Phone       *string        `gorm:"type:varchar(20)" json:"phone,omitempty"`
+	Address     *string        `gorm:"type:text" json:"address,omitempty"`
+	City        *string        `gorm:"type:varchar(100)" json:"city,omitempty"`
+	Country     string         `gorm:"type:varchar(100);not null;default:'Vietnam'" json:"country"`
+	MaxMembers  int            `gorm:"not null;default:100" json:"max_members"`
+	MaxCourses  int            `gorm:"not null;default:10" json:"max_courses"`
+	IsVerified  bool           `gorm:"not null;default:false" json:"is_verified"`
+	IsActive    bool           `gorm:"not null;default:true" json:"is_active"`
+
+	// Relationships
+	Owner   User                 `gorm:"foreignKey:OwnerID" json:"-"`
+	Members []OrganizationMember `gorm:"foreignKey:OrganizationID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+func (Organization) TableName() string {
+	return "organizations"
+}
+
+// OrganizationMember liên kết user với tổ chức. Một user có thể thuộc nhiều tổ chức.
+type OrganizationMember struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	OrganizationID uuid.UUID  `gorm:"type:uuid;not null;index;uniqueIndex:idx_org_member" json:"organization_id"`
+	UserID         uuid.UUID  `gorm:"type:uuid;not null;index;uniqueIndex:idx_org_member" json:"user_id"`
+	MemberRole     string     `gorm:"type:varchar(50);not null;default:'member';check:member_role IN ('owner', 'admin', 'manager', 'teacher', 'ta', 'student', 'member')" json:"member_role"`
+	Department     *string    `gorm:"type:varchar(100)" json:"department,omitempty"`
+	StudentCode    *string    `gorm:"type:varchar(50);column:student_id" json:"student_id,omitempty"` // Mã học sinh do tổ chức cấp
+	Status         string     `gorm:"type:varchar(20);not null;default:'active';check:status IN ('active', 'suspended')" json:"status"`
+	JoinedAt       time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP" json:"joined_at"`
+	InvitedBy      *uuid.UUID `gorm:"type:uuid" json:"invited_by,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+
+	// Relationships
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+func (OrganizationMember) TableName() string {
+	return "organization_members"
+}
+
+// OrganizationInvitation là lời mời tham gia tổ chức gửi qua email.
+// Chỉ lưu hash của token, token gốc chỉ có trong email.
+type OrganizationInvitation struct {
+	ID             uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	OrganizationID uuid.UUID      `gorm:"type:uuid;not null;index" json:"organization_id"`
+	Email          string         `gorm:"type:varchar(255);not null;index" json:"email"`
+	MemberRole     string         `gorm:"type:varchar(50);not null;default:'member'" json:"member_role"`
+	RoleIDs        pq.StringArray `gorm:"type:uuid[];not null;default:'{}'" json:"role_ids"` // Role được gán kèm organization_id khi chấp nhận
+	TokenHash      string         `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	InvitedBy      uuid.UUID      `gorm:"type:uuid;not null" json:"invited_by"`
+	ExpiresAt      time.Time      `gorm:"not null" json:"expires_at"`
+	AcceptedAt     *time.Time     `json:"accepted_at,omitempty"`
+	AcceptedBy     *uuid.UUID     `gorm:"type:uuid" json:"accepted_by,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+
+	// Relationships
+	Organization Organization `gorm:"foreignKey:OrganizationID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+func (OrganizationInvitation) TableName() string {
+	return "organization_invitations"
+}
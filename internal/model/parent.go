@@ -0,0 +1,91 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Trạng thái liên kết phụ huynh - học sinh
+const (
+	ParentLinkPending = "pending" // Đang chờ xác nhận bằng OTP
+	ParentLinkActive  = "active"
+	ParentLinkRevoked = "revoked"
+)
+
+// Quan hệ giữa phụ huynh và học sinh
+const (
+	RelationshipParent      = "parent"      // Bố/Mẹ
+	RelationshipGuardian    = "guardian"    // Người giám hộ
+	RelationshipGrandparent = "grandparent" // Ông/Bà
+	RelationshipSibling     = "sibling"     // Anh/Chị
+)
+
+// Ai đã nhập OTP để xác nhận liên kết
+const (
+	ParentLinkConfirmedByStudent = "student"
+	ParentLinkConfirmedByParent  = "parent" // Phụ huynh nhập mã do học sinh đọc cho
+)
+
+// Trạng thái duyệt mua hàng của phụ huynh trên đơn hàng
+const (
+	ParentApprovalNotRequired = "not_required"
+	ParentApprovalPending     = "pending"
+	ParentApprovalApproved    = "approved"
+	ParentApprovalRejected    = "rejected"
+)
+
+// Trạng thái và phương thức xác minh độ tuổi
+const (
+	AgeVerificationApproved      = "approved"
+	AgeVerificationParentConsent = "parent_consent"
+)
+
+// ParentStudentRelation là liên kết phụ huynh - học sinh. Phụ huynh gửi yêu cầu, liên kết chỉ
+// có hiệu lực khi OTP gửi tới email học sinh được xác nhận. Các cờ can_* giới hạn dữ liệu phụ huynh được xem.
+type ParentStudentRelation struct {
+	ID                  uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+	ParentUserID        uuid.UUID  `gorm:"type:uuid;not null;index;uniqueIndex:idx_parent_student,priority:1" json:"parent_user_id"`
+	StudentUserID       uuid.UUID  `gorm:"type:uuid;not null;index;uniqueIndex:idx_parent_student,priority:2" json:"student_user_id"`
+	Relationship        string     `gorm:"type:varchar(20);not null;default:'parent';check:relationship IN ('parent', 'guardian', 'grandparent', 'sibling')" json:"relationship"`
+	Status              string     `gorm:"type:varchar(20);not null;default:'pending';index;check:status IN ('pending', 'active', 'revoked')" json:"status"`
+	CanViewProgress     bool       `gorm:"not null;default:true" json:"can_view_progress"`
+	CanViewGrades       bool       `gorm:"not null;default:true" json:"can_view_grades"`
+	CanApprovePurchases bool       `gorm:"not null;default:true" json:"can_approve_purchases"` // Xem lịch sử và duyệt đơn hàng
+	ConfirmedAt         *time.Time `json:"confirmed_at,omitempty"`
+	ConfirmedBy         *string    `gorm:"type:varchar(20);check:confirmed_by IN ('student', 'parent')" json:"confirmed_by,omitempty"`
+	RevokedAt           *time.Time `json:"revoked_at,omitempty"`
+	RevokedBy           *uuid.UUID `gorm:"type:uuid" json:"revoked_by,omitempty"`
+
+	// Relationships
+	Parent  User `gorm:"foreignKey:ParentUserID;constraint:OnDelete:CASCADE" json:"-"`
+	Student User `gorm:"foreignKey:StudentUserID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+func (ParentStudentRelation) TableName() string {
+	return "parent_student_relations"
+}
+
+// AgeVerification lưu bằng chứng xác minh độ tuổi. Hiện tại chỉ ghi nhận sự đồng ý của phụ huynh
+// khi một học sinh chưa đủ tuổi xác nhận liên kết.
+type AgeVerification struct {
+	ID                 uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+	UserID             uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	VerificationMethod string     `gorm:"type:varchar(30);not null;check:verification_method IN ('document', 'parent_consent', 'school_verification', 'self_declaration')" json:"verification_method"`
+	Status             string     `gorm:"type:varchar(20);not null;default:'pending';check:status IN ('pending', 'reviewing', 'approved', 'rejected', 'expired')" json:"status"`
+	ParentUserID       *uuid.UUID `gorm:"type:uuid;index" json:"parent_user_id,omitempty"`
+	ParentConsentAt    *time.Time `json:"parent_consent_at,omitempty"`
+	VerifiedAt         *time.Time `json:"verified_at,omitempty"`
+
+	// Relationships
+	User   User  `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	Parent *User `gorm:"foreignKey:ParentUserID;constraint:OnDelete:SET NULL" json:"-"`
+}
+
+func (AgeVerification) TableName() string {
+	return "age_verifications"
+}
@@ -9,6 +9,16 @@ import (
 	"gorm.io/gorm"
 )
 
+// Trạng thái đơn hàng
+const (
+	OrderStatusPending    = "pending"
+	OrderStatusProcessing = "processing"
+	OrderStatusCompleted  = "completed"
+	OrderStatusFailed     = "failed"
+	OrderStatusRefunded   = "refunded"
+	OrderStatusCancelled  = "cancelled"
+)
+
 type Order struct {
 	ID                   uuid.UUID       `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
 	UserID               uuid.UUID       `gorm:"type:uuid;not null;index" json:"user_id"`
@@ -25,6 +35,11 @@ type Order struct {
 	PaidAt               *time.Time      `json:"paid_at,omitempty"`
 	CouponID             *uuid.UUID      `gorm:"type:uuid" json:"coupon_id,omitempty"`
 	Notes                *string         `gorm:"type:text" json:"notes,omitempty"`
+	// Đơn của học sinh chưa đủ tuổi phải được phụ huynh duyệt trước khi thanh toán
+	ParentApprovalStatus string     `gorm:"type:varchar(20);not null;default:'not_required';check:parent_approval_status IN ('not_required', 'pending', 'approved', 'rejected')" json:"parent_approval_status"`
+	ParentApprovedBy     *uuid.UUID `gorm:"type:uuid" json:"parent_approved_by,omitempty"`
+	ParentApprovedAt     *time.Time `json:"parent_approved_at,omitempty"`
+	ParentApprovalNote   *string    `gorm:"type:text" json:"parent_approval_note,omitempty"`
 
 	// Relationships
 	User        User         `gorm:"foreignKey:UserID" json:"-"`
@@ -1,19 +1,175 @@
 package model
 
 import (
-    "database/sql"
-    "github.com/google/uuid"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PermissionWildcard cấp toàn bộ quyền trong hệ thống (dùng cho SYSTEM_ADMIN)
+const PermissionWildcard = "*"
+
+// Tên các role hệ thống, khớp với data/roles.json
+const (
+	RoleSystemAdmin = "SYSTEM_ADMIN"
+	RoleOrgOwner    = "ORG_OWNER"
+	RoleTeacher     = "TEACHER"
+	RoleStudent     = "STUDENT"
+	RoleParent      = "PARENT"
+)
+
+// Tên các permission được kiểm tra trực tiếp trong code, khớp với data/permissions/*.json
+const (
+	PermissionOrgCreate                 = "ORG_CREATE"
+	PermissionOrgDelete                 = "ORG_DELETE"
+	PermissionOrgSettingsManage         = "ORG_SETTINGS_MANAGE"
+	PermissionOrgMembersManage          = "ORG_MEMBERS_MANAGE"
+	PermissionOrgRolesManage            = "ORG_ROLES_MANAGE"
+	PermissionOrgCategoriesManage       = "ORG_CATEGORIES_MANAGE"
+	PermissionTrackingViewOrgStudents   = "TRACKING_VIEW_ORG_STUDENTS"
+	PermissionCoursesCreate             = "COURSES_CREATE"
+	PermissionCoursesUpdateOwn          = "COURSES_UPDATE_OWN"
+	PermissionCoursesDeleteOwn          = "COURSES_DELETE_OWN"
+	PermissionCoursesDeleteOrg          = "COURSES_DELETE_ORG"
+	PermissionCoursesApproveAll         = "COURSES_APPROVE_ALL"
+	PermissionCoursesApproveOwnOrg      = "COURSES_APPROVE_OWN_ORG"
+	PermissionApplicationViewStatus     = "APPLICATION_VIEW_STATUS"
+	PermissionTeacherProfileUpdate      = "TEACHER_PROFILE_UPDATE"
+	PermissionTeacherApplicationsReview = "TEACHER_APPLICATIONS_REVIEW"
 )
 
 type Permission struct {
-    ID          uuid.UUID
-    Name        string
-    Description sql.NullString
+	ID          uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	Name        string         `gorm:"type:varchar(100);uniqueIndex;not null" json:"name"`
+	Description sql.NullString `gorm:"type:text" json:"description"`
+}
+
+func (Permission) TableName() string {
+	return "permissions"
 }
 
+// Role với OrganizationID = NULL là role toàn hệ thống, ngược lại là role nội bộ của tổ chức.
+// Tên role toàn hệ thống là duy nhất, tên role nội bộ chỉ cần duy nhất trong tổ chức.
 type Role struct {
-    ID          uuid.UUID
-    Name        string
-    Description sql.NullString
-    Permissions []Permission `gorm:"many2many:role_permissions;"`
-}
\ No newline at end of file
+	ID             uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	OrganizationID *uuid.UUID     `gorm:"type:uuid;index;uniqueIndex:idx_roles_org_name,priority:1,where:organization_id IS NOT NULL" json:"organization_id,omitempty"`
+	Name           string         `gorm:"type:varchar(100);not null;uniqueIndex:idx_roles_name,where:organization_id IS NULL;uniqueIndex:idx_roles_org_name,priority:2" json:"name"`
+	Description    sql.NullString `gorm:"type:text" json:"description"`
+
+	RolePermissions []RolePermission `gorm:"foreignKey:RoleID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+func (Role) TableName() string {
+	return "roles"
+}
+
+// Các khoá điều kiện ABAC được hỗ trợ trong role_permissions.conditions
+const (
+	// ConditionOwnResourceOnly chỉ cho phép thao tác trên resource do chính user sở hữu
+	// (VD: Course.InstructorID == user hiện tại)
+	ConditionOwnResourceOnly = "own_resource_only"
+)
+
+// PermissionConditions là điều kiện ABAC lưu dạng JSONB, VD: {"own_resource_only": true}
+type PermissionConditions map[string]interface{}
+
+func (c PermissionConditions) Value() (driver.Value, error) {
+	if len(c) == 0 {
+		return "{}", nil
+	}
+	b, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (c *PermissionConditions) Scan(value interface{}) error {
+	var raw []byte
+	switch v := value.(type) {
+	case nil:
+		*c = nil
+		return nil
+	case []byte:
+		raw = v
+	case string:
+		raw = []byte(v)
+	default:
+		return fmt.Errorf("unsupported type %T for PermissionConditions", value)
+	}
+	result := PermissionConditions{}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return err
+	}
+	*c = result
+	return nil
+}
+
+// RolePermission liên kết role với permission.
+// IsGranted = false là từ chối rõ ràng (explicit deny), thắng mọi grant từ role khác.
+type RolePermission struct {
+	RoleID       uuid.UUID            `gorm:"type:uuid;primaryKey" json:"role_id"`
+	PermissionID uuid.UUID            `gorm:"type:uuid;primaryKey;index" json:"permission_id"`
+	IsGranted    bool                 `gorm:"not null;default:true" json:"is_granted"`
+	Conditions   PermissionConditions `gorm:"type:jsonb;not null;default:'{}'" json:"conditions"`
+	CreatedAt    time.Time            `json:"created_at"`
+
+	// Relationships
+	Permission Permission `gorm:"foreignKey:PermissionID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+func (RolePermission) TableName() string {
+	return "role_permissions"
+}
+
+// UserPermissionOverride là ngoại lệ quyền cho riêng một user, ưu tiên hơn quyền từ role.
+// Nếu có ResourceType/ResourceID thì override chỉ áp dụng cho resource đó.
+type UserPermissionOverride struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UserID         uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	PermissionID   uuid.UUID  `gorm:"type:uuid;not null;index" json:"permission_id"`
+	OrganizationID *uuid.UUID `gorm:"type:uuid;index" json:"organization_id,omitempty"`
+	ResourceType   *string    `gorm:"type:varchar(50)" json:"resource_type,omitempty"` // course, lesson, ...
+	ResourceID     *uuid.UUID `gorm:"type:uuid" json:"resource_id,omitempty"`
+	IsGranted      bool       `gorm:"not null" json:"is_granted"`
+	Reason         *string    `gorm:"type:text" json:"reason,omitempty"`
+	GrantedBy      *uuid.UUID `gorm:"type:uuid" json:"granted_by,omitempty"`
+	GrantedAt      time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP" json:"granted_at"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"` // NULL = vĩnh viễn
+	CreatedAt      time.Time  `json:"created_at"`
+
+	// Relationships
+	User       User       `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	Permission Permission `gorm:"foreignKey:PermissionID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+func (UserPermissionOverride) TableName() string {
+	return "user_permission_overrides"
+}
+
+// UserRole gán role cho user. Một user có thể có nhiều role (VD: vừa là TEACHER vừa là PARENT).
+// Khi OrganizationID khác NULL, quyền của role chỉ có hiệu lực trong tổ chức đó.
+type UserRole struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UserID         uuid.UUID  `gorm:"type:uuid;not null;index;uniqueIndex:idx_user_role,where:organization_id IS NULL;uniqueIndex:idx_user_role_org,priority:1,where:organization_id IS NOT NULL" json:"user_id"`
+	RoleID         uuid.UUID  `gorm:"type:uuid;not null;index;uniqueIndex:idx_user_role;uniqueIndex:idx_user_role_org,priority:2" json:"role_id"`
+	OrganizationID *uuid.UUID `gorm:"type:uuid;index;uniqueIndex:idx_user_role_org,priority:3" json:"organization_id,omitempty"`
+	AssignedAt     time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP" json:"assigned_at"`
+	AssignedBy     *uuid.UUID `gorm:"type:uuid" json:"assigned_by,omitempty"` // NULL = hệ thống tự gán
+
+	// Relationships
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	Role Role `gorm:"foreignKey:RoleID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+func (UserRole) TableName() string {
+	return "user_roles"
+}
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshToken lưu hash của refresh token, không lưu token gốc.
+// Các token được xoay vòng từ cùng một lần đăng nhập chia sẻ FamilyID.
+type RefreshToken struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UserID        uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	FamilyID      uuid.UUID  `gorm:"type:uuid;not null;index" json:"family_id"`
+	TokenHash     string     `gorm:"type:varchar(255);uniqueIndex;not null" json:"-"`
+	DeviceID      uuid.UUID  `gorm:"type:uuid;not null;index" json:"device_id"`
+	DeviceName    *string    `gorm:"type:varchar(255)" json:"device_name,omitempty"`
+	IPAddress     *string    `gorm:"type:varchar(45);column:ip_address" json:"ip_address,omitempty"`
+	UserAgent     *string    `gorm:"type:text" json:"user_agent,omitempty"`
+	ExpiresAt     time.Time  `gorm:"not null;index" json:"expires_at"`
+	IsRevoked     bool       `gorm:"default:false;index" json:"is_revoked"`
+	RevokedAt     *time.Time `json:"revoked_at,omitempty"`
+	RevokedReason *string    `gorm:"type:varchar(100)" json:"revoked_reason,omitempty"`
+	ReplacedByID  *uuid.UUID `gorm:"type:uuid" json:"replaced_by_id,omitempty"` // Token mới sinh ra khi xoay vòng
+	LastUsedAt    *time.Time `json:"last_used_at,omitempty"`
+
+	// Relationships
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Trạng thái hồ sơ đăng ký giáo viên
+const (
+	TeacherApplicationPending  = "pending"
+	TeacherApplicationApproved = "approved"
+	TeacherApplicationRejected = "rejected"
+)
+
+// Loại tài liệu đính kèm hồ sơ
+const (
+	ApplicationDocumentCV         = "cv"
+	ApplicationDocumentCredential = "credential" // Bằng cấp, chứng chỉ
+)
+
+// TeacherApplication là hồ sơ đăng ký trở thành giáo viên, được Admin xét duyệt.
+// Mỗi user chỉ có tối đa một hồ sơ đang chờ duyệt; hồ sơ bị từ chối được giữ lại làm lịch sử.
+type TeacherApplication struct {
+	ID                uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	UserID            uuid.UUID      `gorm:"type:uuid;not null;index;uniqueIndex:idx_teacher_applications_user_pending,where:status = 'pending'" json:"user_id"`
+	Status            string         `gorm:"type:varchar(20);not null;default:'pending';index;check:status IN ('pending', 'approved', 'rejected')" json:"status"`
+	Headline          string         `gorm:"type:varchar(255);not null" json:"headline"` // VD: Giáo viên Toán THPT 10 năm kinh nghiệm
+	Bio               string         `gorm:"type:text;not null" json:"bio"`
+	Expertise         pq.StringArray `gorm:"type:text[];not null;default:'{}'" json:"expertise"` // Các môn/lĩnh vực giảng dạy
+	YearsOfExperience int            `gorm:"not null;default:0" json:"years_of_experience"`
+	Education         *string        `gorm:"type:text" json:"education,omitempty"`
+	Phone             *string        `gorm:"type:varchar(20)" json:"phone,omitempty"`
+	WebsiteURL        *string        `gorm:"type:varchar(500);column:website_url" json:"website_url,omitempty"`
+	ReviewedBy        *uuid.UUID     `gorm:"type:uuid" json:"reviewed_by,omitempty"`
+	ReviewNote        *string        `gorm:"type:text" json:"review_note,omitempty"`
+	ReviewedAt        *time.Time     `json:"reviewed_at,omitempty"`
+
+	// Relationships
+	User      User                         `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	Documents []TeacherApplicationDocument `gorm:"foreignKey:ApplicationID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+func (TeacherApplication) TableName() string {
+	return "teacher_applications"
+}
+
+// TeacherApplicationDocument là file (CV, bằng cấp) lưu trong bucket tài liệu riêng tư của MinIO.
+type TeacherApplicationDocument struct {
+	ID            uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ApplicationID uuid.UUID `gorm:"type:uuid;not null;index" json:"application_id"`
+	DocType       string    `gorm:"type:varchar(20);not null;check:doc_type IN ('cv', 'credential')" json:"doc_type"`
+	FileName      string    `gorm:"type:varchar(255);not null" json:"file_name"`
+	ObjectKey     string    `gorm:"type:varchar(500);not null" json:"-"`
+	ContentType   string    `gorm:"type:varchar(100);not null" json:"content_type"`
+	SizeBytes     int64     `gorm:"not null" json:"size_bytes"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func (TeacherApplicationDocument) TableName() string {
+	return "teacher_application_documents"
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// VideoCaption là một track phụ đề WebVTT của video bài học, file nằm trong bucket videos
+// cạnh các bản HLS. Hiện phụ đề do worker tạo từ bản chép lời, mỗi ngôn ngữ một track.
+type VideoCaption struct {
+	ID            uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	CreatedAt     time.Time `json:"created_at"`
+	LessonVideoID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_video_captions_video_language" json:"lesson_video_id"`
+	Language      string    `gorm:"type:varchar(10);not null;uniqueIndex:idx_video_captions_video_language" json:"language"`
+	ObjectKey     string    `gorm:"type:varchar(500);not null" json:"-"`
+
+	// Relationships
+	LessonVideo LessonVideo `gorm:"foreignKey:LessonVideoID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+func (VideoCaption) TableName() string {
+	return "video_captions"
+}
+
+// TranscriptSegment là một đoạn lời nói của video kèm mốc thời gian, để học viên tìm trong
+// bài giảng rồi nhảy đến đúng chỗ. Chỉ mục toàn văn trên Text nằm trong migration 0010.
+type TranscriptSegment struct {
+	ID            uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	LessonVideoID uuid.UUID `gorm:"type:uuid;not null;index:idx_transcript_segments_video_start" json:"lesson_video_id"`
+	StartMs       int       `gorm:"not null;index:idx_transcript_segments_video_start" json:"start_ms"`
+	EndMs         int       `gorm:"not null" json:"end_ms"`
+	Text          string    `gorm:"type:text;not null" json:"text"`
+
+	// Relationships
+	LessonVideo LessonVideo `gorm:"foreignKey:LessonVideoID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+func (TranscriptSegment) TableName() string {
+	return "transcript_segments"
+}
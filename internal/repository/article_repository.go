@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"study.com/v1/internal/model"
+)
+
+type ArticleRepositoryInterface interface {
+	FindByLesson(ctx context.Context, lessonID uuid.UUID) (*model.LessonArticle, error)
+	Save(ctx context.Context, article *model.LessonArticle, revision *model.LessonArticleRevision, baseVersion *int) error
+	ListRevisions(ctx context.Context, articleID uuid.UUID) ([]model.LessonArticleRevision, error)
+	FindRevision(ctx context.Context, articleID uuid.UUID, version int) (*model.LessonArticleRevision, error)
+}
+
+type ArticleRepository struct {
+	db *gorm.DB
+}
+
+func NewArticleRepository(db *gorm.DB) *ArticleRepository {
+	return &ArticleRepository{db: db}
+}
+
+func (r *ArticleRepository) FindByLesson(ctx context.Context, lessonID uuid.UUID) (*model.LessonArticle, error) {
+	var article model.LessonArticle
+	err := r.db.WithContext(ctx).Where("lesson_id = ?", lessonID).First(&article).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &article, nil
+}
+
+// Save writes the article of the lesson as its next version and records the revision.
+// With a baseVersion the save only goes through if the article is still at that version
+// (0 when it does not exist yet): gorm.ErrRecordNotFound means someone saved in between.
+func (r *ArticleRepository) Save(ctx context.Context, article *model.LessonArticle, revision *model.LessonArticleRevision, baseVersion *int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// lesson_id is unique including soft deleted rows, so an old row is revived and
+		// keeps counting its versions
+		var existing model.LessonArticle
+		err := tx.Unscoped().
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("lesson_id = ?", article.LessonID).
+			Take(&existing).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		found := err == nil
+
+		current := 0
+		if found && !existing.DeletedAt.Valid {
+			current = existing.Version
+		}
+		if baseVersion != nil && *baseVersion != current {
+			return gorm.ErrRecordNotFound
+		}
+
+		if !found {
+			article.Version = 1
+			if err := tx.Omit(clause.Associations).Create(article).Error; err != nil {
+				return err
+			}
+		} else {
+			article.ID = existing.ID
+			article.CreatedAt = existing.CreatedAt
+			article.UpdatedAt = time.Now()
+			article.Version = existing.Version + 1
+			err := tx.Unscoped().Model(&model.LessonArticle{}).
+				Where("id = ?", existing.ID).
+				Updates(map[string]interface{}{
+					"content":              article.Content,
+					"content_html":         article.ContentHTML,
+					"reading_time_minutes": article.ReadingTimeMins,
+					"version":              article.Version,
+					"updated_at":           article.UpdatedAt,
+					"deleted_at":           nil,
+				}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		revision.ArticleID = article.ID
+		revision.Version = article.Version
+		return tx.Omit(clause.Associations).Create(revision).Error
+	})
+}
+
+// ListRevisions lists the revisions newest first, without their content.
+func (r *ArticleRepository) ListRevisions(ctx context.Context, articleID uuid.UUID) ([]model.LessonArticleRevision, error) {
+	var revisions []model.LessonArticleRevision
+	err := r.db.WithContext(ctx).
+		Omit("content").
+		Preload("Author").
+		Where("article_id = ?", articleID).
+		Order("version DESC").
+		Find(&revisions).Error
+	return revisions, err
+}
+
+func (r *ArticleRepository) FindRevision(ctx context.Context, articleID uuid.UUID, version int) (*model.LessonArticleRevision, error) {
+	var revision model.LessonArticleRevision
+	err := r.db.WithContext(ctx).
+		Preload("Author").
+		Where("article_id = ? AND version = ?", articleID, version).
+		First(&revision).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &revision, nil
+}
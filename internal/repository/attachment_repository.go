@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"study.com/v1/internal/model"
+)
+
+type AttachmentRepositoryInterface interface {
+	FindByID(ctx context.Context, lessonID, id uuid.UUID) (*model.LessonAttachment, error)
+	IncrementDownloadCount(ctx context.Context, id uuid.UUID) error
+}
+
+type AttachmentRepository struct {
+	db *gorm.DB
+}
+
+func NewAttachmentRepository(db *gorm.DB) *AttachmentRepository {
+	return &AttachmentRepository{db: db}
+}
+
+// FindByID returns the attachment only if it belongs to the lesson.
+func (r *AttachmentRepository) FindByID(ctx context.Context, lessonID, id uuid.UUID) (*model.LessonAttachment, error) {
+	var attachment model.LessonAttachment
+	err := r.db.WithContext(ctx).Where("id = ? AND lesson_id = ?", id, lessonID).First(&attachment).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+func (r *AttachmentRepository) IncrementDownloadCount(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Model(&model.LessonAttachment{}).
+		Where("id = ?", id).
+		UpdateColumn("download_count", gorm.Expr("download_count + 1")).Error
+}
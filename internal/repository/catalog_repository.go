@@ -0,0 +1,337 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"study.com/v1/internal/model"
+)
+
+// Sort orders of the public catalog
+const (
+	// CatalogSortRelevance needs CatalogFilter.Search
+	CatalogSortRelevance = "relevance"
+	CatalogSortPopular   = "popular"
+	CatalogSortRating    = "rating"
+	CatalogSortNewest    = "newest"
+	CatalogSortPriceAsc  = "price_asc"
+	CatalogSortPriceDesc = "price_desc"
+)
+
+// Facet dimensions, used to leave a dimension's own filter out of its counts
+const (
+	catalogFacetCategory = "category"
+	catalogFacetLevel    = "level"
+	catalogFacetLanguage = "language"
+	catalogFacetPrice    = "price"
+	catalogFacetRating   = "rating"
+)
+
+// CatalogRepositoryInterface is the read side of published courses.
+type CatalogRepositoryInterface interface {
+	Search(ctx context.Context, filter CatalogFilter, sort string, after *CatalogCursor, limit int) ([]CatalogHit, error)
+	Facets(ctx context.Context, filter CatalogFilter) (*CatalogFacets, error)
+	FindPublishedBySlug(ctx context.Context, slug string) (*model.Course, error)
+}
+
+// CatalogFilter narrows the published courses. Zero values do not filter.
+type CatalogFilter struct {
+	// CategoryID or CategorySlug (platform-wide categories) include the descendants
+	CategoryID   *uuid.UUID
+	CategorySlug string
+	TagSlug      string
+	Level        string
+	Language     string
+	MinPrice     *decimal.Decimal
+	MaxPrice     *decimal.Decimal
+	IsFree       *bool
+	MinRating    *decimal.Decimal
+	// Search is the text typed by the user, matched as SearchMode says
+	Search     string
+	SearchMode string
+}
+
+// CatalogCursor is the position after the last course of the previous page: the value of
+// the sort key (int64, decimal.Decimal, time.Time or the float64 relevance depending on
+// the sort) and the id.
+type CatalogCursor struct {
+	Key interface{}
+	ID  uuid.UUID
+}
+
+// CatalogHit is a course of a result page. Relevance is only set when sorting by relevance.
+type CatalogHit struct {
+	Course    model.Course
+	Relevance float64
+}
+
+type FacetCount struct {
+	Value string
+	Count int64
+}
+
+type CategoryFacet struct {
+	ID    uuid.UUID
+	Name  string
+	Slug  string
+	Count int64
+}
+
+type PriceFacet struct {
+	Free int64
+	Paid int64
+}
+
+// RatingFacet counts the courses rated at least 4.5, 4.0, 3.5 and 3.0.
+type RatingFacet struct {
+	Min45 int64 `gorm:"column:min45"`
+	Min40 int64 `gorm:"column:min40"`
+	Min35 int64 `gorm:"column:min35"`
+	Min30 int64 `gorm:"column:min30"`
+}
+
+type CatalogFacets struct {
+	Categories []CategoryFacet
+	Levels     []FacetCount
+	Languages  []FacetCount
+	Price      PriceFacet
+	Ratings    RatingFacet
+}
+
+type catalogOrder struct {
+	key  string
+	vars []interface{}
+	desc bool
+}
+
+var catalogOrders = map[string]catalogOrder{
+	CatalogSortPopular:   {key: "courses.total_students", desc: true},
+	CatalogSortRating:    {key: "courses.average_rating", desc: true},
+	CatalogSortNewest:    {key: "COALESCE(courses.published_at, courses.created_at)", desc: true},
+	CatalogSortPriceAsc:  {key: "courses.price"},
+	CatalogSortPriceDesc: {key: "courses.price", desc: true},
+}
+
+type CatalogRepository struct {
+	db *gorm.DB
+}
+
+func NewCatalogRepository(db *gorm.DB) *CatalogRepository {
+	return &CatalogRepository{db: db}
+}
+
+// Search pages through the catalog with keyset pagination on (sort key, id), which stays
+// stable while courses are being published. Unknown sorts fall back to popularity, and
+// relevance without a search text as well.
+func (r *CatalogRepository) Search(ctx context.Context, filter CatalogFilter, sort string, after *CatalogCursor, limit int) ([]CatalogHit, error) {
+	order := catalogOrderFor(sort, filter)
+	direction, compare := "ASC", ">"
+	if order.desc {
+		direction, compare = "DESC", "<"
+	}
+
+	query := r.published(ctx, filter, "")
+	if after != nil {
+		vars := append(append([]interface{}{}, order.vars...), after.Key, after.ID)
+		query = query.Where("("+order.key+", courses.id) "+compare+" (?, ?)", vars...)
+	}
+
+	// The page is picked on ids first, then loaded with its associations
+	var keys []struct {
+		ID        uuid.UUID
+		Relevance float64
+	}
+	relevance, relevanceVars := "0", []interface{}(nil)
+	if sort == CatalogSortRelevance {
+		relevance, relevanceVars = order.key, order.vars
+	}
+	err := query.
+		Select("courses.id, "+relevance+" AS relevance", relevanceVars...).
+		Order(orderByExpr(order.key+" "+direction+", courses.id "+direction, order.vars...)).
+		Limit(limit).
+		Scan(&keys).Error
+	if err != nil || len(keys) == 0 {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, 0, len(keys))
+	for _, k := range keys {
+		ids = append(ids, k.ID)
+	}
+	var courses []model.Course
+	err = r.db.WithContext(ctx).
+		Preload("Instructor").
+		Preload("Category").
+		Where("id IN ?", ids).
+		Find(&courses).Error
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]model.Course, len(courses))
+	for _, c := range courses {
+		byID[c.ID] = c
+	}
+
+	hits := make([]CatalogHit, 0, len(keys))
+	for _, k := range keys {
+		// A course deleted between the two queries is left out
+		if course, ok := byID[k.ID]; ok {
+			hits = append(hits, CatalogHit{Course: course, Relevance: k.Relevance})
+		}
+	}
+	return hits, nil
+}
+
+// Facets counts the matching courses per value of each dimension. Each dimension ignores
+// its own filter, so picking a level still shows how many courses the other levels have.
+func (r *CatalogRepository) Facets(ctx context.Context, filter CatalogFilter) (*CatalogFacets, error) {
+	facets := &CatalogFacets{}
+
+	err := r.published(ctx, filter, catalogFacetCategory).
+		Joins("JOIN categories ON categories.id = courses.category_id AND categories.deleted_at IS NULL").
+		Select("categories.id, categories.name, categories.slug, COUNT(*) AS count").
+		Group("categories.id, categories.name, categories.slug").
+		Order("count DESC, categories.name").
+		Scan(&facets.Categories).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.published(ctx, filter, catalogFacetLevel).
+		Select("courses.level AS value, COUNT(*) AS count").
+		Group("courses.level").
+		Order("count DESC").
+		Scan(&facets.Levels).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.published(ctx, filter, catalogFacetLanguage).
+		Select("courses.language AS value, COUNT(*) AS count").
+		Group("courses.language").
+		Order("count DESC").
+		Scan(&facets.Languages).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.published(ctx, filter, catalogFacetPrice).
+		Select("COUNT(*) FILTER (WHERE courses.is_free) AS free, COUNT(*) FILTER (WHERE NOT courses.is_free) AS paid").
+		Scan(&facets.Price).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.published(ctx, filter, catalogFacetRating).
+		Select(`COUNT(*) FILTER (WHERE courses.average_rating >= 4.5) AS min45,
+			COUNT(*) FILTER (WHERE courses.average_rating >= 4.0) AS min40,
+			COUNT(*) FILTER (WHERE courses.average_rating >= 3.5) AS min35,
+			COUNT(*) FILTER (WHERE courses.average_rating >= 3.0) AS min30`).
+		Scan(&facets.Ratings).Error
+	if err != nil {
+		return nil, err
+	}
+	return facets, nil
+}
+
+// FindPublishedBySlug loads the course page: instructor, category, tags and the curriculum.
+func (r *CatalogRepository) FindPublishedBySlug(ctx context.Context, slug string) (*model.Course, error) {
+	var course model.Course
+	err := r.db.WithContext(ctx).
+		Preload("Instructor").
+		Preload("Category").
+		Preload("Tags", func(db *gorm.DB) *gorm.DB { return db.Order("name") }).
+		Preload("Sections", func(db *gorm.DB) *gorm.DB { return db.Order("display_order, created_at") }).
+		Preload("Sections.Lessons", func(db *gorm.DB) *gorm.DB { return db.Order("display_order") }).
+		Where("slug = ? AND status = ?", slug, model.CourseStatusPublished).
+		First(&course).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &course, nil
+}
+
+// published starts a query over the published courses with every filter applied except
+// the one of the skipped facet dimension.
+func (r *CatalogRepository) published(ctx context.Context, filter CatalogFilter, skip string) *gorm.DB {
+	query := r.db.WithContext(ctx).
+		Model(&model.Course{}).
+		Where("courses.status = ?", model.CourseStatusPublished)
+
+	if skip != catalogFacetCategory {
+		if filter.CategoryID != nil {
+			query = query.Where("courses.category_id IN ("+categoryTreeSQL+")", gorm.Expr("categories.id = ?", *filter.CategoryID))
+		} else if filter.CategorySlug != "" {
+			query = query.Where("courses.category_id IN ("+categoryTreeSQL+")",
+				gorm.Expr("categories.slug = ? AND categories.organization_id IS NULL", filter.CategorySlug))
+		}
+	}
+	if filter.Search != "" {
+		match := fullTextMatchSQL
+		if filter.SearchMode == SearchModeFuzzy {
+			match = fuzzyMatchSQL
+		}
+		query = query.Joins(searchJoinSQL).Where(match, filter.Search)
+	}
+	if filter.TagSlug != "" {
+		query = query.Where(`EXISTS (
+			SELECT 1 FROM course_tags
+			JOIN tags ON tags.id = course_tags.tag_id
+			WHERE course_tags.course_id = courses.id AND tags.slug = ?
+		)`, filter.TagSlug)
+	}
+	if filter.Level != "" && skip != catalogFacetLevel {
+		query = query.Where("courses.level = ?", filter.Level)
+	}
+	if filter.Language != "" && skip != catalogFacetLanguage {
+		query = query.Where("courses.language = ?", filter.Language)
+	}
+	if skip != catalogFacetPrice {
+		if filter.IsFree != nil {
+			query = query.Where("courses.is_free = ?", *filter.IsFree)
+		}
+		if filter.MinPrice != nil {
+			query = query.Where("courses.price >= ?", *filter.MinPrice)
+		}
+		if filter.MaxPrice != nil {
+			query = query.Where("courses.price <= ?", *filter.MaxPrice)
+		}
+	}
+	if filter.MinRating != nil && skip != catalogFacetRating {
+		query = query.Where("courses.average_rating >= ?", *filter.MinRating)
+	}
+	return query
+}
+
+// catalogOrderFor resolves the sort key, relevance being the rank of the search mode in use.
+func catalogOrderFor(sort string, filter CatalogFilter) catalogOrder {
+	if sort == CatalogSortRelevance && filter.Search != "" {
+		if filter.SearchMode == SearchModeFuzzy {
+			return catalogOrder{key: fuzzyRankSQL, vars: []interface{}{filter.Search}, desc: true}
+		}
+		return catalogOrder{key: fullTextRankSQL, vars: []interface{}{filter.Search}, desc: true}
+	}
+	if order, ok := catalogOrders[sort]; ok {
+		return order
+	}
+	return catalogOrders[CatalogSortPopular]
+}
+
+// categoryTreeSQL selects the ids of the categories matching the root condition and all of
+// their descendants. UNION rather than UNION ALL so a parent_id cycle cannot loop forever.
+const categoryTreeSQL = `
+	WITH RECURSIVE tree AS (
+		SELECT categories.id FROM categories
+		WHERE categories.deleted_at IS NULL AND categories.is_active AND ?
+		UNION
+		SELECT categories.id FROM categories
+		JOIN tree ON categories.parent_id = tree.id
+		WHERE categories.deleted_at IS NULL AND categories.is_active
+	)
+	SELECT id FROM tree`
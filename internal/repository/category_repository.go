@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"study.com/v1/internal/model"
+)
+
+// CategoryRepositoryInterface is scoped by organization: a nil orgID means the
+// platform-wide categories, never "all organizations".
+type CategoryRepositoryInterface interface {
+	Create(ctx context.Context, category *model.Category) error
+	Update(ctx context.Context, category *model.Category) error
+	Delete(ctx context.Context, orgID *uuid.UUID, id uuid.UUID) (bool, error)
+	FindByID(ctx context.Context, orgID *uuid.UUID, id uuid.UUID) (*model.Category, error)
+	SlugExists(ctx context.Context, orgID *uuid.UUID, slug string, excludeID uuid.UUID) (bool, error)
+	List(ctx context.Context, orgID *uuid.UUID) ([]model.Category, error)
+}
+
+type CategoryRepository struct {
+	db *gorm.DB
+}
+
+func NewCategoryRepository(db *gorm.DB) *CategoryRepository {
+	return &CategoryRepository{db: db}
+}
+
+func (r *CategoryRepository) Create(ctx context.Context, category *model.Category) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Create(category).Error
+}
+
+func (r *CategoryRepository) Update(ctx context.Context, category *model.Category) error {
+	return r.db.WithContext(ctx).
+		Model(category).
+		Select("parent_id", "name", "slug", "description", "icon_url", "display_order", "is_active").
+		Updates(category).Error
+}
+
+// Delete detaches child categories and courses before removing the category.
+func (r *CategoryRepository) Delete(ctx context.Context, orgID *uuid.UUID, id uuid.UUID) (bool, error) {
+	var deleted bool
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := scopeOrganization(tx, orgID).Where("id = ?", id).Delete(&model.Category{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		deleted = true
+		if err := tx.Model(&model.Category{}).Where("parent_id = ?", id).Update("parent_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.Course{}).Where("category_id = ?", id).Update("category_id", nil).Error
+	})
+	return deleted, err
+}
+
+func (r *CategoryRepository) FindByID(ctx context.Context, orgID *uuid.UUID, id uuid.UUID) (*model.Category, error) {
+	var category model.Category
+	err := scopeOrganization(r.db.WithContext(ctx), orgID).Where("id = ?", id).First(&category).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &category, nil
+}
+
+// SlugExists also looks at deleted categories, their slugs are still taken by the unique index.
+func (r *CategoryRepository) SlugExists(ctx context.Context, orgID *uuid.UUID, slug string, excludeID uuid.UUID) (bool, error) {
+	var count int64
+	err := scopeOrganization(r.db.WithContext(ctx), orgID).
+		Unscoped().
+		Model(&model.Category{}).
+		Where("slug = ? AND id <> ?", slug, excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *CategoryRepository) List(ctx context.Context, orgID *uuid.UUID) ([]model.Category, error) {
+	var categories []model.Category
+	err := scopeOrganization(r.db.WithContext(ctx), orgID).
+		Order("display_order, name").
+		Find(&categories).Error
+	return categories, err
+}
+
+// scopeOrganization restricts a query to one tenant, or to platform-wide rows when orgID is nil.
+func scopeOrganization(db *gorm.DB, orgID *uuid.UUID) *gorm.DB {
+	if orgID == nil {
+		return db.Where("organization_id IS NULL")
+	}
+	return db.Where("organization_id = ?", *orgID)
+}
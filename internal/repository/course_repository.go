@@ -0,0 +1,456 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"study.com/v1/internal/model"
+)
+
+type CourseRepositoryInterface interface {
+	Create(ctx context.Context, course *model.Course) error
+	Update(ctx context.Context, course *model.Course) error
+	SetTags(ctx context.Context, courseID uuid.UUID, tags []model.Tag) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	FindByID(ctx context.Context, id uuid.UUID) (*model.Course, error)
+	FindDetail(ctx context.Context, id uuid.UUID) (*model.Course, error)
+	SlugExists(ctx context.Context, slug string, excludeID uuid.UUID) (bool, error)
+	ListByInstructor(ctx context.Context, instructorID uuid.UUID, filter CourseFilter, offset, limit int) ([]model.Course, int64, error)
+
+	FindSection(ctx context.Context, courseID, sectionID uuid.UUID) (*model.Section, error)
+	CreateSection(ctx context.Context, section *model.Section) error
+	UpdateSection(ctx context.Context, section *model.Section) error
+	DeleteSection(ctx context.Context, section *model.Section) error
+
+	FindLesson(ctx context.Context, courseID, lessonID uuid.UUID) (*model.Lesson, error)
+	FindLessonWithCourse(ctx context.Context, lessonID uuid.UUID) (*model.Lesson, error)
+	CreateLesson(ctx context.Context, courseID uuid.UUID, lesson *model.Lesson) error
+	UpdateLesson(ctx context.Context, courseID uuid.UUID, lesson *model.Lesson) error
+	DeleteLesson(ctx context.Context, courseID uuid.UUID, lesson *model.Lesson) error
+
+	Reorder(ctx context.Context, courseID uuid.UUID, outline []SectionOutline) error
+
+	Transition(ctx context.Context, course *model.Course, fromStatus string, change *model.CourseStatusChange, notification *model.Notification) error
+	ListStatusChanges(ctx context.Context, courseID uuid.UUID) ([]model.CourseStatusChange, error)
+	ListPendingReview(ctx context.Context, organizationID *uuid.UUID, offset, limit int) ([]model.Course, int64, error)
+}
+
+type CourseFilter struct {
+	Status string
+	Search string
+}
+
+// SectionOutline is one section of a reordered course with its lessons in order.
+type SectionOutline struct {
+	SectionID uuid.UUID
+	LessonIDs []uuid.UUID
+}
+
+type CourseRepository struct {
+	db *gorm.DB
+}
+
+func NewCourseRepository(db *gorm.DB) *CourseRepository {
+	return &CourseRepository{db: db}
+}
+
+// Create inserts the course with its sections and lessons and fills in the lesson totals.
+func (r *CourseRepository) Create(ctx context.Context, course *model.Course) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(course).Error; err != nil {
+			return err
+		}
+		for i := range course.Sections {
+			course.Sections[i].CourseID = course.ID
+			if err := createSection(tx, &course.Sections[i]); err != nil {
+				return err
+			}
+		}
+		if err := syncCourseTags(tx, course.ID, course.Tags); err != nil {
+			return err
+		}
+		return recomputeCourseTotals(tx, course.ID)
+	})
+}
+
+func (r *CourseRepository) Update(ctx context.Context, course *model.Course) error {
+	return r.db.WithContext(ctx).
+		Model(course).
+		Select("category_id", "title", "slug", "short_description", "description", "thumbnail_url",
+			"preview_video_url", "level", "language", "price", "discount_price", "discount_expires_at",
+			"is_free", "requirements", "objectives", "target_audience").
+		Updates(course).Error
+}
+
+// SetTags replaces the tags of the course, creating the tags that do not exist yet.
+func (r *CourseRepository) SetTags(ctx context.Context, courseID uuid.UUID, tags []model.Tag) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := syncCourseTags(tx, courseID, tags); err != nil {
+			return err
+		}
+		return tx.Model(&model.Course{}).Where("id = ?", courseID).Update("updated_at", time.Now()).Error
+	})
+}
+
+// Delete is a soft delete, enrollments and orders keep pointing at the course.
+func (r *CourseRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&model.Course{}).Error
+}
+
+func (r *CourseRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Course, error) {
+	var course model.Course
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&course).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &course, nil
+}
+
+// FindDetail loads the course with its sections and lessons in display order.
+func (r *CourseRepository) FindDetail(ctx context.Context, id uuid.UUID) (*model.Course, error) {
+	var course model.Course
+	err := r.db.WithContext(ctx).
+		Preload("Sections", func(db *gorm.DB) *gorm.DB { return db.Order("display_order, created_at") }).
+		Preload("Sections.Lessons", func(db *gorm.DB) *gorm.DB { return db.Order("display_order") }).
+		Preload("Tags", func(db *gorm.DB) *gorm.DB { return db.Order("name") }).
+		Where("id = ?", id).
+		First(&course).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &course, nil
+}
+
+// SlugExists also looks at deleted courses, their slugs are still taken by the unique index.
+func (r *CourseRepository) SlugExists(ctx context.Context, slug string, excludeID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Unscoped().
+		Model(&model.Course{}).
+		Where("slug = ? AND id <> ?", slug, excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *CourseRepository) ListByInstructor(ctx context.Context, instructorID uuid.UUID, filter CourseFilter, offset, limit int) ([]model.Course, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.Course{}).Where("instructor_id = ?", instructorID)
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Search != "" {
+		query = query.Where("title ILIKE ?", "%"+filter.Search+"%")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var courses []model.Course
+	err := query.
+		Order("updated_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&courses).Error
+	return courses, total, err
+}
+
+func (r *CourseRepository) FindSection(ctx context.Context, courseID, sectionID uuid.UUID) (*model.Section, error) {
+	var section model.Section
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND course_id = ?", sectionID, courseID).
+		First(&section).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &section, nil
+}
+
+// CreateSection appends the section, with its lessons, after the last section of the course.
+func (r *CourseRepository) CreateSection(ctx context.Context, section *model.Section) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockCourse(tx, section.CourseID); err != nil {
+			return err
+		}
+		next, err := nextDisplayOrder(tx.Model(&model.Section{}).Where("course_id = ?", section.CourseID))
+		if err != nil {
+			return err
+		}
+		section.DisplayOrder = next
+		if err := createSection(tx, section); err != nil {
+			return err
+		}
+		return recomputeCourseTotals(tx, section.CourseID)
+	})
+}
+
+func (r *CourseRepository) UpdateSection(ctx context.Context, section *model.Section) error {
+	return r.db.WithContext(ctx).
+		Model(section).
+		Select("title", "description").
+		Updates(section).Error
+}
+
+// DeleteSection removes the section for good so its lessons go with it.
+func (r *CourseRepository) DeleteSection(ctx context.Context, section *model.Section) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("id = ?", section.ID).Delete(&model.Section{}).Error; err != nil {
+			return err
+		}
+		return recomputeCourseTotals(tx, section.CourseID)
+	})
+}
+
+// FindLesson returns the lesson only if it belongs to a section of the course.
+func (r *CourseRepository) FindLesson(ctx context.Context, courseID, lessonID uuid.UUID) (*model.Lesson, error) {
+	var lesson model.Lesson
+	err := r.db.WithContext(ctx).
+		Joins("JOIN sections ON sections.id = lessons.section_id AND sections.deleted_at IS NULL").
+		Where("lessons.id = ? AND sections.course_id = ?", lessonID, courseID).
+		First(&lesson).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &lesson, nil
+}
+
+// FindLessonWithCourse returns the lesson with Section.Course filled in, for callers that
+// start from a lesson id alone.
+func (r *CourseRepository) FindLessonWithCourse(ctx context.Context, lessonID uuid.UUID) (*model.Lesson, error) {
+	var lesson model.Lesson
+	err := r.db.WithContext(ctx).
+		Joins("JOIN sections ON sections.id = lessons.section_id AND sections.deleted_at IS NULL").
+		Joins("JOIN courses ON courses.id = sections.course_id AND courses.deleted_at IS NULL").
+		Preload("Section.Course").
+		Where("lessons.id = ?", lessonID).
+		First(&lesson).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &lesson, nil
+}
+
+// CreateLesson appends the lesson to its section and updates the course totals.
+func (r *CourseRepository) CreateLesson(ctx context.Context, courseID uuid.UUID, lesson *model.Lesson) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockCourse(tx, courseID); err != nil {
+			return err
+		}
+		next, err := nextDisplayOrder(tx.Model(&model.Lesson{}).Where("section_id = ?", lesson.SectionID))
+		if err != nil {
+			return err
+		}
+		lesson.DisplayOrder = next
+		if err := createLesson(tx, lesson); err != nil {
+			return err
+		}
+		return recomputeCourseTotals(tx, courseID)
+	})
+}
+
+func (r *CourseRepository) UpdateLesson(ctx context.Context, courseID uuid.UUID, lesson *model.Lesson) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(lesson).
+			Select("title", "description", "content_type", "duration_minutes", "is_preview", "is_mandatory").
+			Updates(lesson).Error
+		if err != nil {
+			return err
+		}
+		return recomputeCourseTotals(tx, courseID)
+	})
+}
+
+func (r *CourseRepository) DeleteLesson(ctx context.Context, courseID uuid.UUID, lesson *model.Lesson) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ?", lesson.ID).Delete(&model.Lesson{}).Error; err != nil {
+			return err
+		}
+		return recomputeCourseTotals(tx, courseID)
+	})
+}
+
+// Reorder rewrites display_order of every section and lesson of the course, moving lessons
+// between sections where the outline says so. The caller checks the outline is complete.
+func (r *CourseRepository) Reorder(ctx context.Context, courseID uuid.UUID, outline []SectionOutline) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockCourse(tx, courseID); err != nil {
+			return err
+		}
+		for i, section := range outline {
+			err := tx.Model(&model.Section{}).
+				Where("id = ? AND course_id = ?", section.SectionID, courseID).
+				Updates(map[string]interface{}{"display_order": i + 1, "updated_at": time.Now()}).Error
+			if err != nil {
+				return err
+			}
+			for j, lessonID := range section.LessonIDs {
+				err := tx.Model(&model.Lesson{}).
+					Where("id = ?", lessonID).
+					Updates(map[string]interface{}{"section_id": section.SectionID, "display_order": j + 1}).Error
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return tx.Model(&model.Course{}).Where("id = ?", courseID).Update("updated_at", time.Now()).Error
+	})
+}
+
+// Transition moves the course out of fromStatus and records the change and the instructor's
+// notification with it. gorm.ErrRecordNotFound means the status changed in the meantime.
+func (r *CourseRepository) Transition(ctx context.Context, course *model.Course, fromStatus string, change *model.CourseStatusChange, notification *model.Notification) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Course{}).
+			Where("id = ? AND status = ?", course.ID, fromStatus).
+			Updates(map[string]interface{}{
+				"status":       course.Status,
+				"published_at": course.PublishedAt,
+				"updated_at":   time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := tx.Omit(clause.Associations).Create(change).Error; err != nil {
+			return err
+		}
+		if notification != nil {
+			if err := tx.Omit(clause.Associations).Create(notification).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListStatusChanges returns the history of the course, oldest first, with the actors loaded.
+func (r *CourseRepository) ListStatusChanges(ctx context.Context, courseID uuid.UUID) ([]model.CourseStatusChange, error) {
+	var changes []model.CourseStatusChange
+	err := r.db.WithContext(ctx).
+		Preload("Actor").
+		Where("course_id = ?", courseID).
+		Order("created_at").
+		Find(&changes).Error
+	return changes, err
+}
+
+// ListPendingReview lists the courses waiting for review, longest waiting first. A nil
+// organizationID lists the personal courses of independent teachers.
+func (r *CourseRepository) ListPendingReview(ctx context.Context, organizationID *uuid.UUID, offset, limit int) ([]model.Course, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.Course{}).Where("status = ?", model.CourseStatusPendingReview)
+	if organizationID != nil {
+		query = query.Where("organization_id = ?", *organizationID)
+	} else {
+		query = query.Where("organization_id IS NULL")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var courses []model.Course
+	err := query.
+		Order("updated_at").
+		Offset(offset).
+		Limit(limit).
+		Find(&courses).Error
+	return courses, total, err
+}
+
+func createSection(tx *gorm.DB, section *model.Section) error {
+	if err := tx.Omit(clause.Associations).Create(section).Error; err != nil {
+		return err
+	}
+	for i := range section.Lessons {
+		section.Lessons[i].SectionID = section.ID
+		if err := createLesson(tx, &section.Lessons[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createLesson writes every column so that is_mandatory = false is kept, which means the
+// id has to be generated here instead of by the database default.
+func createLesson(tx *gorm.DB, lesson *model.Lesson) error {
+	if lesson.ID == uuid.Nil {
+		lesson.ID = uuid.New()
+	}
+	return tx.Select("*").Omit(clause.Associations).Create(lesson).Error
+}
+
+// syncCourseTags makes the tags of the course exactly the given ones. Tags are matched by
+// slug; a name clash with an existing tag keeps the existing tag.
+func syncCourseTags(tx *gorm.DB, courseID uuid.UUID, tags []model.Tag) error {
+	if err := tx.Exec("DELETE FROM course_tags WHERE course_id = ?", courseID).Error; err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+	slugs := make([]string, 0, len(tags))
+	for i := range tags {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Omit(clause.Associations).Create(&tags[i]).Error; err != nil {
+			return err
+		}
+		slugs = append(slugs, tags[i].Slug)
+	}
+	return tx.Exec("INSERT INTO course_tags (course_id, tag_id) SELECT ?, id FROM tags WHERE slug IN ? ON CONFLICT DO NOTHING",
+		courseID, slugs).Error
+}
+
+// lockCourse serializes structure changes of one course so display orders do not collide.
+func lockCourse(tx *gorm.DB, courseID uuid.UUID) error {
+	var course model.Course
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		Where("id = ?", courseID).
+		First(&course).Error
+}
+
+func nextDisplayOrder(query *gorm.DB) (int, error) {
+	var last int
+	if err := query.Select("COALESCE(MAX(display_order), 0)").Scan(&last).Error; err != nil {
+		return 0, err
+	}
+	return last + 1, nil
+}
+
+// recomputeCourseTotals keeps total_lessons and total_duration_minutes in line with the lessons.
+func recomputeCourseTotals(tx *gorm.DB, courseID uuid.UUID) error {
+	return tx.Exec(`
+		UPDATE courses SET
+			total_lessons = totals.lessons,
+			total_duration_minutes = totals.minutes,
+			updated_at = CURRENT_TIMESTAMP
+		FROM (
+			SELECT COUNT(lessons.id) AS lessons, COALESCE(SUM(lessons.duration_minutes), 0) AS minutes
+			FROM lessons
+			JOIN sections ON sections.id = lessons.section_id
+			WHERE sections.course_id = ? AND sections.deleted_at IS NULL
+		) AS totals
+		WHERE courses.id = ?
+	`, courseID, courseID).Error
+}
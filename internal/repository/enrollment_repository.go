@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"study.com/v1/internal/model"
+)
+
+type EnrollmentRepositoryInterface interface {
+	ListByUserID(ctx context.Context, userID uuid.UUID, offset, limit int) ([]model.Enrollment, int64, error)
+	HasActiveEnrollment(ctx context.Context, userID, courseID uuid.UUID, at time.Time) (bool, error)
+}
+
+type EnrollmentRepository struct {
+	db *gorm.DB
+}
+
+func NewEnrollmentRepository(db *gorm.DB) *EnrollmentRepository {
+	return &EnrollmentRepository{db: db}
+}
+
+// ListByUserID returns the user's enrollments with their course, most recently active first.
+func (r *EnrollmentRepository) ListByUserID(ctx context.Context, userID uuid.UUID, offset, limit int) ([]model.Enrollment, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.Enrollment{}).Where("user_id = ?", userID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var enrollments []model.Enrollment
+	err := query.
+		Preload("Course").
+		Order("COALESCE(last_accessed_at, enrolled_at) DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&enrollments).Error
+	return enrollments, total, err
+}
+
+// HasActiveEnrollment reports whether the user is enrolled in the course and the enrollment
+// has not expired at the given time.
+func (r *EnrollmentRepository) HasActiveEnrollment(ctx context.Context, userID, courseID uuid.UUID, at time.Time) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.Enrollment{}).
+		Where("user_id = ? AND course_id = ?", userID, courseID).
+		Where("expires_at IS NULL OR expires_at > ?", at).
+		Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"study.com/v1/internal/model"
+)
+
+// JobRepositoryInterface is the queue behind the background worker. Every method that
+// finishes a job is guarded by the lease: gorm.ErrRecordNotFound means the lease expired
+// and another worker took the job over.
+type JobRepositoryInterface interface {
+	Enqueue(ctx context.Context, job *model.Job) error
+	Claim(ctx context.Context, types []string, workerID string, lease time.Duration) (*model.Job, error)
+	ExtendLease(ctx context.Context, id uuid.UUID, workerID string, lease time.Duration) error
+	Complete(ctx context.Context, id uuid.UUID, workerID string) error
+	Retry(ctx context.Context, id uuid.UUID, workerID string, runAt time.Time, reason string) error
+	Fail(ctx context.Context, id uuid.UUID, workerID string, reason string) error
+}
+
+type JobRepository struct {
+	db *gorm.DB
+}
+
+func NewJobRepository(db *gorm.DB) *JobRepository {
+	return &JobRepository{db: db}
+}
+
+func (r *JobRepository) Enqueue(ctx context.Context, job *model.Job) error {
+	return enqueueJob(r.db.WithContext(ctx), job)
+}
+
+// Claim takes the oldest job that is due, or whose worker stopped renewing its lease, and
+// leases it to workerID. Concurrent workers skip each other's rows instead of waiting.
+// Returns nil when there is nothing to do.
+func (r *JobRepository) Claim(ctx context.Context, types []string, workerID string, lease time.Duration) (*model.Job, error) {
+	now := time.Now()
+	var jobs []model.Job
+	err := r.db.WithContext(ctx).Raw(`
+		UPDATE jobs SET
+			status = ?,
+			attempts = attempts + 1,
+			locked_by = ?,
+			locked_until = ?,
+			updated_at = ?
+		WHERE id = (
+			SELECT id FROM jobs
+			WHERE type IN ?
+				AND ((status = ? AND run_at <= ?) OR (status = ? AND locked_until < ?))
+			ORDER BY run_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *
+	`, model.JobStatusRunning, workerID, now.Add(lease), now,
+		types, model.JobStatusQueued, now, model.JobStatusRunning, now,
+	).Scan(&jobs).Error
+	if err != nil || len(jobs) == 0 {
+		return nil, err
+	}
+	return &jobs[0], nil
+}
+
+func (r *JobRepository) ExtendLease(ctx context.Context, id uuid.UUID, workerID string, lease time.Duration) error {
+	return r.updateLeased(ctx, id, workerID, map[string]interface{}{
+		"locked_until": time.Now().Add(lease),
+		"updated_at":   time.Now(),
+	})
+}
+
+func (r *JobRepository) Complete(ctx context.Context, id uuid.UUID, workerID string) error {
+	now := time.Now()
+	return r.updateLeased(ctx, id, workerID, map[string]interface{}{
+		"status":       model.JobStatusSucceeded,
+		"locked_by":    nil,
+		"locked_until": nil,
+		"last_error":   nil,
+		"completed_at": now,
+		"updated_at":   now,
+	})
+}
+
+// Retry puts the job back in the queue until runAt, keeping the error for inspection.
+func (r *JobRepository) Retry(ctx context.Context, id uuid.UUID, workerID string, runAt time.Time, reason string) error {
+	return r.updateLeased(ctx, id, workerID, map[string]interface{}{
+		"status":       model.JobStatusQueued,
+		"run_at":       runAt,
+		"locked_by":    nil,
+		"locked_until": nil,
+		"last_error":   reason,
+		"updated_at":   time.Now(),
+	})
+}
+
+func (r *JobRepository) Fail(ctx context.Context, id uuid.UUID, workerID string, reason string) error {
+	now := time.Now()
+	return r.updateLeased(ctx, id, workerID, map[string]interface{}{
+		"status":       model.JobStatusFailed,
+		"locked_by":    nil,
+		"locked_until": nil,
+		"last_error":   reason,
+		"completed_at": now,
+		"updated_at":   now,
+	})
+}
+
+func (r *JobRepository) updateLeased(ctx context.Context, id uuid.UUID, workerID string, updates map[string]interface{}) error {
+	result := r.db.WithContext(ctx).Model(&model.Job{}).
+		Where("id = ? AND status = ? AND locked_by = ?", id, model.JobStatusRunning, workerID).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// enqueueJob queues a job, due now unless RunAt is set. Call it inside the transaction that
+// creates the work so the job exists exactly when the work does.
+func enqueueJob(tx *gorm.DB, job *model.Job) error {
+	job.Status = model.JobStatusQueued
+	if job.RunAt.IsZero() {
+		job.RunAt = time.Now()
+	}
+	if job.MaxAttempts == 0 {
+		job.MaxAttempts = model.DefaultJobMaxAttempts
+	}
+	return tx.Omit(clause.Associations).Create(job).Error
+}
@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"study.com/v1/internal/model"
+)
+
+type MediaUploadRepositoryInterface interface {
+	Create(ctx context.Context, upload *model.MediaUpload) error
+	FindByID(ctx context.Context, courseID, id uuid.UUID) (*model.MediaUpload, error)
+	SetStatus(ctx context.Context, id uuid.UUID, fromStatus, toStatus string) error
+	ListExpired(ctx context.Context, before time.Time, limit int) ([]model.MediaUpload, error)
+
+	CompleteVideo(ctx context.Context, upload *model.MediaUpload, video *model.LessonVideo, jobs []*model.Job) (*model.LessonVideo, error)
+	CompleteAttachment(ctx context.Context, upload *model.MediaUpload, attachment *model.LessonAttachment) error
+	CompleteThumbnail(ctx context.Context, upload *model.MediaUpload, thumbnailURL string) (*string, error)
+}
+
+type MediaUploadRepository struct {
+	db *gorm.DB
+}
+
+func NewMediaUploadRepository(db *gorm.DB) *MediaUploadRepository {
+	return &MediaUploadRepository{db: db}
+}
+
+func (r *MediaUploadRepository) Create(ctx context.Context, upload *model.MediaUpload) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Create(upload).Error
+}
+
+// FindByID returns the upload only if it belongs to the course.
+func (r *MediaUploadRepository) FindByID(ctx context.Context, courseID, id uuid.UUID) (*model.MediaUpload, error) {
+	var upload model.MediaUpload
+	err := r.db.WithContext(ctx).Where("id = ? AND course_id = ?", id, courseID).First(&upload).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &upload, nil
+}
+
+// SetStatus moves the upload out of fromStatus, gorm.ErrRecordNotFound means another
+// request changed it first.
+func (r *MediaUploadRepository) SetStatus(ctx context.Context, id uuid.UUID, fromStatus, toStatus string) error {
+	return setUploadStatus(r.db.WithContext(ctx), id, fromStatus, toStatus)
+}
+
+// ListExpired returns pending uploads whose URLs expired before the given time.
+func (r *MediaUploadRepository) ListExpired(ctx context.Context, before time.Time, limit int) ([]model.MediaUpload, error) {
+	var uploads []model.MediaUpload
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND expires_at < ?", model.MediaUploadStatusPending, before).
+		Order("expires_at").
+		Limit(limit).
+		Find(&uploads).Error
+	return uploads, err
+}
+
+// CompleteVideo sets the video of the lesson, replacing the previous one and its transcript,
+// and queues the jobs that process it. The replaced video is returned so that its objects
+// can be removed.
+func (r *MediaUploadRepository) CompleteVideo(ctx context.Context, upload *model.MediaUpload, video *model.LessonVideo, jobs []*model.Job) (*model.LessonVideo, error) {
+	var replaced *model.LessonVideo
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := setUploadStatus(tx, upload.ID, model.MediaUploadStatusPending, model.MediaUploadStatusCompleted); err != nil {
+			return err
+		}
+		for _, job := range jobs {
+			if err := enqueueJob(tx, job); err != nil {
+				return err
+			}
+		}
+
+		// lesson_id is unique including soft deleted rows, so an old row is revived
+		var existing model.LessonVideo
+		err := tx.Unscoped().
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("lesson_id = ?", video.LessonID).
+			Take(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return tx.Omit(clause.Associations).Create(video).Error
+		}
+		if err != nil {
+			return err
+		}
+		if !existing.DeletedAt.Valid {
+			replaced = &existing
+		}
+
+		if err := clearTranscript(tx, existing.ID); err != nil {
+			return err
+		}
+		video.ID = existing.ID
+		video.CreatedAt = existing.CreatedAt
+		video.UpdatedAt = time.Now()
+		return tx.Unscoped().Model(&model.LessonVideo{}).
+			Where("id = ?", existing.ID).
+			Updates(map[string]interface{}{
+				"video_url":            video.VideoURL,
+				"video_hls_url":        video.VideoHlsURL,
+				"thumbnail_url":        video.ThumbnailURL,
+				"duration_seconds":     video.DurationSeconds,
+				"resolution":           video.Resolution,
+				"file_size_bytes":      video.FileSizeBytes,
+				"transcription":        video.Transcription,
+				"transcription_status": video.TranscriptionStatus,
+				"updated_at":           video.UpdatedAt,
+				"deleted_at":           nil,
+			}).Error
+	})
+	return replaced, err
+}
+
+func (r *MediaUploadRepository) CompleteAttachment(ctx context.Context, upload *model.MediaUpload, attachment *model.LessonAttachment) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := setUploadStatus(tx, upload.ID, model.MediaUploadStatusPending, model.MediaUploadStatusCompleted); err != nil {
+			return err
+		}
+		return tx.Omit(clause.Associations).Create(attachment).Error
+	})
+}
+
+// CompleteThumbnail sets the thumbnail of the course and returns the previous URL.
+func (r *MediaUploadRepository) CompleteThumbnail(ctx context.Context, upload *model.MediaUpload, thumbnailURL string) (*string, error) {
+	var previous *string
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := setUploadStatus(tx, upload.ID, model.MediaUploadStatusPending, model.MediaUploadStatusCompleted); err != nil {
+			return err
+		}
+		var course model.Course
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "thumbnail_url").
+			Where("id = ?", upload.CourseID).
+			Take(&course).Error
+		if err != nil {
+			return err
+		}
+		previous = course.ThumbnailURL
+		return tx.Model(&model.Course{}).
+			Where("id = ?", upload.CourseID).
+			Updates(map[string]interface{}{"thumbnail_url": thumbnailURL, "updated_at": time.Now()}).Error
+	})
+	return previous, err
+}
+
+func setUploadStatus(tx *gorm.DB, id uuid.UUID, fromStatus, toStatus string) error {
+	updates := map[string]interface{}{"status": toStatus}
+	if toStatus == model.MediaUploadStatusCompleted {
+		updates["completed_at"] = time.Now()
+	}
+	result := tx.Model(&model.MediaUpload{}).
+		Where("id = ? AND status = ?", id, fromStatus).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
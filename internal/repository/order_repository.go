@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"study.com/v1/internal/model"
+)
+
+type OrderRepositoryInterface interface {
+	FindByID(ctx context.Context, id uuid.UUID) (*model.Order, error)
+	ListByUserID(ctx context.Context, userID uuid.UUID, filter OrderFilter, offset, limit int) ([]model.Order, int64, error)
+	DecideParentApproval(ctx context.Context, order *model.Order) error
+}
+
+type OrderFilter struct {
+	Status               string
+	ParentApprovalStatus string
+}
+
+type OrderRepository struct {
+	db *gorm.DB
+}
+
+func NewOrderRepository(db *gorm.DB) *OrderRepository {
+	return &OrderRepository{db: db}
+}
+
+func (r *OrderRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Order, error) {
+	var order model.Order
+	err := r.db.WithContext(ctx).
+		Preload("Items.Course").
+		Where("id = ?", id).
+		First(&order).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *OrderRepository) ListByUserID(ctx context.Context, userID uuid.UUID, filter OrderFilter, offset, limit int) ([]model.Order, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.Order{}).Where("user_id = ?", userID)
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.ParentApprovalStatus != "" {
+		query = query.Where("parent_approval_status = ?", filter.ParentApprovalStatus)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var orders []model.Order
+	err := query.
+		Preload("Items.Course").
+		// Orders have no created_at, order numbers are issued in sequence
+		Order("order_number DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&orders).Error
+	return orders, total, err
+}
+
+// DecideParentApproval stores the parent's decision on an unpaid order that is waiting for
+// it; a rejection also cancels the order. It returns gorm.ErrRecordNotFound when the order
+// was paid, cancelled or decided concurrently.
+func (r *OrderRepository) DecideParentApproval(ctx context.Context, order *model.Order) error {
+	updates := map[string]interface{}{
+		"parent_approval_status": order.ParentApprovalStatus,
+		"parent_approved_by":     order.ParentApprovedBy,
+		"parent_approved_at":     order.ParentApprovedAt,
+		"parent_approval_note":   order.ParentApprovalNote,
+	}
+	if order.ParentApprovalStatus == model.ParentApprovalRejected {
+		updates["status"] = model.OrderStatusCancelled
+	}
+	result := r.db.WithContext(ctx).
+		Model(&model.Order{}).
+		Where("id = ? AND status = ? AND parent_approval_status = ?", order.ID, model.OrderStatusPending, model.ParentApprovalPending).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
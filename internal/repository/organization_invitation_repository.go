@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"study.com/v1/internal/model"
+)
+
+type OrganizationInvitationRepositoryInterface interface {
+	Create(ctx context.Context, invitation *model.OrganizationInvitation) error
+	Delete(ctx context.Context, orgID, id uuid.UUID) (bool, error)
+	FindByTokenHash(ctx context.Context, tokenHash string) (*model.OrganizationInvitation, error)
+	ListPending(ctx context.Context, orgID uuid.UUID, now time.Time) ([]model.OrganizationInvitation, error)
+	Accept(ctx context.Context, invitation *model.OrganizationInvitation, member *model.OrganizationMember, roleIDs []uuid.UUID) error
+}
+
+type OrganizationInvitationRepository struct {
+	db *gorm.DB
+}
+
+func NewOrganizationInvitationRepository(db *gorm.DB) *OrganizationInvitationRepository {
+	return &OrganizationInvitationRepository{db: db}
+}
+
+// Create replaces any pending invitation for the same email, so only the newest link works.
+func (r *OrganizationInvitationRepository) Create(ctx context.Context, invitation *model.OrganizationInvitation) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Where("organization_id = ? AND email = ? AND accepted_at IS NULL", invitation.OrganizationID, invitation.Email).
+			Delete(&model.OrganizationInvitation{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Omit(clause.Associations).Create(invitation).Error
+	})
+}
+
+func (r *OrganizationInvitationRepository) Delete(ctx context.Context, orgID, id uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND organization_id = ? AND accepted_at IS NULL", id, orgID).
+		Delete(&model.OrganizationInvitation{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *OrganizationInvitationRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*model.OrganizationInvitation, error) {
+	var invitation model.OrganizationInvitation
+	err := r.db.WithContext(ctx).
+		Preload("Organization").
+		Where("token_hash = ?", tokenHash).
+		First(&invitation).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *OrganizationInvitationRepository) ListPending(ctx context.Context, orgID uuid.UUID, now time.Time) ([]model.OrganizationInvitation, error) {
+	var invitations []model.OrganizationInvitation
+	err := r.db.WithContext(ctx).
+		Where("organization_id = ? AND accepted_at IS NULL AND expires_at > ?", orgID, now).
+		Order("created_at DESC").
+		Find(&invitations).Error
+	return invitations, err
+}
+
+// Accept marks the invitation as used and creates the membership in one transaction.
+// It returns gorm.ErrRecordNotFound when the invitation was accepted concurrently.
+func (r *OrganizationInvitationRepository) Accept(ctx context.Context, invitation *model.OrganizationInvitation, member *model.OrganizationMember, roleIDs []uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.OrganizationInvitation{}).
+			Where("id = ? AND accepted_at IS NULL", invitation.ID).
+			Updates(map[string]interface{}{
+				"accepted_at": invitation.AcceptedAt,
+				"accepted_by": invitation.AcceptedBy,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return addMember(tx, member, roleIDs, &invitation.InvitedBy)
+	})
+}
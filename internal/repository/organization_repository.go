@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"study.com/v1/internal/model"
+)
+
+// OrganizationRepositoryInterface covers organizations and their members. Every member
+// query takes the organization id so that data never crosses tenants.
+type OrganizationRepositoryInterface interface {
+	Create(ctx context.Context, org *model.Organization, ownerRoleID uuid.UUID) error
+	Update(ctx context.Context, org *model.Organization) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	FindByID(ctx context.Context, id uuid.UUID) (*model.Organization, error)
+	SlugExists(ctx context.Context, slug string) (bool, error)
+	ListByMember(ctx context.Context, userID uuid.UUID, offset, limit int) ([]model.Organization, int64, error)
+
+	FindMember(ctx context.Context, orgID, userID uuid.UUID) (*model.OrganizationMember, error)
+	ListMembers(ctx context.Context, orgID uuid.UUID, filter MemberFilter, offset, limit int) ([]model.OrganizationMember, int64, error)
+	CountMembers(ctx context.Context, orgID uuid.UUID) (int64, error)
+	UpdateMember(ctx context.Context, member *model.OrganizationMember) error
+	RemoveMember(ctx context.Context, orgID, userID uuid.UUID) error
+	FindMemberRoles(ctx context.Context, orgID uuid.UUID, userIDs []uuid.UUID) ([]model.UserRole, error)
+	ReplaceMemberRoles(ctx context.Context, orgID, userID uuid.UUID, roleIDs []uuid.UUID, assignedBy *uuid.UUID) error
+}
+
+type MemberFilter struct {
+	Search     string
+	MemberRole string
+	Status     string
+}
+
+type OrganizationRepository struct {
+	db *gorm.DB
+}
+
+func NewOrganizationRepository(db *gorm.DB) *OrganizationRepository {
+	return &OrganizationRepository{db: db}
+}
+
+// Create inserts the organization together with its owner membership and gives the
+// owner the ORG_OWNER role inside the new organization.
+func (r *OrganizationRepository) Create(ctx context.Context, org *model.Organization, ownerRoleID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(org).Error; err != nil {
+			return err
+		}
+		owner := &model.OrganizationMember{
+			OrganizationID: org.ID,
+			UserID:         org.OwnerID,
+			MemberRole:     model.OrgMemberOwner,
+			Status:         model.OrgMemberStatusActive,
+			JoinedAt:       time.Now(),
+		}
+		return addMember(tx, owner, []uuid.UUID{ownerRoleID}, nil)
+	})
+}
+
+func (r *OrganizationRepository) Update(ctx context.Context, org *model.Organization) error {
+	return r.db.WithContext(ctx).
+		Model(org).
+		Select("name", "description", "logo_url", "website", "org_type", "email", "phone", "address", "city", "country").
+		Updates(org).Error
+}
+
+// Delete soft-deletes the organization. Its members and org-scoped role assignments
+// stay in place but stop granting anything, see PermissionRepository.
+func (r *OrganizationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&model.Organization{}).Error
+}
+
+func (r *OrganizationRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Organization, error) {
+	var org model.Organization
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&org).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &org, nil
+}
+
+// SlugExists also looks at deleted organizations, their slugs are still taken by the
+// unique index.
+func (r *OrganizationRepository) SlugExists(ctx context.Context, slug string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Unscoped().
+		Model(&model.Organization{}).
+		Where("slug = ?", slug).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *OrganizationRepository) ListByMember(ctx context.Context, userID uuid.UUID, offset, limit int) ([]model.Organization, int64, error) {
+	query := r.db.WithContext(ctx).
+		Model(&model.Organization{}).
+		Joins("JOIN organization_members om ON om.organization_id = organizations.id").
+		Where("om.user_id = ? AND om.status = ?", userID, model.OrgMemberStatusActive)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var orgs []model.Organization
+	err := query.Order("organizations.name").Offset(offset).Limit(limit).Find(&orgs).Error
+	return orgs, total, err
+}
+
+func (r *OrganizationRepository) FindMember(ctx context.Context, orgID, userID uuid.UUID) (*model.OrganizationMember, error) {
+	var member model.OrganizationMember
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Where("organization_id = ? AND user_id = ?", orgID, userID).
+		First(&member).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (r *OrganizationRepository) ListMembers(ctx context.Context, orgID uuid.UUID, filter MemberFilter, offset, limit int) ([]model.OrganizationMember, int64, error) {
+	query := r.db.WithContext(ctx).
+		Model(&model.OrganizationMember{}).
+		Where("organization_members.organization_id = ?", orgID)
+	if filter.MemberRole != "" {
+		query = query.Where("organization_members.member_role = ?", filter.MemberRole)
+	}
+	if filter.Status != "" {
+		query = query.Where("organization_members.status = ?", filter.Status)
+	}
+	if filter.Search != "" {
+		like := "%" + filter.Search + "%"
+		query = query.
+			Joins("JOIN users ON users.id = organization_members.user_id").
+			Where("users.email ILIKE ? OR users.user_name ILIKE ? OR users.full_name ILIKE ? OR organization_members.student_id ILIKE ?",
+				like, like, like, like)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var members []model.OrganizationMember
+	err := query.
+		Preload("User").
+		Order("organization_members.joined_at").
+		Offset(offset).
+		Limit(limit).
+		Find(&members).Error
+	return members, total, err
+}
+
+func (r *OrganizationRepository) CountMembers(ctx context.Context, orgID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&model.OrganizationMember{}).
+		Where("organization_id = ?", orgID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *OrganizationRepository) UpdateMember(ctx context.Context, member *model.OrganizationMember) error {
+	return r.db.WithContext(ctx).
+		Model(member).
+		Select("member_role", "department", "student_id", "status").
+		Updates(member).Error
+}
+
+// RemoveMember deletes the membership and every role the user holds in the organization.
+func (r *OrganizationRepository) RemoveMember(ctx context.Context, orgID, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("organization_id = ? AND user_id = ?", orgID, userID).Delete(&model.UserRole{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("organization_id = ? AND user_id = ?", orgID, userID).Delete(&model.OrganizationMember{}).Error
+	})
+}
+
+func (r *OrganizationRepository) FindMemberRoles(ctx context.Context, orgID uuid.UUID, userIDs []uuid.UUID) ([]model.UserRole, error) {
+	var userRoles []model.UserRole
+	if len(userIDs) == 0 {
+		return userRoles, nil
+	}
+	err := r.db.WithContext(ctx).
+		Preload("Role").
+		Where("organization_id = ? AND user_id IN ?", orgID, userIDs).
+		Order("assigned_at").
+		Find(&userRoles).Error
+	return userRoles, err
+}
+
+// ReplaceMemberRoles makes roleIDs the complete list of roles the user holds in the organization.
+func (r *OrganizationRepository) ReplaceMemberRoles(ctx context.Context, orgID, userID uuid.UUID, roleIDs []uuid.UUID, assignedBy *uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		stale := tx.Where("organization_id = ? AND user_id = ?", orgID, userID)
+		if len(roleIDs) > 0 {
+			stale = stale.Where("role_id NOT IN ?", roleIDs)
+		}
+		if err := stale.Delete(&model.UserRole{}).Error; err != nil {
+			return err
+		}
+		return assignOrganizationRoles(tx, orgID, userID, roleIDs, assignedBy)
+	})
+}
+
+// addMember inserts the membership and its org-scoped roles. It is shared with the
+// invitation repository so that accepting an invitation is a single transaction.
+func addMember(tx *gorm.DB, member *model.OrganizationMember, roleIDs []uuid.UUID, assignedBy *uuid.UUID) error {
+	if err := tx.Omit(clause.Associations).Create(member).Error; err != nil {
+		return err
+	}
+	return assignOrganizationRoles(tx, member.OrganizationID, member.UserID, roleIDs, assignedBy)
+}
+
+func assignOrganizationRoles(tx *gorm.DB, orgID, userID uuid.UUID, roleIDs []uuid.UUID, assignedBy *uuid.UUID) error {
+	if len(roleIDs) == 0 {
+		return nil
+	}
+	now := time.Now()
+	userRoles := make([]model.UserRole, 0, len(roleIDs))
+	for _, roleID := range roleIDs {
+		userRoles = append(userRoles, model.UserRole{
+			UserID:         userID,
+			RoleID:         roleID,
+			OrganizationID: &orgID,
+			AssignedAt:     now,
+			AssignedBy:     assignedBy,
+		})
+	}
+	return tx.Omit(clause.Associations).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&userRoles).Error
+}
@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"study.com/v1/internal/model"
+)
+
+type ParentRepositoryInterface interface {
+	Create(ctx context.Context, relation *model.ParentStudentRelation) error
+	Reopen(ctx context.Context, relation *model.ParentStudentRelation) error
+	Activate(ctx context.Context, relation *model.ParentStudentRelation, parentRoleID uuid.UUID, consent *model.AgeVerification) error
+	Revoke(ctx context.Context, relation *model.ParentStudentRelation) error
+	UpdatePermissions(ctx context.Context, relation *model.ParentStudentRelation) error
+	FindByID(ctx context.Context, id uuid.UUID) (*model.ParentStudentRelation, error)
+	FindByPair(ctx context.Context, parentID, studentID uuid.UUID) (*model.ParentStudentRelation, error)
+	FindActive(ctx context.Context, parentID, studentID uuid.UUID) (*model.ParentStudentRelation, error)
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]model.ParentStudentRelation, error)
+	ListActiveChildren(ctx context.Context, parentID uuid.UUID) ([]model.ParentStudentRelation, error)
+	CountActiveParents(ctx context.Context, studentID uuid.UUID) (int64, error)
+}
+
+type ParentRepository struct {
+	db *gorm.DB
+}
+
+func NewParentRepository(db *gorm.DB) *ParentRepository {
+	return &ParentRepository{db: db}
+}
+
+func (r *ParentRepository) Create(ctx context.Context, relation *model.ParentStudentRelation) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Create(relation).Error
+}
+
+// Reopen turns a revoked link back into a pending request so the pair keeps a single row.
+func (r *ParentRepository) Reopen(ctx context.Context, relation *model.ParentStudentRelation) error {
+	return r.db.WithContext(ctx).
+		Model(&model.ParentStudentRelation{}).
+		Where("id = ?", relation.ID).
+		Updates(map[string]interface{}{
+			"relationship":          relation.Relationship,
+			"status":                model.ParentLinkPending,
+			"can_view_progress":     true,
+			"can_view_grades":       true,
+			"can_approve_purchases": true,
+			"confirmed_at":          nil,
+			"confirmed_by":          nil,
+			"revoked_at":            nil,
+			"revoked_by":            nil,
+			"updated_at":            time.Now(),
+		}).Error
+}
+
+// Activate confirms a pending link, gives the parent the global PARENT role and stores the
+// consent record of a minor, all in one transaction. It returns gorm.ErrRecordNotFound when
+// the link is no longer pending.
+func (r *ParentRepository) Activate(ctx context.Context, relation *model.ParentStudentRelation, parentRoleID uuid.UUID, consent *model.AgeVerification) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.ParentStudentRelation{}).
+			Where("id = ? AND status = ?", relation.ID, model.ParentLinkPending).
+			Updates(map[string]interface{}{
+				"status":       model.ParentLinkActive,
+				"confirmed_at": relation.ConfirmedAt,
+				"confirmed_by": relation.ConfirmedBy,
+				"updated_at":   time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := tx.Exec(`
+			INSERT INTO user_roles (user_id, role_id, assigned_at)
+			VALUES (?, ?, CURRENT_TIMESTAMP)
+			ON CONFLICT (user_id, role_id) WHERE organization_id IS NULL DO NOTHING
+		`, relation.ParentUserID, parentRoleID).Error; err != nil {
+			return err
+		}
+		if consent == nil {
+			return nil
+		}
+		return tx.Omit(clause.Associations).Create(consent).Error
+	})
+}
+
+func (r *ParentRepository) Revoke(ctx context.Context, relation *model.ParentStudentRelation) error {
+	return r.db.WithContext(ctx).
+		Model(&model.ParentStudentRelation{}).
+		Where("id = ?", relation.ID).
+		Updates(map[string]interface{}{
+			"status":     model.ParentLinkRevoked,
+			"revoked_at": relation.RevokedAt,
+			"revoked_by": relation.RevokedBy,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+func (r *ParentRepository) UpdatePermissions(ctx context.Context, relation *model.ParentStudentRelation) error {
+	return r.db.WithContext(ctx).
+		Model(relation).
+		Select("can_view_progress", "can_view_grades", "can_approve_purchases").
+		Updates(relation).Error
+}
+
+func (r *ParentRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.ParentStudentRelation, error) {
+	return r.first(ctx, "id = ?", id)
+}
+
+func (r *ParentRepository) FindByPair(ctx context.Context, parentID, studentID uuid.UUID) (*model.ParentStudentRelation, error) {
+	return r.first(ctx, "parent_user_id = ? AND student_user_id = ?", parentID, studentID)
+}
+
+func (r *ParentRepository) FindActive(ctx context.Context, parentID, studentID uuid.UUID) (*model.ParentStudentRelation, error) {
+	return r.first(ctx, "parent_user_id = ? AND student_user_id = ? AND status = ?", parentID, studentID, model.ParentLinkActive)
+}
+
+// ListByUserID returns the links where the user is either the parent or the student.
+func (r *ParentRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]model.ParentStudentRelation, error) {
+	var relations []model.ParentStudentRelation
+	err := r.db.WithContext(ctx).
+		Preload("Parent").
+		Preload("Student").
+		Where("parent_user_id = ? OR student_user_id = ?", userID, userID).
+		Order("created_at DESC").
+		Find(&relations).Error
+	return relations, err
+}
+
+func (r *ParentRepository) ListActiveChildren(ctx context.Context, parentID uuid.UUID) ([]model.ParentStudentRelation, error) {
+	var relations []model.ParentStudentRelation
+	err := r.db.WithContext(ctx).
+		Preload("Parent").
+		Preload("Student").
+		Where("parent_user_id = ? AND status = ?", parentID, model.ParentLinkActive).
+		Order("confirmed_at").
+		Find(&relations).Error
+	return relations, err
+}
+
+func (r *ParentRepository) CountActiveParents(ctx context.Context, studentID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&model.ParentStudentRelation{}).
+		Where("student_user_id = ? AND status = ?", studentID, model.ParentLinkActive).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *ParentRepository) first(ctx context.Context, query string, args ...interface{}) (*model.ParentStudentRelation, error) {
+	var relation model.ParentStudentRelation
+	err := r.db.WithContext(ctx).
+		Preload("Parent").
+		Preload("Student").
+		Where(query, args...).
+		First(&relation).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &relation, nil
+}
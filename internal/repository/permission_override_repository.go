@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"study.com/v1/internal/model"
+)
+
+type PermissionOverrideRepositoryInterface interface {
+	Create(ctx context.Context, override *model.UserPermissionOverride) error
+	Delete(ctx context.Context, userID, id uuid.UUID) (bool, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*model.UserPermissionOverride, error)
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]model.UserPermissionOverride, error)
+	FindActiveByUserID(ctx context.Context, userID uuid.UUID, now time.Time) ([]model.UserPermissionOverride, error)
+}
+
+type PermissionOverrideRepository struct {
+	db *gorm.DB
+}
+
+func NewPermissionOverrideRepository(db *gorm.DB) *PermissionOverrideRepository {
+	return &PermissionOverrideRepository{db: db}
+}
+
+// Create writes every column so that zero values such as is_granted = false are kept.
+// The caller must set ID and GrantedAt.
+func (r *PermissionOverrideRepository) Create(ctx context.Context, override *model.UserPermissionOverride) error {
+	return r.db.WithContext(ctx).
+		Select("*").
+		Omit(clause.Associations).
+		Create(override).Error
+}
+
+// Delete removes the override only if it belongs to userID, and reports whether a row was removed.
+func (r *PermissionOverrideRepository) Delete(ctx context.Context, userID, id uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", id, userID).
+		Delete(&model.UserPermissionOverride{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *PermissionOverrideRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.UserPermissionOverride, error) {
+	var override model.UserPermissionOverride
+	err := r.db.WithContext(ctx).Preload("Permission").Where("id = ?", id).First(&override).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &override, nil
+}
+
+func (r *PermissionOverrideRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]model.UserPermissionOverride, error) {
+	var overrides []model.UserPermissionOverride
+	err := r.db.WithContext(ctx).
+		Preload("Permission").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&overrides).Error
+	return overrides, err
+}
+
+// FindActiveByUserID skips overrides whose expires_at has passed.
+func (r *PermissionOverrideRepository) FindActiveByUserID(ctx context.Context, userID uuid.UUID, now time.Time) ([]model.UserPermissionOverride, error) {
+	var overrides []model.UserPermissionOverride
+	err := r.db.WithContext(ctx).
+		Preload("Permission").
+		Where("user_id = ? AND (expires_at IS NULL OR expires_at > ?)", userID, now).
+		Find(&overrides).Error
+	return overrides, err
+}
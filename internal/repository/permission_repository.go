@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"study.com/v1/internal/model"
+)
+
+type PermissionRepositoryInterface interface {
+	FindRolePermissionsByUserID(ctx context.Context, userID uuid.UUID) ([]model.RolePermission, error)
+	FindOrganizationRolePermissionsByUserID(ctx context.Context, userID uuid.UUID) (map[uuid.UUID][]model.RolePermission, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*model.Permission, error)
+	FindByIDs(ctx context.Context, ids []uuid.UUID) ([]model.Permission, error)
+	List(ctx context.Context, search string, offset, limit int) ([]model.Permission, int64, error)
+	UpdateDescription(ctx context.Context, id uuid.UUID, description string) error
+}
+
+type PermissionRepository struct {
+	db *gorm.DB
+}
+
+func NewPermissionRepository(db *gorm.DB) *PermissionRepository {
+	return &PermissionRepository{db: db}
+}
+
+// FindRolePermissionsByUserID returns every role_permissions row reachable through the
+// user's global roles, grants and denies alike, with the permission preloaded.
+func (r *PermissionRepository) FindRolePermissionsByUserID(ctx context.Context, userID uuid.UUID) ([]model.RolePermission, error) {
+	var rolePermissions []model.RolePermission
+	err := r.db.WithContext(ctx).
+		Preload("Permission").
+		Joins("JOIN user_roles ON user_roles.role_id = role_permissions.role_id").
+		Where("user_roles.user_id = ? AND user_roles.organization_id IS NULL", userID).
+		Find(&rolePermissions).Error
+	return rolePermissions, err
+}
+
+// FindOrganizationRolePermissionsByUserID returns the role_permissions rows of the roles the
+// user holds inside organizations, grouped by organization. Only active memberships of
+// organizations that are active and not deleted count.
+func (r *PermissionRepository) FindOrganizationRolePermissionsByUserID(ctx context.Context, userID uuid.UUID) (map[uuid.UUID][]model.RolePermission, error) {
+	var assignments []struct {
+		OrganizationID uuid.UUID
+		RoleID         uuid.UUID
+	}
+	err := r.db.WithContext(ctx).
+		Table("user_roles").
+		Select("user_roles.organization_id, user_roles.role_id").
+		Joins("JOIN organization_members om ON om.organization_id = user_roles.organization_id AND om.user_id = user_roles.user_id").
+		Joins("JOIN organizations o ON o.id = user_roles.organization_id").
+		Where("user_roles.user_id = ? AND om.status = ? AND o.is_active AND o.deleted_at IS NULL", userID, model.OrgMemberStatusActive).
+		Scan(&assignments).Error
+	if err != nil || len(assignments) == 0 {
+		return nil, err
+	}
+
+	roleIDs := make([]uuid.UUID, 0, len(assignments))
+	for _, a := range assignments {
+		roleIDs = append(roleIDs, a.RoleID)
+	}
+	var rolePermissions []model.RolePermission
+	err = r.db.WithContext(ctx).
+		Preload("Permission").
+		Where("role_id IN ?", roleIDs).
+		Find(&rolePermissions).Error
+	if err != nil {
+		return nil, err
+	}
+	byRole := make(map[uuid.UUID][]model.RolePermission)
+	for _, rp := range rolePermissions {
+		byRole[rp.RoleID] = append(byRole[rp.RoleID], rp)
+	}
+
+	result := make(map[uuid.UUID][]model.RolePermission)
+	for _, a := range assignments {
+		result[a.OrganizationID] = append(result[a.OrganizationID], byRole[a.RoleID]...)
+	}
+	return result, nil
+}
+
+func (r *PermissionRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Permission, error) {
+	var permission model.Permission
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&permission).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &permission, nil
+}
+
+func (r *PermissionRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]model.Permission, error) {
+	var permissions []model.Permission
+	if len(ids) == 0 {
+		return permissions, nil
+	}
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&permissions).Error
+	return permissions, err
+}
+
+func (r *PermissionRepository) List(ctx context.Context, search string, offset, limit int) ([]model.Permission, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.Permission{})
+	if search != "" {
+		like := "%" + search + "%"
+		query = query.Where("name ILIKE ? OR description ILIKE ?", like, like)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var permissions []model.Permission
+	err := query.Order("name").Offset(offset).Limit(limit).Find(&permissions).Error
+	return permissions, total, err
+}
+
+func (r *PermissionRepository) UpdateDescription(ctx context.Context, id uuid.UUID, description string) error {
+	return r.db.WithContext(ctx).
+		Model(&model.Permission{}).
+		Where("id = ?", id).
+		Update("description", description).Error
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"study.com/v1/internal/model"
+)
+
+type QuizRepositoryInterface interface {
+	ListAttemptsByUserID(ctx context.Context, userID uuid.UUID, offset, limit int) ([]model.QuizAttempt, int64, error)
+}
+
+type QuizRepository struct {
+	db *gorm.DB
+}
+
+func NewQuizRepository(db *gorm.DB) *QuizRepository {
+	return &QuizRepository{db: db}
+}
+
+// ListAttemptsByUserID returns the user's quiz attempts with their quiz, newest first.
+func (r *QuizRepository) ListAttemptsByUserID(ctx context.Context, userID uuid.UUID, offset, limit int) ([]model.QuizAttempt, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.QuizAttempt{}).Where("user_id = ?", userID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var attempts []model.QuizAttempt
+	err := query.
+		Preload("Quiz").
+		Order("started_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&attempts).Error
+	return attempts, total, err
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"study.com/v1/internal/model"
+)
+
+const (
+	RevokeReasonRotated = "rotated"
+	RevokeReasonReused  = "reuse_detected"
+	RevokeReasonLogout  = "logout"
+	RevokeReasonRelogin = "relogin"
+)
+
+type RefreshTokenRepositoryInterface interface {
+	Create(ctx context.Context, token *model.RefreshToken) error
+	FindByHash(ctx context.Context, tokenHash string) (*model.RefreshToken, error)
+	Rotate(ctx context.Context, id uuid.UUID, next *model.RefreshToken) (bool, error)
+	RevokeFamily(ctx context.Context, familyID uuid.UUID, reason string) error
+	RevokeDevice(ctx context.Context, userID, deviceID uuid.UUID, reason string) error
+	RevokeAllForUser(ctx context.Context, userID uuid.UUID, reason string) error
+}
+
+type RefreshTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{db: db}
+}
+
+func (r *RefreshTokenRepository) Create(ctx context.Context, token *model.RefreshToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+func (r *RefreshTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*model.RefreshToken, error) {
+	var token model.RefreshToken
+	err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Rotate revokes a token only if it is still active and stores its successor next in one
+// transaction, so a failed insert cannot leave the family without a usable token. It
+// reports false when another request already rotated it, which callers must treat as reuse.
+func (r *RefreshTokenRepository) Rotate(ctx context.Context, id uuid.UUID, next *model.RefreshToken) (bool, error) {
+	rotated := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.
+			Model(&model.RefreshToken{}).
+			Where("id = ? AND is_revoked = ?", id, false).
+			Updates(map[string]interface{}{
+				"is_revoked":     true,
+				"revoked_at":     now,
+				"revoked_reason": RevokeReasonRotated,
+				"replaced_by_id": next.ID,
+				"last_used_at":   now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != 1 {
+			return nil
+		}
+		if err := tx.Create(next).Error; err != nil {
+			return err
+		}
+		rotated = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return rotated, nil
+}
+
+func (r *RefreshTokenRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID, reason string) error {
+	return r.revokeWhere(ctx, reason, "family_id = ?", familyID)
+}
+
+func (r *RefreshTokenRepository) RevokeDevice(ctx context.Context, userID, deviceID uuid.UUID, reason string) error {
+	return r.revokeWhere(ctx, reason, "user_id = ? AND device_id = ?", userID, deviceID)
+}
+
+func (r *RefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID uuid.UUID, reason string) error {
+	return r.revokeWhere(ctx, reason, "user_id = ?", userID)
+}
+
+func (r *RefreshTokenRepository) revokeWhere(ctx context.Context, reason string, query string, args ...interface{}) error {
+	return r.db.WithContext(ctx).
+		Model(&model.RefreshToken{}).
+		Where(query, args...).
+		Where("is_revoked = ?", false).
+		Updates(map[string]interface{}{
+			"is_revoked":     true,
+			"revoked_at":     time.Now(),
+			"revoked_reason": reason,
+		}).Error
+}
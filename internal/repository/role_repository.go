@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"study.com/v1/internal/model"
+)
+
+type RoleRepositoryInterface interface {
+	Create(ctx context.Context, role *model.Role) error
+	Update(ctx context.Context, role *model.Role) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	FindByID(ctx context.Context, id uuid.UUID) (*model.Role, error)
+	FindByIDs(ctx context.Context, ids []uuid.UUID) ([]model.Role, error)
+	FindByName(ctx context.Context, orgID *uuid.UUID, name string) (*model.Role, error)
+	List(ctx context.Context, orgID *uuid.UUID, search string, offset, limit int) ([]model.Role, int64, error)
+	AddPermissions(ctx context.Context, grants []model.RolePermission) error
+	RemovePermissions(ctx context.Context, roleID uuid.UUID, permissionIDs []uuid.UUID) error
+	ReplacePermissions(ctx context.Context, roleID uuid.UUID, grants []model.RolePermission) error
+}
+
+// RoleRepository lists and looks up roles per tenant: a nil orgID means the global roles,
+// otherwise the custom roles of that organization.
+type RoleRepository struct {
+	db *gorm.DB
+}
+
+func NewRoleRepository(db *gorm.DB) *RoleRepository {
+	return &RoleRepository{db: db}
+}
+
+func (r *RoleRepository) Create(ctx context.Context, role *model.Role) error {
+	return r.db.WithContext(ctx).Create(role).Error
+}
+
+func (r *RoleRepository) Update(ctx context.Context, role *model.Role) error {
+	return r.db.WithContext(ctx).
+		Model(role).
+		Select("name", "description").
+		Updates(role).Error
+}
+
+// Delete also removes the role from every user and drops its permission links.
+func (r *RoleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("role_id = ?", id).Delete(&model.UserRole{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("role_id = ?", id).Delete(&model.RolePermission{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&model.Role{}).Error
+	})
+}
+
+func (r *RoleRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Role, error) {
+	var role model.Role
+	err := r.db.WithContext(ctx).
+		Preload("RolePermissions.Permission").
+		Where("id = ?", id).
+		First(&role).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *RoleRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]model.Role, error) {
+	var roles []model.Role
+	if len(ids) == 0 {
+		return roles, nil
+	}
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&roles).Error
+	return roles, err
+}
+
+func (r *RoleRepository) FindByName(ctx context.Context, orgID *uuid.UUID, name string) (*model.Role, error) {
+	var role model.Role
+	err := scopeOrganization(r.db.WithContext(ctx), orgID).Where("name = ?", name).First(&role).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *RoleRepository) List(ctx context.Context, orgID *uuid.UUID, search string, offset, limit int) ([]model.Role, int64, error) {
+	query := scopeOrganization(r.db.WithContext(ctx), orgID).Model(&model.Role{})
+	if search != "" {
+		like := "%" + search + "%"
+		query = query.Where("name ILIKE ? OR description ILIKE ?", like, like)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var roles []model.Role
+	err := query.Order("name").Offset(offset).Limit(limit).Find(&roles).Error
+	return roles, total, err
+}
+
+// AddPermissions inserts the grants, overwriting is_granted and conditions of links
+// that already exist.
+func (r *RoleRepository) AddPermissions(ctx context.Context, grants []model.RolePermission) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return upsertRolePermissions(tx, grants)
+	})
+}
+
+func (r *RoleRepository) RemovePermissions(ctx context.Context, roleID uuid.UUID, permissionIDs []uuid.UUID) error {
+	if len(permissionIDs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Where("role_id = ? AND permission_id IN ?", roleID, permissionIDs).
+		Delete(&model.RolePermission{}).Error
+}
+
+// ReplacePermissions makes grants the complete permission list of the role.
+func (r *RoleRepository) ReplacePermissions(ctx context.Context, roleID uuid.UUID, grants []model.RolePermission) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		keep := make([]uuid.UUID, 0, len(grants))
+		for _, g := range grants {
+			keep = append(keep, g.PermissionID)
+		}
+		stale := tx.Where("role_id = ?", roleID)
+		if len(keep) > 0 {
+			stale = stale.Where("permission_id NOT IN ?", keep)
+		}
+		if err := stale.Delete(&model.RolePermission{}).Error; err != nil {
+			return err
+		}
+		return upsertRolePermissions(tx, grants)
+	})
+}
+
+// upsertRolePermissions writes the rows with plain SQL: GORM would replace a false
+// is_granted with the column default and silently turn a deny into a grant.
+func upsertRolePermissions(tx *gorm.DB, grants []model.RolePermission) error {
+	for _, g := range grants {
+		err := tx.Exec(`
+			INSERT INTO role_permissions (role_id, permission_id, is_granted, conditions, created_at)
+			VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)
+			ON CONFLICT (role_id, permission_id)
+			DO UPDATE SET is_granted = EXCLUDED.is_granted, conditions = EXCLUDED.conditions
+		`, g.RoleID, g.PermissionID, g.IsGranted, g.Conditions).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"study.com/v1/internal/model"
+)
+
+// How the search text is matched against course_search_documents
+const (
+	// SearchModeFullText matches whole words with websearch syntax ("quoted phrase", -word, or)
+	SearchModeFullText = "fulltext"
+	// SearchModeFuzzy matches by trigram similarity of the title and tags, it tolerates typos
+	SearchModeFuzzy = "fuzzy"
+)
+
+// The text search configuration and the fragments below come from migration 0007. Every
+// fragment takes the search text as its only argument.
+const (
+	searchJoinSQL = "JOIN course_search_documents ON course_search_documents.course_id = courses.id"
+
+	fullTextMatchSQL = "course_search_documents.document @@ websearch_to_tsquery('vietnamese_unaccent', ?)"
+	fullTextRankSQL  = "ts_rank_cd(course_search_documents.document, websearch_to_tsquery('vietnamese_unaccent', ?))"
+	prefixMatchSQL   = "course_search_documents.document @@ to_tsquery('vietnamese_unaccent', ?)"
+	prefixRankSQL    = "ts_rank_cd(course_search_documents.document, to_tsquery('vietnamese_unaccent', ?))"
+
+	// <% uses the trigram index with pg_trgm.word_similarity_threshold (0.6 by default)
+	fuzzyMatchSQL = "lower(immutable_unaccent(?)) <% course_search_documents.search_text"
+	fuzzyRankSQL  = "word_similarity(lower(immutable_unaccent(?)), course_search_documents.search_text)"
+)
+
+// SearchRepositoryInterface serves search-as-you-type and maintains the search index. The
+// search itself is a filter of the catalog, see CatalogFilter.Search.
+type SearchRepositoryInterface interface {
+	SuggestCourses(ctx context.Context, prefixQuery, text string, limit int) ([]CourseSuggestion, error)
+	SuggestTags(ctx context.Context, slugPrefix string, limit int) ([]TagSuggestion, error)
+	Reindex(ctx context.Context) (int64, error)
+}
+
+type CourseSuggestion struct {
+	ID    uuid.UUID
+	Slug  string
+	Title string
+}
+
+type TagSuggestion struct {
+	Name        string
+	Slug        string
+	CourseCount int64
+}
+
+type SearchRepository struct {
+	db *gorm.DB
+}
+
+func NewSearchRepository(db *gorm.DB) *SearchRepository {
+	return &SearchRepository{db: db}
+}
+
+// SuggestCourses completes the last word being typed: prefixQuery is a to_tsquery
+// expression such as "lap & trin:*". When nothing starts with it, the raw text is matched
+// by trigram similarity so a typo still gets suggestions.
+func (r *SearchRepository) SuggestCourses(ctx context.Context, prefixQuery, text string, limit int) ([]CourseSuggestion, error) {
+	var suggestions []CourseSuggestion
+	if prefixQuery != "" {
+		err := r.publishedDocuments(ctx).
+			Select("courses.id, courses.slug, courses.title").
+			Where(prefixMatchSQL, prefixQuery).
+			Order(orderByExpr(prefixRankSQL+" DESC, courses.total_students DESC", prefixQuery)).
+			Limit(limit).
+			Scan(&suggestions).Error
+		if err != nil || len(suggestions) > 0 {
+			return suggestions, err
+		}
+	}
+
+	err := r.publishedDocuments(ctx).
+		Select("courses.id, courses.slug, courses.title").
+		Where(fuzzyMatchSQL, text).
+		Order(orderByExpr(fuzzyRankSQL+" DESC, courses.total_students DESC", text)).
+		Limit(limit).
+		Scan(&suggestions).Error
+	return suggestions, err
+}
+
+// SuggestTags lists the tags of published courses whose slug starts with slugPrefix, the
+// most used first. Slugs are already lowercase and unaccented.
+func (r *SearchRepository) SuggestTags(ctx context.Context, slugPrefix string, limit int) ([]TagSuggestion, error) {
+	var suggestions []TagSuggestion
+	err := r.db.WithContext(ctx).
+		Model(&model.Tag{}).
+		Select("tags.name, tags.slug, COUNT(*) AS course_count").
+		Joins("JOIN course_tags ON course_tags.tag_id = tags.id").
+		Joins("JOIN courses ON courses.id = course_tags.course_id AND courses.status = ? AND courses.deleted_at IS NULL",
+			model.CourseStatusPublished).
+		Where(`tags.slug LIKE ? ESCAPE '\'`, escapeLike(slugPrefix)+"%").
+		Group("tags.id, tags.name, tags.slug").
+		Order("course_count DESC, tags.name").
+		Limit(limit).
+		Scan(&suggestions).Error
+	return suggestions, err
+}
+
+// Reindex rebuilds the search document of every course. The triggers keep the index up to
+// date, this is for repairs, e.g. after changing the weights in the refresh function.
+func (r *SearchRepository) Reindex(ctx context.Context) (int64, error) {
+	result := r.db.WithContext(ctx).Exec("SELECT refresh_course_search_document(id) FROM courses")
+	return result.RowsAffected, result.Error
+}
+
+func (r *SearchRepository) publishedDocuments(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Model(&model.Course{}).
+		Joins(searchJoinSQL).
+		Where("courses.status = ?", model.CourseStatusPublished)
+}
+
+// likeEscaper makes user input match literally in a LIKE pattern with ESCAPE '\'.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// orderByExpr is an ORDER BY with bound arguments, which Order only accepts as a clause.
+func orderByExpr(sql string, vars ...interface{}) clause.OrderBy {
+	return clause.OrderBy{Expression: clause.Expr{SQL: sql, Vars: vars, WithoutParentheses: true}}
+}
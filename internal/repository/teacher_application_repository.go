@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"study.com/v1/internal/model"
+)
+
+type TeacherApplicationRepositoryInterface interface {
+	Create(ctx context.Context, application *model.TeacherApplication) error
+	Update(ctx context.Context, application *model.TeacherApplication) error
+	AddDocument(ctx context.Context, document *model.TeacherApplicationDocument) error
+	FindByID(ctx context.Context, id uuid.UUID) (*model.TeacherApplication, error)
+	FindPendingByUserID(ctx context.Context, userID uuid.UUID) (*model.TeacherApplication, error)
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]model.TeacherApplication, error)
+	List(ctx context.Context, filter TeacherApplicationFilter, offset, limit int) ([]model.TeacherApplication, int64, error)
+	Review(ctx context.Context, application *model.TeacherApplication, teacherRoleID *uuid.UUID) error
+}
+
+type TeacherApplicationFilter struct {
+	Status string
+	Search string
+}
+
+type TeacherApplicationRepository struct {
+	db *gorm.DB
+}
+
+func NewTeacherApplicationRepository(db *gorm.DB) *TeacherApplicationRepository {
+	return &TeacherApplicationRepository{db: db}
+}
+
+// Create inserts the application together with its documents.
+func (r *TeacherApplicationRepository) Create(ctx context.Context, application *model.TeacherApplication) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(application).Error; err != nil {
+			return err
+		}
+		if len(application.Documents) == 0 {
+			return nil
+		}
+		for i := range application.Documents {
+			application.Documents[i].ApplicationID = application.ID
+		}
+		return tx.Create(&application.Documents).Error
+	})
+}
+
+func (r *TeacherApplicationRepository) Update(ctx context.Context, application *model.TeacherApplication) error {
+	return r.db.WithContext(ctx).
+		Model(application).
+		Select("headline", "bio", "expertise", "years_of_experience", "education", "phone", "website_url").
+		Updates(application).Error
+}
+
+func (r *TeacherApplicationRepository) AddDocument(ctx context.Context, document *model.TeacherApplicationDocument) error {
+	return r.db.WithContext(ctx).Create(document).Error
+}
+
+func (r *TeacherApplicationRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.TeacherApplication, error) {
+	var application model.TeacherApplication
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Preload("Documents", func(db *gorm.DB) *gorm.DB { return db.Order("created_at") }).
+		Where("id = ?", id).
+		First(&application).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &application, nil
+}
+
+func (r *TeacherApplicationRepository) FindPendingByUserID(ctx context.Context, userID uuid.UUID) (*model.TeacherApplication, error) {
+	var application model.TeacherApplication
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Preload("Documents", func(db *gorm.DB) *gorm.DB { return db.Order("created_at") }).
+		Where("user_id = ? AND status = ?", userID, model.TeacherApplicationPending).
+		First(&application).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &application, nil
+}
+
+// ListByUserID returns the user's applications, newest first.
+func (r *TeacherApplicationRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]model.TeacherApplication, error) {
+	var applications []model.TeacherApplication
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Preload("Documents", func(db *gorm.DB) *gorm.DB { return db.Order("created_at") }).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&applications).Error
+	return applications, err
+}
+
+// List is the review queue: oldest applications first so they are handled in order.
+func (r *TeacherApplicationRepository) List(ctx context.Context, filter TeacherApplicationFilter, offset, limit int) ([]model.TeacherApplication, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.TeacherApplication{})
+	if filter.Status != "" {
+		query = query.Where("teacher_applications.status = ?", filter.Status)
+	}
+	if filter.Search != "" {
+		like := "%" + filter.Search + "%"
+		query = query.
+			Joins("JOIN users ON users.id = teacher_applications.user_id").
+			Where("users.email ILIKE ? OR users.user_name ILIKE ? OR users.full_name ILIKE ? OR teacher_applications.headline ILIKE ?",
+				like, like, like, like)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var applications []model.TeacherApplication
+	err := query.
+		Preload("User").
+		Order("teacher_applications.created_at").
+		Offset(offset).
+		Limit(limit).
+		Find(&applications).Error
+	return applications, total, err
+}
+
+// Review stores the decision of a pending application and, when teacherRoleID is set,
+// gives the applicant that global role in the same transaction. It returns
+// gorm.ErrRecordNotFound when the application was reviewed concurrently.
+func (r *TeacherApplicationRepository) Review(ctx context.Context, application *model.TeacherApplication, teacherRoleID *uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.TeacherApplication{}).
+			Where("id = ? AND status = ?", application.ID, model.TeacherApplicationPending).
+			Updates(map[string]interface{}{
+				"status":      application.Status,
+				"reviewed_by": application.ReviewedBy,
+				"review_note": application.ReviewNote,
+				"reviewed_at": application.ReviewedAt,
+				"updated_at":  time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if teacherRoleID == nil {
+			return nil
+		}
+		return tx.Exec(`
+			INSERT INTO user_roles (user_id, role_id, assigned_at, assigned_by)
+			VALUES (?, ?, CURRENT_TIMESTAMP, ?)
+			ON CONFLICT (user_id, role_id) WHERE organization_id IS NULL DO NOTHING
+		`, application.UserID, *teacherRoleID, application.ReviewedBy).Error
+	})
+}
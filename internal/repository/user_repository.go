@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -11,10 +12,13 @@ import (
 
 type UserRepositoryInterface interface {
 	CreateUser(ctx context.Context, user *model.User) error
+	CreateUserWithRoles(ctx context.Context, user *model.User, roleNames []string) error
 	FindUserByEmail(ctx context.Context, email string) (*model.User, error)
+	FindUserByUserName(ctx context.Context, userName string) (*model.User, error)
 	FindUserByID(ctx context.Context, id uuid.UUID) (*model.User, error)
 	UpdateUser(ctx context.Context, user *model.User) error
 	UpdatePasswordHash(ctx context.Context, userID uuid.UUID, newPasswordHash string) error
+	UpdateLastLogin(ctx context.Context, userID uuid.UUID, at time.Time) error
 }
 
 type UserRepository struct {
@@ -26,7 +30,25 @@ func NewUserRepository(db *gorm.DB) *UserRepository {
 }
 
 func (r *UserRepository) CreateUser(ctx context.Context, user *model.User) error {
-	return nil
+	return r.db.WithContext(ctx).Create(user).Error
+}
+
+// CreateUserWithRoles inserts the user and links the named roles in one transaction.
+// Role names that have not been seeded are skipped.
+func (r *UserRepository) CreateUserWithRoles(ctx context.Context, user *model.User, roleNames []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		if len(roleNames) == 0 {
+			return nil
+		}
+		return tx.Exec(`
+			INSERT INTO user_roles (user_id, role_id, assigned_at)
+			SELECT ?, id, ? FROM roles WHERE name IN ? AND organization_id IS NULL
+			ON CONFLICT (user_id, role_id) WHERE organization_id IS NULL DO NOTHING
+		`, user.ID, time.Now(), roleNames).Error
+	})
 }
 
 func (r *UserRepository) FindUserByEmail(ctx context.Context, email string) (*model.User, error) {
@@ -38,18 +60,47 @@ func (r *UserRepository) FindUserByEmail(ctx context.Context, email string) (*mo
 		}
 		return nil, err
 	}
-	return nil, nil
+	return &user, nil
 }
 
-func (r *UserRepository) FindUserByID(ctx context.Context, id uuid.UUID) (*model.User, error) {
+func (r *UserRepository) FindUserByUserName(ctx context.Context, userName string) (*model.User, error) {
+	var user model.User
+	err := r.db.WithContext(ctx).Where("user_name = ?", userName).First(&user).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &user, nil
+}
 
-	return nil, nil
+func (r *UserRepository) FindUserByID(ctx context.Context, id uuid.UUID) (*model.User, error) {
+	var user model.User
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&user).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &user, nil
 }
 
 func (r *UserRepository) UpdateUser(ctx context.Context, user *model.User) error {
-	return nil
+	return r.db.WithContext(ctx).Save(user).Error
 }
 
 func (r *UserRepository) UpdatePasswordHash(ctx context.Context, userID uuid.UUID, newPasswordHash string) error {
-	return nil
+	return r.db.WithContext(ctx).
+		Model(&model.User{}).
+		Where("id = ?", userID).
+		Update("password_hash", newPasswordHash).Error
+}
+
+func (r *UserRepository) UpdateLastLogin(ctx context.Context, userID uuid.UUID, at time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&model.User{}).
+		Where("id = ?", userID).
+		Update("last_login_at", at).Error
 }
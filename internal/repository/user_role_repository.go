@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"study.com/v1/internal/model"
+)
+
+type UserRoleRepositoryInterface interface {
+	AssignRoles(ctx context.Context, userID uuid.UUID, roleIDs []uuid.UUID, assignedBy *uuid.UUID) error
+	RemoveRoles(ctx context.Context, userID uuid.UUID, roleIDs []uuid.UUID) error
+	FindByUserID(ctx context.Context, userID uuid.UUID) ([]model.UserRole, error)
+	ListUsersWithRoles(ctx context.Context, roleID *uuid.UUID, search string, offset, limit int) ([]model.User, int64, error)
+}
+
+// UserRoleRepository manages global role assignments only, roles held inside an
+// organization go through OrganizationRepository.
+type UserRoleRepository struct {
+	db *gorm.DB
+}
+
+func NewUserRoleRepository(db *gorm.DB) *UserRoleRepository {
+	return &UserRoleRepository{db: db}
+}
+
+// AssignRoles is idempotent, roles the user already holds keep their original AssignedAt.
+func (r *UserRoleRepository) AssignRoles(ctx context.Context, userID uuid.UUID, roleIDs []uuid.UUID, assignedBy *uuid.UUID) error {
+	if len(roleIDs) == 0 {
+		return nil
+	}
+	now := time.Now()
+	userRoles := make([]model.UserRole, 0, len(roleIDs))
+	for _, roleID := range roleIDs {
+		userRoles = append(userRoles, model.UserRole{
+			UserID:     userID,
+			RoleID:     roleID,
+			AssignedAt: now,
+			AssignedBy: assignedBy,
+		})
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&userRoles).Error
+}
+
+func (r *UserRoleRepository) RemoveRoles(ctx context.Context, userID uuid.UUID, roleIDs []uuid.UUID) error {
+	if len(roleIDs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Where("user_id = ? AND role_id IN ? AND organization_id IS NULL", userID, roleIDs).
+		Delete(&model.UserRole{}).Error
+}
+
+func (r *UserRoleRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]model.UserRole, error) {
+	var userRoles []model.UserRole
+	err := r.db.WithContext(ctx).
+		Preload("Role.RolePermissions.Permission").
+		Where("user_id = ? AND organization_id IS NULL", userID).
+		Order("assigned_at").
+		Find(&userRoles).Error
+	return userRoles, err
+}
+
+func (r *UserRoleRepository) ListUsersWithRoles(ctx context.Context, roleID *uuid.UUID, search string, offset, limit int) ([]model.User, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.User{})
+	if roleID != nil {
+		query = query.Where("EXISTS (SELECT 1 FROM user_roles ur WHERE ur.user_id = users.id AND ur.role_id = ? AND ur.organization_id IS NULL)", *roleID)
+	}
+	if search != "" {
+		like := "%" + search + "%"
+		query = query.Where("users.email ILIKE ? OR users.user_name ILIKE ? OR users.full_name ILIKE ?", like, like, like)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []model.User
+	err := query.
+		Preload("UserRoles", "organization_id IS NULL").
+		Preload("UserRoles.Role.RolePermissions.Permission").
+		Order("users.created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&users).Error
+	return users, total, err
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"study.com/v1/internal/model"
+)
+
+type VideoRepositoryInterface interface {
+	FindByLesson(ctx context.Context, lessonID uuid.UUID) (*model.LessonVideo, error)
+	SetProcessed(ctx context.Context, video *model.LessonVideo) error
+	SetTranscriptionStatus(ctx context.Context, video *model.LessonVideo, status string) error
+	SaveTranscript(ctx context.Context, video *model.LessonVideo, caption *model.VideoCaption, segments []model.TranscriptSegment) error
+	ListCaptions(ctx context.Context, videoID uuid.UUID) ([]model.VideoCaption, error)
+	ListTranscriptSegments(ctx context.Context, videoID uuid.UUID) ([]model.TranscriptSegment, error)
+	SearchTranscripts(ctx context.Context, courseID uuid.UUID, query string, limit int) ([]TranscriptHit, error)
+}
+
+// The expression indexed by migration 0010, queries must repeat it to use the index. The
+// argument is websearch syntax, like the catalog search.
+const transcriptMatchSQL = "to_tsvector('vietnamese_unaccent', normalize(transcript_segments.text, NFC)) @@ websearch_to_tsquery('vietnamese_unaccent', ?)"
+
+// TranscriptHit is a transcript segment matching a search, with the lesson it is from.
+type TranscriptHit struct {
+	LessonID    uuid.UUID
+	LessonTitle string
+	StartMs     int
+	EndMs       int
+	Text        string
+}
+
+type VideoRepository struct {
+	db *gorm.DB
+}
+
+func NewVideoRepository(db *gorm.DB) *VideoRepository {
+	return &VideoRepository{db: db}
+}
+
+func (r *VideoRepository) FindByLesson(ctx context.Context, lessonID uuid.UUID) (*model.LessonVideo, error) {
+	var video model.LessonVideo
+	err := r.db.WithContext(ctx).Where("lesson_id = ?", lessonID).First(&video).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &video, nil
+}
+
+// SetProcessed stores what transcoding found out about the video and copies its length to
+// the lesson and the course totals. The row is only updated while it still points at the
+// source that was transcoded: gorm.ErrRecordNotFound means a new upload replaced it.
+func (r *VideoRepository) SetProcessed(ctx context.Context, video *model.LessonVideo) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.LessonVideo{}).
+			Where("id = ? AND video_url = ?", video.ID, video.VideoURL).
+			Updates(map[string]interface{}{
+				"video_hls_url":    video.VideoHlsURL,
+				"thumbnail_url":    video.ThumbnailURL,
+				"duration_seconds": video.DurationSeconds,
+				"resolution":       video.Resolution,
+				"updated_at":       time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		var courseIDs []uuid.UUID
+		err := tx.Model(&model.Lesson{}).
+			Joins("JOIN sections ON sections.id = lessons.section_id").
+			Where("lessons.id = ?", video.LessonID).
+			Pluck("sections.course_id", &courseIDs).Error
+		if err != nil {
+			return err
+		}
+		if len(courseIDs) == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		minutes := (video.DurationSeconds + 59) / 60
+		if err := tx.Model(&model.Lesson{}).Where("id = ?", video.LessonID).Update("duration_minutes", minutes).Error; err != nil {
+			return err
+		}
+		return recomputeCourseTotals(tx, courseIDs[0])
+	})
+}
+
+// SetTranscriptionStatus is guarded like SetProcessed: gorm.ErrRecordNotFound means the
+// source was replaced.
+func (r *VideoRepository) SetTranscriptionStatus(ctx context.Context, video *model.LessonVideo, status string) error {
+	result := r.db.WithContext(ctx).Model(&model.LessonVideo{}).
+		Where("id = ? AND video_url = ?", video.ID, video.VideoURL).
+		Updates(map[string]interface{}{
+			"transcription_status": status,
+			"updated_at":           time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// SaveTranscript stores the plain transcript on the video and replaces its caption tracks
+// and segments, then marks the transcription completed. caption is nil for a video without
+// speech. Guarded like SetProcessed.
+func (r *VideoRepository) SaveTranscript(ctx context.Context, video *model.LessonVideo, caption *model.VideoCaption, segments []model.TranscriptSegment) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.LessonVideo{}).
+			Where("id = ? AND video_url = ?", video.ID, video.VideoURL).
+			Updates(map[string]interface{}{
+				"transcription":        video.Transcription,
+				"transcription_status": model.TranscriptionStatusCompleted,
+				"updated_at":           time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := clearTranscript(tx, video.ID); err != nil {
+			return err
+		}
+		if caption != nil {
+			if err := tx.Omit(clause.Associations).Create(caption).Error; err != nil {
+				return err
+			}
+		}
+		if len(segments) == 0 {
+			return nil
+		}
+		return tx.Omit(clause.Associations).CreateInBatches(segments, 500).Error
+	})
+}
+
+func (r *VideoRepository) ListCaptions(ctx context.Context, videoID uuid.UUID) ([]model.VideoCaption, error) {
+	var captions []model.VideoCaption
+	err := r.db.WithContext(ctx).Where("lesson_video_id = ?", videoID).Order("language").Find(&captions).Error
+	return captions, err
+}
+
+func (r *VideoRepository) ListTranscriptSegments(ctx context.Context, videoID uuid.UUID) ([]model.TranscriptSegment, error) {
+	var segments []model.TranscriptSegment
+	err := r.db.WithContext(ctx).Where("lesson_video_id = ?", videoID).Order("start_ms").Find(&segments).Error
+	return segments, err
+}
+
+// SearchTranscripts finds the segments of the course's videos that match the query, in the
+// order of the course outline and then of time.
+func (r *VideoRepository) SearchTranscripts(ctx context.Context, courseID uuid.UUID, query string, limit int) ([]TranscriptHit, error) {
+	var hits []TranscriptHit
+	err := r.db.WithContext(ctx).Table("transcript_segments").
+		Select("lessons.id AS lesson_id, lessons.title AS lesson_title, transcript_segments.start_ms, transcript_segments.end_ms, transcript_segments.text").
+		Joins("JOIN lesson_videos ON lesson_videos.id = transcript_segments.lesson_video_id AND lesson_videos.deleted_at IS NULL").
+		Joins("JOIN lessons ON lessons.id = lesson_videos.lesson_id").
+		Joins("JOIN sections ON sections.id = lessons.section_id AND sections.deleted_at IS NULL").
+		Where("sections.course_id = ?", courseID).
+		Where(transcriptMatchSQL, query).
+		Order("sections.display_order, lessons.display_order, transcript_segments.start_ms").
+		Limit(limit).
+		Scan(&hits).Error
+	return hits, err
+}
+
+// clearTranscript deletes the caption tracks and segments of a video, whose objects go
+// with the video's other outputs.
+func clearTranscript(tx *gorm.DB, videoID uuid.UUID) error {
+	if err := tx.Where("lesson_video_id = ?", videoID).Delete(&model.VideoCaption{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("lesson_video_id = ?", videoID).Delete(&model.TranscriptSegment{}).Error
+}
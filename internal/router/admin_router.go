@@ -0,0 +1,47 @@
+package router
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+	"study.com/v1/internal/config"
+	"study.com/v1/internal/handler"
+	"study.com/v1/internal/middleware"
+)
+
+func SetupAdminRoutes(
+	api fiber.Router,
+	cfg *config.Config,
+	roleHandler *handler.RoleHandler,
+	userRoleHandler *handler.UserRoleHandler,
+	authz middleware.PermissionChecker,
+	redis *redis.Client,
+) {
+	admin := api.Group("/admin",
+		middleware.AuthMiddleware(cfg, redis),
+		middleware.CSRFProtection(cfg),
+		middleware.RequirePermissions(authz, "ROLES_MANAGE_SYSTEM"),
+	)
+
+	roles := admin.Group("/roles")
+	roles.Get("/", roleHandler.ListRoles)
+	roles.Post("/", roleHandler.CreateRole)
+	roles.Get("/:id", roleHandler.GetRole)
+	roles.Put("/:id", roleHandler.UpdateRole)
+	roles.Delete("/:id", roleHandler.DeleteRole)
+	roles.Post("/:id/permissions", roleHandler.AddPermissions)
+	roles.Delete("/:id/permissions", roleHandler.RemovePermissions)
+
+	permissions := admin.Group("/permissions")
+	permissions.Get("/", roleHandler.ListPermissions)
+	permissions.Get("/:id", roleHandler.GetPermission)
+	permissions.Put("/:id", roleHandler.UpdatePermission)
+
+	users := admin.Group("/users")
+	users.Get("/", userRoleHandler.ListUsers)
+	users.Get("/:id/roles", userRoleHandler.GetUserRoles)
+	users.Post("/:id/roles", userRoleHandler.AssignRoles)
+	users.Delete("/:id/roles", userRoleHandler.RemoveRoles)
+	users.Get("/:id/permission-overrides", userRoleHandler.ListPermissionOverrides)
+	users.Post("/:id/permission-overrides", userRoleHandler.CreatePermissionOverride)
+	users.Delete("/:id/permission-overrides/:override_id", userRoleHandler.DeletePermissionOverride)
+}
@@ -15,11 +15,13 @@ func SetupAuthRoutes(api fiber.Router, cfg *config.Config, authHandler *handler.
 	auth.Post("/login", authHandler.Login)
 	auth.Post("/reset-password/request", authHandler.RequestPasswordReset)
 	auth.Post("/reset-password", authHandler.ResetPassword)
-	auth.Post("/refresh-token", authHandler.RefreshToken)
+	auth.Post("/refresh-token", middleware.CSRFProtection(cfg), authHandler.RefreshToken)
 
-	auth.Use(middleware.AuthMiddleware(cfg, redis))
+	auth.Use(middleware.AuthMiddleware(cfg, redis), middleware.CSRFProtection(cfg))
 	auth.Get("/me", authHandler.GetMe)
 	auth.Post("/logout", authHandler.LogoutOneDevice)
 	auth.Post("/logout-all", authHandler.LogoutAll)
 	auth.Put("/change-password", authHandler.UpdatePasswordHash)
+	auth.Get("/sessions", authHandler.ListSessions)
+	auth.Delete("/sessions/:device_id", authHandler.RevokeSession)
 }
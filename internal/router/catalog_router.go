@@ -0,0 +1,17 @@
+package router
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"study.com/v1/internal/handler"
+)
+
+// SetupCatalogRoutes mounts the public course catalog and its search box, they need no
+// authentication.
+func SetupCatalogRoutes(api fiber.Router, catalogHandler *handler.CatalogHandler, searchHandler *handler.SearchHandler) {
+	api.Get("/search/suggestions", searchHandler.Suggest)
+
+	courses := api.Group("/courses")
+
+	courses.Get("/", catalogHandler.ListCourses)
+	courses.Get("/:slug", catalogHandler.GetCourse)
+}
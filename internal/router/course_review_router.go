@@ -0,0 +1,31 @@
+package router
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+	"study.com/v1/internal/config"
+	"study.com/v1/internal/handler"
+	"study.com/v1/internal/middleware"
+)
+
+// SetupCourseReviewRoutes mounts the review queue. COURSES_APPROVE_OWN_ORG can come from an
+// organization role, which a route-level check does not see, so the service authorizes
+// every request against the course or the organization of the queue.
+func SetupCourseReviewRoutes(
+	api fiber.Router,
+	cfg *config.Config,
+	reviewHandler *handler.CourseReviewHandler,
+	redis *redis.Client,
+) {
+	reviews := api.Group("/course-reviews",
+		middleware.AuthMiddleware(cfg, redis),
+		middleware.CSRFProtection(cfg),
+	)
+
+	reviews.Get("/", reviewHandler.ListPendingReviews)
+	reviews.Get("/:id", reviewHandler.GetCourseForReview)
+	reviews.Get("/:id/history", reviewHandler.ListStatusHistory)
+	reviews.Post("/:id/approve", reviewHandler.ApproveCourse)
+	reviews.Post("/:id/reject", reviewHandler.RejectCourse)
+	reviews.Post("/:id/archive", reviewHandler.ArchiveCourse)
+}
@@ -0,0 +1,63 @@
+package router
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+	"study.com/v1/internal/config"
+	"study.com/v1/internal/handler"
+	"study.com/v1/internal/middleware"
+)
+
+// SetupInstructorRoutes mounts course authoring. The routes only need a signed in user here:
+// COURSES_CREATE can come from an organization role, which a route-level check does not see,
+// so the service checks it, and COURSES_UPDATE_OWN (or the delete permissions) against the
+// course itself, which is what limits teachers to their own courses.
+func SetupInstructorRoutes(
+	api fiber.Router,
+	cfg *config.Config,
+	courseHandler *handler.CourseHandler,
+	reviewHandler *handler.CourseReviewHandler,
+	uploadHandler *handler.MediaUploadHandler,
+	articleHandler *handler.ArticleHandler,
+	redis *redis.Client,
+) {
+	courses := api.Group("/instructor/courses",
+		middleware.AuthMiddleware(cfg, redis),
+		middleware.CSRFProtection(cfg),
+	)
+
+	courses.Get("/", courseHandler.ListMyCourses)
+	courses.Post("/", courseHandler.CreateCourse)
+	courses.Get("/:id", courseHandler.GetCourse)
+	courses.Patch("/:id", courseHandler.UpdateCourse)
+	courses.Delete("/:id", courseHandler.DeleteCourse)
+	courses.Put("/:id/reorder", courseHandler.ReorderCourse)
+
+	courses.Post("/:id/sections", courseHandler.CreateSection)
+	courses.Patch("/:id/sections/:section_id", courseHandler.UpdateSection)
+	courses.Delete("/:id/sections/:section_id", courseHandler.DeleteSection)
+
+	courses.Post("/:id/sections/:section_id/lessons", courseHandler.CreateLesson)
+	courses.Patch("/:id/lessons/:lesson_id", courseHandler.UpdateLesson)
+	courses.Delete("/:id/lessons/:lesson_id", courseHandler.DeleteLesson)
+
+	// Markdown of article lessons, every save is a revision
+	courses.Get("/:id/lessons/:lesson_id/article", articleHandler.GetArticle)
+	courses.Put("/:id/lessons/:lesson_id/article", articleHandler.SaveArticle)
+	courses.Get("/:id/lessons/:lesson_id/article/revisions", articleHandler.ListRevisions)
+	courses.Get("/:id/lessons/:lesson_id/article/revisions/:version", articleHandler.GetRevision)
+	courses.Post("/:id/lessons/:lesson_id/article/revisions/:version/restore", articleHandler.RestoreRevision)
+	courses.Get("/:id/lessons/:lesson_id/article/diff", articleHandler.DiffRevisions)
+
+	// Media goes straight to storage through presigned URLs, only the metadata passes here
+	courses.Post("/:id/uploads", uploadHandler.CreateUpload)
+	courses.Post("/:id/uploads/:upload_id/complete", uploadHandler.CompleteUpload)
+	courses.Delete("/:id/uploads/:upload_id", uploadHandler.AbortUpload)
+
+	// Publishing workflow
+	courses.Post("/:id/submit", reviewHandler.SubmitForReview)
+	courses.Post("/:id/withdraw", reviewHandler.WithdrawReview)
+	courses.Post("/:id/archive", reviewHandler.ArchiveCourse)
+	courses.Post("/:id/restore", reviewHandler.RestoreCourse)
+	courses.Get("/:id/history", reviewHandler.ListStatusHistory)
+}
@@ -0,0 +1,46 @@
+package router
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+	"study.com/v1/internal/config"
+	"study.com/v1/internal/handler"
+	"study.com/v1/internal/middleware"
+)
+
+// SetupMediaRoutes mounts access to lesson videos, their transcripts, articles and attachments.
+// Enrollment is checked by the service, and minors need a linked parent before they can study.
+// The HLS playlists carry their own token in the path because players fetch them, and the
+// playlists they reference, without the user's credentials; the token is only issued by playback.
+func SetupMediaRoutes(
+	api fiber.Router,
+	cfg *config.Config,
+	mediaHandler *handler.MediaAccessHandler,
+	articleHandler *handler.ArticleHandler,
+	guardian middleware.GuardianChecker,
+	redis *redis.Client,
+) {
+	lessons := api.Group("/lessons",
+		middleware.AuthMiddleware(cfg, redis),
+		middleware.CSRFProtection(cfg),
+		middleware.RequireParentLink(guardian),
+	)
+
+	lessons.Get("/:lesson_id/playback", mediaHandler.GetPlayback)
+	lessons.Get("/:lesson_id/transcript", mediaHandler.GetTranscript)
+	lessons.Get("/:lesson_id/article", articleHandler.GetLessonArticle)
+	lessons.Get("/:lesson_id/attachments/:attachment_id/download", mediaHandler.DownloadAttachment)
+
+	// Under the public catalog's /courses, so the middleware is per route
+	api.Get("/courses/:course_id/transcripts/search",
+		middleware.AuthMiddleware(cfg, redis),
+		middleware.CSRFProtection(cfg),
+		middleware.RequireParentLink(guardian),
+		mediaHandler.SearchTranscripts,
+	)
+
+	hls := api.Group("/media/hls")
+
+	hls.Get("/:token/master.m3u8", mediaHandler.GetMasterPlaylist)
+	hls.Get("/:token/:rendition/index.m3u8", mediaHandler.GetRenditionPlaylist)
+}
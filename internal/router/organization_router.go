@@ -0,0 +1,56 @@
+package router
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+	"study.com/v1/internal/config"
+	"study.com/v1/internal/handler"
+	"study.com/v1/internal/middleware"
+)
+
+// SetupOrganizationRoutes only guards creation at route level. Everything under /:id
+// depends on the roles held inside that organization, which the services check.
+func SetupOrganizationRoutes(
+	api fiber.Router,
+	cfg *config.Config,
+	organizationHandler *handler.OrganizationHandler,
+	categoryHandler *handler.CategoryHandler,
+	authz middleware.PermissionChecker,
+	guardian middleware.GuardianChecker,
+	redis *redis.Client,
+) {
+	orgs := api.Group("/orgs",
+		middleware.AuthMiddleware(cfg, redis),
+		middleware.CSRFProtection(cfg),
+		middleware.RequireParentLink(guardian),
+	)
+
+	orgs.Get("/", organizationHandler.ListMyOrganizations)
+	orgs.Post("/", middleware.RequirePermissions(authz, "ORG_CREATE"), organizationHandler.CreateOrganization)
+	orgs.Post("/invitations/accept", organizationHandler.AcceptInvitation)
+	orgs.Get("/:id", organizationHandler.GetOrganization)
+	orgs.Put("/:id", organizationHandler.UpdateOrganization)
+	orgs.Delete("/:id", organizationHandler.DeleteOrganization)
+	orgs.Post("/:id/leave", organizationHandler.LeaveOrganization)
+
+	orgs.Get("/:id/members", organizationHandler.ListMembers)
+	orgs.Patch("/:id/members/:user_id", organizationHandler.UpdateMember)
+	orgs.Delete("/:id/members/:user_id", organizationHandler.RemoveMember)
+
+	orgs.Get("/:id/invitations", organizationHandler.ListInvitations)
+	orgs.Post("/:id/invitations", organizationHandler.InviteMember)
+	orgs.Delete("/:id/invitations/:invitation_id", organizationHandler.RevokeInvitation)
+
+	orgs.Get("/:id/roles", organizationHandler.ListRoles)
+	orgs.Post("/:id/roles", organizationHandler.CreateRole)
+	orgs.Get("/:id/roles/:role_id", organizationHandler.GetRole)
+	orgs.Put("/:id/roles/:role_id", organizationHandler.UpdateRole)
+	orgs.Delete("/:id/roles/:role_id", organizationHandler.DeleteRole)
+	orgs.Post("/:id/roles/:role_id/permissions", organizationHandler.AddRolePermissions)
+	orgs.Delete("/:id/roles/:role_id/permissions", organizationHandler.RemoveRolePermissions)
+
+	orgs.Get("/:id/categories", categoryHandler.ListCategories)
+	orgs.Post("/:id/categories", categoryHandler.CreateCategory)
+	orgs.Put("/:id/categories/:category_id", categoryHandler.UpdateCategory)
+	orgs.Delete("/:id/categories/:category_id", categoryHandler.DeleteCategory)
+}
@@ -0,0 +1,44 @@
+package router
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+	"study.com/v1/internal/config"
+	"study.com/v1/internal/handler"
+	"study.com/v1/internal/middleware"
+)
+
+// SetupParentRoutes leaves the linking endpoints open to any signed in user: the PARENT role
+// is only granted once a link is confirmed. Access to a given child is checked by the service.
+func SetupParentRoutes(
+	api fiber.Router,
+	cfg *config.Config,
+	parentHandler *handler.ParentHandler,
+	authz middleware.PermissionChecker,
+	redis *redis.Client,
+) {
+	parents := api.Group("/parents",
+		middleware.AuthMiddleware(cfg, redis),
+		middleware.CSRFProtection(cfg),
+	)
+
+	// Linking, used by both the parent and the student
+	parents.Get("/status", parentHandler.GetGuardianStatus)
+	parents.Get("/links", parentHandler.ListMyLinks)
+	parents.Post("/links", parentHandler.RequestLink)
+	parents.Post("/links/:id/resend", parentHandler.ResendLinkOTP)
+	parents.Post("/links/:id/confirm", parentHandler.ConfirmLink)
+	parents.Patch("/links/:id", parentHandler.UpdateLink)
+	parents.Delete("/links/:id", parentHandler.RevokeLink)
+
+	// Read-only views of linked children
+	view := middleware.RequirePermissions(authz, "PARENT_CHILDREN_VIEW")
+	parents.Get("/children", view, parentHandler.ListChildren)
+	parents.Get("/children/:student_id/enrollments", view, parentHandler.ListChildEnrollments)
+	parents.Get("/children/:student_id/quiz-attempts", view, parentHandler.ListChildQuizAttempts)
+	parents.Get("/children/:student_id/orders", middleware.RequirePermissions(authz, "PARENT_ORDERS_VIEW"), parentHandler.ListChildOrders)
+
+	approve := middleware.RequirePermissions(authz, "PARENT_PURCHASES_APPROVE")
+	parents.Post("/children/:student_id/orders/:order_id/approve", approve, parentHandler.ApproveChildOrder)
+	parents.Post("/children/:student_id/orders/:order_id/reject", approve, parentHandler.RejectChildOrder)
+}
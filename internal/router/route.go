@@ -6,15 +6,45 @@ import (
 	"github.com/redis/go-redis/v9"
 	"study.com/v1/internal/config"
 	"study.com/v1/internal/handler"
+	"study.com/v1/internal/middleware"
+	"study.com/v1/internal/utils"
 )
 
 func SetupAllRoutes(
 	app *fiber.App,
 	cfg *config.Config,
 	authHandler *handler.AuthHandler,
+	roleHandler *handler.RoleHandler,
+	userRoleHandler *handler.UserRoleHandler,
+	organizationHandler *handler.OrganizationHandler,
+	categoryHandler *handler.CategoryHandler,
+	applicationHandler *handler.TeacherApplicationHandler,
+	parentHandler *handler.ParentHandler,
+	courseHandler *handler.CourseHandler,
+	courseReviewHandler *handler.CourseReviewHandler,
+	mediaUploadHandler *handler.MediaUploadHandler,
+	catalogHandler *handler.CatalogHandler,
+	searchHandler *handler.SearchHandler,
+	mediaAccessHandler *handler.MediaAccessHandler,
+	articleHandler *handler.ArticleHandler,
+	authz middleware.PermissionChecker,
+	guardian middleware.GuardianChecker,
 	redis *redis.Client,
 	minio *minio.Client,
 ) {
+	// Public keys for verifying access tokens outside this service
+	app.Get("/.well-known/jwks.json", func(c *fiber.Ctx) error {
+		jwks, err := utils.JWKS(cfg)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"message": "Failed to load signing keys",
+				"error":   err.Error(),
+			})
+		}
+		c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+		return c.JSON(jwks)
+	})
+
 	api := app.Group("/api")
 
 	// Health check
@@ -26,4 +56,12 @@ func SetupAllRoutes(
 	})
 
 	SetupAuthRoutes(api, cfg, authHandler, redis)
+	SetupAdminRoutes(api, cfg, roleHandler, userRoleHandler, authz, redis)
+	SetupOrganizationRoutes(api, cfg, organizationHandler, categoryHandler, authz, guardian, redis)
+	SetupTeacherApplicationRoutes(api, cfg, applicationHandler, authz, guardian, redis)
+	SetupParentRoutes(api, cfg, parentHandler, authz, redis)
+	SetupInstructorRoutes(api, cfg, courseHandler, courseReviewHandler, mediaUploadHandler, articleHandler, redis)
+	SetupCourseReviewRoutes(api, cfg, courseReviewHandler, redis)
+	SetupCatalogRoutes(api, catalogHandler, searchHandler)
+	SetupMediaRoutes(api, cfg, mediaAccessHandler, articleHandler, guardian, redis)
 }
@@ -0,0 +1,37 @@
+package router
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+	"study.com/v1/internal/config"
+	"study.com/v1/internal/handler"
+	"study.com/v1/internal/middleware"
+)
+
+func SetupTeacherApplicationRoutes(
+	api fiber.Router,
+	cfg *config.Config,
+	applicationHandler *handler.TeacherApplicationHandler,
+	authz middleware.PermissionChecker,
+	guardian middleware.GuardianChecker,
+	redis *redis.Client,
+) {
+	applications := api.Group("/teacher-applications",
+		middleware.AuthMiddleware(cfg, redis),
+		middleware.CSRFProtection(cfg),
+		middleware.RequireParentLink(guardian),
+	)
+
+	// Applicant
+	applications.Post("/", middleware.RequirePermissions(authz, "TEACHER_PROFILE_UPDATE"), applicationHandler.SubmitApplication)
+	applications.Get("/me", middleware.RequirePermissions(authz, "APPLICATION_VIEW_STATUS"), applicationHandler.ListMyApplications)
+	applications.Put("/me", middleware.RequirePermissions(authz, "TEACHER_PROFILE_UPDATE"), applicationHandler.UpdateMyApplication)
+	applications.Post("/me/documents", middleware.RequirePermissions(authz, "TEACHER_PROFILE_UPDATE"), applicationHandler.AddMyDocument)
+
+	// Review queue
+	review := middleware.RequirePermissions(authz, "TEACHER_APPLICATIONS_REVIEW")
+	applications.Get("/", review, applicationHandler.ListApplications)
+	applications.Get("/:id", review, applicationHandler.GetApplication)
+	applications.Post("/:id/approve", review, applicationHandler.ApproveApplication)
+	applications.Post("/:id/reject", review, applicationHandler.RejectApplication)
+}
@@ -0,0 +1,391 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"study.com/v1/internal/config"
+	"study.com/v1/internal/dto"
+	"study.com/v1/internal/markdown"
+	"study.com/v1/internal/model"
+	"study.com/v1/internal/repository"
+	"study.com/v1/internal/storage"
+	"study.com/v1/internal/utils"
+)
+
+const (
+	maxArticleSize = 200 << 10
+	// Reading speeds behind ReadingTimeMins. Vietnamese words are counted per syllable,
+	// which readers go through at about 200 a minute; code is read at half that speed.
+	articleWordsPerMinute = 200
+	codeWordsPerMinute    = 100
+	secondsPerImage       = 12
+)
+
+var (
+	ErrArticleNotFound         = errors.New("this lesson has no article yet")
+	ErrArticleLessonRequired   = errors.New("articles can only be written for article lessons")
+	ErrInvalidArticle          = errors.New("content is required and must be at most 200 KB of UTF-8 text")
+	ErrArticleVersionConflict  = errors.New("the article was saved by someone else in the meantime, reload and try again")
+	ErrArticleRevisionNotFound = errors.New("revision not found")
+)
+
+type ArticleServiceInterface interface {
+	GetArticle(ctx context.Context, actorID, courseID, lessonID uuid.UUID) (*dto.ArticleDTO, error)
+	SaveArticle(ctx context.Context, actorID, courseID, lessonID uuid.UUID, req dto.SaveArticleDTO) (*dto.ArticleDTO, error)
+	ListRevisions(ctx context.Context, actorID, courseID, lessonID uuid.UUID) ([]dto.ArticleRevisionDTO, error)
+	GetRevision(ctx context.Context, actorID, courseID, lessonID uuid.UUID, version int) (*dto.ArticleRevisionDTO, error)
+	DiffRevisions(ctx context.Context, actorID, courseID, lessonID uuid.UUID, query dto.ArticleDiffQueryDTO) (*dto.ArticleDiffDTO, error)
+	RestoreRevision(ctx context.Context, actorID, courseID, lessonID uuid.UUID, version int) (*dto.ArticleDTO, error)
+
+	GetLessonArticle(ctx context.Context, actorID, lessonID uuid.UUID) (*dto.ArticleDTO, error)
+}
+
+// ArticleService keeps the Markdown of article lessons. Every save renders the sanitized
+// HTML students get, measures the reading time and records a revision, so authors can
+// compare versions and bring an old one back (as a new revision, history is never
+// rewritten).
+type ArticleService struct {
+	articleRepo    repository.ArticleRepositoryInterface
+	courseRepo     repository.CourseRepositoryInterface
+	enrollmentRepo repository.EnrollmentRepositoryInterface
+	authz          AuthorizationServiceInterface
+	renderer       *markdown.Renderer
+}
+
+func NewArticleService(
+	cfg *config.Config,
+	articleRepo repository.ArticleRepositoryInterface,
+	courseRepo repository.CourseRepositoryInterface,
+	enrollmentRepo repository.EnrollmentRepositoryInterface,
+	authz AuthorizationServiceInterface,
+) *ArticleService {
+	return &ArticleService{
+		articleRepo:    articleRepo,
+		courseRepo:     courseRepo,
+		enrollmentRepo: enrollmentRepo,
+		authz:          authz,
+		// Images are uploaded as article_image to the public images bucket, others are dropped
+		renderer: markdown.NewRenderer(storage.PublicObjectURL(cfg, cfg.MinioBucketImages, "")),
+	}
+}
+
+// GetArticle returns the article with its Markdown, for the editor. Courses under review
+// can still be read.
+func (s *ArticleService) GetArticle(ctx context.Context, actorID, courseID, lessonID uuid.UUID) (*dto.ArticleDTO, error) {
+	lesson, err := s.authorLesson(ctx, actorID, courseID, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	article, err := s.findArticle(ctx, lesson.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.toArticleResponse(article, true)
+}
+
+func (s *ArticleService) SaveArticle(ctx context.Context, actorID, courseID, lessonID uuid.UUID, req dto.SaveArticleDTO) (*dto.ArticleDTO, error) {
+	lesson, err := s.editableArticleLesson(ctx, actorID, courseID, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	article, err := s.save(ctx, actorID, lesson.ID, req.Content, req.BaseVersion, nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.toArticleResponse(article, true)
+}
+
+// ListRevisions lists the revisions of the article, newest first.
+func (s *ArticleService) ListRevisions(ctx context.Context, actorID, courseID, lessonID uuid.UUID) ([]dto.ArticleRevisionDTO, error) {
+	lesson, err := s.authorLesson(ctx, actorID, courseID, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	article, err := s.findArticle(ctx, lesson.ID)
+	if err != nil {
+		return nil, err
+	}
+	revisions, err := s.articleRepo.ListRevisions(ctx, article.ID)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]dto.ArticleRevisionDTO, 0, len(revisions))
+	for _, revision := range revisions {
+		res = append(res, toArticleRevisionResponse(revision))
+	}
+	return res, nil
+}
+
+// GetRevision returns a revision with its Markdown and rendered HTML, to preview it
+// before restoring it.
+func (s *ArticleService) GetRevision(ctx context.Context, actorID, courseID, lessonID uuid.UUID, version int) (*dto.ArticleRevisionDTO, error) {
+	lesson, err := s.authorLesson(ctx, actorID, courseID, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	article, err := s.findArticle(ctx, lesson.ID)
+	if err != nil {
+		return nil, err
+	}
+	revision, err := s.findRevision(ctx, article.ID, version)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := s.renderer.Render(revision.Content)
+	if err != nil {
+		return nil, err
+	}
+	res := toArticleRevisionResponse(*revision)
+	res.Content = revision.Content
+	res.HTML = doc.HTML
+	return &res, nil
+}
+
+// DiffRevisions compares the Markdown of two revisions line by line. To defaults to the
+// current version and From to the one before To.
+func (s *ArticleService) DiffRevisions(ctx context.Context, actorID, courseID, lessonID uuid.UUID, query dto.ArticleDiffQueryDTO) (*dto.ArticleDiffDTO, error) {
+	lesson, err := s.authorLesson(ctx, actorID, courseID, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	article, err := s.findArticle(ctx, lesson.ID)
+	if err != nil {
+		return nil, err
+	}
+	to := query.To
+	if to == 0 {
+		to = article.Version
+	}
+	from := query.From
+	if from == 0 {
+		from = to - 1
+	}
+	fromRevision, err := s.findRevision(ctx, article.ID, from)
+	if err != nil {
+		return nil, err
+	}
+	toRevision, err := s.findRevision(ctx, article.ID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.ArticleDiffDTO{From: from, To: to, Lines: []dto.ArticleDiffLineDTO{}}
+	oldLine, newLine := 0, 0
+	for _, line := range utils.DiffLines(splitLines(fromRevision.Content), splitLines(toRevision.Content)) {
+		diffLine := dto.ArticleDiffLineDTO{Op: line.Op, Text: line.Text}
+		if line.Op != utils.DiffInsert {
+			oldLine++
+			n := oldLine
+			diffLine.OldLine = &n
+		}
+		if line.Op != utils.DiffDelete {
+			newLine++
+			n := newLine
+			diffLine.NewLine = &n
+		}
+		switch line.Op {
+		case utils.DiffInsert:
+			res.Added++
+		case utils.DiffDelete:
+			res.Removed++
+		}
+		res.Lines = append(res.Lines, diffLine)
+	}
+	return res, nil
+}
+
+// RestoreRevision saves the content of an earlier revision as the next version.
+func (s *ArticleService) RestoreRevision(ctx context.Context, actorID, courseID, lessonID uuid.UUID, version int) (*dto.ArticleDTO, error) {
+	lesson, err := s.editableArticleLesson(ctx, actorID, courseID, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	article, err := s.findArticle(ctx, lesson.ID)
+	if err != nil {
+		return nil, err
+	}
+	revision, err := s.findRevision(ctx, article.ID, version)
+	if err != nil {
+		return nil, err
+	}
+	restored, err := s.save(ctx, actorID, lesson.ID, revision.Content, &article.Version, &revision.Version)
+	if err != nil {
+		return nil, err
+	}
+	return s.toArticleResponse(restored, true)
+}
+
+// GetLessonArticle returns the rendered article to the students who may study the lesson.
+func (s *ArticleService) GetLessonArticle(ctx context.Context, actorID, lessonID uuid.UUID) (*dto.ArticleDTO, error) {
+	lesson, err := loadAccessibleLesson(ctx, s.courseRepo, s.enrollmentRepo, s.authz, actorID, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	article, err := s.findArticle(ctx, lesson.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.toArticleResponse(article, false)
+}
+
+func (s *ArticleService) save(ctx context.Context, actorID, lessonID uuid.UUID, content string, baseVersion, restoredFrom *int) (*model.LessonArticle, error) {
+	if strings.TrimSpace(content) == "" || len(content) > maxArticleSize || !utf8.ValidString(content) {
+		return nil, ErrInvalidArticle
+	}
+	doc, err := s.renderer.Render(content)
+	if err != nil {
+		return nil, err
+	}
+	readingTime := articleReadingTime(doc)
+
+	article := &model.LessonArticle{
+		LessonID:        lessonID,
+		Content:         content,
+		ContentHTML:     doc.HTML,
+		ReadingTimeMins: readingTime,
+	}
+	revision := &model.LessonArticleRevision{
+		AuthorID:        &actorID,
+		Content:         content,
+		ReadingTimeMins: readingTime,
+		RestoredFrom:    restoredFrom,
+	}
+	if err := s.articleRepo.Save(ctx, article, revision, baseVersion); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrArticleVersionConflict
+		}
+		return nil, err
+	}
+	return article, nil
+}
+
+// authorLesson checks that the actor may edit the course, without refusing courses under
+// review, and finds the lesson in it.
+func (s *ArticleService) authorLesson(ctx context.Context, actorID, courseID, lessonID uuid.UUID) (*model.Lesson, error) {
+	course, err := s.courseRepo.FindByID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if course == nil {
+		return nil, ErrCourseNotFound
+	}
+	allowed, err := s.authz.CanAccessResource(ctx, actorID, model.PermissionCoursesUpdateOwn, CourseResource(course))
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, ErrCourseForbidden
+	}
+	return s.findLesson(ctx, course.ID, lessonID)
+}
+
+func (s *ArticleService) editableArticleLesson(ctx context.Context, actorID, courseID, lessonID uuid.UUID) (*model.Lesson, error) {
+	course, err := loadEditableCourse(ctx, s.courseRepo, s.authz, actorID, courseID)
+	if err != nil {
+		return nil, err
+	}
+	lesson, err := s.findLesson(ctx, course.ID, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	if lesson.ContentType != model.LessonContentArticle {
+		return nil, ErrArticleLessonRequired
+	}
+	return lesson, nil
+}
+
+func (s *ArticleService) findLesson(ctx context.Context, courseID, lessonID uuid.UUID) (*model.Lesson, error) {
+	lesson, err := s.courseRepo.FindLesson(ctx, courseID, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	if lesson == nil {
+		return nil, ErrLessonNotFound
+	}
+	return lesson, nil
+}
+
+func (s *ArticleService) findArticle(ctx context.Context, lessonID uuid.UUID) (*model.LessonArticle, error) {
+	article, err := s.articleRepo.FindByLesson(ctx, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	if article == nil {
+		return nil, ErrArticleNotFound
+	}
+	return article, nil
+}
+
+func (s *ArticleService) findRevision(ctx context.Context, articleID uuid.UUID, version int) (*model.LessonArticleRevision, error) {
+	if version < 1 {
+		return nil, ErrArticleRevisionNotFound
+	}
+	revision, err := s.articleRepo.FindRevision(ctx, articleID, version)
+	if err != nil {
+		return nil, err
+	}
+	if revision == nil {
+		return nil, ErrArticleRevisionNotFound
+	}
+	return revision, nil
+}
+
+// toArticleResponse renders articles written before the HTML was stored, which only
+// happens until their next save.
+func (s *ArticleService) toArticleResponse(article *model.LessonArticle, withSource bool) (*dto.ArticleDTO, error) {
+	html := article.ContentHTML
+	if html == "" && article.Content != "" {
+		doc, err := s.renderer.Render(article.Content)
+		if err != nil {
+			return nil, err
+		}
+		html = doc.HTML
+	}
+	res := &dto.ArticleDTO{
+		LessonID:           article.LessonID,
+		HTML:               html,
+		ReadingTimeMinutes: article.ReadingTimeMins,
+		Version:            article.Version,
+		UpdatedAt:          article.UpdatedAt.Format(time.RFC3339),
+	}
+	if withSource {
+		res.Content = article.Content
+	}
+	return res, nil
+}
+
+func toArticleRevisionResponse(revision model.LessonArticleRevision) dto.ArticleRevisionDTO {
+	res := dto.ArticleRevisionDTO{
+		Version:            revision.Version,
+		AuthorID:           revision.AuthorID,
+		ReadingTimeMinutes: revision.ReadingTimeMins,
+		RestoredFrom:       revision.RestoredFrom,
+		CreatedAt:          revision.CreatedAt.Format(time.RFC3339),
+	}
+	if revision.Author != nil {
+		res.AuthorName = revision.Author.UserName
+		if revision.Author.FullName != nil && *revision.Author.FullName != "" {
+			res.AuthorName = *revision.Author.FullName
+		}
+	}
+	return res
+}
+
+// articleReadingTime is rounded up to whole minutes, at least one.
+func articleReadingTime(doc *markdown.Document) int {
+	seconds := float64(doc.Words)*60/articleWordsPerMinute +
+		float64(doc.CodeWords)*60/codeWordsPerMinute +
+		float64(doc.Images*secondsPerImage)
+	return max(int(math.Ceil(seconds/60)), 1)
+}
+
+func splitLines(content string) []string {
+	return strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+}
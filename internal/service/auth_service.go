@@ -2,14 +2,75 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
+	"study.com/v1/internal/cache"
 	"study.com/v1/internal/config"
 	"study.com/v1/internal/dto"
+	"study.com/v1/internal/model"
 	"study.com/v1/internal/repository"
+	"study.com/v1/internal/utils"
 )
 
+const (
+	otpLength              = 6
+	registerOTPTTL         = 5 * time.Minute
+	registerResendCooldown = 60 * time.Second
+	registerMaxOTPAttempts = 5
+	dateOfBirthLayout      = "2006-01-02"
+)
+
+var (
+	ErrEmailAlreadyExists    = errors.New("email already exists")
+	ErrUserNameAlreadyExists = errors.New("username already exists")
+	ErrInvalidEmail          = errors.New("invalid email")
+	ErrInvalidUserName       = errors.New("username must be at least 6 characters")
+	ErrInvalidPassword       = errors.New("password must be at least 6 characters")
+	ErrInvalidPhone          = errors.New("phone must be at least 9 characters")
+	ErrInvalidGender         = errors.New("gender must be one of male, female, other")
+	ErrInvalidDateOfBirth    = errors.New("date_of_birth must be in YYYY-MM-DD format")
+	ErrOTPResendCooldown     = errors.New("please wait before requesting a new OTP")
+	ErrOTPExpired            = errors.New("OTP expired or not requested")
+	ErrOTPInvalid            = errors.New("invalid OTP")
+	ErrOTPTooManyAttempts    = errors.New("too many failed attempts, please request a new OTP")
+	ErrInvalidCredentials    = errors.New("invalid email or password")
+	ErrAccountDisabled       = errors.New("account is disabled")
+	ErrUserNotFound          = errors.New("user not found")
+	ErrInvalidRefreshToken   = errors.New("invalid or expired refresh token")
+	ErrSessionNotFound       = errors.New("session not found")
+	ErrRefreshTokenReused    = errors.New("refresh token reuse detected, please login again")
+)
+
+// pendingRegistration is the signup payload kept in Redis until the OTP is confirmed.
+// The password is hashed before it is stashed so it never sits in Redis in plain text.
+type pendingRegistration struct {
+	Email        string `json:"email"`
+	UserName     string `json:"user_name"`
+	PasswordHash string `json:"password_hash"`
+	Phone        string `json:"phone"`
+	DateOfBirth  string `json:"date_of_birth"`
+	Gender       string `json:"gender"`
+	OTP          string `json:"otp"`
+}
+
+func registerPendingKey(email string) string {
+	return fmt.Sprintf("register:pending:%s", email)
+}
+
+func registerCooldownKey(email string) string {
+	return fmt.Sprintf("register:cooldown:%s", email)
+}
+
+func registerAttemptsKey(email string) string {
+	return fmt.Sprintf("register:attempts:%s", email)
+}
+
 type AuthServiceInterface interface {
 	RequestRegister(ctx context.Context, req dto.RegisterRequestDto) error
 	Register(ctx context.Context, req dto.RegisterDto) (*dto.RegisterResponseDto, error)
@@ -17,6 +78,8 @@ type AuthServiceInterface interface {
 	Logout(ctx context.Context, userId, deviceId uuid.UUID) error
 	LogoutAllDevice(ctx context.Context, userId uuid.UUID) error
 	RefreshToken(ctx context.Context, oldRefreshToken string) (*dto.RefreshTokenResponseDto, error)
+	ListSessions(ctx context.Context, userID, currentDeviceID uuid.UUID) ([]dto.SessionResponseDto, error)
+	RevokeSession(ctx context.Context, userID, deviceID uuid.UUID) error
 	GetMe(ctx context.Context, userID uuid.UUID) (*dto.UserResponseDto, error)
 	ChangePassword(ctx context.Context, userID uuid.UUID, req dto.ChangePasswordDto) error
 	RequestPasswordReset(ctx context.Context, email string) error
@@ -24,49 +87,384 @@ type AuthServiceInterface interface {
 }
 
 type AuthService struct {
-	cfg         *config.Config
-	userRepo    repository.UserRepositoryInterface
-	redisClient *redis.Client
+	cfg              *config.Config
+	userRepo         repository.UserRepositoryInterface
+	refreshTokenRepo repository.RefreshTokenRepositoryInterface
+	redisClient      *redis.Client
+	sessions         *cache.SessionStore
 }
 
 func NewAuthService(
 	cfg *config.Config,
 	userRepo repository.UserRepositoryInterface,
+	refreshTokenRepo repository.RefreshTokenRepositoryInterface,
 	redisClient *redis.Client,
 ) *AuthService {
 	return &AuthService{
-		cfg:         cfg,
-		userRepo:    userRepo,
-		redisClient: redisClient,
+		cfg:              cfg,
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		redisClient:      redisClient,
+		sessions:         cache.NewSessionStore(redisClient, cfg.JWTRefreshExpiration),
 	}
 }
 
 func (s *AuthService) RequestRegister(ctx context.Context, req dto.RegisterRequestDto) error {
+	req.Email = normalizeEmail(req.Email)
+	req.UserName = strings.TrimSpace(req.UserName)
+	if err := validateRegisterRequest(req); err != nil {
+		return err
+	}
+
+	if err := s.ensureAccountAvailable(ctx, req.Email, req.UserName); err != nil {
+		return err
+	}
+
+	// SetNX doubles as the resend cooldown: a second request inside the window is rejected.
+	ok, err := s.redisClient.SetNX(ctx, registerCooldownKey(req.Email), 1, registerResendCooldown).Result()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrOTPResendCooldown
+	}
+
+	otp, err := utils.GenerateOTP(otpLength)
+	if err != nil {
+		return err
+	}
+
+	passwordHash, err := utils.HashPassword(req.Password)
+	if err != nil {
+		return err
+	}
+
+	pending := pendingRegistration{
+		Email:        req.Email,
+		UserName:     req.UserName,
+		PasswordHash: passwordHash,
+		Phone:        strings.TrimSpace(req.Phone),
+		DateOfBirth:  req.DateOfBirth,
+		Gender:       req.Gender,
+		OTP:          otp,
+	}
+	payload, err := json.Marshal(pending)
+	if err != nil {
+		return err
+	}
+
+	pipe := s.redisClient.TxPipeline()
+	pipe.Set(ctx, registerPendingKey(req.Email), payload, registerOTPTTL)
+	pipe.Del(ctx, registerAttemptsKey(req.Email))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+
+	if err := utils.SendRegisterOTP(s.cfg, req.Email, otp); err != nil {
+		s.redisClient.Del(ctx, registerPendingKey(req.Email), registerCooldownKey(req.Email))
+		return fmt.Errorf("failed to send OTP: %w", err)
+	}
+
 	return nil
 }
 
 func (s *AuthService) Register(ctx context.Context, req dto.RegisterDto) (*dto.RegisterResponseDto, error) {
-	return nil, nil
+	email := normalizeEmail(req.Email)
+
+	data, err := s.redisClient.Get(ctx, registerPendingKey(email)).Bytes()
+	if err == redis.Nil {
+		return nil, ErrOTPExpired
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var pending pendingRegistration
+	if err := json.Unmarshal(data, &pending); err != nil {
+		return nil, err
+	}
+
+	if !utils.OTPMatches(req.OTP, pending.OTP) {
+		attempts, err := s.redisClient.Incr(ctx, registerAttemptsKey(email)).Result()
+		if err != nil {
+			return nil, err
+		}
+		if attempts == 1 {
+			s.redisClient.Expire(ctx, registerAttemptsKey(email), registerOTPTTL)
+		}
+		if attempts >= registerMaxOTPAttempts {
+			s.redisClient.Del(ctx, registerPendingKey(email), registerAttemptsKey(email))
+			return nil, ErrOTPTooManyAttempts
+		}
+		return nil, ErrOTPInvalid
+	}
+
+	// The OTP is single use, drop it before touching the database.
+	s.redisClient.Del(ctx, registerPendingKey(email), registerAttemptsKey(email))
+
+	// Someone may have taken the email or username while the OTP was pending.
+	if err := s.ensureAccountAvailable(ctx, pending.Email, pending.UserName); err != nil {
+		return nil, err
+	}
+
+	dob, err := time.Parse(dateOfBirthLayout, pending.DateOfBirth)
+	if err != nil {
+		return nil, ErrInvalidDateOfBirth
+	}
+
+	user := &model.User{
+		Email:        pending.Email,
+		UserName:     pending.UserName,
+		PasswordHash: pending.PasswordHash,
+		Phone:        &pending.Phone,
+		DateOfBirth:  &dob,
+		Gender:       &pending.Gender,
+		IsVerified:   true,
+		IsActive:     true,
+	}
+	if err := s.userRepo.CreateUserWithRoles(ctx, user, []string{model.RoleStudent}); err != nil {
+		return nil, err
+	}
+
+	return &dto.RegisterResponseDto{
+		User: toUserResponse(user),
+	}, nil
 }
 
 func (s *AuthService) Login(ctx context.Context, req dto.LoginDTO) (*dto.LoginResponseDto, error) {
-	return nil, nil
+	user, err := s.userRepo.FindUserByEmail(ctx, normalizeEmail(req.Email))
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || !utils.CheckPassword(req.Password, user.PasswordHash) {
+		return nil, ErrInvalidCredentials
+	}
+	if !user.IsActive {
+		return nil, ErrAccountDisabled
+	}
+
+	deviceID := req.DeviceId
+	if deviceID == uuid.Nil {
+		deviceID = uuid.New()
+	}
+
+	version, err := s.sessions.CurrentVersion(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, refreshToken, err := utils.GenerateTokens(s.cfg, user.ID, deviceID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := &cache.Session{
+		UserID:     user.ID,
+		DeviceID:   deviceID,
+		DeviceName: req.DeviceName,
+		UserAgent:  req.UserAgent,
+		IPAddress:  req.IpAddress,
+		CreatedAt:  now,
+		LastSeenAt: now,
+	}
+	if err := s.sessions.Save(ctx, session); err != nil {
+		return nil, err
+	}
+
+	// A fresh login on a device starts a new token family and ends the previous one.
+	if err := s.refreshTokenRepo.RevokeDevice(ctx, user.ID, deviceID, repository.RevokeReasonRelogin); err != nil {
+		return nil, err
+	}
+	record := &model.RefreshToken{
+		UserID:     user.ID,
+		FamilyID:   uuid.New(),
+		TokenHash:  utils.HashToken(refreshToken),
+		DeviceID:   deviceID,
+		DeviceName: &req.DeviceName,
+		IPAddress:  &req.IpAddress,
+		UserAgent:  &req.UserAgent,
+		ExpiresAt:  now.Add(s.cfg.JWTRefreshExpiration),
+	}
+	if err := s.refreshTokenRepo.Create(ctx, record); err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.UpdateLastLogin(ctx, user.ID, now); err != nil {
+		return nil, err
+	}
+	user.LastLoginAt = &now
+
+	return &dto.LoginResponseDto{
+		AccessToken:           accessToken,
+		RefreshToken:          refreshToken,
+		AccessTokenExpiresAt:  now.Add(s.cfg.JWTAccessExpiration).Format(time.RFC3339),
+		RefreshTokenExpiresAt: record.ExpiresAt.Format(time.RFC3339),
+		User:                  toUserResponse(user),
+	}, nil
 }
 
 func (s *AuthService) Logout(ctx context.Context, userId, deviceId uuid.UUID) error {
-	return nil
+	if err := s.refreshTokenRepo.RevokeDevice(ctx, userId, deviceId, repository.RevokeReasonLogout); err != nil {
+		return err
+	}
+	return s.sessions.Delete(ctx, userId, deviceId)
 }
 
 func (s *AuthService) LogoutAllDevice(ctx context.Context, userId uuid.UUID) error {
-	return nil
+	// Bumping the version kills every outstanding access token in one step,
+	// revoking the stored hashes makes the refresh tokens useless as well.
+	if _, err := s.sessions.BumpVersion(ctx, userId); err != nil {
+		return err
+	}
+	if err := s.refreshTokenRepo.RevokeAllForUser(ctx, userId, repository.RevokeReasonLogout); err != nil {
+		return err
+	}
+	return s.sessions.DeleteAll(ctx, userId)
 }
 
 func (s *AuthService) RefreshToken(ctx context.Context, oldRefreshToken string) (*dto.RefreshTokenResponseDto, error) {
-	return nil, nil
+	if oldRefreshToken == "" {
+		return nil, ErrInvalidRefreshToken
+	}
+	claims, err := utils.ParseToken(s.cfg, oldRefreshToken, utils.TokenTypeRefresh)
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	record, err := s.refreshTokenRepo.FindByHash(ctx, utils.HashToken(oldRefreshToken))
+	if err != nil {
+		return nil, err
+	}
+	if record == nil || record.UserID != claims.UserID || record.DeviceID != claims.DeviceID {
+		return nil, ErrInvalidRefreshToken
+	}
+	if record.IsRevoked {
+		if record.RevokedReason != nil && *record.RevokedReason == repository.RevokeReasonRotated {
+			return nil, s.revokeReusedFamily(ctx, record)
+		}
+		return nil, ErrInvalidRefreshToken
+	}
+	if time.Now().After(record.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	version, err := s.sessions.CurrentVersion(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if version != claims.Version {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	exists, err := s.sessions.Exists(ctx, claims.UserID, claims.DeviceID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	accessToken, refreshToken, err := utils.GenerateTokens(s.cfg, claims.UserID, claims.DeviceID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	next := &model.RefreshToken{
+		ID:         uuid.New(),
+		UserID:     record.UserID,
+		FamilyID:   record.FamilyID,
+		TokenHash:  utils.HashToken(refreshToken),
+		DeviceID:   record.DeviceID,
+		DeviceName: record.DeviceName,
+		IPAddress:  record.IPAddress,
+		UserAgent:  record.UserAgent,
+		ExpiresAt:  time.Now().Add(s.cfg.JWTRefreshExpiration),
+	}
+	// Claiming the old token and storing the new one commit together, and only one of two
+	// concurrent refreshes with the same token can claim it.
+	rotated, err := s.refreshTokenRepo.Rotate(ctx, record.ID, next)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		return nil, s.revokeReusedFamily(ctx, record)
+	}
+
+	if err := s.sessions.Extend(ctx, claims.UserID, claims.DeviceID, "", ""); err != nil {
+		if errors.Is(err, cache.ErrSessionNotFound) {
+			// Signed out while the tokens were being rotated
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	return &dto.RefreshTokenResponseDto{
+		AccessToken:           accessToken,
+		RefreshToken:          refreshToken,
+		AccessTokenExpiresAt:  time.Now().Add(s.cfg.JWTAccessExpiration).Format(time.RFC3339),
+		RefreshTokenExpiresAt: next.ExpiresAt.Format(time.RFC3339),
+	}, nil
+}
+
+// revokeReusedFamily handles a refresh token that was presented after it had been rotated.
+// Either the legitimate client or an attacker holds a stale copy, so the whole family and
+// the device session are revoked and the user has to sign in again.
+func (s *AuthService) revokeReusedFamily(ctx context.Context, record *model.RefreshToken) error {
+	if err := s.refreshTokenRepo.RevokeFamily(ctx, record.FamilyID, repository.RevokeReasonReused); err != nil {
+		return err
+	}
+	if err := s.sessions.Delete(ctx, record.UserID, record.DeviceID); err != nil {
+		return err
+	}
+	return ErrRefreshTokenReused
+}
+
+func (s *AuthService) ListSessions(ctx context.Context, userID, currentDeviceID uuid.UUID) ([]dto.SessionResponseDto, error) {
+	sessions, err := s.sessions.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]dto.SessionResponseDto, 0, len(sessions))
+	for _, session := range sessions {
+		res = append(res, dto.SessionResponseDto{
+			DeviceID:   session.DeviceID,
+			DeviceName: session.DeviceName,
+			UserAgent:  session.UserAgent,
+			IpAddress:  session.IPAddress,
+			CreatedAt:  session.CreatedAt.Format(time.RFC3339),
+			LastSeenAt: session.LastSeenAt.Format(time.RFC3339),
+			IsCurrent:  session.DeviceID == currentDeviceID,
+		})
+	}
+	return res, nil
+}
+
+func (s *AuthService) RevokeSession(ctx context.Context, userID, deviceID uuid.UUID) error {
+	exists, err := s.sessions.Exists(ctx, userID, deviceID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrSessionNotFound
+	}
+	if err := s.refreshTokenRepo.RevokeDevice(ctx, userID, deviceID, repository.RevokeReasonLogout); err != nil {
+		return err
+	}
+	return s.sessions.Delete(ctx, userID, deviceID)
 }
 
 func (s *AuthService) GetMe(ctx context.Context, userID uuid.UUID) (*dto.UserResponseDto, error) {
-	return nil, nil
+	user, err := s.userRepo.FindUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	res := toUserResponse(user)
+	return &res, nil
 }
 
 func (s *AuthService) ChangePassword(ctx context.Context, userID uuid.UUID, req dto.ChangePasswordDto) error {
@@ -82,3 +480,72 @@ func (s *AuthService) RequestPasswordReset(ctx context.Context, email string) er
 func (s *AuthService) ResetPassword(ctx context.Context, req dto.ResetPasswordDto) error {
 	return nil
 }
+
+func (s *AuthService) ensureAccountAvailable(ctx context.Context, email, userName string) error {
+	existing, err := s.userRepo.FindUserByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return ErrEmailAlreadyExists
+	}
+
+	existing, err = s.userRepo.FindUserByUserName(ctx, userName)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return ErrUserNameAlreadyExists
+	}
+	return nil
+}
+
+func validateRegisterRequest(req dto.RegisterRequestDto) error {
+	if !utils.IsValidEmail(req.Email) {
+		return ErrInvalidEmail
+	}
+	if len(req.UserName) < 6 {
+		return ErrInvalidUserName
+	}
+	if len(req.Password) < 6 {
+		return ErrInvalidPassword
+	}
+	if len(strings.TrimSpace(req.Phone)) < 9 {
+		return ErrInvalidPhone
+	}
+	switch req.Gender {
+	case "male", "female", "other":
+	default:
+		return ErrInvalidGender
+	}
+	if _, err := time.Parse(dateOfBirthLayout, req.DateOfBirth); err != nil {
+		return ErrInvalidDateOfBirth
+	}
+	return nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func toUserResponse(user *model.User) dto.UserResponseDto {
+	res := dto.UserResponseDto{
+		ID:        user.ID,
+		Username:  user.UserName,
+		Email:     user.Email,
+		Phone:     user.Phone,
+		AvatarUrl: user.AvatarURL,
+		Gender:    user.Gender,
+		CreatedAt: user.CreatedAt.Format(time.RFC3339),
+	}
+	if user.DateOfBirth != nil {
+		dob := user.DateOfBirth.Format(dateOfBirthLayout)
+		res.DateOfBirth = &dob
+	}
+	status := "inactive"
+	if user.IsActive {
+		status = "active"
+	}
+	res.Status = &status
+	return res
+}
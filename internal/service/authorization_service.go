@@ -0,0 +1,477 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"study.com/v1/internal/model"
+	"study.com/v1/internal/repository"
+)
+
+const (
+	permissionCacheTTL        = 10 * time.Minute
+	permissionGenerationKey   = "permissions:generation"
+	permissionCacheKeyPattern = "user_permissions:%d:%s"
+)
+
+// Resource types used by resource-scoped overrides and ResourceContext.
+const (
+	ResourceTypeOrganization = "organization"
+	ResourceTypeCourse       = "course"
+	ResourceTypeLesson       = "lesson"
+)
+
+type AuthorizationServiceInterface interface {
+	GetUserPermissions(ctx context.Context, userID uuid.UUID) (map[string]struct{}, error)
+	HasAllPermissions(ctx context.Context, userID uuid.UUID, permissions ...string) (bool, error)
+	HasAnyPermission(ctx context.Context, userID uuid.UUID, permissions ...string) (bool, error)
+	CanAccessResource(ctx context.Context, userID uuid.UUID, permission string, resource ResourceContext) (bool, error)
+	HasPermissionInOrganization(ctx context.Context, userID, organizationID uuid.UUID, permission string) (bool, error)
+	InvalidateUserPermissions(ctx context.Context, userID uuid.UUID) error
+	InvalidateAllPermissions(ctx context.Context) error
+}
+
+// ResourceContext describes the object a permission is checked against.
+// OwnerID is compared with the acting user for the own_resource_only condition, and
+// OrganizationID selects the roles the user holds inside that organization.
+type ResourceContext struct {
+	Type           string
+	ID             uuid.UUID
+	OwnerID        uuid.UUID
+	OrganizationID *uuid.UUID
+}
+
+// CourseResource builds the context for checks such as COURSES_UPDATE_OWN,
+// where the owner of a course is its instructor.
+func CourseResource(course *model.Course) ResourceContext {
+	return ResourceContext{
+		Type:           ResourceTypeCourse,
+		ID:             course.ID,
+		OwnerID:        course.InstructorID,
+		OrganizationID: course.OrganizationID,
+	}
+}
+
+// OrganizationResource builds the context for checks on the organization itself,
+// such as ORG_MEMBERS_MANAGE.
+func OrganizationResource(org *model.Organization) ResourceContext {
+	return ResourceContext{
+		Type:           ResourceTypeOrganization,
+		ID:             org.ID,
+		OwnerID:        org.OwnerID,
+		OrganizationID: &org.ID,
+	}
+}
+
+// permissionGrant is a granted permission. It is unconditional, or it holds when any
+// one of the condition sets (one per granting role) is satisfied.
+type permissionGrant struct {
+	Unconditional bool                         `json:"unconditional,omitempty"`
+	Conditions    []model.PermissionConditions `json:"conditions,omitempty"`
+}
+
+// scopedOverride is a user override that only applies to one organization or resource.
+type scopedOverride struct {
+	Permission     string     `json:"permission"`
+	IsGranted      bool       `json:"is_granted"`
+	OrganizationID *uuid.UUID `json:"organization_id,omitempty"`
+	ResourceType   string     `json:"resource_type,omitempty"`
+	ResourceID     *uuid.UUID `json:"resource_id,omitempty"`
+}
+
+// organizationPolicy holds what the roles a user was given inside one organization grant.
+type organizationPolicy struct {
+	Granted map[string]permissionGrant `json:"granted"`
+	Denied  map[string]bool            `json:"denied,omitempty"`
+}
+
+// permissionPolicy is the resolved, cacheable view of everything a user may do.
+type permissionPolicy struct {
+	Granted       map[string]permissionGrant       `json:"granted"`
+	Denied        map[string]bool                  `json:"denied"`
+	Scoped        []scopedOverride                 `json:"scoped,omitempty"`
+	Organizations map[uuid.UUID]organizationPolicy `json:"organizations,omitempty"`
+}
+
+// AuthorizationService resolves a user's effective permissions and caches them in Redis.
+// Cache keys embed a global generation number so that editing a role's permissions can
+// drop every cached policy at once by bumping the generation.
+//
+// Resolution order, from weakest to strongest:
+//  1. grants from all of the user's roles (conditions from different roles are OR-ed)
+//  2. explicit denies from any role, which win over grants from other roles
+//  3. user_permission_overrides without a scope; a deny override wins over a grant override
+//  4. overrides scoped to an organization or resource, checked only by CanAccessResource
+//
+// Roles assigned inside an organization are kept apart from the global ones and only
+// count when the checked resource belongs to that organization, so HasAllPermissions
+// and HasAnyPermission never see them. A deny from an organization role wins over a
+// global grant within that organization.
+type AuthorizationService struct {
+	userRepo       repository.UserRepositoryInterface
+	permissionRepo repository.PermissionRepositoryInterface
+	overrideRepo   repository.PermissionOverrideRepositoryInterface
+	redisClient    *redis.Client
+}
+
+func NewAuthorizationService(
+	userRepo repository.UserRepositoryInterface,
+	permissionRepo repository.PermissionRepositoryInterface,
+	overrideRepo repository.PermissionOverrideRepositoryInterface,
+	redisClient *redis.Client,
+) *AuthorizationService {
+	return &AuthorizationService{
+		userRepo:       userRepo,
+		permissionRepo: permissionRepo,
+		overrideRepo:   overrideRepo,
+		redisClient:    redisClient,
+	}
+}
+
+// GetUserPermissions returns the permissions the user holds in at least some context,
+// including conditional grants. The wildcard is returned as "*".
+func (s *AuthorizationService) GetUserPermissions(ctx context.Context, userID uuid.UUID) (map[string]struct{}, error) {
+	policy, err := s.policy(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	set := make(map[string]struct{}, len(policy.Granted))
+	for name := range policy.Granted {
+		set[name] = struct{}{}
+	}
+	return set, nil
+}
+
+// HasAllPermissions is a route-level check: conditional grants pass, so handlers guarding
+// a specific resource must follow up with CanAccessResource.
+func (s *AuthorizationService) HasAllPermissions(ctx context.Context, userID uuid.UUID, permissions ...string) (bool, error) {
+	policy, err := s.policy(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	for _, p := range permissions {
+		if !policy.mayHold(p) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (s *AuthorizationService) HasAnyPermission(ctx context.Context, userID uuid.UUID, permissions ...string) (bool, error) {
+	policy, err := s.policy(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	for _, p := range permissions {
+		if policy.mayHold(p) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// CanAccessResource checks one permission against a concrete resource, applying
+// resource-scoped overrides and ABAC conditions.
+func (s *AuthorizationService) CanAccessResource(ctx context.Context, userID uuid.UUID, permission string, resource ResourceContext) (bool, error) {
+	policy, err := s.policy(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	if granted, matched := policy.scopedDecision(permission, resource); matched {
+		return granted, nil
+	}
+	if policy.Denied[permission] {
+		return false, nil
+	}
+	if _, ok := policy.Granted[model.PermissionWildcard]; ok {
+		return true, nil
+	}
+	if resource.OrganizationID != nil {
+		if org, ok := policy.Organizations[*resource.OrganizationID]; ok {
+			if org.Denied[permission] {
+				return false, nil
+			}
+			if org.Granted[permission].allows(userID, resource) {
+				return true, nil
+			}
+		}
+	}
+	return policy.Granted[permission].allows(userID, resource), nil
+}
+
+// HasPermissionInOrganization reports whether the user holds the permission, possibly
+// conditionally, somewhere inside the organization. It is used to stop organization
+// managers from handing out permissions they do not have themselves.
+func (s *AuthorizationService) HasPermissionInOrganization(ctx context.Context, userID, organizationID uuid.UUID, permission string) (bool, error) {
+	policy, err := s.policy(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	resource := ResourceContext{Type: ResourceTypeOrganization, ID: organizationID, OrganizationID: &organizationID}
+	if granted, matched := policy.scopedDecision(permission, resource); matched {
+		return granted, nil
+	}
+	if policy.Denied[permission] {
+		return false, nil
+	}
+	if _, ok := policy.Granted[model.PermissionWildcard]; ok {
+		return true, nil
+	}
+	if org, ok := policy.Organizations[organizationID]; ok {
+		if org.Denied[permission] {
+			return false, nil
+		}
+		if _, ok := org.Granted[permission]; ok {
+			return true, nil
+		}
+	}
+	_, ok := policy.Granted[permission]
+	return ok, nil
+}
+
+// InvalidateUserPermissions must be called whenever the roles or overrides of a user change.
+func (s *AuthorizationService) InvalidateUserPermissions(ctx context.Context, userID uuid.UUID) error {
+	key, err := s.cacheKey(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return s.redisClient.Del(ctx, key).Err()
+}
+
+// InvalidateAllPermissions must be called whenever the permissions of a role change.
+func (s *AuthorizationService) InvalidateAllPermissions(ctx context.Context) error {
+	return s.redisClient.Incr(ctx, permissionGenerationKey).Err()
+}
+
+func (s *AuthorizationService) policy(ctx context.Context, userID uuid.UUID) (*permissionPolicy, error) {
+	key, err := s.cacheKey(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if cached, err := s.redisClient.Get(ctx, key).Bytes(); err == nil {
+		var policy permissionPolicy
+		if err := json.Unmarshal(cached, &policy); err == nil && policy.Granted != nil {
+			return &policy, nil
+		}
+	} else if err != redis.Nil {
+		return nil, err
+	}
+
+	policy, ttl, err := s.loadPolicy(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(policy)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.redisClient.Set(ctx, key, payload, ttl).Err(); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (s *AuthorizationService) cacheKey(ctx context.Context, userID uuid.UUID) (string, error) {
+	generation, err := s.redisClient.Get(ctx, permissionGenerationKey).Int64()
+	if err != nil && err != redis.Nil {
+		return "", err
+	}
+	return fmt.Sprintf(permissionCacheKeyPattern, generation, userID), nil
+}
+
+// loadPolicy resolves the policy from the database. The returned TTL is shortened so
+// that the cached policy does not outlive the first override to expire.
+func (s *AuthorizationService) loadPolicy(ctx context.Context, userID uuid.UUID) (*permissionPolicy, time.Duration, error) {
+	policy := &permissionPolicy{
+		Granted: map[string]permissionGrant{},
+		Denied:  map[string]bool{},
+	}
+	ttl := permissionCacheTTL
+
+	user, err := s.userRepo.FindUserByID(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if user == nil || !user.IsActive {
+		return policy, ttl, nil
+	}
+
+	rolePermissions, err := s.permissionRepo.FindRolePermissionsByUserID(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	policy.Granted, policy.Denied = resolveRolePermissions(rolePermissions)
+
+	orgRolePermissions, err := s.permissionRepo.FindOrganizationRolePermissionsByUserID(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(orgRolePermissions) > 0 {
+		policy.Organizations = make(map[uuid.UUID]organizationPolicy, len(orgRolePermissions))
+		for orgID, rps := range orgRolePermissions {
+			granted, denied := resolveRolePermissions(rps)
+			policy.Organizations[orgID] = organizationPolicy{Granted: granted, Denied: denied}
+		}
+	}
+
+	now := time.Now()
+	overrides, err := s.overrideRepo.FindActiveByUserID(ctx, userID, now)
+	if err != nil {
+		return nil, 0, err
+	}
+	var denyOverrides []string
+	for _, o := range overrides {
+		if o.ExpiresAt != nil && o.ExpiresAt.Sub(now) < ttl {
+			ttl = o.ExpiresAt.Sub(now)
+		}
+		name := o.Permission.Name
+		if o.OrganizationID != nil || o.ResourceID != nil || o.ResourceType != nil {
+			scoped := scopedOverride{
+				Permission:     name,
+				IsGranted:      o.IsGranted,
+				OrganizationID: o.OrganizationID,
+				ResourceID:     o.ResourceID,
+			}
+			if o.ResourceType != nil {
+				scoped.ResourceType = *o.ResourceType
+			}
+			policy.Scoped = append(policy.Scoped, scoped)
+			continue
+		}
+		if !o.IsGranted {
+			denyOverrides = append(denyOverrides, name)
+			continue
+		}
+		delete(policy.Denied, name)
+		policy.Granted[name] = permissionGrant{Unconditional: true}
+	}
+	for _, name := range denyOverrides {
+		delete(policy.Granted, name)
+		policy.Denied[name] = true
+	}
+
+	if ttl < time.Second {
+		ttl = time.Second
+	}
+	return policy, ttl, nil
+}
+
+// resolveRolePermissions merges the rows of several roles: conditions of different roles
+// are OR-ed, an unconditional grant absorbs them and a deny removes the grant.
+func resolveRolePermissions(rolePermissions []model.RolePermission) (map[string]permissionGrant, map[string]bool) {
+	granted := map[string]permissionGrant{}
+	denied := map[string]bool{}
+	for _, rp := range rolePermissions {
+		name := rp.Permission.Name
+		if !rp.IsGranted {
+			denied[name] = true
+			continue
+		}
+		grant := granted[name]
+		if len(rp.Conditions) == 0 {
+			grant.Unconditional = true
+			grant.Conditions = nil
+		} else if !grant.Unconditional {
+			grant.Conditions = append(grant.Conditions, rp.Conditions)
+		}
+		granted[name] = grant
+	}
+	for name := range denied {
+		delete(granted, name)
+	}
+	return granted, denied
+}
+
+// allows evaluates the grant against a resource. The zero value allows nothing.
+func (g permissionGrant) allows(userID uuid.UUID, resource ResourceContext) bool {
+	if g.Unconditional {
+		return true
+	}
+	for _, conditions := range g.Conditions {
+		if conditionsMet(conditions, userID, resource) {
+			return true
+		}
+	}
+	return false
+}
+
+// mayHold reports whether the permission is granted in at least some context.
+func (p *permissionPolicy) mayHold(permission string) bool {
+	if p.Denied[permission] {
+		return p.hasScopedGrant(permission)
+	}
+	if _, ok := p.Granted[permission]; ok {
+		return true
+	}
+	if _, ok := p.Granted[model.PermissionWildcard]; ok {
+		return true
+	}
+	return p.hasScopedGrant(permission)
+}
+
+func (p *permissionPolicy) hasScopedGrant(permission string) bool {
+	for _, o := range p.Scoped {
+		if o.Permission == permission && o.IsGranted {
+			return true
+		}
+	}
+	return false
+}
+
+// scopedDecision applies the scoped overrides that match the resource. A matching
+// deny wins over a matching grant.
+func (p *permissionPolicy) scopedDecision(permission string, resource ResourceContext) (granted bool, matched bool) {
+	for _, o := range p.Scoped {
+		if o.Permission != permission || !o.matches(resource) {
+			continue
+		}
+		if !o.IsGranted {
+			return false, true
+		}
+		granted, matched = true, true
+	}
+	return granted, matched
+}
+
+func (o scopedOverride) matches(resource ResourceContext) bool {
+	if o.OrganizationID != nil {
+		if resource.OrganizationID == nil || *resource.OrganizationID != *o.OrganizationID {
+			return false
+		}
+	}
+	if o.ResourceType != "" && o.ResourceType != resource.Type {
+		return false
+	}
+	if o.ResourceID != nil && *o.ResourceID != resource.ID {
+		return false
+	}
+	return true
+}
+
+// conditionsMet evaluates ABAC conditions. Unknown keys never match so that a
+// condition the code does not understand cannot widen access.
+func conditionsMet(conditions model.PermissionConditions, userID uuid.UUID, resource ResourceContext) bool {
+	for key, value := range conditions {
+		switch key {
+		case model.ConditionOwnResourceOnly:
+			ownOnly, ok := value.(bool)
+			if !ok {
+				return false
+			}
+			if ownOnly && (resource.OwnerID == uuid.Nil || resource.OwnerID != userID) {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,369 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"study.com/v1/internal/dto"
+	"study.com/v1/internal/model"
+	"study.com/v1/internal/repository"
+)
+
+var (
+	ErrInvalidCatalogQuery = errors.New("invalid catalog filter")
+	ErrInvalidCursor       = errors.New("invalid or expired cursor")
+)
+
+// ratingFacetSteps are the "x stars and up" buckets of the rating facet.
+var ratingFacetSteps = []string{"4.5", "4.0", "3.5", "3.0"}
+
+type CatalogServiceInterface interface {
+	SearchCourses(ctx context.Context, query dto.CatalogQueryDTO) (*dto.CatalogListResponseDTO, error)
+	GetCourse(ctx context.Context, slug string) (*dto.CatalogCourseDetailDTO, error)
+}
+
+// CatalogService is the public, unauthenticated view of published courses.
+type CatalogService struct {
+	catalogRepo repository.CatalogRepositoryInterface
+}
+
+func NewCatalogService(catalogRepo repository.CatalogRepositoryInterface) *CatalogService {
+	return &CatalogService{catalogRepo: catalogRepo}
+}
+
+// catalogCursor is what next_cursor encodes. The sort is kept so that a cursor cannot be
+// replayed against another order, where its key would mean something else; the search
+// mode so that the following pages keep the typo tolerant matching of the first.
+type catalogCursor struct {
+	Sort string    `json:"s"`
+	Mode string    `json:"m,omitempty"`
+	Key  string    `json:"k"`
+	ID   uuid.UUID `json:"id"`
+}
+
+func (s *CatalogService) SearchCourses(ctx context.Context, query dto.CatalogQueryDTO) (*dto.CatalogListResponseDTO, error) {
+	filter, err := parseCatalogFilter(query)
+	if err != nil {
+		return nil, err
+	}
+	sort := query.Sort
+	if sort == "" {
+		sort = repository.CatalogSortPopular
+		if filter.Search != "" {
+			sort = repository.CatalogSortRelevance
+		}
+	}
+	switch sort {
+	case repository.CatalogSortPopular, repository.CatalogSortRating, repository.CatalogSortNewest,
+		repository.CatalogSortPriceAsc, repository.CatalogSortPriceDesc:
+	case repository.CatalogSortRelevance:
+		if filter.Search == "" {
+			return nil, invalidCatalogQuery("sort=relevance needs q")
+		}
+	default:
+		return nil, invalidCatalogQuery("sort must be relevance, popular, rating, newest, price_asc or price_desc")
+	}
+	_, limit := normalizePage(1, query.Limit)
+
+	var after *repository.CatalogCursor
+	if query.Cursor != "" {
+		if after, filter.SearchMode, err = decodeCatalogCursor(query.Cursor, sort); err != nil {
+			return nil, err
+		}
+	}
+	if filter.Search != "" && filter.SearchMode == "" {
+		filter.SearchMode = repository.SearchModeFullText
+	}
+
+	// One extra row tells whether there is a next page
+	hits, err := s.catalogRepo.Search(ctx, filter, sort, after, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	// Nothing matched whole words on the first page: try again tolerating typos
+	if len(hits) == 0 && after == nil && filter.SearchMode == repository.SearchModeFullText {
+		filter.SearchMode = repository.SearchModeFuzzy
+		if hits, err = s.catalogRepo.Search(ctx, filter, sort, nil, limit+1); err != nil {
+			return nil, err
+		}
+	}
+
+	res := &dto.CatalogListResponseDTO{
+		Courses:    make([]dto.CatalogCourseDTO, 0, limit),
+		SearchMode: filter.SearchMode,
+	}
+	if len(hits) > limit {
+		hits = hits[:limit]
+		res.HasMore = true
+		cursor := encodeCatalogCursor(hits[limit-1], sort, filter.SearchMode)
+		res.NextCursor = &cursor
+	}
+	now := time.Now()
+	for _, hit := range hits {
+		res.Courses = append(res.Courses, toCatalogCourse(hit.Course, now))
+	}
+
+	if query.Cursor == "" {
+		facets, err := s.catalogRepo.Facets(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		res.Facets = toCatalogFacets(facets)
+	}
+	return res, nil
+}
+
+func (s *CatalogService) GetCourse(ctx context.Context, slug string) (*dto.CatalogCourseDetailDTO, error) {
+	course, err := s.catalogRepo.FindPublishedBySlug(ctx, strings.ToLower(strings.TrimSpace(slug)))
+	if err != nil {
+		return nil, err
+	}
+	if course == nil {
+		return nil, ErrCourseNotFound
+	}
+
+	res := &dto.CatalogCourseDetailDTO{
+		CatalogCourseDTO: toCatalogCourse(*course, time.Now()),
+		Description:      course.Description,
+		PreviewVideoURL:  course.PreviewVideoURL,
+		Requirements:     nonNilStrings(course.Requirements),
+		Objectives:       nonNilStrings(course.Objectives),
+		TargetAudience:   nonNilStrings(course.TargetAudience),
+		Tags:             make([]dto.CatalogTagDTO, 0, len(course.Tags)),
+		Sections:         make([]dto.CatalogSectionDTO, 0, len(course.Sections)),
+	}
+	res.Instructor.Bio = course.Instructor.Bio
+	for _, tag := range course.Tags {
+		res.Tags = append(res.Tags, dto.CatalogTagDTO{Name: tag.Name, Slug: tag.Slug})
+	}
+	for _, section := range course.Sections {
+		item := dto.CatalogSectionDTO{
+			ID:           section.ID,
+			Title:        section.Title,
+			Description:  section.Description,
+			DisplayOrder: section.DisplayOrder,
+			Lessons:      make([]dto.CatalogLessonDTO, 0, len(section.Lessons)),
+		}
+		for _, lesson := range section.Lessons {
+			item.DurationMins += lesson.DurationMins
+			if lesson.IsPreview {
+				res.TotalPreviews++
+			}
+			item.Lessons = append(item.Lessons, dto.CatalogLessonDTO{
+				ID:           lesson.ID,
+				Title:        lesson.Title,
+				ContentType:  lesson.ContentType,
+				DisplayOrder: lesson.DisplayOrder,
+				DurationMins: lesson.DurationMins,
+				IsPreview:    lesson.IsPreview,
+			})
+		}
+		res.Sections = append(res.Sections, item)
+	}
+	return res, nil
+}
+
+func parseCatalogFilter(query dto.CatalogQueryDTO) (repository.CatalogFilter, error) {
+	search, err := normalizeSearchText(query.Q)
+	if err != nil {
+		return repository.CatalogFilter{}, err
+	}
+	filter := repository.CatalogFilter{
+		Search:       search,
+		CategorySlug: strings.ToLower(strings.TrimSpace(query.Category)),
+		TagSlug:      strings.ToLower(strings.TrimSpace(query.Tag)),
+		Language:     strings.TrimSpace(query.Language),
+	}
+	if query.CategoryID != "" {
+		id, err := uuid.Parse(query.CategoryID)
+		if err != nil {
+			return filter, invalidCatalogQuery("category_id must be a UUID")
+		}
+		filter.CategoryID = &id
+	}
+	if query.Level != "" {
+		switch query.Level {
+		case model.CourseLevelBeginner, model.CourseLevelIntermediate, model.CourseLevelAdvanced, model.CourseLevelAllLevels:
+			filter.Level = query.Level
+		default:
+			return filter, ErrInvalidCourseLevel
+		}
+	}
+	if query.IsFree != "" {
+		isFree, err := strconv.ParseBool(query.IsFree)
+		if err != nil {
+			return filter, invalidCatalogQuery("is_free must be true or false")
+		}
+		filter.IsFree = &isFree
+	}
+
+	if filter.MinPrice, err = parseCatalogDecimal(query.MinPrice, "min_price"); err != nil {
+		return filter, err
+	}
+	if filter.MaxPrice, err = parseCatalogDecimal(query.MaxPrice, "max_price"); err != nil {
+		return filter, err
+	}
+	if filter.MinPrice != nil && filter.MaxPrice != nil && filter.MinPrice.GreaterThan(*filter.MaxPrice) {
+		return filter, invalidCatalogQuery("min_price must not be greater than max_price")
+	}
+	if filter.MinRating, err = parseCatalogDecimal(query.MinRating, "min_rating"); err != nil {
+		return filter, err
+	}
+	if filter.MinRating != nil && filter.MinRating.GreaterThan(decimal.NewFromInt(5)) {
+		return filter, invalidCatalogQuery("min_rating must be between 0 and 5")
+	}
+	return filter, nil
+}
+
+func parseCatalogDecimal(raw, name string) (*decimal.Decimal, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	value, err := decimal.NewFromString(raw)
+	if err != nil || value.IsNegative() {
+		return nil, invalidCatalogQuery(name + " must be a non-negative number")
+	}
+	return &value, nil
+}
+
+func invalidCatalogQuery(reason string) error {
+	return fmt.Errorf("%w: %s", ErrInvalidCatalogQuery, reason)
+}
+
+func encodeCatalogCursor(hit repository.CatalogHit, sort, mode string) string {
+	course := hit.Course
+	cursor := catalogCursor{Sort: sort, Mode: mode, ID: course.ID}
+	switch sort {
+	case repository.CatalogSortRelevance:
+		cursor.Key = strconv.FormatFloat(hit.Relevance, 'g', -1, 64)
+	case repository.CatalogSortRating:
+		cursor.Key = course.AverageRating.String()
+	case repository.CatalogSortNewest:
+		published := course.CreatedAt
+		if course.PublishedAt != nil {
+			published = *course.PublishedAt
+		}
+		cursor.Key = published.UTC().Format(time.RFC3339Nano)
+	case repository.CatalogSortPriceAsc, repository.CatalogSortPriceDesc:
+		cursor.Key = course.Price.String()
+	default:
+		cursor.Key = strconv.Itoa(course.TotalStudents)
+	}
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeCatalogCursor turns next_cursor back into the typed key the repository compares
+// with, and the search mode of the first page.
+func decodeCatalogCursor(encoded, sort string) (*repository.CatalogCursor, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, "", ErrInvalidCursor
+	}
+	var cursor catalogCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil || cursor.Sort != sort || cursor.ID == uuid.Nil {
+		return nil, "", ErrInvalidCursor
+	}
+	switch cursor.Mode {
+	case "", repository.SearchModeFullText, repository.SearchModeFuzzy:
+	default:
+		return nil, "", ErrInvalidCursor
+	}
+
+	after := &repository.CatalogCursor{ID: cursor.ID}
+	switch sort {
+	case repository.CatalogSortRelevance:
+		key, err := strconv.ParseFloat(cursor.Key, 64)
+		if err != nil {
+			return nil, "", ErrInvalidCursor
+		}
+		after.Key = key
+	case repository.CatalogSortRating, repository.CatalogSortPriceAsc, repository.CatalogSortPriceDesc:
+		key, err := decimal.NewFromString(cursor.Key)
+		if err != nil {
+			return nil, "", ErrInvalidCursor
+		}
+		after.Key = key
+	case repository.CatalogSortNewest:
+		key, err := time.Parse(time.RFC3339Nano, cursor.Key)
+		if err != nil {
+			return nil, "", ErrInvalidCursor
+		}
+		after.Key = key
+	default:
+		key, err := strconv.ParseInt(cursor.Key, 10, 64)
+		if err != nil {
+			return nil, "", ErrInvalidCursor
+		}
+		after.Key = key
+	}
+	return after, cursor.Mode, nil
+}
+
+func toCatalogCourse(c model.Course, now time.Time) dto.CatalogCourseDTO {
+	res := dto.CatalogCourseDTO{
+		ID:                c.ID,
+		Slug:              c.Slug,
+		Title:             c.Title,
+		ShortDescription:  c.ShortDescription,
+		ThumbnailURL:      c.ThumbnailURL,
+		Level:             c.Level,
+		Language:          c.Language,
+		Price:             c.Price,
+		IsFree:            c.IsFree,
+		AverageRating:     c.AverageRating,
+		TotalReviews:      c.TotalReviews,
+		TotalStudents:     c.TotalStudents,
+		TotalLessons:      c.TotalLessons,
+		TotalDurationMins: c.TotalDurationMins,
+		Instructor: dto.CatalogInstructorDTO{
+			ID:        c.Instructor.ID,
+			Name:      c.Instructor.UserName,
+			AvatarURL: c.Instructor.AvatarURL,
+		},
+		PublishedAt: formatTimePtr(c.PublishedAt),
+	}
+	if c.Instructor.FullName != nil && *c.Instructor.FullName != "" {
+		res.Instructor.Name = *c.Instructor.FullName
+	}
+	if c.DiscountPrice != nil && (c.DiscountExpiresAt == nil || c.DiscountExpiresAt.After(now)) {
+		res.DiscountPrice = c.DiscountPrice
+		res.DiscountExpiresAt = formatTimePtr(c.DiscountExpiresAt)
+	}
+	if c.Category != nil {
+		res.Category = &dto.CatalogCategoryDTO{ID: c.Category.ID, Name: c.Category.Name, Slug: c.Category.Slug}
+	}
+	return res
+}
+
+func toCatalogFacets(f *repository.CatalogFacets) *dto.CatalogFacetsDTO {
+	res := &dto.CatalogFacetsDTO{
+		Categories: make([]dto.CategoryFacetDTO, 0, len(f.Categories)),
+		Levels:     make([]dto.FacetCountDTO, 0, len(f.Levels)),
+		Languages:  make([]dto.FacetCountDTO, 0, len(f.Languages)),
+		Price:      dto.PriceFacetDTO{Free: f.Price.Free, Paid: f.Price.Paid},
+		Ratings:    make([]dto.RatingFacetDTO, 0, len(ratingFacetSteps)),
+	}
+	for _, c := range f.Categories {
+		res.Categories = append(res.Categories, dto.CategoryFacetDTO{ID: c.ID, Name: c.Name, Slug: c.Slug, Count: c.Count})
+	}
+	for _, l := range f.Levels {
+		res.Levels = append(res.Levels, dto.FacetCountDTO{Value: l.Value, Count: l.Count})
+	}
+	for _, l := range f.Languages {
+		res.Languages = append(res.Languages, dto.FacetCountDTO{Value: l.Value, Count: l.Count})
+	}
+	counts := []int64{f.Ratings.Min45, f.Ratings.Min40, f.Ratings.Min35, f.Ratings.Min30}
+	for i, step := range ratingFacetSteps {
+		res.Ratings = append(res.Ratings, dto.RatingFacetDTO{MinRating: step, Count: counts[i]})
+	}
+	return res
+}
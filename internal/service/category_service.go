@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"study.com/v1/internal/dto"
+	"study.com/v1/internal/model"
+	"study.com/v1/internal/repository"
+)
+
+var (
+	ErrCategoryNotFound      = errors.New("category not found")
+	ErrCategorySlugExists    = errors.New("category slug already exists")
+	ErrInvalidCategoryName   = errors.New("category name must be between 2 and 100 characters")
+	ErrInvalidCategoryParent = errors.New("parent category must belong to the same organization and cannot be the category itself or one of its children")
+)
+
+type CategoryServiceInterface interface {
+	ListCategories(ctx context.Context, actorID, orgID uuid.UUID) ([]dto.CategoryResponseDTO, error)
+	CreateCategory(ctx context.Context, actorID, orgID uuid.UUID, req dto.CreateCategoryDTO) (*dto.CategoryResponseDTO, error)
+	UpdateCategory(ctx context.Context, actorID, orgID, categoryID uuid.UUID, req dto.UpdateCategoryDTO) (*dto.CategoryResponseDTO, error)
+	DeleteCategory(ctx context.Context, actorID, orgID, categoryID uuid.UUID) error
+}
+
+// CategoryService manages the categories private to an organization. Platform-wide
+// categories (organization_id NULL) are not editable through it.
+type CategoryService struct {
+	orgRepo      repository.OrganizationRepositoryInterface
+	categoryRepo repository.CategoryRepositoryInterface
+	authz        AuthorizationServiceInterface
+}
+
+func NewCategoryService(
+	orgRepo repository.OrganizationRepositoryInterface,
+	categoryRepo repository.CategoryRepositoryInterface,
+	authz AuthorizationServiceInterface,
+) *CategoryService {
+	return &CategoryService{
+		orgRepo:      orgRepo,
+		categoryRepo: categoryRepo,
+		authz:        authz,
+	}
+}
+
+// ListCategories is open to every member of the organization.
+func (s *CategoryService) ListCategories(ctx context.Context, actorID, orgID uuid.UUID) ([]dto.CategoryResponseDTO, error) {
+	org, err := findVisibleOrganization(ctx, s.orgRepo, s.authz, actorID, orgID)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := s.categoryRepo.List(ctx, &org.ID)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]dto.CategoryResponseDTO, 0, len(categories))
+	for _, c := range categories {
+		res = append(res, toCategoryResponse(c))
+	}
+	return res, nil
+}
+
+func (s *CategoryService) CreateCategory(ctx context.Context, actorID, orgID uuid.UUID, req dto.CreateCategoryDTO) (*dto.CategoryResponseDTO, error) {
+	org, err := authorizeInOrganization(ctx, s.orgRepo, s.authz, actorID, orgID, model.PermissionOrgCategoriesManage)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if len(name) < 2 || len(name) > 100 {
+		return nil, ErrInvalidCategoryName
+	}
+	slug, err := normalizeSlug(req.Slug, name)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureSlugFree(ctx, org.ID, slug, uuid.Nil); err != nil {
+		return nil, err
+	}
+	if req.ParentID != nil {
+		if err := s.ensureValidParent(ctx, org.ID, uuid.Nil, *req.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	category := &model.Category{
+		OrganizationID: &org.ID,
+		ParentID:       req.ParentID,
+		Name:           name,
+		Slug:           slug,
+		Description:    emptyToNil(req.Description),
+		IconURL:        emptyToNil(req.IconURL),
+		DisplayOrder:   req.DisplayOrder,
+		IsActive:       true,
+	}
+	if err := s.categoryRepo.Create(ctx, category); err != nil {
+		return nil, err
+	}
+
+	res := toCategoryResponse(*category)
+	return &res, nil
+}
+
+func (s *CategoryService) UpdateCategory(ctx context.Context, actorID, orgID, categoryID uuid.UUID, req dto.UpdateCategoryDTO) (*dto.CategoryResponseDTO, error) {
+	org, err := authorizeInOrganization(ctx, s.orgRepo, s.authz, actorID, orgID, model.PermissionOrgCategoriesManage)
+	if err != nil {
+		return nil, err
+	}
+	category, err := s.categoryRepo.FindByID(ctx, &org.ID, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	if category == nil {
+		return nil, ErrCategoryNotFound
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if len(name) < 2 || len(name) > 100 {
+			return nil, ErrInvalidCategoryName
+		}
+		category.Name = name
+	}
+	if req.Slug != nil {
+		slug, err := normalizeSlug(*req.Slug, category.Name)
+		if err != nil {
+			return nil, err
+		}
+		if slug != category.Slug {
+			if err := s.ensureSlugFree(ctx, org.ID, slug, category.ID); err != nil {
+				return nil, err
+			}
+			category.Slug = slug
+		}
+	}
+	if req.ParentID != nil {
+		if *req.ParentID == uuid.Nil {
+			category.ParentID = nil
+		} else {
+			if err := s.ensureValidParent(ctx, org.ID, category.ID, *req.ParentID); err != nil {
+				return nil, err
+			}
+			category.ParentID = req.ParentID
+		}
+	}
+	if req.Description != nil {
+		category.Description = emptyToNil(req.Description)
+	}
+	if req.IconURL != nil {
+		category.IconURL = emptyToNil(req.IconURL)
+	}
+	if req.DisplayOrder != nil {
+		category.DisplayOrder = *req.DisplayOrder
+	}
+	if req.IsActive != nil {
+		category.IsActive = *req.IsActive
+	}
+
+	if err := s.categoryRepo.Update(ctx, category); err != nil {
+		return nil, err
+	}
+	res := toCategoryResponse(*category)
+	return &res, nil
+}
+
+func (s *CategoryService) DeleteCategory(ctx context.Context, actorID, orgID, categoryID uuid.UUID) error {
+	org, err := authorizeInOrganization(ctx, s.orgRepo, s.authz, actorID, orgID, model.PermissionOrgCategoriesManage)
+	if err != nil {
+		return err
+	}
+	deleted, err := s.categoryRepo.Delete(ctx, &org.ID, categoryID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrCategoryNotFound
+	}
+	return nil
+}
+
+func (s *CategoryService) ensureSlugFree(ctx context.Context, orgID uuid.UUID, slug string, selfID uuid.UUID) error {
+	exists, err := s.categoryRepo.SlugExists(ctx, &orgID, slug, selfID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrCategorySlugExists
+	}
+	return nil
+}
+
+// ensureValidParent walks up from parentID to make sure the parent lives in the same
+// organization and that selfID is not one of its ancestors.
+func (s *CategoryService) ensureValidParent(ctx context.Context, orgID, selfID, parentID uuid.UUID) error {
+	seen := make(map[uuid.UUID]struct{})
+	for id := &parentID; id != nil; {
+		if *id == selfID {
+			return ErrInvalidCategoryParent
+		}
+		if _, ok := seen[*id]; ok {
+			return ErrInvalidCategoryParent
+		}
+		seen[*id] = struct{}{}
+
+		parent, err := s.categoryRepo.FindByID(ctx, &orgID, *id)
+		if err != nil {
+			return err
+		}
+		if parent == nil {
+			return ErrInvalidCategoryParent
+		}
+		id = parent.ParentID
+	}
+	return nil
+}
+
+func toCategoryResponse(c model.Category) dto.CategoryResponseDTO {
+	return dto.CategoryResponseDTO{
+		ID:             c.ID,
+		OrganizationID: c.OrganizationID,
+		ParentID:       c.ParentID,
+		Name:           c.Name,
+		Slug:           c.Slug,
+		Description:    c.Description,
+		IconURL:        c.IconURL,
+		DisplayOrder:   c.DisplayOrder,
+		IsActive:       c.IsActive,
+	}
+}
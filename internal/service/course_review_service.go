@@ -0,0 +1,419 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"study.com/v1/internal/config"
+	"study.com/v1/internal/dto"
+	"study.com/v1/internal/model"
+	"study.com/v1/internal/repository"
+	"study.com/v1/internal/utils"
+)
+
+var (
+	ErrCourseIncomplete       = errors.New("the course is not ready for review")
+	ErrInvalidCourseStatus    = errors.New("the course cannot make this transition from its current status")
+	ErrReviewCommentRequired  = errors.New("a comment is required when rejecting a course")
+	ErrCourseReviewForbidden  = errors.New("you are not allowed to review this course")
+	ErrCourseStatusConflict   = errors.New("the course status changed in the meantime, reload and try again")
+	ErrReviewQueueForbidden   = errors.New("you are not allowed to see this review queue")
+	errCourseTransitionNotSet = errors.New("unknown course transition")
+)
+
+// courseTransition describes one edge of the course workflow.
+type courseTransition struct {
+	from, to string
+	// title and content of the notification sent to the instructor, %s is the course title
+	title, content string
+}
+
+var courseTransitions = map[string]courseTransition{
+	model.CourseActionSubmit: {
+		from: model.CourseStatusDraft, to: model.CourseStatusPendingReview,
+		title: "Đã gửi duyệt khoá học", content: "Khoá học \"%s\" đã được gửi duyệt và đang chờ người duyệt xem xét.",
+	},
+	model.CourseActionWithdraw: {
+		from: model.CourseStatusPendingReview, to: model.CourseStatusDraft,
+		title: "Đã rút lại yêu cầu duyệt", content: "Khoá học \"%s\" đã được rút khỏi hàng chờ duyệt và trở về bản nháp.",
+	},
+	model.CourseActionApprove: {
+		from: model.CourseStatusPendingReview, to: model.CourseStatusPublished,
+		title: "Khoá học đã được xuất bản", content: "Khoá học \"%s\" đã được duyệt và đang hiển thị với học viên.",
+	},
+	model.CourseActionReject: {
+		from: model.CourseStatusPendingReview, to: model.CourseStatusDraft,
+		title: "Khoá học chưa được duyệt", content: "Khoá học \"%s\" chưa được duyệt, xem nhận xét của người duyệt để chỉnh sửa và gửi lại.",
+	},
+	model.CourseActionArchive: {
+		from: model.CourseStatusPublished, to: model.CourseStatusArchived,
+		title: "Khoá học đã được lưu trữ", content: "Khoá học \"%s\" đã ngừng hiển thị. Học viên đã ghi danh vẫn tiếp tục học được.",
+	},
+	model.CourseActionRestore: {
+		from: model.CourseStatusArchived, to: model.CourseStatusDraft,
+		title: "Khoá học đã được khôi phục", content: "Khoá học \"%s\" đã trở về bản nháp, hãy gửi duyệt lại để xuất bản.",
+	},
+}
+
+type CourseReviewServiceInterface interface {
+	SubmitForReview(ctx context.Context, actorID, courseID uuid.UUID, req dto.CourseTransitionDTO) (*dto.CourseResponseDTO, error)
+	WithdrawReview(ctx context.Context, actorID, courseID uuid.UUID, req dto.CourseTransitionDTO) (*dto.CourseResponseDTO, error)
+	ArchiveCourse(ctx context.Context, actorID, courseID uuid.UUID, req dto.CourseTransitionDTO) (*dto.CourseResponseDTO, error)
+	RestoreCourse(ctx context.Context, actorID, courseID uuid.UUID, req dto.CourseTransitionDTO) (*dto.CourseResponseDTO, error)
+	ListStatusHistory(ctx context.Context, actorID, courseID uuid.UUID) ([]dto.CourseStatusChangeDTO, error)
+
+	ListPendingReviews(ctx context.Context, actorID uuid.UUID, organizationID *uuid.UUID, page, pageSize int) (*dto.CourseListResponseDTO, error)
+	GetCourseForReview(ctx context.Context, reviewerID, courseID uuid.UUID) (*dto.CourseResponseDTO, error)
+	ApproveCourse(ctx context.Context, reviewerID, courseID uuid.UUID, req dto.CourseTransitionDTO) (*dto.CourseResponseDTO, error)
+	RejectCourse(ctx context.Context, reviewerID, courseID uuid.UUID, req dto.CourseTransitionDTO) (*dto.CourseResponseDTO, error)
+}
+
+// CourseReviewService runs the publishing workflow:
+//
+//	draft --submit--> pending_review --approve--> published --archive--> archived
+//	  ^                 |  withdraw, reject                                  |
+//	  +-----------------+-------------------------restore--------------------+
+//
+// Instructors submit, withdraw, archive and restore with COURSES_UPDATE_OWN. Personal courses
+// are reviewed by holders of COURSES_APPROVE_ALL, organization courses also by holders of
+// COURSES_APPROVE_OWN_ORG in that organization. Every transition is recorded and notified.
+type CourseReviewService struct {
+	cfg        *config.Config
+	courseRepo repository.CourseRepositoryInterface
+	orgRepo    repository.OrganizationRepositoryInterface
+	userRepo   repository.UserRepositoryInterface
+	authz      AuthorizationServiceInterface
+}
+
+func NewCourseReviewService(
+	cfg *config.Config,
+	courseRepo repository.CourseRepositoryInterface,
+	orgRepo repository.OrganizationRepositoryInterface,
+	userRepo repository.UserRepositoryInterface,
+	authz AuthorizationServiceInterface,
+) *CourseReviewService {
+	return &CourseReviewService{
+		cfg:        cfg,
+		courseRepo: courseRepo,
+		orgRepo:    orgRepo,
+		userRepo:   userRepo,
+		authz:      authz,
+	}
+}
+
+// SubmitForReview checks the course is complete enough to be looked at before queueing it.
+func (s *CourseReviewService) SubmitForReview(ctx context.Context, actorID, courseID uuid.UUID, req dto.CourseTransitionDTO) (*dto.CourseResponseDTO, error) {
+	course, err := s.instructorCourse(ctx, actorID, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if course.Status != model.CourseStatusDraft {
+		return nil, ErrInvalidCourseStatus
+	}
+	if err := s.ensureComplete(ctx, course); err != nil {
+		return nil, err
+	}
+	return s.transition(ctx, actorID, course, model.CourseActionSubmit, req.Comment)
+}
+
+func (s *CourseReviewService) WithdrawReview(ctx context.Context, actorID, courseID uuid.UUID, req dto.CourseTransitionDTO) (*dto.CourseResponseDTO, error) {
+	course, err := s.instructorCourse(ctx, actorID, courseID)
+	if err != nil {
+		return nil, err
+	}
+	return s.transition(ctx, actorID, course, model.CourseActionWithdraw, req.Comment)
+}
+
+// ArchiveCourse hides a published course from the catalog. Enrollments are not touched, so
+// students who already joined keep their access. Reviewers may archive as well as the instructor.
+func (s *CourseReviewService) ArchiveCourse(ctx context.Context, actorID, courseID uuid.UUID, req dto.CourseTransitionDTO) (*dto.CourseResponseDTO, error) {
+	course, err := s.findCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	allowed, err := s.authz.CanAccessResource(ctx, actorID, model.PermissionCoursesUpdateOwn, CourseResource(course))
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		if err := s.authorizeReviewer(ctx, actorID, course); err != nil {
+			return nil, err
+		}
+	}
+	return s.transition(ctx, actorID, course, model.CourseActionArchive, req.Comment)
+}
+
+// RestoreCourse brings an archived course back as a draft; it has to pass review again.
+func (s *CourseReviewService) RestoreCourse(ctx context.Context, actorID, courseID uuid.UUID, req dto.CourseTransitionDTO) (*dto.CourseResponseDTO, error) {
+	course, err := s.instructorCourse(ctx, actorID, courseID)
+	if err != nil {
+		return nil, err
+	}
+	return s.transition(ctx, actorID, course, model.CourseActionRestore, req.Comment)
+}
+
+// ListStatusHistory is visible to the instructor and to the course's reviewers.
+func (s *CourseReviewService) ListStatusHistory(ctx context.Context, actorID, courseID uuid.UUID) ([]dto.CourseStatusChangeDTO, error) {
+	course, err := s.findCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	allowed, err := s.authz.CanAccessResource(ctx, actorID, model.PermissionCoursesUpdateOwn, CourseResource(course))
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		if err := s.authorizeReviewer(ctx, actorID, course); err != nil {
+			return nil, err
+		}
+	}
+
+	changes, err := s.courseRepo.ListStatusChanges(ctx, course.ID)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]dto.CourseStatusChangeDTO, 0, len(changes))
+	for _, change := range changes {
+		items = append(items, toCourseStatusChangeResponse(change))
+	}
+	return items, nil
+}
+
+// ListPendingReviews lists the queue of one organization, or the queue of personal courses
+// when organizationID is nil.
+func (s *CourseReviewService) ListPendingReviews(ctx context.Context, actorID uuid.UUID, organizationID *uuid.UUID, page, pageSize int) (*dto.CourseListResponseDTO, error) {
+	if organizationID != nil {
+		_, err := authorizeInOrganization(ctx, s.orgRepo, s.authz, actorID, *organizationID,
+			model.PermissionCoursesApproveOwnOrg, model.PermissionCoursesApproveAll)
+		if err != nil {
+			if errors.Is(err, ErrOrganizationForbidden) {
+				return nil, ErrReviewQueueForbidden
+			}
+			return nil, err
+		}
+	} else {
+		allowed, err := s.authz.HasAllPermissions(ctx, actorID, model.PermissionCoursesApproveAll)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, ErrReviewQueueForbidden
+		}
+	}
+
+	page, pageSize = normalizePage(page, pageSize)
+	courses, total, err := s.courseRepo.ListPendingReview(ctx, organizationID, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]dto.CourseResponseDTO, 0, len(courses))
+	for _, c := range courses {
+		items = append(items, toCourseResponse(c))
+	}
+	return &dto.CourseListResponseDTO{
+		Courses:  items,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+func (s *CourseReviewService) GetCourseForReview(ctx context.Context, reviewerID, courseID uuid.UUID) (*dto.CourseResponseDTO, error) {
+	course, err := s.findCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeReviewer(ctx, reviewerID, course); err != nil {
+		return nil, err
+	}
+	return courseDetail(ctx, s.courseRepo, course.ID)
+}
+
+func (s *CourseReviewService) ApproveCourse(ctx context.Context, reviewerID, courseID uuid.UUID, req dto.CourseTransitionDTO) (*dto.CourseResponseDTO, error) {
+	return s.review(ctx, reviewerID, courseID, model.CourseActionApprove, req.Comment)
+}
+
+func (s *CourseReviewService) RejectCourse(ctx context.Context, reviewerID, courseID uuid.UUID, req dto.CourseTransitionDTO) (*dto.CourseResponseDTO, error) {
+	if strings.TrimSpace(req.Comment) == "" {
+		return nil, ErrReviewCommentRequired
+	}
+	return s.review(ctx, reviewerID, courseID, model.CourseActionReject, req.Comment)
+}
+
+func (s *CourseReviewService) review(ctx context.Context, reviewerID, courseID uuid.UUID, action, comment string) (*dto.CourseResponseDTO, error) {
+	course, err := s.findCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeReviewer(ctx, reviewerID, course); err != nil {
+		return nil, err
+	}
+	res, err := s.transition(ctx, reviewerID, course, action, comment)
+	if err != nil {
+		return nil, err
+	}
+
+	instructor, err := s.userRepo.FindUserByID(ctx, course.InstructorID)
+	if err != nil || instructor == nil {
+		log.Printf("Warning: failed to load instructor %s of course %s: %v", course.InstructorID, course.ID, err)
+		return res, nil
+	}
+	approved := action == model.CourseActionApprove
+	if err := utils.SendCourseReviewDecision(s.cfg, instructor.Email, course.Title, approved, strings.TrimSpace(comment)); err != nil {
+		log.Printf("Warning: failed to send course review decision to %s: %v", instructor.Email, err)
+	}
+	return res, nil
+}
+
+// transition applies the action if the course is in the status it starts from, writes the
+// history row and notifies the instructor in the same transaction.
+func (s *CourseReviewService) transition(ctx context.Context, actorID uuid.UUID, course *model.Course, action, comment string) (*dto.CourseResponseDTO, error) {
+	edge, ok := courseTransitions[action]
+	if !ok {
+		return nil, errCourseTransitionNotSet
+	}
+	if course.Status != edge.from {
+		return nil, ErrInvalidCourseStatus
+	}
+
+	now := time.Now()
+	course.Status = edge.to
+	if action == model.CourseActionApprove {
+		course.PublishedAt = &now
+	}
+	comment = strings.TrimSpace(comment)
+	change := &model.CourseStatusChange{
+		CourseID:   course.ID,
+		ActorID:    &actorID,
+		Action:     action,
+		FromStatus: edge.from,
+		ToStatus:   edge.to,
+		Comment:    emptyToNil(&comment),
+	}
+
+	content := fmt.Sprintf(edge.content, course.Title)
+	if comment != "" && actorID != course.InstructorID {
+		content += "\n" + comment
+	}
+	referenceType := ResourceTypeCourse
+	notification := &model.Notification{
+		UserID:           course.InstructorID,
+		Title:            edge.title,
+		Content:          content,
+		NotificationType: model.NotificationTypeCourseUpdate,
+		ReferenceType:    &referenceType,
+		ReferenceID:      &course.ID,
+	}
+
+	if err := s.courseRepo.Transition(ctx, course, edge.from, change, notification); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCourseStatusConflict
+		}
+		return nil, err
+	}
+	return courseDetail(ctx, s.courseRepo, course.ID)
+}
+
+// ensureComplete lists everything a course still lacks before it can be reviewed.
+func (s *CourseReviewService) ensureComplete(ctx context.Context, course *model.Course) error {
+	detail, err := s.courseRepo.FindDetail(ctx, course.ID)
+	if err != nil {
+		return err
+	}
+	if detail == nil {
+		return ErrCourseNotFound
+	}
+
+	var missing []string
+	if len(detail.Sections) == 0 {
+		missing = append(missing, "at least one section")
+	}
+	if detail.TotalLessons == 0 {
+		missing = append(missing, "at least one lesson")
+	}
+	if !detail.IsFree && !detail.Price.IsPositive() {
+		missing = append(missing, "a price, or marking the course as free")
+	}
+	if detail.ThumbnailURL == nil || strings.TrimSpace(*detail.ThumbnailURL) == "" {
+		missing = append(missing, "a thumbnail")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w, it still needs %s", ErrCourseIncomplete, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func (s *CourseReviewService) instructorCourse(ctx context.Context, actorID, courseID uuid.UUID) (*model.Course, error) {
+	course, err := s.findCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	allowed, err := s.authz.CanAccessResource(ctx, actorID, model.PermissionCoursesUpdateOwn, CourseResource(course))
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, ErrCourseForbidden
+	}
+	return course, nil
+}
+
+// authorizeReviewer checks the approve permissions. COURSES_APPROVE_OWN_ORG is held through
+// the organization (its owner, or an organization role), so it is checked against the
+// organization rather than the course. Instructors never review their own course unless
+// they hold COURSES_APPROVE_ALL.
+func (s *CourseReviewService) authorizeReviewer(ctx context.Context, actorID uuid.UUID, course *model.Course) error {
+	if course.OrganizationID != nil && actorID != course.InstructorID {
+		_, err := authorizeInOrganization(ctx, s.orgRepo, s.authz, actorID, *course.OrganizationID,
+			model.PermissionCoursesApproveOwnOrg, model.PermissionCoursesApproveAll)
+		if errors.Is(err, ErrOrganizationForbidden) || errors.Is(err, ErrOrganizationNotFound) {
+			return ErrCourseReviewForbidden
+		}
+		return err
+	}
+
+	allowed, err := s.authz.CanAccessResource(ctx, actorID, model.PermissionCoursesApproveAll, CourseResource(course))
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return ErrCourseReviewForbidden
+	}
+	return nil
+}
+
+func (s *CourseReviewService) findCourse(ctx context.Context, id uuid.UUID) (*model.Course, error) {
+	course, err := s.courseRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if course == nil {
+		return nil, ErrCourseNotFound
+	}
+	return course, nil
+}
+
+func toCourseStatusChangeResponse(change model.CourseStatusChange) dto.CourseStatusChangeDTO {
+	res := dto.CourseStatusChangeDTO{
+		ID:         change.ID,
+		Action:     change.Action,
+		FromStatus: change.FromStatus,
+		ToStatus:   change.ToStatus,
+		Comment:    change.Comment,
+		ActorID:    change.ActorID,
+		CreatedAt:  change.CreatedAt.Format(time.RFC3339),
+	}
+	if change.Actor != nil {
+		res.ActorName = change.Actor.UserName
+		if change.Actor.FullName != nil && *change.Actor.FullName != "" {
+			res.ActorName = *change.Actor.FullName
+		}
+	}
+	return res
+}
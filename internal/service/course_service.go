@@ -0,0 +1,796 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"study.com/v1/internal/dto"
+	"study.com/v1/internal/model"
+	"study.com/v1/internal/repository"
+	"study.com/v1/internal/utils"
+)
+
+const (
+	maxCourseSlugAttempts = 5
+	maxCourseTags         = 10
+)
+
+var (
+	ErrCourseNotFound         = errors.New("course not found")
+	ErrCourseForbidden        = errors.New("you are not allowed to manage this course")
+	ErrCourseSlugExists       = errors.New("course slug already exists")
+	ErrInvalidCourse          = errors.New("title must be 5-255 characters and short_description at most 500 characters")
+	ErrInvalidCourseLevel     = errors.New("level must be beginner, intermediate, advanced or all_levels")
+	ErrInvalidCoursePrice     = errors.New("price must not be negative and discount_price must be lower than price")
+	ErrSectionNotFound        = errors.New("section not found")
+	ErrLessonNotFound         = errors.New("lesson not found")
+	ErrInvalidSection         = errors.New("section title must be 1-255 characters")
+	ErrInvalidLesson          = errors.New("lesson title must be 1-255 characters and duration_minutes must not be negative")
+	ErrInvalidLessonType      = errors.New("content_type must be video, article, quiz or assignment")
+	ErrInvalidCourseOutline   = errors.New("the outline must list every section and every lesson of the course exactly once")
+	ErrInvalidCourseStatusArg = errors.New("status must be draft, pending_review, published or archived")
+	ErrCourseUnderReview      = errors.New("the course is under review, withdraw it before making changes")
+	ErrInvalidCourseTags      = errors.New("a course has at most 10 tags of 1-50 characters")
+)
+
+type CourseServiceInterface interface {
+	ListMyCourses(ctx context.Context, actorID uuid.UUID, query dto.CourseQueryDTO) (*dto.CourseListResponseDTO, error)
+	CreateCourse(ctx context.Context, actorID uuid.UUID, req dto.CreateCourseDTO) (*dto.CourseResponseDTO, error)
+	GetCourse(ctx context.Context, actorID, courseID uuid.UUID) (*dto.CourseResponseDTO, error)
+	UpdateCourse(ctx context.Context, actorID, courseID uuid.UUID, req dto.UpdateCourseDTO) (*dto.CourseResponseDTO, error)
+	DeleteCourse(ctx context.Context, actorID, courseID uuid.UUID) error
+
+	CreateSection(ctx context.Context, actorID, courseID uuid.UUID, req dto.CreateSectionDTO) (*dto.CourseResponseDTO, error)
+	UpdateSection(ctx context.Context, actorID, courseID, sectionID uuid.UUID, req dto.UpdateSectionDTO) (*dto.CourseResponseDTO, error)
+	DeleteSection(ctx context.Context, actorID, courseID, sectionID uuid.UUID) (*dto.CourseResponseDTO, error)
+
+	CreateLesson(ctx context.Context, actorID, courseID, sectionID uuid.UUID, req dto.CreateLessonDTO) (*dto.CourseResponseDTO, error)
+	UpdateLesson(ctx context.Context, actorID, courseID, lessonID uuid.UUID, req dto.UpdateLessonDTO) (*dto.CourseResponseDTO, error)
+	DeleteLesson(ctx context.Context, actorID, courseID, lessonID uuid.UUID) (*dto.CourseResponseDTO, error)
+
+	ReorderCourse(ctx context.Context, actorID, courseID uuid.UUID, req dto.ReorderCourseDTO) (*dto.CourseResponseDTO, error)
+}
+
+// CourseService is the authoring side of courses. Creating needs COURSES_CREATE (inside the
+// organization for organization courses); every change afterwards needs COURSES_UPDATE_OWN
+// on the course, which teachers only hold for the courses they instruct.
+type CourseService struct {
+	courseRepo   repository.CourseRepositoryInterface
+	orgRepo      repository.OrganizationRepositoryInterface
+	categoryRepo repository.CategoryRepositoryInterface
+	authz        AuthorizationServiceInterface
+}
+
+func NewCourseService(
+	courseRepo repository.CourseRepositoryInterface,
+	orgRepo repository.OrganizationRepositoryInterface,
+	categoryRepo repository.CategoryRepositoryInterface,
+	authz AuthorizationServiceInterface,
+) *CourseService {
+	return &CourseService{
+		courseRepo:   courseRepo,
+		orgRepo:      orgRepo,
+		categoryRepo: categoryRepo,
+		authz:        authz,
+	}
+}
+
+func (s *CourseService) ListMyCourses(ctx context.Context, actorID uuid.UUID, query dto.CourseQueryDTO) (*dto.CourseListResponseDTO, error) {
+	if query.Status != "" && !validCourseStatus(query.Status) {
+		return nil, ErrInvalidCourseStatusArg
+	}
+	page, pageSize := normalizePage(query.Page, query.PageSize)
+	filter := repository.CourseFilter{Status: query.Status, Search: strings.TrimSpace(query.Search)}
+
+	courses, total, err := s.courseRepo.ListByInstructor(ctx, actorID, filter, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]dto.CourseResponseDTO, 0, len(courses))
+	for _, c := range courses {
+		items = append(items, toCourseResponse(c))
+	}
+	return &dto.CourseListResponseDTO{
+		Courses:  items,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+func (s *CourseService) CreateCourse(ctx context.Context, actorID uuid.UUID, req dto.CreateCourseDTO) (*dto.CourseResponseDTO, error) {
+	// COURSES_CREATE may come from an organization role, so it is checked here rather than
+	// on the route: inside the organization for its courses, globally for personal ones.
+	if req.OrganizationID != nil {
+		if _, err := authorizeInOrganization(ctx, s.orgRepo, s.authz, actorID, *req.OrganizationID, model.PermissionCoursesCreate); err != nil {
+			return nil, err
+		}
+	} else {
+		allowed, err := s.authz.HasAllPermissions(ctx, actorID, model.PermissionCoursesCreate)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, ErrCourseForbidden
+		}
+	}
+	if err := s.ensureCategory(ctx, req.OrganizationID, req.CategoryID); err != nil {
+		return nil, err
+	}
+
+	level := req.Level
+	if level == "" {
+		level = model.CourseLevelBeginner
+	}
+	language := strings.TrimSpace(req.Language)
+	if language == "" {
+		language = "vi"
+	}
+	course := &model.Course{
+		InstructorID:      actorID,
+		OrganizationID:    req.OrganizationID,
+		CategoryID:        req.CategoryID,
+		Title:             strings.TrimSpace(req.Title),
+		ShortDescription:  emptyToNil(&req.ShortDescription),
+		Description:       emptyToNil(&req.Description),
+		ThumbnailURL:      emptyToNil(&req.ThumbnailURL),
+		PreviewVideoURL:   emptyToNil(&req.PreviewVideoURL),
+		Level:             level,
+		Language:          language,
+		Price:             req.Price,
+		DiscountPrice:     req.DiscountPrice,
+		DiscountExpiresAt: req.DiscountExpiresAt,
+		IsFree:            req.Price.IsZero(),
+		Requirements:      cleanStringList(req.Requirements),
+		Objectives:        cleanStringList(req.Objectives),
+		TargetAudience:    cleanStringList(req.TargetAudience),
+		Status:            model.CourseStatusDraft,
+	}
+	if err := validateCourse(course); err != nil {
+		return nil, err
+	}
+	tags, err := buildTags(req.Tags)
+	if err != nil {
+		return nil, err
+	}
+	course.Tags = tags
+
+	for i, sectionReq := range req.Sections {
+		section, err := newSection(sectionReq)
+		if err != nil {
+			return nil, err
+		}
+		section.DisplayOrder = i + 1
+		course.Sections = append(course.Sections, *section)
+	}
+
+	slug, err := s.uniqueSlug(ctx, req.Slug, course.Title, uuid.Nil)
+	if err != nil {
+		return nil, err
+	}
+	course.Slug = slug
+
+	if err := s.courseRepo.Create(ctx, course); err != nil {
+		return nil, err
+	}
+	return courseDetail(ctx, s.courseRepo, course.ID)
+}
+
+func (s *CourseService) GetCourse(ctx context.Context, actorID, courseID uuid.UUID) (*dto.CourseResponseDTO, error) {
+	if _, err := s.authorizeCourse(ctx, actorID, courseID, model.PermissionCoursesUpdateOwn); err != nil {
+		return nil, err
+	}
+	return courseDetail(ctx, s.courseRepo, courseID)
+}
+
+func (s *CourseService) UpdateCourse(ctx context.Context, actorID, courseID uuid.UUID, req dto.UpdateCourseDTO) (*dto.CourseResponseDTO, error) {
+	course, err := s.editableCourse(ctx, actorID, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.CategoryID != nil {
+		if err := s.ensureCategory(ctx, course.OrganizationID, req.CategoryID); err != nil {
+			return nil, err
+		}
+		course.CategoryID = req.CategoryID
+	}
+	if req.Title != nil {
+		course.Title = strings.TrimSpace(*req.Title)
+	}
+	if req.ShortDescription != nil {
+		course.ShortDescription = emptyToNil(req.ShortDescription)
+	}
+	if req.Description != nil {
+		course.Description = emptyToNil(req.Description)
+	}
+	if req.ThumbnailURL != nil {
+		course.ThumbnailURL = emptyToNil(req.ThumbnailURL)
+	}
+	if req.PreviewVideoURL != nil {
+		course.PreviewVideoURL = emptyToNil(req.PreviewVideoURL)
+	}
+	if req.Level != nil {
+		course.Level = *req.Level
+	}
+	if req.Language != nil && strings.TrimSpace(*req.Language) != "" {
+		course.Language = strings.TrimSpace(*req.Language)
+	}
+	if req.Price != nil {
+		course.Price = *req.Price
+		course.IsFree = req.Price.IsZero()
+	}
+	if req.DiscountPrice != nil {
+		course.DiscountPrice = req.DiscountPrice
+	}
+	if req.DiscountExpiresAt != nil {
+		course.DiscountExpiresAt = req.DiscountExpiresAt
+	}
+	if req.Requirements != nil {
+		course.Requirements = cleanStringList(*req.Requirements)
+	}
+	if req.Objectives != nil {
+		course.Objectives = cleanStringList(*req.Objectives)
+	}
+	if req.TargetAudience != nil {
+		course.TargetAudience = cleanStringList(*req.TargetAudience)
+	}
+	if err := validateCourse(course); err != nil {
+		return nil, err
+	}
+
+	if req.Slug != nil && *req.Slug != course.Slug {
+		slug, err := s.uniqueSlug(ctx, *req.Slug, course.Title, course.ID)
+		if err != nil {
+			return nil, err
+		}
+		course.Slug = slug
+	}
+
+	var tags []model.Tag
+	if req.Tags != nil {
+		if tags, err = buildTags(*req.Tags); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.courseRepo.Update(ctx, course); err != nil {
+		return nil, err
+	}
+	if req.Tags != nil {
+		if err := s.courseRepo.SetTags(ctx, course.ID, tags); err != nil {
+			return nil, err
+		}
+	}
+	return courseDetail(ctx, s.courseRepo, course.ID)
+}
+
+// DeleteCourse lets a teacher delete their personal courses. Organization courses belong to
+// the organization and need COURSES_DELETE_ORG there, which is checked against the
+// organization rather than the course.
+func (s *CourseService) DeleteCourse(ctx context.Context, actorID, courseID uuid.UUID) error {
+	course, err := s.findCourse(ctx, courseID)
+	if err != nil {
+		return err
+	}
+	if course.OrganizationID != nil {
+		if _, err := authorizeInOrganization(ctx, s.orgRepo, s.authz, actorID, *course.OrganizationID, model.PermissionCoursesDeleteOrg); err != nil {
+			if errors.Is(err, ErrOrganizationForbidden) || errors.Is(err, ErrOrganizationNotFound) {
+				return ErrCourseForbidden
+			}
+			return err
+		}
+		return s.courseRepo.Delete(ctx, course.ID)
+	}
+
+	allowed, err := s.authz.CanAccessResource(ctx, actorID, model.PermissionCoursesDeleteOwn, CourseResource(course))
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return ErrCourseForbidden
+	}
+	return s.courseRepo.Delete(ctx, course.ID)
+}
+
+func (s *CourseService) CreateSection(ctx context.Context, actorID, courseID uuid.UUID, req dto.CreateSectionDTO) (*dto.CourseResponseDTO, error) {
+	if _, err := s.editableCourse(ctx, actorID, courseID); err != nil {
+		return nil, err
+	}
+	section, err := newSection(req)
+	if err != nil {
+		return nil, err
+	}
+	section.CourseID = courseID
+	if err := s.courseRepo.CreateSection(ctx, section); err != nil {
+		return nil, err
+	}
+	return courseDetail(ctx, s.courseRepo, courseID)
+}
+
+func (s *CourseService) UpdateSection(ctx context.Context, actorID, courseID, sectionID uuid.UUID, req dto.UpdateSectionDTO) (*dto.CourseResponseDTO, error) {
+	if _, err := s.editableCourse(ctx, actorID, courseID); err != nil {
+		return nil, err
+	}
+	section, err := s.findSection(ctx, courseID, sectionID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Title != nil {
+		section.Title = strings.TrimSpace(*req.Title)
+	}
+	if req.Description != nil {
+		section.Description = emptyToNil(req.Description)
+	}
+	if section.Title == "" || len(section.Title) > 255 {
+		return nil, ErrInvalidSection
+	}
+	if err := s.courseRepo.UpdateSection(ctx, section); err != nil {
+		return nil, err
+	}
+	return courseDetail(ctx, s.courseRepo, courseID)
+}
+
+func (s *CourseService) DeleteSection(ctx context.Context, actorID, courseID, sectionID uuid.UUID) (*dto.CourseResponseDTO, error) {
+	if _, err := s.editableCourse(ctx, actorID, courseID); err != nil {
+		return nil, err
+	}
+	section, err := s.findSection(ctx, courseID, sectionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.courseRepo.DeleteSection(ctx, section); err != nil {
+		return nil, err
+	}
+	return courseDetail(ctx, s.courseRepo, courseID)
+}
+
+func (s *CourseService) CreateLesson(ctx context.Context, actorID, courseID, sectionID uuid.UUID, req dto.CreateLessonDTO) (*dto.CourseResponseDTO, error) {
+	if _, err := s.editableCourse(ctx, actorID, courseID); err != nil {
+		return nil, err
+	}
+	if _, err := s.findSection(ctx, courseID, sectionID); err != nil {
+		return nil, err
+	}
+	lesson, err := newLesson(req)
+	if err != nil {
+		return nil, err
+	}
+	lesson.SectionID = sectionID
+	if err := s.courseRepo.CreateLesson(ctx, courseID, lesson); err != nil {
+		return nil, err
+	}
+	return courseDetail(ctx, s.courseRepo, courseID)
+}
+
+func (s *CourseService) UpdateLesson(ctx context.Context, actorID, courseID, lessonID uuid.UUID, req dto.UpdateLessonDTO) (*dto.CourseResponseDTO, error) {
+	if _, err := s.editableCourse(ctx, actorID, courseID); err != nil {
+		return nil, err
+	}
+	lesson, err := s.findLesson(ctx, courseID, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Title != nil {
+		lesson.Title = strings.TrimSpace(*req.Title)
+	}
+	if req.Description != nil {
+		lesson.Description = emptyToNil(req.Description)
+	}
+	if req.ContentType != nil {
+		lesson.ContentType = *req.ContentType
+	}
+	if req.DurationMins != nil {
+		lesson.DurationMins = *req.DurationMins
+	}
+	if req.IsPreview != nil {
+		lesson.IsPreview = *req.IsPreview
+	}
+	if req.IsMandatory != nil {
+		lesson.IsMandatory = *req.IsMandatory
+	}
+	if err := validateLesson(lesson); err != nil {
+		return nil, err
+	}
+	if err := s.courseRepo.UpdateLesson(ctx, courseID, lesson); err != nil {
+		return nil, err
+	}
+	return courseDetail(ctx, s.courseRepo, courseID)
+}
+
+func (s *CourseService) DeleteLesson(ctx context.Context, actorID, courseID, lessonID uuid.UUID) (*dto.CourseResponseDTO, error) {
+	if _, err := s.editableCourse(ctx, actorID, courseID); err != nil {
+		return nil, err
+	}
+	lesson, err := s.findLesson(ctx, courseID, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.courseRepo.DeleteLesson(ctx, courseID, lesson); err != nil {
+		return nil, err
+	}
+	return courseDetail(ctx, s.courseRepo, courseID)
+}
+
+// ReorderCourse applies a full outline of the course. Sending the whole tree keeps the
+// display orders dense and means two editors cannot leave a half applied order behind.
+func (s *CourseService) ReorderCourse(ctx context.Context, actorID, courseID uuid.UUID, req dto.ReorderCourseDTO) (*dto.CourseResponseDTO, error) {
+	if _, err := s.editableCourse(ctx, actorID, courseID); err != nil {
+		return nil, err
+	}
+	course, err := s.courseRepo.FindDetail(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if course == nil {
+		return nil, ErrCourseNotFound
+	}
+
+	sections := make(map[uuid.UUID]struct{}, len(course.Sections))
+	lessons := make(map[uuid.UUID]struct{})
+	for _, section := range course.Sections {
+		sections[section.ID] = struct{}{}
+		for _, lesson := range section.Lessons {
+			lessons[lesson.ID] = struct{}{}
+		}
+	}
+	if len(req.Sections) != len(sections) {
+		return nil, ErrInvalidCourseOutline
+	}
+
+	outline := make([]repository.SectionOutline, 0, len(req.Sections))
+	seenSections := make(map[uuid.UUID]struct{}, len(req.Sections))
+	seenLessons := make(map[uuid.UUID]struct{}, len(lessons))
+	for _, section := range req.Sections {
+		if _, ok := sections[section.ID]; !ok {
+			return nil, ErrInvalidCourseOutline
+		}
+		if _, dup := seenSections[section.ID]; dup {
+			return nil, ErrInvalidCourseOutline
+		}
+		seenSections[section.ID] = struct{}{}
+		for _, lessonID := range section.LessonIDs {
+			if _, ok := lessons[lessonID]; !ok {
+				return nil, ErrInvalidCourseOutline
+			}
+			if _, dup := seenLessons[lessonID]; dup {
+				return nil, ErrInvalidCourseOutline
+			}
+			seenLessons[lessonID] = struct{}{}
+		}
+		outline = append(outline, repository.SectionOutline{SectionID: section.ID, LessonIDs: section.LessonIDs})
+	}
+	if len(seenLessons) != len(lessons) {
+		return nil, ErrInvalidCourseOutline
+	}
+
+	if err := s.courseRepo.Reorder(ctx, courseID, outline); err != nil {
+		return nil, err
+	}
+	return courseDetail(ctx, s.courseRepo, courseID)
+}
+
+// authorizeCourse loads the course and checks the permission against it, so that the
+// own_resource_only condition and organization roles are taken into account.
+func (s *CourseService) authorizeCourse(ctx context.Context, actorID, courseID uuid.UUID, permission string) (*model.Course, error) {
+	course, err := s.findCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	allowed, err := s.authz.CanAccessResource(ctx, actorID, permission, CourseResource(course))
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, ErrCourseForbidden
+	}
+	return course, nil
+}
+
+func (s *CourseService) editableCourse(ctx context.Context, actorID, courseID uuid.UUID) (*model.Course, error) {
+	return loadEditableCourse(ctx, s.courseRepo, s.authz, actorID, courseID)
+}
+
+// loadEditableCourse checks COURSES_UPDATE_OWN for changes to the content. A course under
+// review is frozen so that reviewers approve exactly what they looked at.
+func loadEditableCourse(ctx context.Context, courseRepo repository.CourseRepositoryInterface, authz AuthorizationServiceInterface, actorID, courseID uuid.UUID) (*model.Course, error) {
+	course, err := courseRepo.FindByID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if course == nil {
+		return nil, ErrCourseNotFound
+	}
+	allowed, err := authz.CanAccessResource(ctx, actorID, model.PermissionCoursesUpdateOwn, CourseResource(course))
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, ErrCourseForbidden
+	}
+	if course.Status == model.CourseStatusPendingReview {
+		return nil, ErrCourseUnderReview
+	}
+	return course, nil
+}
+
+func (s *CourseService) findCourse(ctx context.Context, id uuid.UUID) (*model.Course, error) {
+	course, err := s.courseRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if course == nil {
+		return nil, ErrCourseNotFound
+	}
+	return course, nil
+}
+
+func (s *CourseService) findSection(ctx context.Context, courseID, sectionID uuid.UUID) (*model.Section, error) {
+	section, err := s.courseRepo.FindSection(ctx, courseID, sectionID)
+	if err != nil {
+		return nil, err
+	}
+	if section == nil {
+		return nil, ErrSectionNotFound
+	}
+	return section, nil
+}
+
+func (s *CourseService) findLesson(ctx context.Context, courseID, lessonID uuid.UUID) (*model.Lesson, error) {
+	lesson, err := s.courseRepo.FindLesson(ctx, courseID, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	if lesson == nil {
+		return nil, ErrLessonNotFound
+	}
+	return lesson, nil
+}
+
+// ensureCategory accepts platform-wide categories and the categories of the course's own organization.
+func (s *CourseService) ensureCategory(ctx context.Context, orgID, categoryID *uuid.UUID) error {
+	if categoryID == nil {
+		return nil
+	}
+	category, err := s.categoryRepo.FindByID(ctx, nil, *categoryID)
+	if err != nil {
+		return err
+	}
+	if category == nil && orgID != nil {
+		category, err = s.categoryRepo.FindByID(ctx, orgID, *categoryID)
+		if err != nil {
+			return err
+		}
+	}
+	if category == nil || !category.IsActive {
+		return ErrCategoryNotFound
+	}
+	return nil
+}
+
+// uniqueSlug validates a requested slug, or derives one from the title and adds a short
+// random suffix until it is free.
+func (s *CourseService) uniqueSlug(ctx context.Context, requested, title string, courseID uuid.UUID) (string, error) {
+	slug, err := normalizeSlug(requested, title)
+	if err != nil {
+		return "", err
+	}
+	exists, err := s.courseRepo.SlugExists(ctx, slug, courseID)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return slug, nil
+	}
+	if strings.TrimSpace(requested) != "" {
+		return "", ErrCourseSlugExists
+	}
+
+	for i := 0; i < maxCourseSlugAttempts; i++ {
+		candidate := slug + "-" + strings.ReplaceAll(uuid.NewString(), "-", "")[:6]
+		exists, err := s.courseRepo.SlugExists(ctx, candidate, courseID)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return candidate, nil
+		}
+	}
+	return "", ErrCourseSlugExists
+}
+
+// courseDetail loads the course with its outline as the authoring and review screens show it.
+func courseDetail(ctx context.Context, courseRepo repository.CourseRepositoryInterface, courseID uuid.UUID) (*dto.CourseResponseDTO, error) {
+	course, err := courseRepo.FindDetail(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if course == nil {
+		return nil, ErrCourseNotFound
+	}
+	res := toCourseResponse(*course)
+	res.Sections = make([]dto.SectionResponseDTO, 0, len(course.Sections))
+	for _, section := range course.Sections {
+		res.Sections = append(res.Sections, toSectionResponse(section))
+	}
+	for _, tag := range course.Tags {
+		res.Tags = append(res.Tags, tag.Name)
+	}
+	return &res, nil
+}
+
+func newSection(req dto.CreateSectionDTO) (*model.Section, error) {
+	section := &model.Section{
+		Title:       strings.TrimSpace(req.Title),
+		Description: emptyToNil(&req.Description),
+	}
+	if section.Title == "" || len(section.Title) > 255 {
+		return nil, ErrInvalidSection
+	}
+	for i, lessonReq := range req.Lessons {
+		lesson, err := newLesson(lessonReq)
+		if err != nil {
+			return nil, err
+		}
+		lesson.DisplayOrder = i + 1
+		section.Lessons = append(section.Lessons, *lesson)
+	}
+	return section, nil
+}
+
+func newLesson(req dto.CreateLessonDTO) (*model.Lesson, error) {
+	lesson := &model.Lesson{
+		Title:        strings.TrimSpace(req.Title),
+		Description:  emptyToNil(&req.Description),
+		ContentType:  req.ContentType,
+		DurationMins: req.DurationMins,
+		IsPreview:    req.IsPreview,
+		IsMandatory:  true,
+	}
+	if req.IsMandatory != nil {
+		lesson.IsMandatory = *req.IsMandatory
+	}
+	if err := validateLesson(lesson); err != nil {
+		return nil, err
+	}
+	return lesson, nil
+}
+
+func validateCourse(course *model.Course) error {
+	if len(course.Title) < 5 || len(course.Title) > 255 {
+		return ErrInvalidCourse
+	}
+	if course.ShortDescription != nil && len(*course.ShortDescription) > 500 {
+		return ErrInvalidCourse
+	}
+	switch course.Level {
+	case model.CourseLevelBeginner, model.CourseLevelIntermediate, model.CourseLevelAdvanced, model.CourseLevelAllLevels:
+	default:
+		return ErrInvalidCourseLevel
+	}
+	if course.Price.IsNegative() {
+		return ErrInvalidCoursePrice
+	}
+	if course.DiscountPrice != nil && (course.DiscountPrice.IsNegative() || course.DiscountPrice.GreaterThanOrEqual(course.Price)) {
+		return ErrInvalidCoursePrice
+	}
+	return nil
+}
+
+func validateLesson(lesson *model.Lesson) error {
+	if lesson.Title == "" || len(lesson.Title) > 255 || lesson.DurationMins < 0 {
+		return ErrInvalidLesson
+	}
+	switch lesson.ContentType {
+	case model.LessonContentVideo, model.LessonContentArticle, model.LessonContentQuiz, model.LessonContentAssignment:
+		return nil
+	}
+	return ErrInvalidLessonType
+}
+
+func validCourseStatus(status string) bool {
+	switch status {
+	case model.CourseStatusDraft, model.CourseStatusPendingReview, model.CourseStatusPublished, model.CourseStatusArchived:
+		return true
+	}
+	return false
+}
+
+// buildTags turns the free text tags of a course into tags keyed by slug, so "Golang" and
+// "golang" end up as the same tag.
+func buildTags(names []string) ([]model.Tag, error) {
+	if len(names) > maxCourseTags {
+		return nil, ErrInvalidCourseTags
+	}
+	tags := make([]model.Tag, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		slug := utils.Slugify(name)
+		if name == "" || len(name) > 50 || slug == "" || len(slug) > 50 {
+			return nil, ErrInvalidCourseTags
+		}
+		if seen[slug] {
+			continue
+		}
+		seen[slug] = true
+		tags = append(tags, model.Tag{Name: name, Slug: slug})
+	}
+	return tags, nil
+}
+
+// cleanStringList trims the entries of a free text list and drops empty ones.
+func cleanStringList(values []string) pq.StringArray {
+	cleaned := make(pq.StringArray, 0, len(values))
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			cleaned = append(cleaned, v)
+		}
+	}
+	return cleaned
+}
+
+func toCourseResponse(c model.Course) dto.CourseResponseDTO {
+	return dto.CourseResponseDTO{
+		ID:                c.ID,
+		InstructorID:      c.InstructorID,
+		OrganizationID:    c.OrganizationID,
+		CategoryID:        c.CategoryID,
+		Title:             c.Title,
+		Slug:              c.Slug,
+		ShortDescription:  c.ShortDescription,
+		Description:       c.Description,
+		ThumbnailURL:      c.ThumbnailURL,
+		PreviewVideoURL:   c.PreviewVideoURL,
+		Level:             c.Level,
+		Language:          c.Language,
+		Price:             c.Price,
+		DiscountPrice:     c.DiscountPrice,
+		DiscountExpiresAt: formatTimePtr(c.DiscountExpiresAt),
+		IsFree:            c.IsFree,
+		TotalLessons:      c.TotalLessons,
+		TotalDurationMins: c.TotalDurationMins,
+		Requirements:      nonNilStrings(c.Requirements),
+		Objectives:        nonNilStrings(c.Objectives),
+		TargetAudience:    nonNilStrings(c.TargetAudience),
+		Status:            c.Status,
+		PublishedAt:       formatTimePtr(c.PublishedAt),
+		CreatedAt:         c.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:         c.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func toSectionResponse(section model.Section) dto.SectionResponseDTO {
+	lessons := make([]dto.LessonResponseDTO, 0, len(section.Lessons))
+	for _, l := range section.Lessons {
+		lessons = append(lessons, dto.LessonResponseDTO{
+			ID:           l.ID,
+			SectionID:    l.SectionID,
+			Title:        l.Title,
+			Description:  l.Description,
+			ContentType:  l.ContentType,
+			DisplayOrder: l.DisplayOrder,
+			DurationMins: l.DurationMins,
+			IsPreview:    l.IsPreview,
+			IsMandatory:  l.IsMandatory,
+		})
+	}
+	return dto.SectionResponseDTO{
+		ID:           section.ID,
+		Title:        section.Title,
+		Description:  section.Description,
+		DisplayOrder: section.DisplayOrder,
+		Lessons:      lessons,
+	}
+}
+
+func nonNilStrings(values pq.StringArray) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
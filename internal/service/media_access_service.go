@@ -0,0 +1,413 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
+	"study.com/v1/internal/config"
+	"study.com/v1/internal/dto"
+	"study.com/v1/internal/model"
+	"study.com/v1/internal/repository"
+	"study.com/v1/internal/utils"
+)
+
+const (
+	// A playback stays valid for the length of the video plus this margin, for pauses
+	playbackURLMargin      = 2 * time.Hour
+	maxPlaybackURLTTL      = 12 * time.Hour
+	minSegmentURLTTL       = 5 * time.Minute
+	attachmentDownloadTTL  = 5 * time.Minute
+	maxPlaylistSize        = 4 << 20
+	playbackSessionIDChars = 10
+	defaultTranscriptHits  = 20
+	maxTranscriptHits      = 50
+)
+
+// renditionPattern matches the rendition directories written by media.TranscodeHLS.
+var renditionPattern = regexp.MustCompile(`^[0-9]+p$`)
+
+var (
+	ErrMediaForbidden     = errors.New("enroll in the course to access this lesson")
+	ErrMediaNotReady      = errors.New("this lesson has no video yet")
+	ErrAttachmentNotFound = errors.New("attachment not found")
+	ErrInvalidMediaToken  = errors.New("the playback link is invalid or has expired")
+	ErrPlaylistNotFound   = errors.New("playlist not found")
+	// ErrInvalidTranscriptQuery is wrapped with the reason by SearchTranscripts
+	ErrInvalidTranscriptQuery = errors.New("invalid transcript search")
+)
+
+type MediaAccessServiceInterface interface {
+	GetPlayback(ctx context.Context, actorID, lessonID uuid.UUID) (*dto.PlaybackDTO, error)
+	GetPlaylist(ctx context.Context, token, rendition string) (string, error)
+	DownloadAttachment(ctx context.Context, actorID, lessonID, attachmentID uuid.UUID) (*dto.AttachmentDownloadDTO, error)
+	GetTranscript(ctx context.Context, actorID, lessonID uuid.UUID) (*dto.TranscriptDTO, error)
+	SearchTranscripts(ctx context.Context, actorID, courseID uuid.UUID, query dto.TranscriptSearchQueryDTO) (*dto.TranscriptSearchDTO, error)
+}
+
+// MediaAccessService hands out lesson videos, their transcripts and attachments to students
+// who may see them:
+// enrolled and not expired, any signed in user for preview lessons, and the course's own
+// instructors. Everything it returns is a URL that expires.
+type MediaAccessService struct {
+	cfg            *config.Config
+	courseRepo     repository.CourseRepositoryInterface
+	enrollmentRepo repository.EnrollmentRepositoryInterface
+	videoRepo      repository.VideoRepositoryInterface
+	attachmentRepo repository.AttachmentRepositoryInterface
+	userRepo       repository.UserRepositoryInterface
+	authz          AuthorizationServiceInterface
+	minio          *minio.Client
+	presigner      *minio.Client
+}
+
+func NewMediaAccessService(
+	cfg *config.Config,
+	courseRepo repository.CourseRepositoryInterface,
+	enrollmentRepo repository.EnrollmentRepositoryInterface,
+	videoRepo repository.VideoRepositoryInterface,
+	attachmentRepo repository.AttachmentRepositoryInterface,
+	userRepo repository.UserRepositoryInterface,
+	authz AuthorizationServiceInterface,
+	minioClient *minio.Client,
+	presigner *minio.Client,
+) *MediaAccessService {
+	return &MediaAccessService{
+		cfg:            cfg,
+		courseRepo:     courseRepo,
+		enrollmentRepo: enrollmentRepo,
+		videoRepo:      videoRepo,
+		attachmentRepo: attachmentRepo,
+		userRepo:       userRepo,
+		authz:          authz,
+		minio:          minioClient,
+		presigner:      presigner,
+	}
+}
+
+// GetPlayback returns where to stream the video of a lesson from. A transcoded video is
+// served as HLS through playlists signed with a media token; until then the source file
+// is presigned as is.
+func (s *MediaAccessService) GetPlayback(ctx context.Context, actorID, lessonID uuid.UUID) (*dto.PlaybackDTO, error) {
+	if s.minio == nil || s.presigner == nil {
+		return nil, ErrStorageUnavailable
+	}
+	if err := s.authorizeLesson(ctx, actorID, lessonID); err != nil {
+		return nil, err
+	}
+	video, err := s.videoRepo.FindByLesson(ctx, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	if video == nil {
+		return nil, ErrMediaNotReady
+	}
+	user, err := s.userRepo.FindUserByID(ctx, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	ttl := min(playbackURLMargin+time.Duration(video.DurationSeconds)*time.Second, maxPlaybackURLTTL)
+	sessionID := utils.GenerateShortCode(playbackSessionIDChars)
+	issuedAt := time.Now()
+	res := &dto.PlaybackDTO{
+		LessonID:        lessonID,
+		DurationSeconds: video.DurationSeconds,
+		Resolution:      video.Resolution,
+		ThumbnailURL:    video.ThumbnailURL,
+		Watermark: dto.WatermarkDTO{
+			Text:      fmt.Sprintf("%s · %s", user.Email, sessionID),
+			UserID:    user.ID,
+			SessionID: sessionID,
+			IssuedAt:  issuedAt.Format(time.RFC3339),
+		},
+		Captions: []dto.CaptionTrackDTO{},
+	}
+	if video.TranscriptionStatus == model.TranscriptionStatusCompleted {
+		captions, err := s.videoRepo.ListCaptions(ctx, video.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, caption := range captions {
+			u, err := s.presigner.PresignedGetObject(ctx, s.cfg.MinioBucketVideos, caption.ObjectKey, ttl, nil)
+			if err != nil {
+				return nil, err
+			}
+			res.Captions = append(res.Captions, dto.CaptionTrackDTO{Language: caption.Language, URL: u.String()})
+		}
+	}
+
+	if video.VideoHlsURL != nil {
+		token, expiresAt, err := utils.SignMediaToken(s.cfg, actorID, lessonID, sessionID, ttl)
+		if err != nil {
+			return nil, err
+		}
+		res.Type = dto.PlaybackTypeHLS
+		res.Token = token
+		res.ExpiresAt = expiresAt.Format(time.RFC3339)
+		return res, nil
+	}
+
+	res.Type = dto.PlaybackTypeFile
+	res.ExpiresAt = issuedAt.Add(ttl).Format(time.RFC3339)
+	if !strings.HasPrefix(video.VideoURL, "courses/") {
+		// Entered by hand as a link before uploads existed
+		res.URL = video.VideoURL
+		return res, nil
+	}
+	u, err := s.presigner.PresignedGetObject(ctx, s.cfg.MinioBucketVideos, video.VideoURL, ttl, nil)
+	if err != nil {
+		return nil, err
+	}
+	res.URL = u.String()
+	return res, nil
+}
+
+// GetPlaylist serves the master playlist (rendition "") or the playlist of one rendition.
+// The master refers to the renditions by relative paths, which resolve back to this
+// endpoint under the same token. Rendition playlists are rewritten so every segment is a
+// presigned URL: segments are downloaded from MinIO directly, not through the API.
+func (s *MediaAccessService) GetPlaylist(ctx context.Context, token, rendition string) (string, error) {
+	if s.minio == nil || s.presigner == nil {
+		return "", ErrStorageUnavailable
+	}
+	claims, err := utils.ParseMediaToken(s.cfg, token)
+	if err != nil {
+		return "", ErrInvalidMediaToken
+	}
+	if rendition != "" && !renditionPattern.MatchString(rendition) {
+		return "", ErrPlaylistNotFound
+	}
+	video, err := s.videoRepo.FindByLesson(ctx, claims.LessonID)
+	if err != nil {
+		return "", err
+	}
+	if video == nil || video.VideoHlsURL == nil {
+		return "", ErrPlaylistNotFound
+	}
+
+	if rendition == "" {
+		return s.readPlaylist(ctx, *video.VideoHlsURL)
+	}
+	dir := path.Join(path.Dir(*video.VideoHlsURL), rendition)
+	playlist, err := s.readPlaylist(ctx, dir+"/index.m3u8")
+	if err != nil {
+		return "", err
+	}
+
+	ttl := max(time.Until(claims.ExpiresAt.Time), minSegmentURLTTL)
+	lines := strings.Split(playlist, "\n")
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		u, err := s.presigner.PresignedGetObject(ctx, s.cfg.MinioBucketVideos, dir+"/"+line, ttl, nil)
+		if err != nil {
+			return "", err
+		}
+		lines[i] = u.String()
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// DownloadAttachment returns a short-lived link that downloads the file under its original
+// name, and counts the download.
+func (s *MediaAccessService) DownloadAttachment(ctx context.Context, actorID, lessonID, attachmentID uuid.UUID) (*dto.AttachmentDownloadDTO, error) {
+	if s.presigner == nil {
+		return nil, ErrStorageUnavailable
+	}
+	if err := s.authorizeLesson(ctx, actorID, lessonID); err != nil {
+		return nil, err
+	}
+	attachment, err := s.attachmentRepo.FindByID(ctx, lessonID, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+	if attachment == nil {
+		return nil, ErrAttachmentNotFound
+	}
+
+	res := &dto.AttachmentDownloadDTO{
+		ID:        attachment.ID,
+		FileName:  attachment.FileName,
+		URL:       attachment.FileURL,
+		ExpiresAt: time.Now().Add(attachmentDownloadTTL).Format(time.RFC3339),
+	}
+	if strings.HasPrefix(attachment.FileURL, "courses/") {
+		params := url.Values{}
+		params.Set("response-content-disposition", "attachment; filename*=UTF-8''"+url.PathEscape(attachment.FileName))
+		u, err := s.presigner.PresignedGetObject(ctx, s.cfg.MinioBucketDocuments, attachment.FileURL, attachmentDownloadTTL, params)
+		if err != nil {
+			return nil, err
+		}
+		res.URL = u.String()
+	}
+
+	if err := s.attachmentRepo.IncrementDownloadCount(ctx, attachment.ID); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// GetTranscript returns the time-coded transcript of a lesson video, for an interactive
+// transcript next to the player.
+func (s *MediaAccessService) GetTranscript(ctx context.Context, actorID, lessonID uuid.UUID) (*dto.TranscriptDTO, error) {
+	if err := s.authorizeLesson(ctx, actorID, lessonID); err != nil {
+		return nil, err
+	}
+	video, err := s.videoRepo.FindByLesson(ctx, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	if video == nil {
+		return nil, ErrMediaNotReady
+	}
+
+	res := &dto.TranscriptDTO{
+		LessonID: lessonID,
+		Status:   video.TranscriptionStatus,
+		Segments: []dto.TranscriptSegmentDTO{},
+	}
+	if video.TranscriptionStatus != model.TranscriptionStatusCompleted {
+		return res, nil
+	}
+	segments, err := s.videoRepo.ListTranscriptSegments(ctx, video.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, seg := range segments {
+		res.Segments = append(res.Segments, dto.TranscriptSegmentDTO{
+			StartSeconds: msToSeconds(seg.StartMs),
+			EndSeconds:   msToSeconds(seg.EndMs),
+			Text:         seg.Text,
+		})
+	}
+	return res, nil
+}
+
+// SearchTranscripts searches what is said in the videos of a course, for students enrolled
+// in it and its instructors. Words match with or without diacritics, as in the catalog.
+func (s *MediaAccessService) SearchTranscripts(ctx context.Context, actorID, courseID uuid.UUID, query dto.TranscriptSearchQueryDTO) (*dto.TranscriptSearchDTO, error) {
+	text, err := normalizeSearchText(query.Q)
+	if err != nil {
+		return nil, fmt.Errorf("%w: q must be at most %d characters", ErrInvalidTranscriptQuery, maxSearchLength)
+	}
+	if text == "" {
+		return nil, fmt.Errorf("%w: q is required", ErrInvalidTranscriptQuery)
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultTranscriptHits
+	}
+	limit = min(limit, maxTranscriptHits)
+
+	course, err := s.courseRepo.FindByID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if course == nil {
+		return nil, ErrCourseNotFound
+	}
+	if err := s.authorizeCourse(ctx, actorID, course); err != nil {
+		return nil, err
+	}
+
+	hits, err := s.videoRepo.SearchTranscripts(ctx, courseID, text, limit)
+	if err != nil {
+		return nil, err
+	}
+	res := &dto.TranscriptSearchDTO{Query: text, Hits: make([]dto.TranscriptSearchHitDTO, 0, len(hits))}
+	for _, hit := range hits {
+		res.Hits = append(res.Hits, dto.TranscriptSearchHitDTO{
+			LessonID:     hit.LessonID,
+			LessonTitle:  hit.LessonTitle,
+			StartSeconds: msToSeconds(hit.StartMs),
+			EndSeconds:   msToSeconds(hit.EndMs),
+			Text:         hit.Text,
+		})
+	}
+	return res, nil
+}
+
+func (s *MediaAccessService) authorizeLesson(ctx context.Context, actorID, lessonID uuid.UUID) error {
+	_, err := loadAccessibleLesson(ctx, s.courseRepo, s.enrollmentRepo, s.authz, actorID, lessonID)
+	return err
+}
+
+func (s *MediaAccessService) authorizeCourse(ctx context.Context, actorID uuid.UUID, course *model.Course) error {
+	return authorizeCourseAccess(ctx, s.enrollmentRepo, s.authz, actorID, course)
+}
+
+// loadAccessibleLesson returns the lesson, with its section and course, when the actor may
+// study it: preview lessons of published courses are open to everyone signed in, the rest
+// to whoever authorizeCourseAccess lets through.
+func loadAccessibleLesson(ctx context.Context, courseRepo repository.CourseRepositoryInterface, enrollmentRepo repository.EnrollmentRepositoryInterface, authz AuthorizationServiceInterface, actorID, lessonID uuid.UUID) (*model.Lesson, error) {
+	lesson, err := courseRepo.FindLessonWithCourse(ctx, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	if lesson == nil {
+		return nil, ErrLessonNotFound
+	}
+	course := &lesson.Section.Course
+	if lesson.IsPreview && course.Status == model.CourseStatusPublished {
+		return lesson, nil
+	}
+	if err := authorizeCourseAccess(ctx, enrollmentRepo, authz, actorID, course); err != nil {
+		return nil, err
+	}
+	return lesson, nil
+}
+
+// authorizeCourseAccess lets through students with an enrollment that has not expired, and
+// whoever may edit the course.
+func authorizeCourseAccess(ctx context.Context, enrollmentRepo repository.EnrollmentRepositoryInterface, authz AuthorizationServiceInterface, actorID uuid.UUID, course *model.Course) error {
+	enrolled, err := enrollmentRepo.HasActiveEnrollment(ctx, actorID, course.ID, time.Now())
+	if err != nil {
+		return err
+	}
+	if enrolled {
+		return nil
+	}
+
+	allowed, err := authz.CanAccessResource(ctx, actorID, model.PermissionCoursesUpdateOwn, CourseResource(course))
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return ErrMediaForbidden
+	}
+	return nil
+}
+
+func msToSeconds(ms int) float64 {
+	return float64(ms) / 1000
+}
+
+func (s *MediaAccessService) readPlaylist(ctx context.Context, key string) (string, error) {
+	object, err := s.minio.GetObject(ctx, s.cfg.MinioBucketVideos, key, minio.GetObjectOptions{})
+	if err != nil {
+		return "", err
+	}
+	defer object.Close()
+	body, err := io.ReadAll(io.LimitReader(object, maxPlaylistSize))
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return "", ErrPlaylistNotFound
+		}
+		return "", err
+	}
+	return string(body), nil
+}
@@ -0,0 +1,573 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
+	"gorm.io/gorm"
+	"study.com/v1/internal/config"
+	"study.com/v1/internal/dto"
+	"study.com/v1/internal/model"
+	"study.com/v1/internal/repository"
+	"study.com/v1/internal/storage"
+)
+
+const (
+	// Files above one part are uploaded in parts, S3 allows at most 10000 of them
+	uploadPartSize        = 64 << 20
+	singleUploadURLTTL    = 15 * time.Minute
+	multipartUploadURLTTL = 6 * time.Hour
+	expiredUploadBatch    = 100
+)
+
+// mediaUploadLimits is the largest file accepted per kind of upload.
+var mediaUploadLimits = map[string]int64{
+	model.MediaUploadKindVideo:        10 << 30, // 10 GB
+	model.MediaUploadKindThumbnail:    5 << 20,  // 5 MB
+	model.MediaUploadKindAttachment:   100 << 20,
+	model.MediaUploadKindArticleImage: 10 << 20,
+}
+
+// mediaType is an accepted content type: what http.DetectContentType must find in the
+// first bytes of the object, and the extension of its key.
+type mediaType struct {
+	sniffed string
+	ext     string
+}
+
+// mediaUploadTypes lists the content types a client may announce per kind of upload.
+// Office files are zip archives to the sniffer.
+var mediaUploadTypes = map[string]map[string]mediaType{
+	model.MediaUploadKindVideo: {
+		"video/mp4":  {sniffed: "video/mp4", ext: ".mp4"},
+		"video/webm": {sniffed: "video/webm", ext: ".webm"},
+	},
+	model.MediaUploadKindThumbnail: {
+		"image/jpeg": {sniffed: "image/jpeg", ext: ".jpg"},
+		"image/png":  {sniffed: "image/png", ext: ".png"},
+		"image/webp": {sniffed: "image/webp", ext: ".webp"},
+	},
+	model.MediaUploadKindAttachment: {
+		"application/pdf": {sniffed: "application/pdf", ext: ".pdf"},
+		"application/zip": {sniffed: "application/zip", ext: ".zip"},
+		"application/vnd.openxmlformats-officedocument.wordprocessingml.document":   {sniffed: "application/zip", ext: ".docx"},
+		"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet":         {sniffed: "application/zip", ext: ".xlsx"},
+		"application/vnd.openxmlformats-officedocument.presentationml.presentation": {sniffed: "application/zip", ext: ".pptx"},
+		"image/jpeg":    {sniffed: "image/jpeg", ext: ".jpg"},
+		"image/png":     {sniffed: "image/png", ext: ".png"},
+		"text/plain":    {sniffed: "text/plain", ext: ".txt"},
+		"text/csv":      {sniffed: "text/plain", ext: ".csv"},
+		"text/markdown": {sniffed: "text/plain", ext: ".md"},
+	},
+	model.MediaUploadKindArticleImage: {
+		"image/jpeg": {sniffed: "image/jpeg", ext: ".jpg"},
+		"image/png":  {sniffed: "image/png", ext: ".png"},
+		"image/webp": {sniffed: "image/webp", ext: ".webp"},
+		"image/gif":  {sniffed: "image/gif", ext: ".gif"},
+	},
+}
+
+var (
+	ErrMediaUploadNotFound   = errors.New("upload not found")
+	ErrInvalidMediaUpload    = errors.New("kind must be video, thumbnail, attachment or article_image, all but thumbnails need lesson_id, and file_name must be 1-255 characters")
+	ErrUnsupportedMediaType  = errors.New("this file type is not accepted")
+	ErrMediaUploadTooLarge   = errors.New("the file is too large")
+	ErrVideoLessonRequired   = errors.New("videos can only be uploaded to video lessons")
+	ErrMediaUploadNotPending = errors.New("the upload is already completed or aborted")
+	ErrMediaUploadExpired    = errors.New("the upload URLs have expired, start a new upload")
+	ErrMediaUploadIncomplete = errors.New("the file has not been fully uploaded yet")
+	ErrMediaUploadMismatch   = errors.New("the uploaded file does not match the announced size or type")
+)
+
+type MediaUploadServiceInterface interface {
+	CreateUpload(ctx context.Context, actorID, courseID uuid.UUID, req dto.CreateMediaUploadDTO) (*dto.MediaUploadSessionDTO, error)
+	CompleteUpload(ctx context.Context, actorID, courseID, uploadID uuid.UUID) (*dto.MediaUploadResponseDTO, error)
+	AbortUpload(ctx context.Context, actorID, courseID, uploadID uuid.UUID) error
+	CleanupExpired(ctx context.Context) (int, error)
+}
+
+// MediaUploadService lets instructors upload course media straight to MinIO. The API
+// only signs URLs and checks the result, so gigabyte videos never pass through Fiber.
+type MediaUploadService struct {
+	cfg        *config.Config
+	uploadRepo repository.MediaUploadRepositoryInterface
+	courseRepo repository.CourseRepositoryInterface
+	authz      AuthorizationServiceInterface
+	minio      *minio.Client
+	presigner  *minio.Client
+}
+
+func NewMediaUploadService(
+	cfg *config.Config,
+	uploadRepo repository.MediaUploadRepositoryInterface,
+	courseRepo repository.CourseRepositoryInterface,
+	authz AuthorizationServiceInterface,
+	minioClient *minio.Client,
+	presigner *minio.Client,
+) *MediaUploadService {
+	return &MediaUploadService{
+		cfg:        cfg,
+		uploadRepo: uploadRepo,
+		courseRepo: courseRepo,
+		authz:      authz,
+		minio:      minioClient,
+		presigner:  presigner,
+	}
+}
+
+// CreateUpload records what the client is about to upload and signs the URLs for it.
+// Files larger than one part get a multipart upload with a URL per part.
+func (s *MediaUploadService) CreateUpload(ctx context.Context, actorID, courseID uuid.UUID, req dto.CreateMediaUploadDTO) (*dto.MediaUploadSessionDTO, error) {
+	if s.minio == nil || s.presigner == nil {
+		return nil, ErrStorageUnavailable
+	}
+	course, err := loadEditableCourse(ctx, s.courseRepo, s.authz, actorID, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	types, ok := mediaUploadTypes[req.Kind]
+	if !ok {
+		return nil, ErrInvalidMediaUpload
+	}
+	fileName := filepath.Base(strings.TrimSpace(req.FileName))
+	if fileName == "" || fileName == "." || fileName == "/" || len(fileName) > 255 {
+		return nil, ErrInvalidMediaUpload
+	}
+	contentType := normalizeContentType(req.ContentType)
+	mediaType, ok := types[contentType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s accepts %s", ErrUnsupportedMediaType, req.Kind, strings.Join(acceptedContentTypes(req.Kind), ", "))
+	}
+	if limit := mediaUploadLimits[req.Kind]; req.SizeBytes <= 0 || req.SizeBytes > limit {
+		return nil, fmt.Errorf("%w: %s uploads must be 1-%d bytes", ErrMediaUploadTooLarge, req.Kind, limit)
+	}
+
+	upload := &model.MediaUpload{
+		ID:          uuid.New(),
+		CourseID:    course.ID,
+		UploaderID:  &actorID,
+		Kind:        req.Kind,
+		Status:      model.MediaUploadStatusPending,
+		FileName:    fileName,
+		ContentType: contentType,
+		SizeBytes:   req.SizeBytes,
+	}
+	switch req.Kind {
+	case model.MediaUploadKindVideo, model.MediaUploadKindAttachment, model.MediaUploadKindArticleImage:
+		if req.LessonID == nil {
+			return nil, ErrInvalidMediaUpload
+		}
+		lesson, err := s.courseRepo.FindLesson(ctx, course.ID, *req.LessonID)
+		if err != nil {
+			return nil, err
+		}
+		if lesson == nil {
+			return nil, ErrLessonNotFound
+		}
+		if req.Kind == model.MediaUploadKindVideo && lesson.ContentType != model.LessonContentVideo {
+			return nil, ErrVideoLessonRequired
+		}
+		if req.Kind == model.MediaUploadKindArticleImage && lesson.ContentType != model.LessonContentArticle {
+			return nil, ErrArticleLessonRequired
+		}
+		upload.LessonID = &lesson.ID
+	}
+	upload.Bucket, upload.ObjectKey = s.objectLocation(upload, mediaType.ext)
+
+	res := &dto.MediaUploadSessionDTO{ID: upload.ID, Kind: upload.Kind, Method: http.MethodPut}
+	if upload.SizeBytes > uploadPartSize {
+		err = s.presignMultipart(ctx, upload, res)
+	} else {
+		err = s.presignSingle(ctx, upload, res)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.uploadRepo.Create(ctx, upload); err != nil {
+		s.discardObject(upload)
+		return nil, err
+	}
+	res.ExpiresAt = upload.ExpiresAt.Format(time.RFC3339)
+	return res, nil
+}
+
+// CompleteUpload is called by the client once the file is in MinIO. The object is checked
+// against what was announced before it is attached to the lesson or the course; a file
+// that does not match is deleted.
+func (s *MediaUploadService) CompleteUpload(ctx context.Context, actorID, courseID, uploadID uuid.UUID) (*dto.MediaUploadResponseDTO, error) {
+	if s.minio == nil {
+		return nil, ErrStorageUnavailable
+	}
+	if _, err := loadEditableCourse(ctx, s.courseRepo, s.authz, actorID, courseID); err != nil {
+		return nil, err
+	}
+	upload, err := s.findPendingUpload(ctx, courseID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().After(upload.ExpiresAt) {
+		return nil, ErrMediaUploadExpired
+	}
+
+	if upload.MultipartUploadID != nil {
+		if err := s.completeMultipart(ctx, upload); err != nil {
+			return nil, s.failOnMismatch(ctx, upload, err)
+		}
+	}
+	if err := s.verifyObject(ctx, upload); err != nil {
+		return nil, s.failOnMismatch(ctx, upload, err)
+	}
+
+	res := toMediaUploadResponse(*upload)
+	switch upload.Kind {
+	case model.MediaUploadKindVideo:
+		video := &model.LessonVideo{
+			LessonID:            *upload.LessonID,
+			VideoURL:            upload.ObjectKey,
+			FileSizeBytes:       &upload.SizeBytes,
+			TranscriptionStatus: model.TranscriptionStatusPending,
+		}
+		jobs := []*model.Job{
+			newTranscodeJob(video.LessonID, upload.ObjectKey),
+			newTranscribeJob(video.LessonID, upload.ObjectKey),
+		}
+		replaced, err := s.uploadRepo.CompleteVideo(ctx, upload, video, jobs)
+		if err != nil {
+			return nil, completeUploadError(err)
+		}
+		if replaced != nil && replaced.VideoURL != upload.ObjectKey {
+			s.removeObject(s.cfg.MinioBucketVideos, replaced.VideoURL)
+			removeVideoOutputs(context.Background(), s.cfg, s.minio, replaced.VideoURL)
+		}
+		res.VideoID = &video.ID
+	case model.MediaUploadKindAttachment:
+		attachment := &model.LessonAttachment{
+			LessonID:      *upload.LessonID,
+			FileName:      upload.FileName,
+			FileURL:       upload.ObjectKey,
+			FileSizeBytes: &upload.SizeBytes,
+		}
+		if t, ok := mediaUploadTypes[upload.Kind][upload.ContentType]; ok {
+			fileType := strings.TrimPrefix(t.ext, ".")
+			attachment.FileType = &fileType
+		}
+		if err := s.uploadRepo.CompleteAttachment(ctx, upload, attachment); err != nil {
+			return nil, completeUploadError(err)
+		}
+		res.AttachmentID = &attachment.ID
+	case model.MediaUploadKindThumbnail:
+		thumbnailURL := storage.PublicObjectURL(s.cfg, upload.Bucket, upload.ObjectKey)
+		previous, err := s.uploadRepo.CompleteThumbnail(ctx, upload, thumbnailURL)
+		if err != nil {
+			return nil, completeUploadError(err)
+		}
+		prefix := storage.PublicObjectURL(s.cfg, upload.Bucket, "")
+		if previous != nil && strings.HasPrefix(*previous, prefix) && *previous != thumbnailURL {
+			s.removeObject(upload.Bucket, strings.TrimPrefix(*previous, prefix))
+		}
+		res.ThumbnailURL = &thumbnailURL
+	case model.MediaUploadKindArticleImage:
+		// Nothing to attach: the author inserts the URL in the Markdown of the article
+		if err := s.uploadRepo.SetStatus(ctx, upload.ID, model.MediaUploadStatusPending, model.MediaUploadStatusCompleted); err != nil {
+			return nil, completeUploadError(err)
+		}
+		imageURL := storage.PublicObjectURL(s.cfg, upload.Bucket, upload.ObjectKey)
+		res.ImageURL = &imageURL
+	}
+
+	res.Status = model.MediaUploadStatusCompleted
+	now := time.Now()
+	res.CompletedAt = formatTimePtr(&now)
+	return &res, nil
+}
+
+// AbortUpload gives up an upload that has not been completed and deletes what was sent.
+func (s *MediaUploadService) AbortUpload(ctx context.Context, actorID, courseID, uploadID uuid.UUID) error {
+	if _, err := loadEditableCourse(ctx, s.courseRepo, s.authz, actorID, courseID); err != nil {
+		return err
+	}
+	upload, err := s.findPendingUpload(ctx, courseID, uploadID)
+	if err != nil {
+		return err
+	}
+	if err := s.uploadRepo.SetStatus(ctx, upload.ID, model.MediaUploadStatusPending, model.MediaUploadStatusAborted); err != nil {
+		return completeUploadError(err)
+	}
+	s.discardObject(upload)
+	return nil
+}
+
+// CleanupExpired aborts the uploads whose URLs expired without a completion and frees
+// their storage, multipart parts included.
+func (s *MediaUploadService) CleanupExpired(ctx context.Context) (int, error) {
+	if s.minio == nil {
+		return 0, ErrStorageUnavailable
+	}
+	cleaned := 0
+	for {
+		uploads, err := s.uploadRepo.ListExpired(ctx, time.Now(), expiredUploadBatch)
+		if err != nil {
+			return cleaned, err
+		}
+		for i := range uploads {
+			err := s.uploadRepo.SetStatus(ctx, uploads[i].ID, model.MediaUploadStatusPending, model.MediaUploadStatusAborted)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			}
+			if err != nil {
+				return cleaned, err
+			}
+			s.discardObject(&uploads[i])
+			cleaned++
+		}
+		if len(uploads) < expiredUploadBatch {
+			return cleaned, nil
+		}
+	}
+}
+
+func (s *MediaUploadService) presignSingle(ctx context.Context, upload *model.MediaUpload, res *dto.MediaUploadSessionDTO) error {
+	upload.ExpiresAt = time.Now().Add(singleUploadURLTTL)
+	// Content-Type is part of the signature, MinIO rejects a PUT announcing another type
+	headers := http.Header{"Content-Type": []string{upload.ContentType}}
+	u, err := s.presigner.PresignHeader(ctx, http.MethodPut, upload.Bucket, upload.ObjectKey, singleUploadURLTTL, nil, headers)
+	if err != nil {
+		return err
+	}
+	res.URL = u.String()
+	res.Headers = map[string]string{"Content-Type": upload.ContentType}
+	return nil
+}
+
+func (s *MediaUploadService) presignMultipart(ctx context.Context, upload *model.MediaUpload, res *dto.MediaUploadSessionDTO) error {
+	core := minio.Core{Client: s.minio}
+	multipartID, err := core.NewMultipartUpload(ctx, upload.Bucket, upload.ObjectKey, minio.PutObjectOptions{
+		ContentType: upload.ContentType,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start multipart upload: %w", err)
+	}
+	upload.MultipartUploadID = &multipartID
+	upload.PartSize = uploadPartSize
+	upload.ExpiresAt = time.Now().Add(multipartUploadURLTTL)
+
+	count := partCount(upload)
+	res.PartSize = upload.PartSize
+	res.Parts = make([]dto.UploadPartDTO, 0, count)
+	for n := 1; n <= count; n++ {
+		params := url.Values{"partNumber": []string{strconv.Itoa(n)}, "uploadId": []string{multipartID}}
+		u, err := s.presigner.Presign(ctx, http.MethodPut, upload.Bucket, upload.ObjectKey, multipartUploadURLTTL, params)
+		if err != nil {
+			s.discardObject(upload)
+			return err
+		}
+		res.Parts = append(res.Parts, dto.UploadPartDTO{PartNumber: n, URL: u.String()})
+	}
+	return nil
+}
+
+// completeMultipart assembles the parts MinIO received. The part list comes from MinIO
+// rather than from the client, so a missing part is noticed here.
+func (s *MediaUploadService) completeMultipart(ctx context.Context, upload *model.MediaUpload) error {
+	core := minio.Core{Client: s.minio}
+	var parts []minio.CompletePart
+	var total int64
+	marker := 0
+	for {
+		result, err := core.ListObjectParts(ctx, upload.Bucket, upload.ObjectKey, *upload.MultipartUploadID, marker, 1000)
+		if err != nil {
+			return fmt.Errorf("failed to list uploaded parts: %w", err)
+		}
+		for _, p := range result.ObjectParts {
+			parts = append(parts, minio.CompletePart{PartNumber: p.PartNumber, ETag: p.ETag})
+			total += p.Size
+		}
+		if !result.IsTruncated {
+			break
+		}
+		marker = result.NextPartNumberMarker
+	}
+
+	expected := partCount(upload)
+	if len(parts) < expected {
+		return fmt.Errorf("%w: %d of %d parts received", ErrMediaUploadIncomplete, len(parts), expected)
+	}
+	if len(parts) > expected || total != upload.SizeBytes {
+		return fmt.Errorf("%w: received %d bytes in %d parts", ErrMediaUploadMismatch, total, len(parts))
+	}
+	for i, p := range parts {
+		if p.PartNumber != i+1 {
+			return fmt.Errorf("%w: part %d is missing", ErrMediaUploadIncomplete, i+1)
+		}
+	}
+
+	_, err := core.CompleteMultipartUpload(ctx, upload.Bucket, upload.ObjectKey, *upload.MultipartUploadID, parts, minio.PutObjectOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+	return nil
+}
+
+// verifyObject compares the stored object with the announced size and content type, and
+// sniffs its first bytes: the declared Content-Type alone is never trusted.
+func (s *MediaUploadService) verifyObject(ctx context.Context, upload *model.MediaUpload) error {
+	info, err := s.minio.StatObject(ctx, upload.Bucket, upload.ObjectKey, minio.StatObjectOptions{})
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return ErrMediaUploadIncomplete
+		}
+		return err
+	}
+	if info.Size != upload.SizeBytes {
+		return fmt.Errorf("%w: announced %d bytes, received %d", ErrMediaUploadMismatch, upload.SizeBytes, info.Size)
+	}
+	if normalizeContentType(info.ContentType) != upload.ContentType {
+		return fmt.Errorf("%w: announced %s, stored as %s", ErrMediaUploadMismatch, upload.ContentType, info.ContentType)
+	}
+
+	opts := minio.GetObjectOptions{}
+	if err := opts.SetRange(0, 511); err != nil {
+		return err
+	}
+	object, err := s.minio.GetObject(ctx, upload.Bucket, upload.ObjectKey, opts)
+	if err != nil {
+		return err
+	}
+	defer object.Close()
+	head := make([]byte, 512)
+	n, err := io.ReadFull(object, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	sniffed := normalizeContentType(http.DetectContentType(head[:n]))
+	if expected := mediaUploadTypes[upload.Kind][upload.ContentType]; sniffed != expected.sniffed {
+		return fmt.Errorf("%w: the content is %s, not %s", ErrMediaUploadMismatch, sniffed, upload.ContentType)
+	}
+	return nil
+}
+
+// failOnMismatch marks the upload failed and deletes the object when the file is not
+// what was announced; other errors leave the upload pending so the client can retry.
+func (s *MediaUploadService) failOnMismatch(ctx context.Context, upload *model.MediaUpload, err error) error {
+	if !errors.Is(err, ErrMediaUploadMismatch) {
+		return err
+	}
+	if statusErr := s.uploadRepo.SetStatus(ctx, upload.ID, model.MediaUploadStatusPending, model.MediaUploadStatusFailed); statusErr != nil {
+		if errors.Is(statusErr, gorm.ErrRecordNotFound) {
+			return ErrMediaUploadNotPending
+		}
+		return statusErr
+	}
+	s.discardObject(upload)
+	return err
+}
+
+func (s *MediaUploadService) findPendingUpload(ctx context.Context, courseID, uploadID uuid.UUID) (*model.MediaUpload, error) {
+	upload, err := s.uploadRepo.FindByID(ctx, courseID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	if upload == nil {
+		return nil, ErrMediaUploadNotFound
+	}
+	if upload.Status != model.MediaUploadStatusPending {
+		return nil, ErrMediaUploadNotPending
+	}
+	return upload, nil
+}
+
+// objectLocation keeps every object of a course under courses/<id>/, thumbnails and
+// article images in the public images bucket and the rest in private buckets.
+func (s *MediaUploadService) objectLocation(upload *model.MediaUpload, ext string) (string, string) {
+	switch upload.Kind {
+	case model.MediaUploadKindVideo:
+		return s.cfg.MinioBucketVideos, fmt.Sprintf("courses/%s/lessons/%s/video-%s%s", upload.CourseID, *upload.LessonID, upload.ID, ext)
+	case model.MediaUploadKindAttachment:
+		return s.cfg.MinioBucketDocuments, fmt.Sprintf("courses/%s/lessons/%s/attachments/%s%s", upload.CourseID, *upload.LessonID, upload.ID, ext)
+	case model.MediaUploadKindArticleImage:
+		return s.cfg.MinioBucketImages, fmt.Sprintf("courses/%s/lessons/%s/images/%s%s", upload.CourseID, *upload.LessonID, upload.ID, ext)
+	default:
+		return s.cfg.MinioBucketImages, fmt.Sprintf("courses/%s/thumbnail-%s%s", upload.CourseID, upload.ID, ext)
+	}
+}
+
+// discardObject frees the storage of an upload that will not be used. Failures are only
+// logged, the object is orphaned but harmless.
+func (s *MediaUploadService) discardObject(upload *model.MediaUpload) {
+	if upload.MultipartUploadID != nil {
+		core := minio.Core{Client: s.minio}
+		err := core.AbortMultipartUpload(context.Background(), upload.Bucket, upload.ObjectKey, *upload.MultipartUploadID)
+		if err != nil && minio.ToErrorResponse(err).Code != "NoSuchUpload" {
+			log.Printf("Warning: failed to abort multipart upload of %s: %v", upload.ObjectKey, err)
+		}
+	}
+	s.removeObject(upload.Bucket, upload.ObjectKey)
+}
+
+// removeObject deletes an object this service created, objects outside courses/ (such as
+// URLs entered by hand before uploads existed) are left alone.
+func (s *MediaUploadService) removeObject(bucket, key string) {
+	if !strings.HasPrefix(key, "courses/") {
+		return
+	}
+	if err := s.minio.RemoveObject(context.Background(), bucket, key, minio.RemoveObjectOptions{}); err != nil {
+		log.Printf("Warning: failed to remove object %s: %v", key, err)
+	}
+}
+
+func partCount(upload *model.MediaUpload) int {
+	return int((upload.SizeBytes + upload.PartSize - 1) / upload.PartSize)
+}
+
+// completeUploadError turns the status guard of the repository into the error the client
+// sees when another request completed or aborted the upload first.
+func completeUploadError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrMediaUploadNotPending
+	}
+	return err
+}
+
+// normalizeContentType drops parameters such as "; charset=utf-8" and lowercases.
+func normalizeContentType(contentType string) string {
+	contentType, _, _ = strings.Cut(contentType, ";")
+	return strings.ToLower(strings.TrimSpace(contentType))
+}
+
+func acceptedContentTypes(kind string) []string {
+	types := make([]string, 0, len(mediaUploadTypes[kind]))
+	for t := range mediaUploadTypes[kind] {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+func toMediaUploadResponse(u model.MediaUpload) dto.MediaUploadResponseDTO {
+	return dto.MediaUploadResponseDTO{
+		ID:          u.ID,
+		CourseID:    u.CourseID,
+		LessonID:    u.LessonID,
+		Kind:        u.Kind,
+		Status:      u.Status,
+		FileName:    u.FileName,
+		ContentType: u.ContentType,
+		SizeBytes:   u.SizeBytes,
+		CompletedAt: formatTimePtr(u.CompletedAt),
+	}
+}
@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"study.com/v1/internal/dto"
+	"study.com/v1/internal/model"
+	"study.com/v1/internal/repository"
+)
+
+type OrganizationRoleServiceInterface interface {
+	ListRoles(ctx context.Context, actorID, orgID uuid.UUID, search string, page, pageSize int) (*dto.RoleListResponseDTO, error)
+	GetRole(ctx context.Context, actorID, orgID, roleID uuid.UUID) (*dto.RoleDetailResponseDTO, error)
+	CreateRole(ctx context.Context, actorID, orgID uuid.UUID, req dto.CreateRoleDTO) (*dto.RoleResponseDTO, error)
+	UpdateRole(ctx context.Context, actorID, orgID, roleID uuid.UUID, req dto.UpdateRoleDTO) (*dto.RoleResponseDTO, error)
+	DeleteRole(ctx context.Context, actorID, orgID, roleID uuid.UUID) error
+	AddPermissionsToRole(ctx context.Context, actorID, orgID, roleID uuid.UUID, req dto.AddPermissionsToRoleDTO) (*dto.RoleDetailResponseDTO, error)
+	RemovePermissionsFromRole(ctx context.Context, actorID, orgID, roleID uuid.UUID, req dto.RemovePermissionsFromRoleDTO) (*dto.RoleDetailResponseDTO, error)
+}
+
+// OrganizationRoleService manages the custom roles of one organization (roles with
+// organization_id set). Such roles only grant permissions inside their organization,
+// and a manager can only put permissions in them that they hold there themselves.
+type OrganizationRoleService struct {
+	orgRepo        repository.OrganizationRepositoryInterface
+	roleRepo       repository.RoleRepositoryInterface
+	permissionRepo repository.PermissionRepositoryInterface
+	authz          AuthorizationServiceInterface
+}
+
+func NewOrganizationRoleService(
+	orgRepo repository.OrganizationRepositoryInterface,
+	roleRepo repository.RoleRepositoryInterface,
+	permissionRepo repository.PermissionRepositoryInterface,
+	authz AuthorizationServiceInterface,
+) *OrganizationRoleService {
+	return &OrganizationRoleService{
+		orgRepo:        orgRepo,
+		roleRepo:       roleRepo,
+		permissionRepo: permissionRepo,
+		authz:          authz,
+	}
+}
+
+// ListRoles is also open to member managers, who need the role ids to invite people.
+func (s *OrganizationRoleService) ListRoles(ctx context.Context, actorID, orgID uuid.UUID, search string, page, pageSize int) (*dto.RoleListResponseDTO, error) {
+	org, err := authorizeInOrganization(ctx, s.orgRepo, s.authz, actorID, orgID,
+		model.PermissionOrgRolesManage, model.PermissionOrgMembersManage)
+	if err != nil {
+		return nil, err
+	}
+
+	page, pageSize = normalizePage(page, pageSize)
+	roles, total, err := s.roleRepo.List(ctx, &org.ID, strings.TrimSpace(search), (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]dto.RoleResponseDTO, 0, len(roles))
+	for _, r := range roles {
+		items = append(items, toRoleResponse(r))
+	}
+	return &dto.RoleListResponseDTO{
+		Roles:    items,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+func (s *OrganizationRoleService) GetRole(ctx context.Context, actorID, orgID, roleID uuid.UUID) (*dto.RoleDetailResponseDTO, error) {
+	org, err := authorizeInOrganization(ctx, s.orgRepo, s.authz, actorID, orgID,
+		model.PermissionOrgRolesManage, model.PermissionOrgMembersManage)
+	if err != nil {
+		return nil, err
+	}
+	role, err := s.findRole(ctx, org.ID, roleID)
+	if err != nil {
+		return nil, err
+	}
+	res := toRoleDetailResponse(*role)
+	return &res, nil
+}
+
+func (s *OrganizationRoleService) CreateRole(ctx context.Context, actorID, orgID uuid.UUID, req dto.CreateRoleDTO) (*dto.RoleResponseDTO, error) {
+	org, err := authorizeInOrganization(ctx, s.orgRepo, s.authz, actorID, orgID, model.PermissionOrgRolesManage)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if err := validateRoleFields(name, req.Description); err != nil {
+		return nil, err
+	}
+	if err := s.ensureRoleNameFree(ctx, org.ID, name, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	role := &model.Role{
+		OrganizationID: &org.ID,
+		Name:           name,
+		Description:    nullString(req.Description),
+	}
+	if err := s.roleRepo.Create(ctx, role); err != nil {
+		return nil, err
+	}
+
+	res := toRoleResponse(*role)
+	return &res, nil
+}
+
+func (s *OrganizationRoleService) UpdateRole(ctx context.Context, actorID, orgID, roleID uuid.UUID, req dto.UpdateRoleDTO) (*dto.RoleResponseDTO, error) {
+	org, err := authorizeInOrganization(ctx, s.orgRepo, s.authz, actorID, orgID, model.PermissionOrgRolesManage)
+	if err != nil {
+		return nil, err
+	}
+	role, err := s.findRole(ctx, org.ID, roleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name != role.Name {
+			if err := s.ensureRoleNameFree(ctx, org.ID, name, role.ID); err != nil {
+				return nil, err
+			}
+			role.Name = name
+		}
+	}
+	if req.Description != nil {
+		role.Description = nullString(*req.Description)
+	}
+	if err := validateRoleFields(role.Name, role.Description.String); err != nil {
+		return nil, err
+	}
+
+	if err := s.roleRepo.Update(ctx, role); err != nil {
+		return nil, err
+	}
+
+	res := toRoleResponse(*role)
+	return &res, nil
+}
+
+func (s *OrganizationRoleService) DeleteRole(ctx context.Context, actorID, orgID, roleID uuid.UUID) error {
+	org, err := authorizeInOrganization(ctx, s.orgRepo, s.authz, actorID, orgID, model.PermissionOrgRolesManage)
+	if err != nil {
+		return err
+	}
+	role, err := s.findRole(ctx, org.ID, roleID)
+	if err != nil {
+		return err
+	}
+	if err := s.roleRepo.Delete(ctx, role.ID); err != nil {
+		return err
+	}
+	return s.authz.InvalidateAllPermissions(ctx)
+}
+
+// AddPermissionsToRole refuses to grant a permission the actor does not hold in the
+// organization. Denies only narrow the role and are always allowed.
+func (s *OrganizationRoleService) AddPermissionsToRole(ctx context.Context, actorID, orgID, roleID uuid.UUID, req dto.AddPermissionsToRoleDTO) (*dto.RoleDetailResponseDTO, error) {
+	org, err := authorizeInOrganization(ctx, s.orgRepo, s.authz, actorID, orgID, model.PermissionOrgRolesManage)
+	if err != nil {
+		return nil, err
+	}
+	role, err := s.findRole(ctx, org.ID, roleID)
+	if err != nil {
+		return nil, err
+	}
+	permissions, err := s.findPermissions(ctx, req.PermissionIDs)
+	if err != nil {
+		return nil, err
+	}
+	isGranted := req.IsGranted == nil || *req.IsGranted
+	if err := validateConditions(isGranted, req.Conditions); err != nil {
+		return nil, err
+	}
+
+	grants := make([]model.RolePermission, 0, len(permissions))
+	for _, p := range permissions {
+		if isGranted {
+			held, err := s.authz.HasPermissionInOrganization(ctx, actorID, org.ID, p.Name)
+			if err != nil {
+				return nil, err
+			}
+			if !held {
+				return nil, ErrOrganizationForbidden
+			}
+		}
+		grants = append(grants, model.RolePermission{
+			RoleID:       role.ID,
+			PermissionID: p.ID,
+			IsGranted:    isGranted,
+			Conditions:   model.PermissionConditions(req.Conditions),
+		})
+	}
+	if err := s.roleRepo.AddPermissions(ctx, grants); err != nil {
+		return nil, err
+	}
+	if err := s.authz.InvalidateAllPermissions(ctx); err != nil {
+		return nil, err
+	}
+	return s.GetRole(ctx, actorID, org.ID, role.ID)
+}
+
+func (s *OrganizationRoleService) RemovePermissionsFromRole(ctx context.Context, actorID, orgID, roleID uuid.UUID, req dto.RemovePermissionsFromRoleDTO) (*dto.RoleDetailResponseDTO, error) {
+	org, err := authorizeInOrganization(ctx, s.orgRepo, s.authz, actorID, orgID, model.PermissionOrgRolesManage)
+	if err != nil {
+		return nil, err
+	}
+	role, err := s.findRole(ctx, org.ID, roleID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.findPermissions(ctx, req.PermissionIDs); err != nil {
+		return nil, err
+	}
+	if err := s.roleRepo.RemovePermissions(ctx, role.ID, req.PermissionIDs); err != nil {
+		return nil, err
+	}
+	if err := s.authz.InvalidateAllPermissions(ctx); err != nil {
+		return nil, err
+	}
+	return s.GetRole(ctx, actorID, org.ID, role.ID)
+}
+
+// findRole only returns roles that belong to orgID, so a role id from another
+// organization looks the same as an unknown one.
+func (s *OrganizationRoleService) findRole(ctx context.Context, orgID, roleID uuid.UUID) (*model.Role, error) {
+	role, err := s.roleRepo.FindByID(ctx, roleID)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil || role.OrganizationID == nil || *role.OrganizationID != orgID {
+		return nil, ErrRoleNotFound
+	}
+	return role, nil
+}
+
+func (s *OrganizationRoleService) findPermissions(ctx context.Context, ids []uuid.UUID) ([]model.Permission, error) {
+	if len(ids) == 0 {
+		return nil, ErrEmptyPermissionList
+	}
+	permissions, err := s.permissionRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[uuid.UUID]struct{}, len(permissions))
+	for _, p := range permissions {
+		if p.Name == model.PermissionWildcard {
+			return nil, ErrWildcardNotAssignable
+		}
+		found[p.ID] = struct{}{}
+	}
+	for _, id := range ids {
+		if _, ok := found[id]; !ok {
+			return nil, ErrPermissionNotFound
+		}
+	}
+	return permissions, nil
+}
+
+func (s *OrganizationRoleService) ensureRoleNameFree(ctx context.Context, orgID uuid.UUID, name string, selfID uuid.UUID) error {
+	existing, err := s.roleRepo.FindByName(ctx, &orgID, name)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != selfID {
+		return ErrRoleNameExists
+	}
+	return nil
+}
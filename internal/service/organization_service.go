@@ -0,0 +1,849 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"study.com/v1/internal/config"
+	"study.com/v1/internal/dto"
+	"study.com/v1/internal/model"
+	"study.com/v1/internal/repository"
+	"study.com/v1/internal/utils"
+)
+
+const invitationTTL = 7 * 24 * time.Hour
+
+var (
+	ErrOrganizationNotFound     = errors.New("organization not found")
+	ErrOrganizationForbidden    = errors.New("you do not have permission to do this in the organization")
+	ErrOrganizationSlugExists   = errors.New("organization slug already exists")
+	ErrInvalidOrganizationName  = errors.New("organization name must be between 2 and 255 characters")
+	ErrInvalidSlug              = errors.New("slug must contain only lowercase letters, digits and dashes")
+	ErrInvalidOrgType           = errors.New("org_type must be one of school, center, company, individual")
+	ErrMemberNotFound           = errors.New("member not found")
+	ErrInvalidMemberRole        = errors.New("member_role must be one of admin, manager, teacher, ta, student, member")
+	ErrInvalidMemberStatus      = errors.New("status must be active or suspended")
+	ErrOwnerMembershipProtected = errors.New("the owner cannot be removed, suspended or demoted")
+	ErrMemberLimitReached       = errors.New("the organization has reached its member limit")
+	ErrAlreadyMember            = errors.New("the user is already a member of the organization")
+	ErrInvitationNotFound       = errors.New("invitation not found")
+	ErrInvitationInvalid        = errors.New("the invitation is invalid, expired or already used")
+	ErrInvitationEmailMismatch  = errors.New("the invitation was sent to a different email address")
+	ErrRoleNotDelegable         = errors.New("a role can only be given if it belongs to the organization and you hold all of its permissions")
+)
+
+// invitableMemberRoles excludes owner: an organization has exactly one owner, its creator.
+var invitableMemberRoles = map[string]struct{}{
+	model.OrgMemberAdmin:   {},
+	model.OrgMemberManager: {},
+	model.OrgMemberTeacher: {},
+	model.OrgMemberTA:      {},
+	model.OrgMemberStudent: {},
+	model.OrgMemberMember:  {},
+}
+
+type OrganizationServiceInterface interface {
+	CreateOrganization(ctx context.Context, actorID uuid.UUID, req dto.CreateOrganizationDTO) (*dto.OrganizationResponseDTO, error)
+	GetOrganization(ctx context.Context, actorID, orgID uuid.UUID) (*dto.OrganizationResponseDTO, error)
+	ListMyOrganizations(ctx context.Context, actorID uuid.UUID, page, pageSize int) (*dto.OrganizationListResponseDTO, error)
+	UpdateOrganization(ctx context.Context, actorID, orgID uuid.UUID, req dto.UpdateOrganizationDTO) (*dto.OrganizationResponseDTO, error)
+	DeleteOrganization(ctx context.Context, actorID, orgID uuid.UUID) error
+
+	ListMembers(ctx context.Context, actorID, orgID uuid.UUID, query dto.OrganizationMemberQueryDTO) (*dto.OrganizationMemberListResponseDTO, error)
+	UpdateMember(ctx context.Context, actorID, orgID, userID uuid.UUID, req dto.UpdateOrganizationMemberDTO) (*dto.OrganizationMemberResponseDTO, error)
+	RemoveMember(ctx context.Context, actorID, orgID, userID uuid.UUID) error
+	LeaveOrganization(ctx context.Context, actorID, orgID uuid.UUID) error
+
+	InviteMember(ctx context.Context, actorID, orgID uuid.UUID, req dto.CreateOrganizationInvitationDTO) (*dto.OrganizationInvitationResponseDTO, error)
+	ListInvitations(ctx context.Context, actorID, orgID uuid.UUID) ([]dto.OrganizationInvitationResponseDTO, error)
+	RevokeInvitation(ctx context.Context, actorID, orgID, invitationID uuid.UUID) error
+	AcceptInvitation(ctx context.Context, actorID uuid.UUID, req dto.AcceptOrganizationInvitationDTO) (*dto.OrganizationResponseDTO, error)
+}
+
+// OrganizationService manages tenants, their members and invitations.
+//
+// Every organization-scoped call first resolves the organization through the tenant
+// guard: users who are neither active members nor platform staff (ORG_DELETE) get
+// ErrOrganizationNotFound, so one organization cannot even learn about another's data.
+// Permissions are then checked with CanAccessResource against the organization, which
+// takes the roles the actor holds inside it into account.
+type OrganizationService struct {
+	cfg            *config.Config
+	orgRepo        repository.OrganizationRepositoryInterface
+	invitationRepo repository.OrganizationInvitationRepositoryInterface
+	roleRepo       repository.RoleRepositoryInterface
+	userRepo       repository.UserRepositoryInterface
+	authz          AuthorizationServiceInterface
+}
+
+func NewOrganizationService(
+	cfg *config.Config,
+	orgRepo repository.OrganizationRepositoryInterface,
+	invitationRepo repository.OrganizationInvitationRepositoryInterface,
+	roleRepo repository.RoleRepositoryInterface,
+	userRepo repository.UserRepositoryInterface,
+	authz AuthorizationServiceInterface,
+) *OrganizationService {
+	return &OrganizationService{
+		cfg:            cfg,
+		orgRepo:        orgRepo,
+		invitationRepo: invitationRepo,
+		roleRepo:       roleRepo,
+		userRepo:       userRepo,
+		authz:          authz,
+	}
+}
+
+func (s *OrganizationService) CreateOrganization(ctx context.Context, actorID uuid.UUID, req dto.CreateOrganizationDTO) (*dto.OrganizationResponseDTO, error) {
+	name := strings.TrimSpace(req.Name)
+	if len(name) < 2 || len(name) > 255 {
+		return nil, ErrInvalidOrganizationName
+	}
+	orgType := req.OrgType
+	if orgType == "" {
+		orgType = model.OrgTypeSchool
+	}
+	if !validOrgType(orgType) {
+		return nil, ErrInvalidOrgType
+	}
+	if req.Email != nil && !utils.IsValidEmail(*req.Email) {
+		return nil, ErrInvalidEmail
+	}
+	slug, err := normalizeSlug(req.Slug, name)
+	if err != nil {
+		return nil, err
+	}
+	exists, err := s.orgRepo.SlugExists(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrOrganizationSlugExists
+	}
+
+	ownerRole, err := s.roleRepo.FindByName(ctx, nil, model.RoleOrgOwner)
+	if err != nil {
+		return nil, err
+	}
+	if ownerRole == nil {
+		return nil, fmt.Errorf("role %s has not been seeded", model.RoleOrgOwner)
+	}
+
+	org := &model.Organization{
+		OwnerID:     actorID,
+		Name:        name,
+		Slug:        slug,
+		Description: req.Description,
+		LogoURL:     req.LogoURL,
+		Website:     req.Website,
+		OrgType:     orgType,
+		Email:       req.Email,
+		Phone:       req.Phone,
+		Address:     req.Address,
+		City:        req.City,
+		Country:     "Vietnam",
+		MaxMembers:  100,
+		MaxCourses:  10,
+		IsActive:    true,
+	}
+	if country := strings.TrimSpace(req.Country); country != "" {
+		org.Country = country
+	}
+	if err := s.orgRepo.Create(ctx, org, ownerRole.ID); err != nil {
+		return nil, err
+	}
+	if err := s.authz.InvalidateUserPermissions(ctx, actorID); err != nil {
+		return nil, err
+	}
+
+	res := toOrganizationResponse(*org)
+	return &res, nil
+}
+
+func (s *OrganizationService) GetOrganization(ctx context.Context, actorID, orgID uuid.UUID) (*dto.OrganizationResponseDTO, error) {
+	org, err := s.visibleOrganization(ctx, actorID, orgID)
+	if err != nil {
+		return nil, err
+	}
+	res := toOrganizationResponse(*org)
+	return &res, nil
+}
+
+func (s *OrganizationService) ListMyOrganizations(ctx context.Context, actorID uuid.UUID, page, pageSize int) (*dto.OrganizationListResponseDTO, error) {
+	page, pageSize = normalizePage(page, pageSize)
+	orgs, total, err := s.orgRepo.ListByMember(ctx, actorID, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]dto.OrganizationResponseDTO, 0, len(orgs))
+	for _, o := range orgs {
+		items = append(items, toOrganizationResponse(o))
+	}
+	return &dto.OrganizationListResponseDTO{
+		Organizations: items,
+		Total:         total,
+		Page:          page,
+		PageSize:      pageSize,
+	}, nil
+}
+
+func (s *OrganizationService) UpdateOrganization(ctx context.Context, actorID, orgID uuid.UUID, req dto.UpdateOrganizationDTO) (*dto.OrganizationResponseDTO, error) {
+	org, err := s.authorize(ctx, actorID, orgID, model.PermissionOrgSettingsManage)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if len(name) < 2 || len(name) > 255 {
+			return nil, ErrInvalidOrganizationName
+		}
+		org.Name = name
+	}
+	if req.OrgType != nil {
+		if !validOrgType(*req.OrgType) {
+			return nil, ErrInvalidOrgType
+		}
+		org.OrgType = *req.OrgType
+	}
+	if req.Email != nil {
+		if *req.Email != "" && !utils.IsValidEmail(*req.Email) {
+			return nil, ErrInvalidEmail
+		}
+		org.Email = emptyToNil(req.Email)
+	}
+	if req.Description != nil {
+		org.Description = emptyToNil(req.Description)
+	}
+	if req.LogoURL != nil {
+		org.LogoURL = emptyToNil(req.LogoURL)
+	}
+	if req.Website != nil {
+		org.Website = emptyToNil(req.Website)
+	}
+	if req.Phone != nil {
+		org.Phone = emptyToNil(req.Phone)
+	}
+	if req.Address != nil {
+		org.Address = emptyToNil(req.Address)
+	}
+	if req.City != nil {
+		org.City = emptyToNil(req.City)
+	}
+	if req.Country != nil && strings.TrimSpace(*req.Country) != "" {
+		org.Country = strings.TrimSpace(*req.Country)
+	}
+
+	if err := s.orgRepo.Update(ctx, org); err != nil {
+		return nil, err
+	}
+	res := toOrganizationResponse(*org)
+	return &res, nil
+}
+
+// DeleteOrganization is allowed to the owner and to platform staff holding ORG_DELETE.
+func (s *OrganizationService) DeleteOrganization(ctx context.Context, actorID, orgID uuid.UUID) error {
+	org, err := s.visibleOrganization(ctx, actorID, orgID)
+	if err != nil {
+		return err
+	}
+	if org.OwnerID != actorID {
+		allowed, err := s.authz.CanAccessResource(ctx, actorID, model.PermissionOrgDelete, OrganizationResource(org))
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return ErrOrganizationForbidden
+		}
+	}
+	if err := s.orgRepo.Delete(ctx, org.ID); err != nil {
+		return err
+	}
+	// Org-scoped grants of every member are cached, drop them all.
+	return s.authz.InvalidateAllPermissions(ctx)
+}
+
+// ListMembers is the only way to see who belongs to an organization; it needs
+// ORG_MEMBERS_MANAGE or TRACKING_VIEW_ORG_STUDENTS inside that organization.
+func (s *OrganizationService) ListMembers(ctx context.Context, actorID, orgID uuid.UUID, query dto.OrganizationMemberQueryDTO) (*dto.OrganizationMemberListResponseDTO, error) {
+	org, err := s.authorize(ctx, actorID, orgID, model.PermissionOrgMembersManage, model.PermissionTrackingViewOrgStudents)
+	if err != nil {
+		return nil, err
+	}
+
+	page, pageSize := normalizePage(query.Page, query.PageSize)
+	filter := repository.MemberFilter{
+		Search:     strings.TrimSpace(query.Search),
+		MemberRole: query.MemberRole,
+		Status:     query.Status,
+	}
+	members, total, err := s.orgRepo.ListMembers(ctx, org.ID, filter, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.toMemberResponses(ctx, org.ID, members)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.OrganizationMemberListResponseDTO{
+		Members:  items,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+func (s *OrganizationService) UpdateMember(ctx context.Context, actorID, orgID, userID uuid.UUID, req dto.UpdateOrganizationMemberDTO) (*dto.OrganizationMemberResponseDTO, error) {
+	org, err := s.authorize(ctx, actorID, orgID, model.PermissionOrgMembersManage)
+	if err != nil {
+		return nil, err
+	}
+	member, err := s.findMember(ctx, org.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.MemberRole != nil {
+		if _, ok := invitableMemberRoles[*req.MemberRole]; !ok {
+			return nil, ErrInvalidMemberRole
+		}
+		if member.MemberRole == model.OrgMemberOwner {
+			return nil, ErrOwnerMembershipProtected
+		}
+		member.MemberRole = *req.MemberRole
+	}
+	if req.Status != nil {
+		if *req.Status != model.OrgMemberStatusActive && *req.Status != model.OrgMemberStatusSuspended {
+			return nil, ErrInvalidMemberStatus
+		}
+		if member.MemberRole == model.OrgMemberOwner && *req.Status != model.OrgMemberStatusActive {
+			return nil, ErrOwnerMembershipProtected
+		}
+		member.Status = *req.Status
+	}
+	if req.Department != nil {
+		member.Department = emptyToNil(req.Department)
+	}
+	if req.StudentID != nil {
+		member.StudentCode = emptyToNil(req.StudentID)
+	}
+
+	if req.RoleIDs != nil {
+		if member.MemberRole == model.OrgMemberOwner {
+			return nil, ErrOwnerMembershipProtected
+		}
+		if err := s.ensureDelegable(ctx, actorID, org.ID, *req.RoleIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.orgRepo.UpdateMember(ctx, member); err != nil {
+		return nil, err
+	}
+	if req.RoleIDs != nil {
+		if err := s.orgRepo.ReplaceMemberRoles(ctx, org.ID, userID, *req.RoleIDs, &actorID); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.authz.InvalidateUserPermissions(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	items, err := s.toMemberResponses(ctx, org.ID, []model.OrganizationMember{*member})
+	if err != nil {
+		return nil, err
+	}
+	return &items[0], nil
+}
+
+func (s *OrganizationService) RemoveMember(ctx context.Context, actorID, orgID, userID uuid.UUID) error {
+	org, err := s.authorize(ctx, actorID, orgID, model.PermissionOrgMembersManage)
+	if err != nil {
+		return err
+	}
+	member, err := s.findMember(ctx, org.ID, userID)
+	if err != nil {
+		return err
+	}
+	if member.MemberRole == model.OrgMemberOwner {
+		return ErrOwnerMembershipProtected
+	}
+	if err := s.orgRepo.RemoveMember(ctx, org.ID, userID); err != nil {
+		return err
+	}
+	return s.authz.InvalidateUserPermissions(ctx, userID)
+}
+
+func (s *OrganizationService) LeaveOrganization(ctx context.Context, actorID, orgID uuid.UUID) error {
+	member, err := s.orgRepo.FindMember(ctx, orgID, actorID)
+	if err != nil {
+		return err
+	}
+	if member == nil {
+		return ErrOrganizationNotFound
+	}
+	if member.MemberRole == model.OrgMemberOwner {
+		return ErrOwnerMembershipProtected
+	}
+	if err := s.orgRepo.RemoveMember(ctx, orgID, actorID); err != nil {
+		return err
+	}
+	return s.authz.InvalidateUserPermissions(ctx, actorID)
+}
+
+func (s *OrganizationService) InviteMember(ctx context.Context, actorID, orgID uuid.UUID, req dto.CreateOrganizationInvitationDTO) (*dto.OrganizationInvitationResponseDTO, error) {
+	org, err := s.authorize(ctx, actorID, orgID, model.PermissionOrgMembersManage)
+	if err != nil {
+		return nil, err
+	}
+
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if !utils.IsValidEmail(email) {
+		return nil, ErrInvalidEmail
+	}
+	memberRole := req.MemberRole
+	if memberRole == "" {
+		memberRole = model.OrgMemberMember
+	}
+	if _, ok := invitableMemberRoles[memberRole]; !ok {
+		return nil, ErrInvalidMemberRole
+	}
+	if err := s.ensureDelegable(ctx, actorID, org.ID, req.RoleIDs); err != nil {
+		return nil, err
+	}
+	if err := s.ensureMemberCapacity(ctx, org); err != nil {
+		return nil, err
+	}
+
+	invitee, err := s.userRepo.FindUserByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if invitee != nil {
+		existing, err := s.orgRepo.FindMember(ctx, org.ID, invitee.ID)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return nil, ErrAlreadyMember
+		}
+	}
+
+	token, err := utils.GenerateSecureToken()
+	if err != nil {
+		return nil, err
+	}
+	roleIDs := make([]string, 0, len(req.RoleIDs))
+	for _, id := range req.RoleIDs {
+		roleIDs = append(roleIDs, id.String())
+	}
+	invitation := &model.OrganizationInvitation{
+		OrganizationID: org.ID,
+		Email:          email,
+		MemberRole:     memberRole,
+		RoleIDs:        roleIDs,
+		TokenHash:      utils.HashToken(token),
+		InvitedBy:      actorID,
+		ExpiresAt:      time.Now().Add(invitationTTL),
+	}
+	if err := s.invitationRepo.Create(ctx, invitation); err != nil {
+		return nil, err
+	}
+
+	acceptURL := strings.TrimRight(s.cfg.FrontendURL, "/") + "/organizations/invitations/accept?token=" + url.QueryEscape(token)
+	if err := utils.SendOrganizationInvitation(s.cfg, email, org.Name, acceptURL, int(invitationTTL.Hours()/24)); err != nil {
+		return nil, fmt.Errorf("failed to send invitation email: %w", err)
+	}
+
+	res := toInvitationResponse(*invitation)
+	return &res, nil
+}
+
+func (s *OrganizationService) ListInvitations(ctx context.Context, actorID, orgID uuid.UUID) ([]dto.OrganizationInvitationResponseDTO, error) {
+	org, err := s.authorize(ctx, actorID, orgID, model.PermissionOrgMembersManage)
+	if err != nil {
+		return nil, err
+	}
+	invitations, err := s.invitationRepo.ListPending(ctx, org.ID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	res := make([]dto.OrganizationInvitationResponseDTO, 0, len(invitations))
+	for _, inv := range invitations {
+		res = append(res, toInvitationResponse(inv))
+	}
+	return res, nil
+}
+
+func (s *OrganizationService) RevokeInvitation(ctx context.Context, actorID, orgID, invitationID uuid.UUID) error {
+	org, err := s.authorize(ctx, actorID, orgID, model.PermissionOrgMembersManage)
+	if err != nil {
+		return err
+	}
+	deleted, err := s.invitationRepo.Delete(ctx, org.ID, invitationID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrInvitationNotFound
+	}
+	return nil
+}
+
+// AcceptInvitation adds the signed-in user to the organization. The invitation must have
+// been sent to the user's own email address.
+func (s *OrganizationService) AcceptInvitation(ctx context.Context, actorID uuid.UUID, req dto.AcceptOrganizationInvitationDTO) (*dto.OrganizationResponseDTO, error) {
+	token := strings.TrimSpace(req.Token)
+	if token == "" {
+		return nil, ErrInvitationInvalid
+	}
+	invitation, err := s.invitationRepo.FindByTokenHash(ctx, utils.HashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if invitation == nil || invitation.AcceptedAt != nil || now.After(invitation.ExpiresAt) {
+		return nil, ErrInvitationInvalid
+	}
+	org := &invitation.Organization
+	if org.ID == uuid.Nil || !org.IsActive {
+		return nil, ErrInvitationInvalid
+	}
+
+	user, err := s.userRepo.FindUserByID(ctx, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	if !strings.EqualFold(user.Email, invitation.Email) {
+		return nil, ErrInvitationEmailMismatch
+	}
+	existing, err := s.orgRepo.FindMember(ctx, org.ID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrAlreadyMember
+	}
+	if err := s.ensureMemberCapacity(ctx, org); err != nil {
+		return nil, err
+	}
+
+	roleIDs, err := s.invitationRoles(ctx, org.ID, invitation.RoleIDs)
+	if err != nil {
+		return nil, err
+	}
+	invitation.AcceptedAt = &now
+	invitation.AcceptedBy = &actorID
+	member := &model.OrganizationMember{
+		OrganizationID: org.ID,
+		UserID:         actorID,
+		MemberRole:     invitation.MemberRole,
+		Status:         model.OrgMemberStatusActive,
+		JoinedAt:       now,
+		InvitedBy:      &invitation.InvitedBy,
+	}
+	if err := s.invitationRepo.Accept(ctx, invitation, member, roleIDs); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvitationInvalid
+		}
+		return nil, err
+	}
+	if err := s.authz.InvalidateUserPermissions(ctx, actorID); err != nil {
+		return nil, err
+	}
+
+	res := toOrganizationResponse(*org)
+	return &res, nil
+}
+
+// visibleOrganization is the tenant guard: the organization is only visible to its
+// active members and to platform staff.
+func (s *OrganizationService) visibleOrganization(ctx context.Context, actorID, orgID uuid.UUID) (*model.Organization, error) {
+	return findVisibleOrganization(ctx, s.orgRepo, s.authz, actorID, orgID)
+}
+
+func (s *OrganizationService) authorize(ctx context.Context, actorID, orgID uuid.UUID, anyOf ...string) (*model.Organization, error) {
+	return authorizeInOrganization(ctx, s.orgRepo, s.authz, actorID, orgID, anyOf...)
+}
+
+func (s *OrganizationService) findMember(ctx context.Context, orgID, userID uuid.UUID) (*model.OrganizationMember, error) {
+	member, err := s.orgRepo.FindMember(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, ErrMemberNotFound
+	}
+	return member, nil
+}
+
+func (s *OrganizationService) ensureMemberCapacity(ctx context.Context, org *model.Organization) error {
+	count, err := s.orgRepo.CountMembers(ctx, org.ID)
+	if err != nil {
+		return err
+	}
+	if org.MaxMembers > 0 && count >= int64(org.MaxMembers) {
+		return ErrMemberLimitReached
+	}
+	return nil
+}
+
+// ensureDelegable checks that every role either belongs to the organization or is a
+// global role, and that the actor holds each permission the role grants. This keeps an
+// organization manager from creating members more powerful than themselves.
+func (s *OrganizationService) ensureDelegable(ctx context.Context, actorID, orgID uuid.UUID, roleIDs []uuid.UUID) error {
+	return ensureRolesDelegable(ctx, s.roleRepo, s.authz, actorID, orgID, roleIDs)
+}
+
+// invitationRoles drops roles deleted since the invitation was sent.
+func (s *OrganizationService) invitationRoles(ctx context.Context, orgID uuid.UUID, raw []string) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, len(raw))
+	for _, r := range raw {
+		id, err := uuid.Parse(r)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	roles, err := s.roleRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	valid := make([]uuid.UUID, 0, len(roles))
+	for _, role := range roles {
+		if role.OrganizationID == nil || *role.OrganizationID == orgID {
+			valid = append(valid, role.ID)
+		}
+	}
+	return valid, nil
+}
+
+func (s *OrganizationService) toMemberResponses(ctx context.Context, orgID uuid.UUID, members []model.OrganizationMember) ([]dto.OrganizationMemberResponseDTO, error) {
+	userIDs := make([]uuid.UUID, 0, len(members))
+	for _, m := range members {
+		userIDs = append(userIDs, m.UserID)
+	}
+	userRoles, err := s.orgRepo.FindMemberRoles(ctx, orgID, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	rolesByUser := make(map[uuid.UUID][]dto.RoleResponseDTO, len(members))
+	for _, ur := range userRoles {
+		rolesByUser[ur.UserID] = append(rolesByUser[ur.UserID], toRoleResponse(ur.Role))
+	}
+
+	res := make([]dto.OrganizationMemberResponseDTO, 0, len(members))
+	for _, m := range members {
+		roles := rolesByUser[m.UserID]
+		if roles == nil {
+			roles = []dto.RoleResponseDTO{}
+		}
+		res = append(res, dto.OrganizationMemberResponseDTO{
+			ID:         m.ID,
+			UserID:     m.UserID,
+			Username:   m.User.UserName,
+			Email:      m.User.Email,
+			FullName:   m.User.FullName,
+			MemberRole: m.MemberRole,
+			Department: m.Department,
+			StudentID:  m.StudentCode,
+			Status:     m.Status,
+			Roles:      roles,
+			JoinedAt:   m.JoinedAt.Format(time.RFC3339),
+		})
+	}
+	return res, nil
+}
+
+// findVisibleOrganization and authorizeInOrganization are shared by every service that
+// works inside an organization.
+func findVisibleOrganization(
+	ctx context.Context,
+	orgRepo repository.OrganizationRepositoryInterface,
+	authz AuthorizationServiceInterface,
+	actorID, orgID uuid.UUID,
+) (*model.Organization, error) {
+	org, err := orgRepo.FindByID(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if org == nil {
+		return nil, ErrOrganizationNotFound
+	}
+
+	member, err := orgRepo.FindMember(ctx, org.ID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if member != nil && member.Status == model.OrgMemberStatusActive && org.IsActive {
+		return org, nil
+	}
+	staff, err := authz.HasAnyPermission(ctx, actorID, model.PermissionOrgDelete)
+	if err != nil {
+		return nil, err
+	}
+	if !staff {
+		return nil, ErrOrganizationNotFound
+	}
+	return org, nil
+}
+
+func authorizeInOrganization(
+	ctx context.Context,
+	orgRepo repository.OrganizationRepositoryInterface,
+	authz AuthorizationServiceInterface,
+	actorID, orgID uuid.UUID,
+	anyOf ...string,
+) (*model.Organization, error) {
+	org, err := findVisibleOrganization(ctx, orgRepo, authz, actorID, orgID)
+	if err != nil {
+		return nil, err
+	}
+	resource := OrganizationResource(org)
+	for _, permission := range anyOf {
+		allowed, err := authz.CanAccessResource(ctx, actorID, permission, resource)
+		if err != nil {
+			return nil, err
+		}
+		if allowed {
+			return org, nil
+		}
+	}
+	return nil, ErrOrganizationForbidden
+}
+
+func ensureRolesDelegable(
+	ctx context.Context,
+	roleRepo repository.RoleRepositoryInterface,
+	authz AuthorizationServiceInterface,
+	actorID, orgID uuid.UUID,
+	roleIDs []uuid.UUID,
+) error {
+	if len(roleIDs) == 0 {
+		return nil
+	}
+	roles, err := roleRepo.FindByIDs(ctx, roleIDs)
+	if err != nil {
+		return err
+	}
+	if len(roles) != len(uniqueIDs(roleIDs)) {
+		return ErrRoleNotFound
+	}
+	for _, r := range roles {
+		if r.OrganizationID != nil && *r.OrganizationID != orgID {
+			return ErrRoleNotFound
+		}
+		role, err := roleRepo.FindByID(ctx, r.ID)
+		if err != nil {
+			return err
+		}
+		for _, rp := range role.RolePermissions {
+			if !rp.IsGranted {
+				continue
+			}
+			if rp.Permission.Name == model.PermissionWildcard {
+				return ErrRoleNotDelegable
+			}
+			held, err := authz.HasPermissionInOrganization(ctx, actorID, orgID, rp.Permission.Name)
+			if err != nil {
+				return err
+			}
+			if !held {
+				return ErrRoleNotDelegable
+			}
+		}
+	}
+	return nil
+}
+
+func normalizeSlug(slug, fallback string) (string, error) {
+	slug = strings.TrimSpace(slug)
+	if slug == "" {
+		slug = utils.Slugify(fallback)
+	} else if utils.Slugify(slug) != slug {
+		return "", ErrInvalidSlug
+	}
+	if slug == "" || len(slug) > 100 {
+		return "", ErrInvalidSlug
+	}
+	return slug, nil
+}
+
+func validOrgType(orgType string) bool {
+	switch orgType {
+	case model.OrgTypeSchool, model.OrgTypeCenter, model.OrgTypeCompany, model.OrgTypeIndividual:
+		return true
+	}
+	return false
+}
+
+func emptyToNil(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
+func uniqueIDs(ids []uuid.UUID) map[uuid.UUID]struct{} {
+	set := make(map[uuid.UUID]struct{}, len(ids))
+	for _, id := range ids {
+		set[id] = struct{}{}
+	}
+	return set
+}
+
+func toOrganizationResponse(o model.Organization) dto.OrganizationResponseDTO {
+	return dto.OrganizationResponseDTO{
+		ID:          o.ID,
+		OwnerID:     o.OwnerID,
+		Name:        o.Name,
+		Slug:        o.Slug,
+		Description: o.Description,
+		OrgType:     o.OrgType,
+		LogoURL:     o.LogoURL,
+		Website:     o.Website,
+		Email:       o.Email,
+		Phone:       o.Phone,
+		Address:     o.Address,
+		City:        o.City,
+		Country:     o.Country,
+		MaxMembers:  o.MaxMembers,
+		MaxCourses:  o.MaxCourses,
+		IsVerified:  o.IsVerified,
+		IsActive:    o.IsActive,
+		CreatedAt:   o.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   o.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func toInvitationResponse(inv model.OrganizationInvitation) dto.OrganizationInvitationResponseDTO {
+	roleIDs := make([]uuid.UUID, 0, len(inv.RoleIDs))
+	for _, raw := range inv.RoleIDs {
+		if id, err := uuid.Parse(raw); err == nil {
+			roleIDs = append(roleIDs, id)
+		}
+	}
+	return dto.OrganizationInvitationResponseDTO{
+		ID:         inv.ID,
+		Email:      inv.Email,
+		MemberRole: inv.MemberRole,
+		RoleIDs:    roleIDs,
+		InvitedBy:  inv.InvitedBy,
+		ExpiresAt:  inv.ExpiresAt.Format(time.RFC3339),
+		CreatedAt:  inv.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,697 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"study.com/v1/internal/config"
+	"study.com/v1/internal/dto"
+	"study.com/v1/internal/model"
+	"study.com/v1/internal/repository"
+	"study.com/v1/internal/utils"
+)
+
+const (
+	parentLinkOTPTTL         = 10 * time.Minute
+	parentLinkResendCooldown = 60 * time.Second
+	parentLinkMaxOTPAttempts = 5
+)
+
+var (
+	ErrParentLinkNotFound     = errors.New("parent link not found")
+	ErrParentLinkExists       = errors.New("this student is already linked to you")
+	ErrParentLinkNotPending   = errors.New("the link is not waiting for confirmation")
+	ErrCannotLinkSelf         = errors.New("you cannot link your own account")
+	ErrParentIsMinor          = errors.New("a parent account must not belong to a minor")
+	ErrInvalidRelationship    = errors.New("relationship must be parent, guardian, grandparent or sibling")
+	ErrMinorCannotUnlink      = errors.New("students under the minor age cannot remove a linked parent")
+	ErrMinorCannotChangeLink  = errors.New("students under the minor age cannot change what their parent can see")
+	ErrChildNotFound          = errors.New("linked student not found")
+	ErrParentAccessDenied     = errors.New("the student has not shared this with you")
+	ErrOrderNotFound          = errors.New("order not found")
+	ErrOrderNotAwaitingParent = errors.New("the order is not waiting for parent approval")
+)
+
+// relationshipLabels is how each relationship reads in the confirmation email.
+var relationshipLabels = map[string]string{
+	model.RelationshipParent:      "phụ huynh",
+	model.RelationshipGuardian:    "người giám hộ",
+	model.RelationshipGrandparent: "ông/bà",
+	model.RelationshipSibling:     "anh/chị",
+}
+
+func parentLinkOTPKey(linkID uuid.UUID) string {
+	return fmt.Sprintf("parent_link:otp:%s", linkID)
+}
+
+func parentLinkCooldownKey(linkID uuid.UUID) string {
+	return fmt.Sprintf("parent_link:cooldown:%s", linkID)
+}
+
+func parentLinkAttemptsKey(linkID uuid.UUID) string {
+	return fmt.Sprintf("parent_link:attempts:%s", linkID)
+}
+
+type ParentServiceInterface interface {
+	RequestLink(ctx context.Context, parentID uuid.UUID, req dto.CreateParentLinkDTO) (*dto.ParentLinkResponseDTO, error)
+	ResendLinkOTP(ctx context.Context, parentID, linkID uuid.UUID) error
+	ConfirmLink(ctx context.Context, actorID, linkID uuid.UUID, req dto.ConfirmParentLinkDTO) (*dto.ParentLinkResponseDTO, error)
+	ListMyLinks(ctx context.Context, userID uuid.UUID) ([]dto.ParentLinkResponseDTO, error)
+	UpdateLink(ctx context.Context, studentID, linkID uuid.UUID, req dto.UpdateParentLinkDTO) (*dto.ParentLinkResponseDTO, error)
+	RevokeLink(ctx context.Context, actorID, linkID uuid.UUID) error
+	GetGuardianStatus(ctx context.Context, userID uuid.UUID) (*dto.GuardianStatusDTO, error)
+	IsParentLinkRequired(ctx context.Context, userID uuid.UUID) (bool, error)
+
+	ListChildren(ctx context.Context, parentID uuid.UUID) ([]dto.ParentLinkResponseDTO, error)
+	ListChildEnrollments(ctx context.Context, parentID, studentID uuid.UUID, page, pageSize int) (*dto.ChildEnrollmentListResponseDTO, error)
+	ListChildQuizAttempts(ctx context.Context, parentID, studentID uuid.UUID, page, pageSize int) (*dto.ChildQuizAttemptListResponseDTO, error)
+	ListChildOrders(ctx context.Context, parentID, studentID uuid.UUID, approvalStatus string, page, pageSize int) (*dto.ChildOrderListResponseDTO, error)
+	ApproveChildOrder(ctx context.Context, parentID, studentID, orderID uuid.UUID, req dto.ParentApprovalDTO) (*dto.ChildOrderDTO, error)
+	RejectChildOrder(ctx context.Context, parentID, studentID, orderID uuid.UUID, req dto.ParentApprovalDTO) (*dto.ChildOrderDTO, error)
+}
+
+// ParentService links parent accounts to students and serves the read-only views a parent
+// gets of a linked child. Students younger than cfg.MinorAgeThreshold must keep at least one
+// active parent, and their orders wait for a parent to approve them.
+type ParentService struct {
+	cfg            *config.Config
+	parentRepo     repository.ParentRepositoryInterface
+	userRepo       repository.UserRepositoryInterface
+	roleRepo       repository.RoleRepositoryInterface
+	enrollmentRepo repository.EnrollmentRepositoryInterface
+	quizRepo       repository.QuizRepositoryInterface
+	orderRepo      repository.OrderRepositoryInterface
+	authz          AuthorizationServiceInterface
+	redisClient    *redis.Client
+}
+
+func NewParentService(
+	cfg *config.Config,
+	parentRepo repository.ParentRepositoryInterface,
+	userRepo repository.UserRepositoryInterface,
+	roleRepo repository.RoleRepositoryInterface,
+	enrollmentRepo repository.EnrollmentRepositoryInterface,
+	quizRepo repository.QuizRepositoryInterface,
+	orderRepo repository.OrderRepositoryInterface,
+	authz AuthorizationServiceInterface,
+	redisClient *redis.Client,
+) *ParentService {
+	return &ParentService{
+		cfg:            cfg,
+		parentRepo:     parentRepo,
+		userRepo:       userRepo,
+		roleRepo:       roleRepo,
+		enrollmentRepo: enrollmentRepo,
+		quizRepo:       quizRepo,
+		orderRepo:      orderRepo,
+		authz:          authz,
+		redisClient:    redisClient,
+	}
+}
+
+func (s *ParentService) RequestLink(ctx context.Context, parentID uuid.UUID, req dto.CreateParentLinkDTO) (*dto.ParentLinkResponseDTO, error) {
+	relationship := strings.TrimSpace(req.Relationship)
+	if relationship == "" {
+		relationship = model.RelationshipParent
+	}
+	if _, ok := relationshipLabels[relationship]; !ok {
+		return nil, ErrInvalidRelationship
+	}
+	email := normalizeEmail(req.StudentEmail)
+	if !utils.IsValidEmail(email) {
+		return nil, ErrInvalidEmail
+	}
+
+	parent, err := s.userRepo.FindUserByID(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+	if parent == nil {
+		return nil, ErrUserNotFound
+	}
+	if s.isMinor(parent) {
+		return nil, ErrParentIsMinor
+	}
+
+	student, err := s.userRepo.FindUserByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if student == nil || !student.IsActive {
+		return nil, ErrUserNotFound
+	}
+	if student.ID == parentID {
+		return nil, ErrCannotLinkSelf
+	}
+
+	relation, err := s.parentRepo.FindByPair(ctx, parentID, student.ID)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case relation == nil:
+		relation = &model.ParentStudentRelation{
+			ParentUserID:  parentID,
+			StudentUserID: student.ID,
+			Relationship:  relationship,
+			Status:        model.ParentLinkPending,
+		}
+		if err := s.parentRepo.Create(ctx, relation); err != nil {
+			return nil, err
+		}
+	case relation.Status == model.ParentLinkActive:
+		return nil, ErrParentLinkExists
+	default:
+		// A pending request is sent again, a revoked one starts over.
+		relation.Relationship = relationship
+		if err := s.parentRepo.Reopen(ctx, relation); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.sendLinkOTP(ctx, relation.ID, parent, student, relationship); err != nil {
+		return nil, err
+	}
+	return s.linkResponse(ctx, relation.ID)
+}
+
+func (s *ParentService) ResendLinkOTP(ctx context.Context, parentID, linkID uuid.UUID) error {
+	relation, err := s.findLink(ctx, linkID)
+	if err != nil {
+		return err
+	}
+	if relation.ParentUserID != parentID {
+		return ErrParentLinkNotFound
+	}
+	if relation.Status != model.ParentLinkPending {
+		return ErrParentLinkNotPending
+	}
+	return s.sendLinkOTP(ctx, relation.ID, &relation.Parent, &relation.Student, relation.Relationship)
+}
+
+// ConfirmLink activates a pending link. The code is sent to the student, so it is entered
+// either by the student or by the parent the student read it out to.
+func (s *ParentService) ConfirmLink(ctx context.Context, actorID, linkID uuid.UUID, req dto.ConfirmParentLinkDTO) (*dto.ParentLinkResponseDTO, error) {
+	relation, err := s.findLink(ctx, linkID)
+	if err != nil {
+		return nil, err
+	}
+	var confirmedBy string
+	switch actorID {
+	case relation.StudentUserID:
+		confirmedBy = model.ParentLinkConfirmedByStudent
+	case relation.ParentUserID:
+		confirmedBy = model.ParentLinkConfirmedByParent
+	default:
+		return nil, ErrParentLinkNotFound
+	}
+	if relation.Status != model.ParentLinkPending {
+		return nil, ErrParentLinkNotPending
+	}
+	if err := s.verifyLinkOTP(ctx, linkID, req.OTP); err != nil {
+		return nil, err
+	}
+
+	parentRole, err := s.roleRepo.FindByName(ctx, nil, model.RoleParent)
+	if err != nil {
+		return nil, err
+	}
+	if parentRole == nil {
+		return nil, fmt.Errorf("role %s has not been seeded", model.RoleParent)
+	}
+
+	now := time.Now()
+	relation.ConfirmedAt = &now
+	relation.ConfirmedBy = &confirmedBy
+
+	var consent *model.AgeVerification
+	if s.isMinor(&relation.Student) {
+		consent = &model.AgeVerification{
+			UserID:             relation.StudentUserID,
+			VerificationMethod: model.AgeVerificationParentConsent,
+			Status:             model.AgeVerificationApproved,
+			ParentUserID:       &relation.ParentUserID,
+			ParentConsentAt:    &now,
+			VerifiedAt:         &now,
+		}
+	}
+	if err := s.parentRepo.Activate(ctx, relation, parentRole.ID, consent); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrParentLinkNotPending
+		}
+		return nil, err
+	}
+	if err := s.authz.InvalidateUserPermissions(ctx, relation.ParentUserID); err != nil {
+		return nil, err
+	}
+	return s.linkResponse(ctx, relation.ID)
+}
+
+func (s *ParentService) ListMyLinks(ctx context.Context, userID uuid.UUID) ([]dto.ParentLinkResponseDTO, error) {
+	relations, err := s.parentRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return toParentLinkResponses(relations), nil
+}
+
+// UpdateLink lets an adult student limit what a linked parent can see. A minor's parent
+// always keeps full visibility.
+func (s *ParentService) UpdateLink(ctx context.Context, studentID, linkID uuid.UUID, req dto.UpdateParentLinkDTO) (*dto.ParentLinkResponseDTO, error) {
+	relation, err := s.findLink(ctx, linkID)
+	if err != nil {
+		return nil, err
+	}
+	if relation.StudentUserID != studentID {
+		return nil, ErrParentLinkNotFound
+	}
+	if s.isMinor(&relation.Student) {
+		return nil, ErrMinorCannotChangeLink
+	}
+
+	if req.CanViewProgress != nil {
+		relation.CanViewProgress = *req.CanViewProgress
+	}
+	if req.CanViewGrades != nil {
+		relation.CanViewGrades = *req.CanViewGrades
+	}
+	if req.CanApprovePurchases != nil {
+		relation.CanApprovePurchases = *req.CanApprovePurchases
+	}
+	if err := s.parentRepo.UpdatePermissions(ctx, relation); err != nil {
+		return nil, err
+	}
+	return s.linkResponse(ctx, relation.ID)
+}
+
+// RevokeLink ends a link from either side. A minor can decline a pending request but cannot
+// drop an active parent on their own.
+func (s *ParentService) RevokeLink(ctx context.Context, actorID, linkID uuid.UUID) error {
+	relation, err := s.findLink(ctx, linkID)
+	if err != nil {
+		return err
+	}
+	if actorID != relation.ParentUserID && actorID != relation.StudentUserID {
+		return ErrParentLinkNotFound
+	}
+	if relation.Status == model.ParentLinkRevoked {
+		return nil
+	}
+	if actorID == relation.StudentUserID && relation.Status == model.ParentLinkActive && s.isMinor(&relation.Student) {
+		return ErrMinorCannotUnlink
+	}
+
+	now := time.Now()
+	relation.RevokedAt = &now
+	relation.RevokedBy = &actorID
+	if err := s.parentRepo.Revoke(ctx, relation); err != nil {
+		return err
+	}
+	s.redisClient.Del(ctx, parentLinkOTPKey(linkID), parentLinkAttemptsKey(linkID))
+	return nil
+}
+
+func (s *ParentService) GetGuardianStatus(ctx context.Context, userID uuid.UUID) (*dto.GuardianStatusDTO, error) {
+	user, err := s.userRepo.FindUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	activeParents, err := s.parentRepo.CountActiveParents(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	minor := s.isMinor(user)
+	return &dto.GuardianStatusDTO{
+		IsMinor:           minor,
+		MinorAgeThreshold: s.cfg.MinorAgeThreshold,
+		ActiveParents:     activeParents,
+		ParentRequired:    minor && activeParents == 0,
+	}, nil
+}
+
+// IsParentLinkRequired reports whether the user is a minor without an active parent.
+// It backs middleware.RequireParentLink.
+func (s *ParentService) IsParentLinkRequired(ctx context.Context, userID uuid.UUID) (bool, error) {
+	user, err := s.userRepo.FindUserByID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	if user == nil || !s.isMinor(user) {
+		return false, nil
+	}
+	activeParents, err := s.parentRepo.CountActiveParents(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return activeParents == 0, nil
+}
+
+func (s *ParentService) ListChildren(ctx context.Context, parentID uuid.UUID) ([]dto.ParentLinkResponseDTO, error) {
+	relations, err := s.parentRepo.ListActiveChildren(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+	return toParentLinkResponses(relations), nil
+}
+
+func (s *ParentService) ListChildEnrollments(ctx context.Context, parentID, studentID uuid.UUID, page, pageSize int) (*dto.ChildEnrollmentListResponseDTO, error) {
+	relation, err := s.findChild(ctx, parentID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	if !relation.CanViewProgress {
+		return nil, ErrParentAccessDenied
+	}
+
+	page, pageSize = normalizePage(page, pageSize)
+	enrollments, total, err := s.enrollmentRepo.ListByUserID(ctx, studentID, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]dto.ChildEnrollmentDTO, 0, len(enrollments))
+	for _, e := range enrollments {
+		items = append(items, dto.ChildEnrollmentDTO{
+			ID:                 e.ID,
+			CourseID:           e.CourseID,
+			CourseTitle:        e.Course.Title,
+			ProgressPercentage: e.ProgressPercent.StringFixed(2),
+			EnrolledAt:         e.EnrolledAt.Format(time.RFC3339),
+			LastAccessedAt:     formatTimePtr(e.LastAccessedAt),
+			CompletedAt:        formatTimePtr(e.CompletedAt),
+		})
+	}
+	return &dto.ChildEnrollmentListResponseDTO{
+		Enrollments: items,
+		Total:       total,
+		Page:        page,
+		PageSize:    pageSize,
+	}, nil
+}
+
+func (s *ParentService) ListChildQuizAttempts(ctx context.Context, parentID, studentID uuid.UUID, page, pageSize int) (*dto.ChildQuizAttemptListResponseDTO, error) {
+	relation, err := s.findChild(ctx, parentID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	if !relation.CanViewGrades {
+		return nil, ErrParentAccessDenied
+	}
+
+	page, pageSize = normalizePage(page, pageSize)
+	attempts, total, err := s.quizRepo.ListAttemptsByUserID(ctx, studentID, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]dto.ChildQuizAttemptDTO, 0, len(attempts))
+	for _, a := range attempts {
+		items = append(items, dto.ChildQuizAttemptDTO{
+			ID:            a.ID,
+			QuizID:        a.QuizID,
+			QuizTitle:     a.Quiz.Title,
+			Score:         formatDecimalPtr(a.Score),
+			TotalPoints:   formatDecimalPtr(a.TotalPoints),
+			Percentage:    formatDecimalPtr(a.Percentage),
+			IsPassed:      a.IsPassed,
+			TimeSpentSecs: a.TimeSpentSecs,
+			StartedAt:     a.StartedAt.Format(time.RFC3339),
+			CompletedAt:   formatTimePtr(a.CompletedAt),
+		})
+	}
+	return &dto.ChildQuizAttemptListResponseDTO{
+		Attempts: items,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+func (s *ParentService) ListChildOrders(ctx context.Context, parentID, studentID uuid.UUID, approvalStatus string, page, pageSize int) (*dto.ChildOrderListResponseDTO, error) {
+	relation, err := s.findChild(ctx, parentID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	if !relation.CanApprovePurchases {
+		return nil, ErrParentAccessDenied
+	}
+
+	page, pageSize = normalizePage(page, pageSize)
+	filter := repository.OrderFilter{ParentApprovalStatus: approvalStatus}
+	orders, total, err := s.orderRepo.ListByUserID(ctx, studentID, filter, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]dto.ChildOrderDTO, 0, len(orders))
+	for _, o := range orders {
+		items = append(items, toChildOrderResponse(o))
+	}
+	return &dto.ChildOrderListResponseDTO{
+		Orders:   items,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+// ApproveChildOrder and RejectChildOrder decide orders in parent_approval_status pending.
+// Nothing creates orders in this service yet: until checkout exists and starts the orders
+// of minors as pending, every order is not_required and these return ErrOrderNotAwaitingParent.
+func (s *ParentService) ApproveChildOrder(ctx context.Context, parentID, studentID, orderID uuid.UUID, req dto.ParentApprovalDTO) (*dto.ChildOrderDTO, error) {
+	return s.decideOrder(ctx, parentID, studentID, orderID, model.ParentApprovalApproved, req.Note)
+}
+
+func (s *ParentService) RejectChildOrder(ctx context.Context, parentID, studentID, orderID uuid.UUID, req dto.ParentApprovalDTO) (*dto.ChildOrderDTO, error) {
+	return s.decideOrder(ctx, parentID, studentID, orderID, model.ParentApprovalRejected, req.Note)
+}
+
+func (s *ParentService) decideOrder(ctx context.Context, parentID, studentID, orderID uuid.UUID, decision, note string) (*dto.ChildOrderDTO, error) {
+	relation, err := s.findChild(ctx, parentID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	if !relation.CanApprovePurchases {
+		return nil, ErrParentAccessDenied
+	}
+
+	order, err := s.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil || order.UserID != studentID {
+		return nil, ErrOrderNotFound
+	}
+	if order.Status != model.OrderStatusPending || order.ParentApprovalStatus != model.ParentApprovalPending {
+		return nil, ErrOrderNotAwaitingParent
+	}
+
+	now := time.Now()
+	order.ParentApprovalStatus = decision
+	order.ParentApprovedBy = &parentID
+	order.ParentApprovedAt = &now
+	order.ParentApprovalNote = emptyToNil(&note)
+	if err := s.orderRepo.DecideParentApproval(ctx, order); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrderNotAwaitingParent
+		}
+		return nil, err
+	}
+	if decision == model.ParentApprovalRejected {
+		order.Status = model.OrderStatusCancelled
+	}
+	res := toChildOrderResponse(*order)
+	return &res, nil
+}
+
+// sendLinkOTP emails a fresh code to the student. The cooldown key doubles as the resend limit.
+func (s *ParentService) sendLinkOTP(ctx context.Context, linkID uuid.UUID, parent, student *model.User, relationship string) error {
+	ok, err := s.redisClient.SetNX(ctx, parentLinkCooldownKey(linkID), 1, parentLinkResendCooldown).Result()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrOTPResendCooldown
+	}
+
+	otp, err := utils.GenerateOTP(otpLength)
+	if err != nil {
+		return err
+	}
+	pipe := s.redisClient.TxPipeline()
+	pipe.Set(ctx, parentLinkOTPKey(linkID), otp, parentLinkOTPTTL)
+	pipe.Del(ctx, parentLinkAttemptsKey(linkID))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+
+	parentName := parent.UserName
+	if parent.FullName != nil && *parent.FullName != "" {
+		parentName = *parent.FullName
+	}
+	minutes := int(parentLinkOTPTTL / time.Minute)
+	if err := utils.SendParentLinkOTP(s.cfg, student.Email, parentName, relationshipLabels[relationship], otp, minutes); err != nil {
+		s.redisClient.Del(ctx, parentLinkOTPKey(linkID), parentLinkCooldownKey(linkID))
+		return fmt.Errorf("failed to send OTP: %w", err)
+	}
+	return nil
+}
+
+func (s *ParentService) verifyLinkOTP(ctx context.Context, linkID uuid.UUID, otp string) error {
+	expected, err := s.redisClient.Get(ctx, parentLinkOTPKey(linkID)).Result()
+	if err == redis.Nil {
+		return ErrOTPExpired
+	}
+	if err != nil {
+		return err
+	}
+
+	if !utils.OTPMatches(otp, expected) {
+		attempts, err := s.redisClient.Incr(ctx, parentLinkAttemptsKey(linkID)).Result()
+		if err != nil {
+			return err
+		}
+		if attempts == 1 {
+			s.redisClient.Expire(ctx, parentLinkAttemptsKey(linkID), parentLinkOTPTTL)
+		}
+		if attempts >= parentLinkMaxOTPAttempts {
+			s.redisClient.Del(ctx, parentLinkOTPKey(linkID), parentLinkAttemptsKey(linkID))
+			return ErrOTPTooManyAttempts
+		}
+		return ErrOTPInvalid
+	}
+
+	s.redisClient.Del(ctx, parentLinkOTPKey(linkID), parentLinkAttemptsKey(linkID))
+	return nil
+}
+
+func (s *ParentService) findLink(ctx context.Context, id uuid.UUID) (*model.ParentStudentRelation, error) {
+	relation, err := s.parentRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if relation == nil {
+		return nil, ErrParentLinkNotFound
+	}
+	return relation, nil
+}
+
+// findChild returns the active link between the parent and the student. Unlinked students
+// look the same as missing ones.
+func (s *ParentService) findChild(ctx context.Context, parentID, studentID uuid.UUID) (*model.ParentStudentRelation, error) {
+	relation, err := s.parentRepo.FindActive(ctx, parentID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	if relation == nil {
+		return nil, ErrChildNotFound
+	}
+	return relation, nil
+}
+
+func (s *ParentService) linkResponse(ctx context.Context, id uuid.UUID) (*dto.ParentLinkResponseDTO, error) {
+	relation, err := s.findLink(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	res := toParentLinkResponse(*relation)
+	return &res, nil
+}
+
+// isMinor reports whether the user is younger than the configured threshold. Users without
+// a date of birth are treated as adults.
+func (s *ParentService) isMinor(user *model.User) bool {
+	if user.DateOfBirth == nil {
+		return false
+	}
+	return ageOn(*user.DateOfBirth, time.Now()) < s.cfg.MinorAgeThreshold
+}
+
+func ageOn(dob, now time.Time) int {
+	age := now.Year() - dob.Year()
+	if now.Month() < dob.Month() || (now.Month() == dob.Month() && now.Day() < dob.Day()) {
+		age--
+	}
+	return age
+}
+
+func toLinkedUser(u model.User) dto.LinkedUserDTO {
+	return dto.LinkedUserDTO{
+		ID:       u.ID,
+		Username: u.UserName,
+		Email:    u.Email,
+		FullName: u.FullName,
+	}
+}
+
+func toParentLinkResponse(r model.ParentStudentRelation) dto.ParentLinkResponseDTO {
+	return dto.ParentLinkResponseDTO{
+		ID:                  r.ID,
+		Parent:              toLinkedUser(r.Parent),
+		Student:             toLinkedUser(r.Student),
+		Relationship:        r.Relationship,
+		Status:              r.Status,
+		CanViewProgress:     r.CanViewProgress,
+		CanViewGrades:       r.CanViewGrades,
+		CanApprovePurchases: r.CanApprovePurchases,
+		ConfirmedAt:         formatTimePtr(r.ConfirmedAt),
+		ConfirmedBy:         r.ConfirmedBy,
+		CreatedAt:           r.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func toParentLinkResponses(relations []model.ParentStudentRelation) []dto.ParentLinkResponseDTO {
+	items := make([]dto.ParentLinkResponseDTO, 0, len(relations))
+	for _, r := range relations {
+		items = append(items, toParentLinkResponse(r))
+	}
+	return items
+}
+
+func toChildOrderResponse(o model.Order) dto.ChildOrderDTO {
+	items := make([]dto.ChildOrderItemDTO, 0, len(o.Items))
+	for _, item := range o.Items {
+		items = append(items, dto.ChildOrderItemDTO{
+			CourseID:    item.CourseID,
+			CourseTitle: item.Course.Title,
+			Price:       item.Price.StringFixed(2),
+			FinalPrice:  item.FinalPrice.StringFixed(2),
+		})
+	}
+	return dto.ChildOrderDTO{
+		ID:                   o.ID,
+		OrderNumber:          o.OrderNumber,
+		Status:               o.Status,
+		TotalAmount:          o.TotalAmount.StringFixed(2),
+		Currency:             o.Currency,
+		PaidAt:               formatTimePtr(o.PaidAt),
+		ParentApprovalStatus: o.ParentApprovalStatus,
+		ParentApprovedBy:     o.ParentApprovedBy,
+		ParentApprovedAt:     formatTimePtr(o.ParentApprovedAt),
+		ParentApprovalNote:   o.ParentApprovalNote,
+		Items:                items,
+	}
+}
+
+func formatDecimalPtr(value *decimal.Decimal) *string {
+	if value == nil {
+		return nil
+	}
+	formatted := value.StringFixed(2)
+	return &formatted
+}
+
+func formatTimePtr(value *time.Time) *string {
+	if value == nil {
+		return nil
+	}
+	formatted := value.Format(time.RFC3339)
+	return &formatted
+}
@@ -0,0 +1,375 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"study.com/v1/internal/dto"
+	"study.com/v1/internal/model"
+	"study.com/v1/internal/repository"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+var (
+	ErrRoleNameExists        = errors.New("role name already exists")
+	ErrInvalidRoleName       = errors.New("role name must be between 2 and 100 characters")
+	ErrDescriptionTooLong    = errors.New("description must be at most 500 characters")
+	ErrSystemRoleProtected   = errors.New("system roles cannot be renamed or deleted")
+	ErrPermissionNotFound    = errors.New("permission not found")
+	ErrEmptyPermissionList   = errors.New("permission_ids must not be empty")
+	ErrEmptyDescription      = errors.New("description must not be empty")
+	ErrWildcardNotAssignable = errors.New("the wildcard permission cannot be edited through the API")
+	ErrInvalidConditions     = errors.New("conditions contain an unsupported key or value, or are set on a deny")
+)
+
+// systemRoles are the roles seeded from data/roles.json. Their permissions can be
+// edited but renaming or deleting them would break the code that refers to them.
+var systemRoles = map[string]struct{}{
+	model.RoleSystemAdmin: {},
+	model.RoleOrgOwner:    {},
+	model.RoleTeacher:     {},
+	model.RoleStudent:     {},
+	model.RoleParent:      {},
+}
+
+type RoleServiceInterface interface {
+	CreateRole(ctx context.Context, req dto.CreateRoleDTO) (*dto.RoleResponseDTO, error)
+	UpdateRole(ctx context.Context, id uuid.UUID, req dto.UpdateRoleDTO) (*dto.RoleResponseDTO, error)
+	DeleteRole(ctx context.Context, id uuid.UUID) error
+	GetRole(ctx context.Context, id uuid.UUID) (*dto.RoleDetailResponseDTO, error)
+	ListRoles(ctx context.Context, search string, page, pageSize int) (*dto.RoleListResponseDTO, error)
+	AddPermissionsToRole(ctx context.Context, id uuid.UUID, req dto.AddPermissionsToRoleDTO) (*dto.RoleDetailResponseDTO, error)
+	RemovePermissionsFromRole(ctx context.Context, id uuid.UUID, req dto.RemovePermissionsFromRoleDTO) (*dto.RoleDetailResponseDTO, error)
+	GetPermission(ctx context.Context, id uuid.UUID) (*dto.PermissionResponseDTO, error)
+	ListPermissions(ctx context.Context, search string, page, pageSize int) (*dto.PermissionListResponseDTO, error)
+	UpdatePermission(ctx context.Context, id uuid.UUID, req dto.UpdatePermissionDTO) (*dto.PermissionResponseDTO, error)
+}
+
+type RoleService struct {
+	roleRepo       repository.RoleRepositoryInterface
+	permissionRepo repository.PermissionRepositoryInterface
+	authz          AuthorizationServiceInterface
+}
+
+func NewRoleService(
+	roleRepo repository.RoleRepositoryInterface,
+	permissionRepo repository.PermissionRepositoryInterface,
+	authz AuthorizationServiceInterface,
+) *RoleService {
+	return &RoleService{
+		roleRepo:       roleRepo,
+		permissionRepo: permissionRepo,
+		authz:          authz,
+	}
+}
+
+func (s *RoleService) CreateRole(ctx context.Context, req dto.CreateRoleDTO) (*dto.RoleResponseDTO, error) {
+	name := strings.TrimSpace(req.Name)
+	if err := validateRoleFields(name, req.Description); err != nil {
+		return nil, err
+	}
+	if err := s.ensureRoleNameFree(ctx, name, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	role := &model.Role{
+		Name:        name,
+		Description: nullString(req.Description),
+	}
+	if err := s.roleRepo.Create(ctx, role); err != nil {
+		return nil, err
+	}
+
+	res := toRoleResponse(*role)
+	return &res, nil
+}
+
+func (s *RoleService) UpdateRole(ctx context.Context, id uuid.UUID, req dto.UpdateRoleDTO) (*dto.RoleResponseDTO, error) {
+	role, err := s.findRole(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name != role.Name {
+			if isSystemRole(role.Name) {
+				return nil, ErrSystemRoleProtected
+			}
+			if err := s.ensureRoleNameFree(ctx, name, role.ID); err != nil {
+				return nil, err
+			}
+			role.Name = name
+		}
+	}
+	if req.Description != nil {
+		role.Description = nullString(*req.Description)
+	}
+	if err := validateRoleFields(role.Name, role.Description.String); err != nil {
+		return nil, err
+	}
+
+	if err := s.roleRepo.Update(ctx, role); err != nil {
+		return nil, err
+	}
+
+	res := toRoleResponse(*role)
+	return &res, nil
+}
+
+func (s *RoleService) DeleteRole(ctx context.Context, id uuid.UUID) error {
+	role, err := s.findRole(ctx, id)
+	if err != nil {
+		return err
+	}
+	if isSystemRole(role.Name) {
+		return ErrSystemRoleProtected
+	}
+	if err := s.roleRepo.Delete(ctx, role.ID); err != nil {
+		return err
+	}
+	return s.authz.InvalidateAllPermissions(ctx)
+}
+
+func (s *RoleService) GetRole(ctx context.Context, id uuid.UUID) (*dto.RoleDetailResponseDTO, error) {
+	role, err := s.findRole(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	res := toRoleDetailResponse(*role)
+	return &res, nil
+}
+
+func (s *RoleService) ListRoles(ctx context.Context, search string, page, pageSize int) (*dto.RoleListResponseDTO, error) {
+	page, pageSize = normalizePage(page, pageSize)
+	roles, total, err := s.roleRepo.List(ctx, nil, strings.TrimSpace(search), (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]dto.RoleResponseDTO, 0, len(roles))
+	for _, r := range roles {
+		items = append(items, toRoleResponse(r))
+	}
+	return &dto.RoleListResponseDTO{
+		Roles:    items,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+func (s *RoleService) AddPermissionsToRole(ctx context.Context, id uuid.UUID, req dto.AddPermissionsToRoleDTO) (*dto.RoleDetailResponseDTO, error) {
+	role, err := s.findRole(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	permissions, err := s.findPermissions(ctx, req.PermissionIDs)
+	if err != nil {
+		return nil, err
+	}
+	isGranted := req.IsGranted == nil || *req.IsGranted
+	if err := validateConditions(isGranted, req.Conditions); err != nil {
+		return nil, err
+	}
+
+	grants := make([]model.RolePermission, 0, len(permissions))
+	for _, p := range permissions {
+		grants = append(grants, model.RolePermission{
+			RoleID:       role.ID,
+			PermissionID: p.ID,
+			IsGranted:    isGranted,
+			Conditions:   model.PermissionConditions(req.Conditions),
+		})
+	}
+	if err := s.roleRepo.AddPermissions(ctx, grants); err != nil {
+		return nil, err
+	}
+	if err := s.authz.InvalidateAllPermissions(ctx); err != nil {
+		return nil, err
+	}
+	return s.GetRole(ctx, id)
+}
+
+func (s *RoleService) RemovePermissionsFromRole(ctx context.Context, id uuid.UUID, req dto.RemovePermissionsFromRoleDTO) (*dto.RoleDetailResponseDTO, error) {
+	role, err := s.findRole(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.findPermissions(ctx, req.PermissionIDs); err != nil {
+		return nil, err
+	}
+	if err := s.roleRepo.RemovePermissions(ctx, role.ID, req.PermissionIDs); err != nil {
+		return nil, err
+	}
+	if err := s.authz.InvalidateAllPermissions(ctx); err != nil {
+		return nil, err
+	}
+	return s.GetRole(ctx, id)
+}
+
+func (s *RoleService) GetPermission(ctx context.Context, id uuid.UUID) (*dto.PermissionResponseDTO, error) {
+	permission, err := s.permissionRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if permission == nil {
+		return nil, ErrPermissionNotFound
+	}
+	res := toPermissionResponse(*permission)
+	return &res, nil
+}
+
+func (s *RoleService) ListPermissions(ctx context.Context, search string, page, pageSize int) (*dto.PermissionListResponseDTO, error) {
+	page, pageSize = normalizePage(page, pageSize)
+	permissions, total, err := s.permissionRepo.List(ctx, strings.TrimSpace(search), (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]dto.PermissionResponseDTO, 0, len(permissions))
+	for _, p := range permissions {
+		items = append(items, toPermissionResponse(p))
+	}
+	return &dto.PermissionListResponseDTO{
+		Permissions: items,
+		Total:       total,
+		Page:        page,
+		PageSize:    pageSize,
+	}, nil
+}
+
+func (s *RoleService) UpdatePermission(ctx context.Context, id uuid.UUID, req dto.UpdatePermissionDTO) (*dto.PermissionResponseDTO, error) {
+	description := strings.TrimSpace(req.Description)
+	if description == "" {
+		return nil, ErrEmptyDescription
+	}
+	if len(description) > 500 {
+		return nil, ErrDescriptionTooLong
+	}
+
+	permission, err := s.permissionRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if permission == nil {
+		return nil, ErrPermissionNotFound
+	}
+	if permission.Name == model.PermissionWildcard {
+		return nil, ErrWildcardNotAssignable
+	}
+
+	if err := s.permissionRepo.UpdateDescription(ctx, id, description); err != nil {
+		return nil, err
+	}
+	return s.GetPermission(ctx, id)
+}
+
+// findRole only returns global roles, the custom roles of an organization are managed
+// through OrganizationRoleService.
+func (s *RoleService) findRole(ctx context.Context, id uuid.UUID) (*model.Role, error) {
+	role, err := s.roleRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil || role.OrganizationID != nil {
+		return nil, ErrRoleNotFound
+	}
+	return role, nil
+}
+
+func (s *RoleService) findPermissions(ctx context.Context, ids []uuid.UUID) ([]model.Permission, error) {
+	if len(ids) == 0 {
+		return nil, ErrEmptyPermissionList
+	}
+	permissions, err := s.permissionRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[uuid.UUID]struct{}, len(permissions))
+	for _, p := range permissions {
+		if p.Name == model.PermissionWildcard {
+			return nil, ErrWildcardNotAssignable
+		}
+		found[p.ID] = struct{}{}
+	}
+	for _, id := range ids {
+		if _, ok := found[id]; !ok {
+			return nil, ErrPermissionNotFound
+		}
+	}
+	return permissions, nil
+}
+
+func (s *RoleService) ensureRoleNameFree(ctx context.Context, name string, selfID uuid.UUID) error {
+	existing, err := s.roleRepo.FindByName(ctx, nil, name)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != selfID {
+		return ErrRoleNameExists
+	}
+	return nil
+}
+
+func validateRoleFields(name, description string) error {
+	if len(name) < 2 || len(name) > 100 {
+		return ErrInvalidRoleName
+	}
+	if len(description) > 500 {
+		return ErrDescriptionTooLong
+	}
+	return nil
+}
+
+// validateConditions only accepts condition keys the authorization service knows how to
+// evaluate, so that a typo cannot silently widen a grant.
+func validateConditions(isGranted bool, conditions map[string]interface{}) error {
+	if len(conditions) == 0 {
+		return nil
+	}
+	if !isGranted {
+		return ErrInvalidConditions
+	}
+	for key, value := range conditions {
+		switch key {
+		case model.ConditionOwnResourceOnly:
+			if _, ok := value.(bool); !ok {
+				return ErrInvalidConditions
+			}
+		default:
+			return ErrInvalidConditions
+		}
+	}
+	return nil
+}
+
+func isSystemRole(name string) bool {
+	_, ok := systemRoles[name]
+	return ok
+}
+
+func nullString(value string) sql.NullString {
+	value = strings.TrimSpace(value)
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+func normalizePage(page, pageSize int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	return page, pageSize
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+	"study.com/v1/internal/dto"
+	"study.com/v1/internal/repository"
+	"study.com/v1/internal/utils"
+)
+
+const (
+	maxSearchLength       = 200
+	minSuggestionLength   = 2
+	defaultSuggestionSize = 8
+	maxSuggestionSize     = 20
+)
+
+type SearchServiceInterface interface {
+	Suggest(ctx context.Context, text string, limit int) (*dto.SearchSuggestionsDTO, error)
+}
+
+// SearchService serves search-as-you-type. Matching is accent-insensitive: the database
+// strips diacritics from both the index and the query, see migration 0007.
+type SearchService struct {
+	searchRepo repository.SearchRepositoryInterface
+}
+
+func NewSearchService(searchRepo repository.SearchRepositoryInterface) *SearchService {
+	return &SearchService{searchRepo: searchRepo}
+}
+
+// Suggest completes the text being typed with course titles and tags. Fewer than two
+// characters return empty lists rather than half the catalog.
+func (s *SearchService) Suggest(ctx context.Context, text string, limit int) (*dto.SearchSuggestionsDTO, error) {
+	text, err := normalizeSearchText(text)
+	if err != nil {
+		return nil, err
+	}
+	if limit < 1 {
+		limit = defaultSuggestionSize
+	}
+	if limit > maxSuggestionSize {
+		limit = maxSuggestionSize
+	}
+
+	res := &dto.SearchSuggestionsDTO{
+		Courses: make([]dto.CourseSuggestionDTO, 0, limit),
+		Tags:    make([]dto.TagSuggestionDTO, 0, limit),
+	}
+	if utf8.RuneCountInString(text) < minSuggestionLength {
+		return res, nil
+	}
+
+	courses, err := s.searchRepo.SuggestCourses(ctx, prefixTSQuery(text), text, limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range courses {
+		res.Courses = append(res.Courses, dto.CourseSuggestionDTO{ID: c.ID, Slug: c.Slug, Title: c.Title})
+	}
+
+	if slug := utils.Slugify(text); slug != "" {
+		tags, err := s.searchRepo.SuggestTags(ctx, slug, limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range tags {
+			res.Tags = append(res.Tags, dto.TagSuggestionDTO{Name: t.Name, Slug: t.Slug, CourseCount: t.CourseCount})
+		}
+	}
+	return res, nil
+}
+
+// normalizeSearchText composes decomposed diacritics (some keyboards send "a" + U+0302)
+// and collapses whitespace, so the database sees the same text however it was typed.
+func normalizeSearchText(text string) (string, error) {
+	text = strings.Join(strings.Fields(norm.NFC.String(text)), " ")
+	if utf8.RuneCountInString(text) > maxSearchLength {
+		return "", invalidCatalogQuery("q must be at most 200 characters")
+	}
+	return text, nil
+}
+
+// prefixTSQuery turns "lập trì" into "lập & trì:*" for to_tsquery. Only letters and digits
+// are kept, so the user cannot inject tsquery operators.
+func prefixTSQuery(text string) string {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r)
+	})
+	if len(words) == 0 {
+		return ""
+	}
+	return strings.Join(words, " & ") + ":*"
+}
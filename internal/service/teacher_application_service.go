@@ -0,0 +1,536 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
+	"gorm.io/gorm"
+	"study.com/v1/internal/config"
+	"study.com/v1/internal/dto"
+	"study.com/v1/internal/model"
+	"study.com/v1/internal/repository"
+	"study.com/v1/internal/utils"
+)
+
+const (
+	maxApplicationDocumentSize = 10 << 20 // 10 MB
+	maxApplicationCredentials  = 10
+	documentDownloadURLTTL     = 15 * time.Minute
+)
+
+// applicationDocumentTypes maps the sniffed content type of an upload to the extension
+// used in its object key. The client supplied Content-Type is never trusted.
+var applicationDocumentTypes = map[string]string{
+	"application/pdf": ".pdf",
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+}
+
+var (
+	ErrApplicationNotFound        = errors.New("teacher application not found")
+	ErrApplicationPending         = errors.New("you already have an application waiting for review")
+	ErrApplicationNotPending      = errors.New("the application has already been reviewed")
+	ErrAlreadyTeacher             = errors.New("you are already a teacher")
+	ErrCannotReviewOwnApplication = errors.New("you cannot review your own application")
+	ErrReviewNoteRequired         = errors.New("a note is required when rejecting an application")
+	ErrInvalidApplication         = errors.New("headline must be 5-255 characters, bio 50-5000 characters, years_of_experience 0-60 and at most 10 expertise entries")
+	ErrInvalidApplicationStatus   = errors.New("status must be pending, approved or rejected")
+	ErrCVRequired                 = errors.New("a CV file is required")
+	ErrTooManyDocuments           = errors.New("an application can have at most 10 credential files")
+	ErrInvalidDocumentType        = errors.New("doc_type must be cv or credential")
+	ErrDocumentTooLarge           = errors.New("files must be smaller than 10 MB")
+	ErrUnsupportedDocumentType    = errors.New("only PDF, JPEG and PNG files are accepted")
+	ErrStorageUnavailable         = errors.New("file storage is not available")
+)
+
+type TeacherApplicationServiceInterface interface {
+	SubmitApplication(ctx context.Context, userID uuid.UUID, req dto.CreateTeacherApplicationDTO, cv *multipart.FileHeader, credentials []*multipart.FileHeader) (*dto.TeacherApplicationResponseDTO, error)
+	ListMyApplications(ctx context.Context, userID uuid.UUID) ([]dto.TeacherApplicationResponseDTO, error)
+	UpdateMyApplication(ctx context.Context, userID uuid.UUID, req dto.UpdateTeacherApplicationDTO) (*dto.TeacherApplicationResponseDTO, error)
+	AddMyDocument(ctx context.Context, userID uuid.UUID, docType string, file *multipart.FileHeader) (*dto.TeacherApplicationResponseDTO, error)
+
+	ListApplications(ctx context.Context, query dto.TeacherApplicationQueryDTO) (*dto.TeacherApplicationListResponseDTO, error)
+	GetApplication(ctx context.Context, id uuid.UUID) (*dto.TeacherApplicationResponseDTO, error)
+	ApproveApplication(ctx context.Context, reviewerID, id uuid.UUID, req dto.ReviewTeacherApplicationDTO) (*dto.TeacherApplicationResponseDTO, error)
+	RejectApplication(ctx context.Context, reviewerID, id uuid.UUID, req dto.ReviewTeacherApplicationDTO) (*dto.TeacherApplicationResponseDTO, error)
+}
+
+// TeacherApplicationService lets students apply to become teachers and lets platform
+// staff review the applications. Approval gives the applicant the global TEACHER role.
+type TeacherApplicationService struct {
+	cfg             *config.Config
+	applicationRepo repository.TeacherApplicationRepositoryInterface
+	roleRepo        repository.RoleRepositoryInterface
+	userRoleRepo    repository.UserRoleRepositoryInterface
+	authz           AuthorizationServiceInterface
+	minio           *minio.Client
+}
+
+func NewTeacherApplicationService(
+	cfg *config.Config,
+	applicationRepo repository.TeacherApplicationRepositoryInterface,
+	roleRepo repository.RoleRepositoryInterface,
+	userRoleRepo repository.UserRoleRepositoryInterface,
+	authz AuthorizationServiceInterface,
+	minioClient *minio.Client,
+) *TeacherApplicationService {
+	return &TeacherApplicationService{
+		cfg:             cfg,
+		applicationRepo: applicationRepo,
+		roleRepo:        roleRepo,
+		userRoleRepo:    userRoleRepo,
+		authz:           authz,
+		minio:           minioClient,
+	}
+}
+
+func (s *TeacherApplicationService) SubmitApplication(ctx context.Context, userID uuid.UUID, req dto.CreateTeacherApplicationDTO, cv *multipart.FileHeader, credentials []*multipart.FileHeader) (*dto.TeacherApplicationResponseDTO, error) {
+	application := &model.TeacherApplication{
+		UserID:            userID,
+		Status:            model.TeacherApplicationPending,
+		Headline:          strings.TrimSpace(req.Headline),
+		Bio:               strings.TrimSpace(req.Bio),
+		Expertise:         cleanExpertise(req.Expertise),
+		YearsOfExperience: req.YearsOfExperience,
+		Education:         emptyToNil(&req.Education),
+		Phone:             emptyToNil(&req.Phone),
+		WebsiteURL:        emptyToNil(&req.WebsiteURL),
+	}
+	if err := validateApplication(application); err != nil {
+		return nil, err
+	}
+	if cv == nil {
+		return nil, ErrCVRequired
+	}
+	if len(credentials) > maxApplicationCredentials {
+		return nil, ErrTooManyDocuments
+	}
+
+	isTeacher, err := s.isTeacher(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if isTeacher {
+		return nil, ErrAlreadyTeacher
+	}
+	pending, err := s.applicationRepo.FindPendingByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if pending != nil {
+		return nil, ErrApplicationPending
+	}
+
+	files := append([]*multipart.FileHeader{cv}, credentials...)
+	for i, file := range files {
+		docType := model.ApplicationDocumentCredential
+		if i == 0 {
+			docType = model.ApplicationDocumentCV
+		}
+		document, err := s.uploadDocument(ctx, userID, docType, file)
+		if err != nil {
+			s.removeDocuments(application.Documents)
+			return nil, err
+		}
+		application.Documents = append(application.Documents, *document)
+	}
+
+	if err := s.applicationRepo.Create(ctx, application); err != nil {
+		s.removeDocuments(application.Documents)
+		return nil, err
+	}
+
+	created, err := s.applicationRepo.FindByID(ctx, application.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := utils.SendTeacherApplicationReceived(s.cfg, created.User.Email); err != nil {
+		log.Printf("Warning: failed to send teacher application email to %s: %v", created.User.Email, err)
+	}
+	return s.toResponse(ctx, created)
+}
+
+func (s *TeacherApplicationService) ListMyApplications(ctx context.Context, userID uuid.UUID) ([]dto.TeacherApplicationResponseDTO, error) {
+	applications, err := s.applicationRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]dto.TeacherApplicationResponseDTO, 0, len(applications))
+	for i := range applications {
+		item, err := s.toResponse(ctx, &applications[i])
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *item)
+	}
+	return res, nil
+}
+
+// UpdateMyApplication edits the pending application; reviewed ones are read-only.
+func (s *TeacherApplicationService) UpdateMyApplication(ctx context.Context, userID uuid.UUID, req dto.UpdateTeacherApplicationDTO) (*dto.TeacherApplicationResponseDTO, error) {
+	application, err := s.findPending(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Headline != nil {
+		application.Headline = strings.TrimSpace(*req.Headline)
+	}
+	if req.Bio != nil {
+		application.Bio = strings.TrimSpace(*req.Bio)
+	}
+	if req.Expertise != nil {
+		application.Expertise = cleanExpertise(*req.Expertise)
+	}
+	if req.YearsOfExperience != nil {
+		application.YearsOfExperience = *req.YearsOfExperience
+	}
+	if req.Education != nil {
+		application.Education = emptyToNil(req.Education)
+	}
+	if req.Phone != nil {
+		application.Phone = emptyToNil(req.Phone)
+	}
+	if req.WebsiteURL != nil {
+		application.WebsiteURL = emptyToNil(req.WebsiteURL)
+	}
+	if err := validateApplication(application); err != nil {
+		return nil, err
+	}
+
+	if err := s.applicationRepo.Update(ctx, application); err != nil {
+		return nil, err
+	}
+	return s.toResponse(ctx, application)
+}
+
+func (s *TeacherApplicationService) AddMyDocument(ctx context.Context, userID uuid.UUID, docType string, file *multipart.FileHeader) (*dto.TeacherApplicationResponseDTO, error) {
+	if docType != model.ApplicationDocumentCV && docType != model.ApplicationDocumentCredential {
+		return nil, ErrInvalidDocumentType
+	}
+	if file == nil {
+		return nil, ErrUnsupportedDocumentType
+	}
+	application, err := s.findPending(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if docType == model.ApplicationDocumentCredential {
+		credentials := 0
+		for _, d := range application.Documents {
+			if d.DocType == model.ApplicationDocumentCredential {
+				credentials++
+			}
+		}
+		if credentials >= maxApplicationCredentials {
+			return nil, ErrTooManyDocuments
+		}
+	}
+
+	document, err := s.uploadDocument(ctx, userID, docType, file)
+	if err != nil {
+		return nil, err
+	}
+	document.ApplicationID = application.ID
+	if err := s.applicationRepo.AddDocument(ctx, document); err != nil {
+		s.removeDocuments([]model.TeacherApplicationDocument{*document})
+		return nil, err
+	}
+	application.Documents = append(application.Documents, *document)
+	return s.toResponse(ctx, application)
+}
+
+func (s *TeacherApplicationService) ListApplications(ctx context.Context, query dto.TeacherApplicationQueryDTO) (*dto.TeacherApplicationListResponseDTO, error) {
+	switch query.Status {
+	case "", model.TeacherApplicationPending, model.TeacherApplicationApproved, model.TeacherApplicationRejected:
+	default:
+		return nil, ErrInvalidApplicationStatus
+	}
+
+	page, pageSize := normalizePage(query.Page, query.PageSize)
+	filter := repository.TeacherApplicationFilter{
+		Status: query.Status,
+		Search: strings.TrimSpace(query.Search),
+	}
+	applications, total, err := s.applicationRepo.List(ctx, filter, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]dto.TeacherApplicationResponseDTO, 0, len(applications))
+	for _, a := range applications {
+		items = append(items, toTeacherApplicationResponse(a))
+	}
+	return &dto.TeacherApplicationListResponseDTO{
+		Applications: items,
+		Total:        total,
+		Page:         page,
+		PageSize:     pageSize,
+	}, nil
+}
+
+func (s *TeacherApplicationService) GetApplication(ctx context.Context, id uuid.UUID) (*dto.TeacherApplicationResponseDTO, error) {
+	application, err := s.findApplication(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.toResponse(ctx, application)
+}
+
+func (s *TeacherApplicationService) ApproveApplication(ctx context.Context, reviewerID, id uuid.UUID, req dto.ReviewTeacherApplicationDTO) (*dto.TeacherApplicationResponseDTO, error) {
+	teacherRole, err := s.roleRepo.FindByName(ctx, nil, model.RoleTeacher)
+	if err != nil {
+		return nil, err
+	}
+	if teacherRole == nil {
+		return nil, fmt.Errorf("role %s has not been seeded", model.RoleTeacher)
+	}
+	return s.review(ctx, reviewerID, id, model.TeacherApplicationApproved, req.Note, &teacherRole.ID)
+}
+
+func (s *TeacherApplicationService) RejectApplication(ctx context.Context, reviewerID, id uuid.UUID, req dto.ReviewTeacherApplicationDTO) (*dto.TeacherApplicationResponseDTO, error) {
+	if strings.TrimSpace(req.Note) == "" {
+		return nil, ErrReviewNoteRequired
+	}
+	return s.review(ctx, reviewerID, id, model.TeacherApplicationRejected, req.Note, nil)
+}
+
+func (s *TeacherApplicationService) review(ctx context.Context, reviewerID, id uuid.UUID, status, note string, teacherRoleID *uuid.UUID) (*dto.TeacherApplicationResponseDTO, error) {
+	application, err := s.findApplication(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if application.Status != model.TeacherApplicationPending {
+		return nil, ErrApplicationNotPending
+	}
+	if application.UserID == reviewerID {
+		return nil, ErrCannotReviewOwnApplication
+	}
+
+	now := time.Now()
+	application.Status = status
+	application.ReviewedBy = &reviewerID
+	application.ReviewNote = emptyToNil(&note)
+	application.ReviewedAt = &now
+	if err := s.applicationRepo.Review(ctx, application, teacherRoleID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrApplicationNotPending
+		}
+		return nil, err
+	}
+	if teacherRoleID != nil {
+		if err := s.authz.InvalidateUserPermissions(ctx, application.UserID); err != nil {
+			return nil, err
+		}
+	}
+
+	approved := status == model.TeacherApplicationApproved
+	reviewNote := ""
+	if application.ReviewNote != nil {
+		reviewNote = *application.ReviewNote
+	}
+	if err := utils.SendTeacherApplicationDecision(s.cfg, application.User.Email, approved, reviewNote); err != nil {
+		log.Printf("Warning: failed to send teacher application decision to %s: %v", application.User.Email, err)
+	}
+	return s.toResponse(ctx, application)
+}
+
+func (s *TeacherApplicationService) findApplication(ctx context.Context, id uuid.UUID) (*model.TeacherApplication, error) {
+	application, err := s.applicationRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if application == nil {
+		return nil, ErrApplicationNotFound
+	}
+	return application, nil
+}
+
+func (s *TeacherApplicationService) findPending(ctx context.Context, userID uuid.UUID) (*model.TeacherApplication, error) {
+	application, err := s.applicationRepo.FindPendingByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if application == nil {
+		return nil, ErrApplicationNotFound
+	}
+	return application, nil
+}
+
+func (s *TeacherApplicationService) isTeacher(ctx context.Context, userID uuid.UUID) (bool, error) {
+	userRoles, err := s.userRoleRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	for _, ur := range userRoles {
+		if ur.Role.Name == model.RoleTeacher {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// uploadDocument checks the size and the sniffed type of the file and stores it in the
+// private documents bucket.
+func (s *TeacherApplicationService) uploadDocument(ctx context.Context, userID uuid.UUID, docType string, header *multipart.FileHeader) (*model.TeacherApplicationDocument, error) {
+	if s.minio == nil {
+		return nil, ErrStorageUnavailable
+	}
+	if header.Size <= 0 || header.Size > maxApplicationDocumentSize {
+		return nil, ErrDocumentTooLarge
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, err
+	}
+	contentType := http.DetectContentType(head[:n])
+	ext, ok := applicationDocumentTypes[contentType]
+	if !ok {
+		return nil, ErrUnsupportedDocumentType
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("teacher-applications/%s/%s%s", userID, uuid.New(), ext)
+	_, err = s.minio.PutObject(ctx, s.cfg.MinioBucketDocuments, key, file, header.Size, minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload %s: %w", header.Filename, err)
+	}
+
+	fileName := filepath.Base(header.Filename)
+	if len(fileName) > 255 {
+		fileName = fileName[len(fileName)-255:]
+	}
+	return &model.TeacherApplicationDocument{
+		DocType:     docType,
+		FileName:    fileName,
+		ObjectKey:   key,
+		ContentType: contentType,
+		SizeBytes:   header.Size,
+	}, nil
+}
+
+// removeDocuments cleans up uploads whose database rows were never written.
+func (s *TeacherApplicationService) removeDocuments(documents []model.TeacherApplicationDocument) {
+	for _, d := range documents {
+		if err := s.minio.RemoveObject(context.Background(), s.cfg.MinioBucketDocuments, d.ObjectKey, minio.RemoveObjectOptions{}); err != nil {
+			log.Printf("Warning: failed to remove orphaned object %s: %v", d.ObjectKey, err)
+		}
+	}
+}
+
+// toResponse includes the documents with presigned download links.
+func (s *TeacherApplicationService) toResponse(ctx context.Context, application *model.TeacherApplication) (*dto.TeacherApplicationResponseDTO, error) {
+	res := toTeacherApplicationResponse(*application)
+	res.Documents = make([]dto.TeacherApplicationDocumentResponseDTO, 0, len(application.Documents))
+	for _, d := range application.Documents {
+		document := dto.TeacherApplicationDocumentResponseDTO{
+			ID:          d.ID,
+			DocType:     d.DocType,
+			FileName:    d.FileName,
+			ContentType: d.ContentType,
+			SizeBytes:   d.SizeBytes,
+			CreatedAt:   d.CreatedAt.Format(time.RFC3339),
+		}
+		if s.minio != nil {
+			u, err := s.minio.PresignedGetObject(ctx, s.cfg.MinioBucketDocuments, d.ObjectKey, documentDownloadURLTTL, nil)
+			if err != nil {
+				return nil, err
+			}
+			document.DownloadURL = u.String()
+		}
+		res.Documents = append(res.Documents, document)
+	}
+	return &res, nil
+}
+
+func validateApplication(a *model.TeacherApplication) error {
+	if len(a.Headline) < 5 || len(a.Headline) > 255 {
+		return ErrInvalidApplication
+	}
+	if len(a.Bio) < 50 || len(a.Bio) > 5000 {
+		return ErrInvalidApplication
+	}
+	if a.YearsOfExperience < 0 || a.YearsOfExperience > 60 || len(a.Expertise) > 10 {
+		return ErrInvalidApplication
+	}
+	if a.Education != nil && len(*a.Education) > 2000 {
+		return ErrInvalidApplication
+	}
+	if a.Phone != nil && len(*a.Phone) > 20 {
+		return ErrInvalidApplication
+	}
+	if a.WebsiteURL != nil && len(*a.WebsiteURL) > 500 {
+		return ErrInvalidApplication
+	}
+	return nil
+}
+
+func cleanExpertise(values []string) []string {
+	res := make([]string, 0, len(values))
+	seen := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		key := strings.ToLower(v)
+		if v == "" {
+			continue
+		}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		res = append(res, v)
+	}
+	return res
+}
+
+func toTeacherApplicationResponse(a model.TeacherApplication) dto.TeacherApplicationResponseDTO {
+	res := dto.TeacherApplicationResponseDTO{
+		ID:                a.ID,
+		UserID:            a.UserID,
+		Username:          a.User.UserName,
+		Email:             a.User.Email,
+		FullName:          a.User.FullName,
+		Status:            a.Status,
+		Headline:          a.Headline,
+		Bio:               a.Bio,
+		Expertise:         a.Expertise,
+		YearsOfExperience: a.YearsOfExperience,
+		Education:         a.Education,
+		Phone:             a.Phone,
+		WebsiteURL:        a.WebsiteURL,
+		ReviewedBy:        a.ReviewedBy,
+		ReviewNote:        a.ReviewNote,
+		CreatedAt:         a.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:         a.UpdatedAt.Format(time.RFC3339),
+	}
+	if res.Expertise == nil {
+		res.Expertise = []string{}
+	}
+	if a.ReviewedAt != nil {
+		reviewedAt := a.ReviewedAt.Format(time.RFC3339)
+		res.ReviewedAt = &reviewedAt
+	}
+	return res
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
+	"gorm.io/gorm"
+	"study.com/v1/internal/config"
+	"study.com/v1/internal/media"
+	"study.com/v1/internal/model"
+	"study.com/v1/internal/repository"
+	"study.com/v1/internal/transcription"
+	"study.com/v1/internal/worker"
+)
+
+// Caption tracks whose language the backend could not tell are tagged "und", as in BCP 47.
+const undeterminedLanguage = "und"
+
+type TranscriptionServiceInterface interface {
+	Transcribe(ctx context.Context, job *model.Job) error
+}
+
+// TranscriptionService transcribes the source video of a lesson, run by the worker for the
+// video_transcribe jobs queued next to the transcode ones. It drives
+// LessonVideo.TranscriptionStatus: pending when uploaded, processing while a worker has the
+// job, then completed, or failed once the job gives up.
+type TranscriptionService struct {
+	cfg         *config.Config
+	courseRepo  repository.CourseRepositoryInterface
+	videoRepo   repository.VideoRepositoryInterface
+	minio       *minio.Client
+	ffmpeg      *media.FFmpeg
+	transcriber transcription.Transcriber
+}
+
+func NewTranscriptionService(
+	cfg *config.Config,
+	courseRepo repository.CourseRepositoryInterface,
+	videoRepo repository.VideoRepositoryInterface,
+	minioClient *minio.Client,
+	ffmpeg *media.FFmpeg,
+	transcriber transcription.Transcriber,
+) *TranscriptionService {
+	return &TranscriptionService{
+		cfg:         cfg,
+		courseRepo:  courseRepo,
+		videoRepo:   videoRepo,
+		minio:       minioClient,
+		ffmpeg:      ffmpeg,
+		transcriber: transcriber,
+	}
+}
+
+// newTranscribeJob is queued with newTranscodeJob, the two run independently.
+func newTranscribeJob(lessonID uuid.UUID, objectKey string) *model.Job {
+	return &model.Job{
+		Type: model.JobTypeVideoTranscribe,
+		Payload: model.JobPayload{
+			"lesson_id":  lessonID.String(),
+			"object_key": objectKey,
+		},
+	}
+}
+
+// captionKey is where the caption track of a source goes, among its renditions so that
+// removeVideoOutputs deletes it with them.
+func captionKey(sourceKey, language string) string {
+	return videoOutputPrefix(sourceKey) + "captions/" + language + ".vtt"
+}
+
+// Transcribe marks the transcription failed when the job will not be retried, so that
+// authors see it rather than a transcription stuck in processing.
+func (s *TranscriptionService) Transcribe(ctx context.Context, job *model.Job) error {
+	lessonID, err := uuid.Parse(job.Payload["lesson_id"])
+	sourceKey := job.Payload["object_key"]
+	if err != nil || sourceKey == "" {
+		return worker.Permanent(fmt.Errorf("invalid payload %v", job.Payload))
+	}
+	video, err := s.videoRepo.FindByLesson(ctx, lessonID)
+	if err != nil {
+		return err
+	}
+	if video == nil || video.VideoURL != sourceKey {
+		log.Printf("Skipping transcription of %s: the lesson video was replaced or deleted", sourceKey)
+		return nil
+	}
+
+	err = s.transcribe(ctx, video)
+	if err != nil && ctx.Err() == nil && (worker.IsPermanent(err) || job.Attempts >= job.MaxAttempts) {
+		failErr := s.videoRepo.SetTranscriptionStatus(context.Background(), video, model.TranscriptionStatusFailed)
+		if failErr != nil && !errors.Is(failErr, gorm.ErrRecordNotFound) {
+			log.Printf("Warning: failed to mark the transcription of %s failed: %v", sourceKey, failErr)
+		}
+	}
+	return err
+}
+
+// transcribe extracts the audio of the source, transcribes it in the language of the
+// course, uploads the WebVTT track and stores the segments for search.
+func (s *TranscriptionService) transcribe(ctx context.Context, video *model.LessonVideo) error {
+	sourceKey := video.VideoURL
+	if err := s.videoRepo.SetTranscriptionStatus(ctx, video, model.TranscriptionStatusProcessing); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("Skipping transcription of %s: the lesson video was replaced", sourceKey)
+			return nil
+		}
+		return err
+	}
+	lesson, err := s.courseRepo.FindLessonWithCourse(ctx, video.LessonID)
+	if err != nil {
+		return err
+	}
+	if lesson == nil {
+		log.Printf("Skipping transcription of %s: the lesson was deleted", sourceKey)
+		return nil
+	}
+
+	workDir, err := os.MkdirTemp(s.cfg.WorkerTempDir, "transcribe-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(workDir)
+
+	source := filepath.Join(workDir, "source"+path.Ext(sourceKey))
+	if err := s.minio.FGetObject(ctx, s.cfg.MinioBucketVideos, sourceKey, source, minio.GetObjectOptions{}); err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return worker.Permanent(fmt.Errorf("source %s is missing: %w", sourceKey, err))
+		}
+		return err
+	}
+	probe, err := s.ffmpeg.Probe(ctx, source)
+	if errors.Is(err, media.ErrNoVideoStream) {
+		return worker.Permanent(err)
+	}
+	if err != nil {
+		return err
+	}
+	if !probe.HasAudio {
+		// Nothing to transcribe: an empty transcript, without caption track
+		empty := ""
+		video.Transcription = &empty
+		return s.save(ctx, video, nil, nil)
+	}
+
+	audio := filepath.Join(workDir, "audio.wav")
+	if err := s.ffmpeg.ExtractAudio(ctx, source, audio); err != nil {
+		return err
+	}
+	transcript, err := s.transcriber.Transcribe(ctx, audio, lesson.Section.Course.Language)
+	if err != nil {
+		return err
+	}
+
+	text := transcript.Text()
+	video.Transcription = &text
+	segments := make([]model.TranscriptSegment, 0, len(transcript.Segments))
+	for _, seg := range transcript.Segments {
+		segments = append(segments, model.TranscriptSegment{
+			LessonVideoID: video.ID,
+			StartMs:       int(seg.Start.Milliseconds()),
+			EndMs:         int(seg.End.Milliseconds()),
+			Text:          seg.Text,
+		})
+	}
+	if len(segments) == 0 {
+		return s.save(ctx, video, nil, nil)
+	}
+
+	language := transcript.Language
+	if language == "" {
+		language = undeterminedLanguage
+	}
+	var vtt bytes.Buffer
+	if err := transcription.WriteWebVTT(&vtt, transcript.Segments); err != nil {
+		return err
+	}
+	caption := &model.VideoCaption{
+		LessonVideoID: video.ID,
+		Language:      language,
+		ObjectKey:     captionKey(sourceKey, language),
+	}
+	_, err = s.minio.PutObject(ctx, s.cfg.MinioBucketVideos, caption.ObjectKey, &vtt, int64(vtt.Len()),
+		minio.PutObjectOptions{ContentType: "text/vtt; charset=utf-8"})
+	if err != nil {
+		return err
+	}
+	return s.save(ctx, video, caption, segments)
+}
+
+func (s *TranscriptionService) save(ctx context.Context, video *model.LessonVideo, caption *model.VideoCaption, segments []model.TranscriptSegment) error {
+	err := s.videoRepo.SaveTranscript(ctx, video, caption, segments)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Printf("Dropping transcription of %s: the lesson video was replaced while transcribing", video.VideoURL)
+		if caption != nil {
+			if err := s.minio.RemoveObject(context.Background(), s.cfg.MinioBucketVideos, caption.ObjectKey, minio.RemoveObjectOptions{}); err != nil {
+				log.Printf("Warning: failed to remove caption track %s: %v", caption.ObjectKey, err)
+			}
+		}
+		return nil
+	}
+	return err
+}
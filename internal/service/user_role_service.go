@@ -0,0 +1,367 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"study.com/v1/internal/dto"
+	"study.com/v1/internal/model"
+	"study.com/v1/internal/repository"
+)
+
+var (
+	ErrRoleNotFound   = errors.New("role not found")
+	ErrEmptyRoleList  = errors.New("role_ids must not be empty")
+	ErrLastRoleRemove = errors.New("a user must keep at least one role")
+
+	ErrOverrideNotFound       = errors.New("permission override not found")
+	ErrOverrideGrantRequired  = errors.New("is_granted is required")
+	ErrOverrideResourceScope  = errors.New("resource_id requires resource_type")
+	ErrOverrideAlreadyExpired = errors.New("expires_at must be in the future")
+)
+
+type UserRoleServiceInterface interface {
+	GetUserRoles(ctx context.Context, userID uuid.UUID) (*dto.UserWithRolesResponseDTO, error)
+	AssignRoles(ctx context.Context, userID uuid.UUID, req dto.AssignRolesToUserDTO, assignedBy uuid.UUID) ([]dto.UserRoleResponseDTO, error)
+	RemoveRoles(ctx context.Context, userID uuid.UUID, req dto.RemoveRolesFromUserDTO) error
+	ListUsersWithRoles(ctx context.Context, query dto.UserRoleQueryDTO) (*dto.UserRoleListResponseDTO, error)
+	ListPermissionOverrides(ctx context.Context, userID uuid.UUID) ([]dto.PermissionOverrideResponseDTO, error)
+	CreatePermissionOverride(ctx context.Context, userID uuid.UUID, req dto.CreatePermissionOverrideDTO, grantedBy uuid.UUID) (*dto.PermissionOverrideResponseDTO, error)
+	DeletePermissionOverride(ctx context.Context, userID, overrideID uuid.UUID) error
+}
+
+type UserRoleService struct {
+	userRepo       repository.UserRepositoryInterface
+	roleRepo       repository.RoleRepositoryInterface
+	userRoleRepo   repository.UserRoleRepositoryInterface
+	permissionRepo repository.PermissionRepositoryInterface
+	overrideRepo   repository.PermissionOverrideRepositoryInterface
+	authz          AuthorizationServiceInterface
+}
+
+func NewUserRoleService(
+	userRepo repository.UserRepositoryInterface,
+	roleRepo repository.RoleRepositoryInterface,
+	userRoleRepo repository.UserRoleRepositoryInterface,
+	permissionRepo repository.PermissionRepositoryInterface,
+	overrideRepo repository.PermissionOverrideRepositoryInterface,
+	authz AuthorizationServiceInterface,
+) *UserRoleService {
+	return &UserRoleService{
+		userRepo:       userRepo,
+		roleRepo:       roleRepo,
+		userRoleRepo:   userRoleRepo,
+		permissionRepo: permissionRepo,
+		overrideRepo:   overrideRepo,
+		authz:          authz,
+	}
+}
+
+func (s *UserRoleService) GetUserRoles(ctx context.Context, userID uuid.UUID) (*dto.UserWithRolesResponseDTO, error) {
+	user, err := s.userRepo.FindUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	userRoles, err := s.userRoleRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	user.UserRoles = userRoles
+
+	res := toUserWithRolesResponse(user)
+	return &res, nil
+}
+
+func (s *UserRoleService) AssignRoles(ctx context.Context, userID uuid.UUID, req dto.AssignRolesToUserDTO, assignedBy uuid.UUID) ([]dto.UserRoleResponseDTO, error) {
+	if len(req.RoleIDs) == 0 {
+		return nil, ErrEmptyRoleList
+	}
+	if err := s.ensureUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+	if err := s.ensureRolesExist(ctx, req.RoleIDs); err != nil {
+		return nil, err
+	}
+
+	if err := s.userRoleRepo.AssignRoles(ctx, userID, req.RoleIDs, &assignedBy); err != nil {
+		return nil, err
+	}
+	if err := s.authz.InvalidateUserPermissions(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	userRoles, err := s.userRoleRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]dto.UserRoleResponseDTO, 0, len(userRoles))
+	for _, ur := range userRoles {
+		res = append(res, toUserRoleResponse(ur))
+	}
+	return res, nil
+}
+
+func (s *UserRoleService) RemoveRoles(ctx context.Context, userID uuid.UUID, req dto.RemoveRolesFromUserDTO) error {
+	if len(req.RoleIDs) == 0 {
+		return ErrEmptyRoleList
+	}
+	if err := s.ensureUserExists(ctx, userID); err != nil {
+		return err
+	}
+
+	current, err := s.userRoleRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	removing := make(map[uuid.UUID]struct{}, len(req.RoleIDs))
+	for _, id := range req.RoleIDs {
+		removing[id] = struct{}{}
+	}
+	remaining := 0
+	for _, ur := range current {
+		if _, ok := removing[ur.RoleID]; !ok {
+			remaining++
+		}
+	}
+	if remaining == 0 {
+		return ErrLastRoleRemove
+	}
+
+	if err := s.userRoleRepo.RemoveRoles(ctx, userID, req.RoleIDs); err != nil {
+		return err
+	}
+	return s.authz.InvalidateUserPermissions(ctx, userID)
+}
+
+func (s *UserRoleService) ListUsersWithRoles(ctx context.Context, query dto.UserRoleQueryDTO) (*dto.UserRoleListResponseDTO, error) {
+	page, pageSize := normalizePage(query.Page, query.PageSize)
+	users, total, err := s.userRoleRepo.ListUsersWithRoles(ctx, query.RoleID, strings.TrimSpace(query.Search), (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]dto.UserWithRolesResponseDTO, 0, len(users))
+	for i := range users {
+		items = append(items, toUserWithRolesResponse(&users[i]))
+	}
+	return &dto.UserRoleListResponseDTO{
+		Users:    items,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+func (s *UserRoleService) ListPermissionOverrides(ctx context.Context, userID uuid.UUID) ([]dto.PermissionOverrideResponseDTO, error) {
+	if err := s.ensureUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+	overrides, err := s.overrideRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]dto.PermissionOverrideResponseDTO, 0, len(overrides))
+	for _, o := range overrides {
+		res = append(res, toPermissionOverrideResponse(o))
+	}
+	return res, nil
+}
+
+func (s *UserRoleService) CreatePermissionOverride(ctx context.Context, userID uuid.UUID, req dto.CreatePermissionOverrideDTO, grantedBy uuid.UUID) (*dto.PermissionOverrideResponseDTO, error) {
+	if err := s.ensureUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+	if req.IsGranted == nil {
+		return nil, ErrOverrideGrantRequired
+	}
+	if req.ResourceID != nil && (req.ResourceType == nil || strings.TrimSpace(*req.ResourceType) == "") {
+		return nil, ErrOverrideResourceScope
+	}
+	now := time.Now()
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(now) {
+		return nil, ErrOverrideAlreadyExpired
+	}
+
+	permission, err := s.permissionRepo.FindByID(ctx, req.PermissionID)
+	if err != nil {
+		return nil, err
+	}
+	if permission == nil {
+		return nil, ErrPermissionNotFound
+	}
+	if permission.Name == model.PermissionWildcard {
+		return nil, ErrWildcardNotAssignable
+	}
+
+	override := &model.UserPermissionOverride{
+		ID:             uuid.New(),
+		UserID:         userID,
+		PermissionID:   permission.ID,
+		OrganizationID: req.OrganizationID,
+		ResourceType:   req.ResourceType,
+		ResourceID:     req.ResourceID,
+		IsGranted:      *req.IsGranted,
+		Reason:         req.Reason,
+		GrantedAt:      now,
+		ExpiresAt:      req.ExpiresAt,
+	}
+	if grantedBy != uuid.Nil {
+		override.GrantedBy = &grantedBy
+	}
+	if err := s.overrideRepo.Create(ctx, override); err != nil {
+		return nil, err
+	}
+	if err := s.authz.InvalidateUserPermissions(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	override.Permission = *permission
+	res := toPermissionOverrideResponse(*override)
+	return &res, nil
+}
+
+func (s *UserRoleService) DeletePermissionOverride(ctx context.Context, userID, overrideID uuid.UUID) error {
+	deleted, err := s.overrideRepo.Delete(ctx, userID, overrideID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrOverrideNotFound
+	}
+	return s.authz.InvalidateUserPermissions(ctx, userID)
+}
+
+func (s *UserRoleService) ensureUserExists(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.userRepo.FindUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+func (s *UserRoleService) ensureRolesExist(ctx context.Context, roleIDs []uuid.UUID) error {
+	roles, err := s.roleRepo.FindByIDs(ctx, roleIDs)
+	if err != nil {
+		return err
+	}
+	found := make(map[uuid.UUID]struct{}, len(roles))
+	for _, r := range roles {
+		// Organization roles are only assigned through the organization API
+		if r.OrganizationID == nil {
+			found[r.ID] = struct{}{}
+		}
+	}
+	for _, id := range roleIDs {
+		if _, ok := found[id]; !ok {
+			return ErrRoleNotFound
+		}
+	}
+	return nil
+}
+
+func toPermissionResponse(p model.Permission) dto.PermissionResponseDTO {
+	res := dto.PermissionResponseDTO{
+		ID:        p.ID,
+		Name:      p.Name,
+		CreatedAt: p.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: p.UpdatedAt.Format(time.RFC3339),
+	}
+	if p.Description.Valid {
+		res.Description = &p.Description.String
+	}
+	return res
+}
+
+func toRoleResponse(r model.Role) dto.RoleResponseDTO {
+	res := dto.RoleResponseDTO{
+		ID:             r.ID,
+		OrganizationID: r.OrganizationID,
+		Name:           r.Name,
+		CreatedAt:      r.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:      r.UpdatedAt.Format(time.RFC3339),
+	}
+	if r.Description.Valid {
+		res.Description = &r.Description.String
+	}
+	return res
+}
+
+func toRoleDetailResponse(r model.Role) dto.RoleDetailResponseDTO {
+	base := toRoleResponse(r)
+	permissions := make([]dto.PermissionResponseDTO, 0, len(r.RolePermissions))
+	for _, rp := range r.RolePermissions {
+		res := toPermissionResponse(rp.Permission)
+		granted := rp.IsGranted
+		res.IsGranted = &granted
+		if len(rp.Conditions) > 0 {
+			res.Conditions = rp.Conditions
+		}
+		permissions = append(permissions, res)
+	}
+	sort.Slice(permissions, func(i, j int) bool { return permissions[i].Name < permissions[j].Name })
+	return dto.RoleDetailResponseDTO{
+		ID:             base.ID,
+		OrganizationID: base.OrganizationID,
+		Name:           base.Name,
+		Description:    base.Description,
+		Permissions:    permissions,
+		CreatedAt:      base.CreatedAt,
+		UpdatedAt:      base.UpdatedAt,
+	}
+}
+
+func toUserRoleResponse(ur model.UserRole) dto.UserRoleResponseDTO {
+	return dto.UserRoleResponseDTO{
+		ID:         ur.ID,
+		UserID:     ur.UserID,
+		RoleID:     ur.RoleID,
+		Role:       toRoleResponse(ur.Role),
+		AssignedAt: ur.AssignedAt.Format(time.RFC3339),
+	}
+}
+
+func toPermissionOverrideResponse(o model.UserPermissionOverride) dto.PermissionOverrideResponseDTO {
+	res := dto.PermissionOverrideResponseDTO{
+		ID:             o.ID,
+		UserID:         o.UserID,
+		Permission:     toPermissionResponse(o.Permission),
+		IsGranted:      o.IsGranted,
+		OrganizationID: o.OrganizationID,
+		ResourceType:   o.ResourceType,
+		ResourceID:     o.ResourceID,
+		Reason:         o.Reason,
+		GrantedBy:      o.GrantedBy,
+		GrantedAt:      o.GrantedAt.Format(time.RFC3339),
+	}
+	if o.ExpiresAt != nil {
+		expiresAt := o.ExpiresAt.Format(time.RFC3339)
+		res.ExpiresAt = &expiresAt
+	}
+	return res
+}
+
+func toUserWithRolesResponse(user *model.User) dto.UserWithRolesResponseDTO {
+	roles := make([]dto.RoleDetailResponseDTO, 0, len(user.UserRoles))
+	for _, ur := range user.UserRoles {
+		roles = append(roles, toRoleDetailResponse(ur.Role))
+	}
+	return dto.UserWithRolesResponseDTO{
+		ID:        user.ID,
+		Username:  user.UserName,
+		Email:     user.Email,
+		Roles:     roles,
+		CreatedAt: user.CreatedAt.Format(time.RFC3339),
+	}
+}
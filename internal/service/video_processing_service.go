@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
+	"gorm.io/gorm"
+	"study.com/v1/internal/config"
+	"study.com/v1/internal/media"
+	"study.com/v1/internal/model"
+	"study.com/v1/internal/repository"
+	"study.com/v1/internal/storage"
+	"study.com/v1/internal/worker"
+)
+
+const (
+	hlsSegmentSeconds  = 6
+	videoThumbnailSize = 720
+)
+
+// hlsContentTypes are set on the uploaded objects so players accept them.
+var hlsContentTypes = map[string]string{
+	".m3u8": "application/vnd.apple.mpegurl",
+	".ts":   "video/mp2t",
+}
+
+type VideoProcessingServiceInterface interface {
+	Transcode(ctx context.Context, job *model.Job) error
+}
+
+// VideoProcessingService turns the source video of a lesson into an HLS ladder, run by the
+// worker for the video_transcode jobs queued when an upload completes.
+type VideoProcessingService struct {
+	cfg       *config.Config
+	videoRepo repository.VideoRepositoryInterface
+	minio     *minio.Client
+	ffmpeg    *media.FFmpeg
+}
+
+func NewVideoProcessingService(
+	cfg *config.Config,
+	videoRepo repository.VideoRepositoryInterface,
+	minioClient *minio.Client,
+	ffmpeg *media.FFmpeg,
+) *VideoProcessingService {
+	return &VideoProcessingService{
+		cfg:       cfg,
+		videoRepo: videoRepo,
+		minio:     minioClient,
+		ffmpeg:    ffmpeg,
+	}
+}
+
+// newTranscodeJob is queued in the transaction that attaches an uploaded source to a lesson.
+func newTranscodeJob(lessonID uuid.UUID, objectKey string) *model.Job {
+	return &model.Job{
+		Type: model.JobTypeVideoTranscode,
+		Payload: model.JobPayload{
+			"lesson_id":  lessonID.String(),
+			"object_key": objectKey,
+		},
+	}
+}
+
+// Transcode downloads the source, encodes the renditions that do not upscale it, grabs a
+// thumbnail and uploads everything next to the source. A source replaced in the meantime
+// is skipped: the new upload has its own job.
+func (s *VideoProcessingService) Transcode(ctx context.Context, job *model.Job) error {
+	lessonID, err := uuid.Parse(job.Payload["lesson_id"])
+	sourceKey := job.Payload["object_key"]
+	if err != nil || sourceKey == "" {
+		return worker.Permanent(fmt.Errorf("invalid payload %v", job.Payload))
+	}
+	video, err := s.videoRepo.FindByLesson(ctx, lessonID)
+	if err != nil {
+		return err
+	}
+	if video == nil || video.VideoURL != sourceKey {
+		log.Printf("Skipping transcode of %s: the lesson video was replaced or deleted", sourceKey)
+		return nil
+	}
+
+	workDir, err := os.MkdirTemp(s.cfg.WorkerTempDir, "transcode-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(workDir)
+
+	source := filepath.Join(workDir, "source"+path.Ext(sourceKey))
+	if err := s.minio.FGetObject(ctx, s.cfg.MinioBucketVideos, sourceKey, source, minio.GetObjectOptions{}); err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return worker.Permanent(fmt.Errorf("source %s is missing: %w", sourceKey, err))
+		}
+		return err
+	}
+	probe, err := s.ffmpeg.Probe(ctx, source)
+	if errors.Is(err, media.ErrNoVideoStream) {
+		return worker.Permanent(err)
+	}
+	if err != nil {
+		return err
+	}
+
+	hlsDir := filepath.Join(workDir, "hls")
+	variants, err := s.ffmpeg.TranscodeHLS(ctx, source, probe, media.LadderFor(probe, media.DefaultLadder), hlsDir, hlsSegmentSeconds)
+	if err != nil {
+		return err
+	}
+	if err := media.WriteMasterPlaylist(filepath.Join(hlsDir, "master.m3u8"), variants); err != nil {
+		return err
+	}
+	thumbnail := filepath.Join(workDir, "thumbnail.jpg")
+	at := math.Min(5, probe.DurationSeconds/10)
+	if err := s.ffmpeg.Thumbnail(ctx, source, probe, at, videoThumbnailSize, thumbnail); err != nil {
+		return err
+	}
+
+	prefix := videoOutputPrefix(sourceKey)
+	if err := s.uploadDir(ctx, hlsDir, prefix); err != nil {
+		return err
+	}
+	thumbnailKey := strings.TrimSuffix(prefix, "/") + ".jpg"
+	_, err = s.minio.FPutObject(ctx, s.cfg.MinioBucketImages, thumbnailKey, thumbnail, minio.PutObjectOptions{ContentType: "image/jpeg"})
+	if err != nil {
+		return err
+	}
+
+	hlsKey := prefix + "master.m3u8"
+	thumbnailURL := storage.PublicObjectURL(s.cfg, s.cfg.MinioBucketImages, thumbnailKey)
+	resolution := probe.Resolution()
+	video.VideoHlsURL = &hlsKey
+	video.ThumbnailURL = &thumbnailURL
+	video.DurationSeconds = int(math.Round(probe.DurationSeconds))
+	video.Resolution = &resolution
+	if err := s.videoRepo.SetProcessed(ctx, video); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("Dropping transcode of %s: the lesson video was replaced while encoding", sourceKey)
+			removeVideoOutputs(ctx, s.cfg, s.minio, sourceKey)
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// uploadDir uploads the files under dir to the videos bucket, keeping their relative paths.
+func (s *VideoProcessingService) uploadDir(ctx context.Context, dir, prefix string) error {
+	return filepath.WalkDir(dir, func(file string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+		opts := minio.PutObjectOptions{ContentType: hlsContentTypes[filepath.Ext(file)]}
+		_, err = s.minio.FPutObject(ctx, s.cfg.MinioBucketVideos, prefix+filepath.ToSlash(rel), file, opts)
+		return err
+	})
+}
+
+// videoOutputPrefix is where the renditions of a source go: next to it, in a directory
+// named after it. The thumbnail is the same path with .jpg in the images bucket.
+func videoOutputPrefix(sourceKey string) string {
+	return strings.TrimSuffix(sourceKey, path.Ext(sourceKey)) + "/"
+}
+
+// removeVideoOutputs deletes what transcoding produced for a source. Failures are only
+// logged, the objects are orphaned but harmless.
+func removeVideoOutputs(ctx context.Context, cfg *config.Config, client *minio.Client, sourceKey string) {
+	if !strings.HasPrefix(sourceKey, "courses/") {
+		return
+	}
+	prefix := videoOutputPrefix(sourceKey)
+	if err := storage.RemovePrefix(ctx, client, cfg.MinioBucketVideos, prefix); err != nil {
+		log.Printf("Warning: failed to remove renditions of %s: %v", sourceKey, err)
+	}
+	thumbnailKey := strings.TrimSuffix(prefix, "/") + ".jpg"
+	if err := client.RemoveObject(ctx, cfg.MinioBucketImages, thumbnailKey, minio.RemoveObjectOptions{}); err != nil {
+		log.Printf("Warning: failed to remove thumbnail of %s: %v", sourceKey, err)
+	}
+}
@@ -1,7 +1,10 @@
 package storage
 
 import (
+	"context"
 	"fmt"
+	"net/url"
+	"strings"
 
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
@@ -14,6 +17,7 @@ func Connect(cfg *config.Config) (*minio.Client, error) {
 	minioClient, err := minio.New(endpoint, &minio.Options{
 		Creds:  credentials.NewStaticV4(cfg.MinioAccessKey, cfg.MinioSecretKey, ""),
 		Secure: cfg.MinioUseSSL,
+		Region: cfg.MinioRegion,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create minio client: %w", err)
@@ -21,3 +25,78 @@ func Connect(cfg *config.Config) (*minio.Client, error) {
 
 	return minioClient, nil
 }
+
+// ConnectPresigner returns a client for signing URLs handed to browsers. A signature
+// covers the host, so it must be the public one when MinIO sits behind another name.
+// Signing happens offline: the region is fixed so no request is made to that host.
+func ConnectPresigner(cfg *config.Config) (*minio.Client, error) {
+	if cfg.MinioPublicURL == "" {
+		return Connect(cfg)
+	}
+	public, err := url.Parse(cfg.MinioPublicURL)
+	if err != nil || public.Host == "" {
+		return nil, fmt.Errorf("invalid MINIO_PUBLIC_URL %q", cfg.MinioPublicURL)
+	}
+
+	minioClient, err := minio.New(public.Host, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.MinioAccessKey, cfg.MinioSecretKey, ""),
+		Secure: public.Scheme == "https",
+		Region: cfg.MinioRegion,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create minio presign client: %w", err)
+	}
+	return minioClient, nil
+}
+
+// EnsureBuckets creates the buckets that do not exist yet. New buckets are private.
+func EnsureBuckets(ctx context.Context, client *minio.Client, buckets ...string) error {
+	for _, bucket := range buckets {
+		exists, err := client.BucketExists(ctx, bucket)
+		if err != nil {
+			return fmt.Errorf("failed to check bucket %s: %w", bucket, err)
+		}
+		if exists {
+			continue
+		}
+		if err := client.MakeBucket(ctx, bucket, minio.MakeBucketOptions{}); err != nil {
+			return fmt.Errorf("failed to create bucket %s: %w", bucket, err)
+		}
+	}
+	return nil
+}
+
+// EnsurePublicRead lets anyone download the objects of the bucket, but not list them.
+// Only for content shown on public pages, such as course thumbnails.
+func EnsurePublicRead(ctx context.Context, client *minio.Client, bucket string) error {
+	policy := fmt.Sprintf(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":["*"]},"Action":["s3:GetObject"],"Resource":["arn:aws:s3:::%s/*"]}]}`, bucket)
+	if err := client.SetBucketPolicy(ctx, bucket, policy); err != nil {
+		return fmt.Errorf("failed to make bucket %s public: %w", bucket, err)
+	}
+	return nil
+}
+
+// PublicObjectURL is the permanent URL of an object in a public bucket.
+func PublicObjectURL(cfg *config.Config, bucket, key string) string {
+	base := strings.TrimSuffix(cfg.MinioPublicURL, "/")
+	if base == "" {
+		scheme := "http"
+		if cfg.MinioUseSSL {
+			scheme = "https"
+		}
+		base = fmt.Sprintf("%s://%s:%s", scheme, cfg.MinioHost, cfg.MinioPort)
+	}
+	return base + "/" + bucket + "/" + key
+}
+
+// RemovePrefix deletes every object whose key starts with prefix, e.g. all the segments of
+// an HLS rendition.
+func RemovePrefix(ctx context.Context, client *minio.Client, bucket, prefix string) error {
+	objects := client.ListObjects(ctx, bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true})
+	for result := range client.RemoveObjects(ctx, bucket, objects, minio.RemoveObjectsOptions{}) {
+		if result.Err != nil {
+			return fmt.Errorf("failed to remove %s: %w", result.ObjectName, result.Err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+// Package transcription turns the speech of lesson videos into time-coded text. Backends
+// implement Transcriber; the first one runs a local whisper.cpp binary.
+package transcription
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Segment is a stretch of speech, usually a sentence or two.
+type Segment struct {
+	Start time.Duration
+	End   time.Duration
+	Text  string
+}
+
+// Transcript is what a backend heard. Language is the ISO 639-1 code of the speech, the
+// one requested or the one detected.
+type Transcript struct {
+	Language string
+	Segments []Segment
+}
+
+// Text joins the segments into the plain transcript.
+func (t *Transcript) Text() string {
+	parts := make([]string, 0, len(t.Segments))
+	for _, s := range t.Segments {
+		parts = append(parts, s.Text)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Transcriber transcribes an audio file, 16 kHz mono 16-bit PCM WAV as
+// media.FFmpeg.ExtractAudio writes it. language is an ISO 639-1 code, or "" to let the
+// backend detect it.
+type Transcriber interface {
+	Transcribe(ctx context.Context, audioPath, language string) (*Transcript, error)
+}
+
+// WriteWebVTT writes the segments as a WebVTT file, the caption format browsers play in a
+// <track> element.
+func WriteWebVTT(w io.Writer, segments []Segment) error {
+	if _, err := io.WriteString(w, "WEBVTT\n"); err != nil {
+		return err
+	}
+	for i, s := range segments {
+		// A blank line ends a cue and "-->" starts a new one, neither may appear in the text
+		text := strings.Join(strings.Fields(s.Text), " ")
+		text = strings.ReplaceAll(text, "-->", "->")
+		if _, err := fmt.Fprintf(w, "\n%d\n%s --> %s\n%s\n", i+1, vttTimestamp(s.Start), vttTimestamp(s.End), text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// vttTimestamp formats a duration as hh:mm:ss.ttt.
+func vttTimestamp(d time.Duration) string {
+	ms := max(d.Milliseconds(), 0)
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
@@ -0,0 +1,107 @@
+package transcription
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// nonSpeech matches the annotations whisper writes for silence and noise, e.g.
+// "[BLANK_AUDIO]", "[Music]" or "(tiếng vỗ tay)".
+var nonSpeech = regexp.MustCompile(`^(\[[^\]]*\]|\([^)]*\))$`)
+
+// WhisperCpp runs the command line program of whisper.cpp (whisper-cli, formerly main)
+// with a ggml model file, on the machine of the worker.
+type WhisperCpp struct {
+	binaryPath string
+	modelPath  string
+	threads    int
+}
+
+// NewWhisperCpp returns a backend running binaryPath with the model at modelPath. threads
+// is the number of CPU threads to use, 0 keeps the whisper.cpp default.
+func NewWhisperCpp(binaryPath, modelPath string, threads int) *WhisperCpp {
+	return &WhisperCpp{binaryPath: binaryPath, modelPath: modelPath, threads: threads}
+}
+
+// whisperOutput is the part of the file written by --output-json that we read.
+type whisperOutput struct {
+	Result struct {
+		Language string `json:"language"`
+	} `json:"result"`
+	Transcription []struct {
+		Offsets struct {
+			From int64 `json:"from"` // milliseconds
+			To   int64 `json:"to"`
+		} `json:"offsets"`
+		Text string `json:"text"`
+	} `json:"transcription"`
+}
+
+func (w *WhisperCpp) Transcribe(ctx context.Context, audioPath, language string) (*Transcript, error) {
+	if language == "" {
+		language = "auto"
+	}
+	outDir, err := os.MkdirTemp(filepath.Dir(audioPath), "whisper-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(outDir)
+	outBase := filepath.Join(outDir, "transcript")
+
+	args := []string{
+		"--model", w.modelPath,
+		"--file", audioPath,
+		"--language", language,
+		"--output-json",
+		"--output-file", outBase,
+		"--no-prints",
+	}
+	if w.threads > 0 {
+		args = append(args, "--threads", strconv.Itoa(w.threads))
+	}
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, w.binaryPath, args...)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if len(msg) > 1000 {
+			msg = "..." + msg[len(msg)-1000:]
+		}
+		return nil, fmt.Errorf("whisper.cpp: %w: %s", err, msg)
+	}
+
+	raw, err := os.ReadFile(outBase + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("whisper.cpp: %w", err)
+	}
+	var out whisperOutput
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil, fmt.Errorf("whisper.cpp: %w", err)
+	}
+
+	transcript := &Transcript{Language: out.Result.Language}
+	if transcript.Language == "" && language != "auto" {
+		transcript.Language = language
+	}
+	for _, s := range out.Transcription {
+		text := strings.TrimSpace(s.Text)
+		if text == "" || nonSpeech.MatchString(text) {
+			continue
+		}
+		transcript.Segments = append(transcript.Segments, Segment{
+			Start: time.Duration(s.Offsets.From) * time.Millisecond,
+			End:   time.Duration(s.Offsets.To) * time.Millisecond,
+			Text:  text,
+		})
+	}
+	return transcript, nil
+}
@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"crypto/rand"
+	"encoding/base64"
 	"fmt"
 	"time"
 
@@ -68,3 +70,13 @@ func ParseCodeTimestamp(code string) (time.Time, error) {
 	}
 	return time.Time{}, fmt.Errorf("cannot extract timestamp from code")
 }
+
+// GenerateSecureToken returns a random URL-safe token for links sent by email.
+// Only its HashToken digest should be stored.
+func GenerateSecureToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
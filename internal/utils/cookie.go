@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"study.com/v1/internal/config"
+)
+
+const (
+	AccessTokenCookie  = "accessToken"
+	RefreshTokenCookie = "rfToken"
+	CSRFTokenCookie    = "csrfToken"
+	CSRFTokenHeader    = "X-CSRF-Token"
+
+	// The refresh cookie is only ever needed by the auth endpoints.
+	refreshCookiePath = "/api/auth"
+)
+
+// CookiePolicy is the single place that decides how auth cookies are written.
+// Login, refresh and logout all go through it so the attributes never drift apart.
+type CookiePolicy struct {
+	domain     string
+	secure     bool
+	sameSite   string
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+func NewCookiePolicy(cfg *config.Config) *CookiePolicy {
+	sameSite := normalizeSameSite(cfg.CookieSameSite)
+	return &CookiePolicy{
+		domain: cfg.CookieDomain,
+		// Browsers drop SameSite=None cookies that are not Secure.
+		secure:     cfg.CookieSecure || sameSite == fiber.CookieSameSiteNoneMode,
+		sameSite:   sameSite,
+		accessTTL:  cfg.JWTAccessExpiration,
+		refreshTTL: cfg.JWTRefreshExpiration,
+	}
+}
+
+// SetAuthCookies writes the token cookies plus a fresh CSRF cookie and returns the
+// CSRF token so it can also be handed to the client in the response body.
+func (p *CookiePolicy) SetAuthCookies(c *fiber.Ctx, accessToken, refreshToken string) (string, error) {
+	csrfToken, err := GenerateCSRFToken()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	c.Cookie(p.cookie(AccessTokenCookie, accessToken, "/", now.Add(p.accessTTL), true))
+	c.Cookie(p.cookie(RefreshTokenCookie, refreshToken, refreshCookiePath, now.Add(p.refreshTTL), true))
+	// Not HttpOnly: the front end reads it and echoes it back in the CSRF header.
+	c.Cookie(p.cookie(CSRFTokenCookie, csrfToken, "/", now.Add(p.refreshTTL), false))
+	return csrfToken, nil
+}
+
+func (p *CookiePolicy) ClearAuthCookies(c *fiber.Ctx) {
+	expired := time.Unix(0, 0)
+	c.Cookie(p.cookie(AccessTokenCookie, "", "/", expired, true))
+	c.Cookie(p.cookie(RefreshTokenCookie, "", refreshCookiePath, expired, true))
+	c.Cookie(p.cookie(CSRFTokenCookie, "", "/", expired, false))
+}
+
+func (p *CookiePolicy) cookie(name, value, path string, expires time.Time, httpOnly bool) *fiber.Cookie {
+	return &fiber.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Domain:   p.domain,
+		Expires:  expires,
+		Secure:   p.secure,
+		HTTPOnly: httpOnly,
+		SameSite: p.sameSite,
+	}
+}
+
+func GenerateCSRFToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func normalizeSameSite(value string) string {
+	switch strings.ToLower(value) {
+	case "strict":
+		return fiber.CookieSameSiteStrictMode
+	case "none":
+		return fiber.CookieSameSiteNoneMode
+	default:
+		return fiber.CookieSameSiteLaxMode
+	}
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"fmt"
+	"html"
 	"net/smtp"
 	"regexp"
 
@@ -253,3 +254,339 @@ func ChangePasswordNotify(cfg *config.Config, to string) error {
 
 	return SendEmail(cfg, []string{to}, subject, body)
 }
+
+func SendOrganizationInvitation(cfg *config.Config, to, organizationName, acceptURL string, expiresInDays int) error {
+	subject := fmt.Sprintf("Lời mời tham gia %s", organizationName)
+	name := html.EscapeString(organizationName)
+
+	body := fmt.Sprintf(`
+<!DOCTYPE html>
+<html lang="vi">
+<head>
+  <meta charset="UTF-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1.0">
+</head>
+<body style="margin:0; padding:0; background:#f8f9fa; font-family:-apple-system,BlinkMacSystemFont,'Segoe UI',Roboto,'Helvetica Neue',Arial,sans-serif; color:#202124; font-size:14px; line-height:1.5;">
+  
+  <table width="100%%" cellpadding="0" cellspacing="0" border="0" style="background:#f8f9fa; padding:20px;">
+    <tr>
+      <td align="center">
+        
+        <table width="600" cellpadding="0" cellspacing="0" border="0" style="background:#ffffff; border-radius:3px; overflow:hidden;">
+          
+          <tr>
+            <td style="padding:20px;">
+              
+              <h2 style="margin:0 0 20px; font-size:20px; font-weight:bold;">
+                Lời mời tham gia %s
+              </h2>
+              
+              <p style="margin:0 0 20px;">
+                Bạn được mời trở thành thành viên của <strong>%s</strong>. Đăng nhập bằng địa chỉ email này rồi bấm nút bên dưới để chấp nhận lời mời.
+              </p>
+
+              <div style="text-align:center; margin:20px 0; padding:20px; background:#f8f9fa; border-radius:3px;">
+                <a href="%s" style="display:inline-block; padding:10px 24px; background:#1a73e8; color:#ffffff; text-decoration:none; border-radius:3px; font-weight:bold;">
+                  Chấp nhận lời mời
+                </a>
+                <p style="margin:10px 0 0; font-size:12px; color:#5f6368;">
+                  Lời mời hết hạn sau <strong>%d ngày</strong>
+                </p>
+              </div>
+
+              <p style="margin:20px 0 0;">
+                Nếu bạn không biết tổ chức này, vui lòng bỏ qua email này.
+              </p>
+
+            </td>
+          </tr>
+
+          <tr>
+            <td style="padding:20px; background:#f8f9fa; text-align:center; font-size:12px; color:#5f6368;">
+              Đây là email tự động, vui lòng không trả lời.<br>
+              © 2025 Tiger Esport. Bảo lưu mọi quyền.
+            </td>
+          </tr>
+
+        </table>
+
+      </td>
+    </tr>
+  </table>
+
+</body>
+</html>
+`, name, name, html.EscapeString(acceptURL), expiresInDays)
+
+	return SendEmail(cfg, []string{to}, subject, body)
+}
+
+func SendTeacherApplicationReceived(cfg *config.Config, to string) error {
+	subject := "Tiger Esport đã nhận hồ sơ đăng ký giáo viên của bạn"
+
+	body := `
+<!DOCTYPE html>
+<html lang="vi">
+<head>
+  <meta charset="UTF-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1.0">
+</head>
+<body style="margin:0; padding:0; background:#f8f9fa; font-family:-apple-system,BlinkMacSystemFont,'Segoe UI',Roboto,'Helvetica Neue',Arial,sans-serif; color:#202124; font-size:14px; line-height:1.5;">
+  
+  <table width="100%" cellpadding="0" cellspacing="0" border="0" style="background:#f8f9fa; padding:20px;">
+    <tr>
+      <td align="center">
+        
+        <table width="600" cellpadding="0" cellspacing="0" border="0" style="background:#ffffff; border-radius:3px; overflow:hidden;">
+          
+          <tr>
+            <td style="padding:20px;">
+              
+              <h2 style="margin:0 0 20px; font-size:20px; font-weight:bold;">
+                Đã nhận hồ sơ đăng ký giáo viên
+              </h2>
+              
+              <p style="margin:0 0 20px;">
+                Cảm ơn bạn đã đăng ký trở thành giáo viên trên Tiger Esport. Hồ sơ của bạn đang chờ đội ngũ quản trị xét duyệt, thường trong vòng 3 ngày làm việc.
+              </p>
+
+              <p style="margin:20px 0 0;">
+                Bạn có thể theo dõi trạng thái hồ sơ trong trang cá nhân và bổ sung thông tin trong lúc chờ duyệt. Chúng tôi sẽ gửi email khi có kết quả.
+              </p>
+
+            </td>
+          </tr>
+
+          <tr>
+            <td style="padding:20px; background:#f8f9fa; text-align:center; font-size:12px; color:#5f6368;">
+              Đây là email tự động, vui lòng không trả lời.<br>
+              © 2025 Tiger Esport. Bảo lưu mọi quyền.
+            </td>
+          </tr>
+
+        </table>
+
+      </td>
+    </tr>
+  </table>
+
+</body>
+</html>
+`
+
+	return SendEmail(cfg, []string{to}, subject, body)
+}
+
+// SendTeacherApplicationDecision tells the applicant whether the application was approved.
+// The reviewer's note is included when present.
+func SendTeacherApplicationDecision(cfg *config.Config, to string, approved bool, note string) error {
+	subject := "Hồ sơ đăng ký giáo viên của bạn chưa được duyệt"
+	title := "Hồ sơ chưa được duyệt"
+	message := "Rất tiếc, hồ sơ đăng ký giáo viên của bạn chưa đáp ứng yêu cầu. Bạn có thể chỉnh sửa theo góp ý bên dưới và gửi lại hồ sơ mới bất cứ lúc nào."
+	if approved {
+		subject = "Chúc mừng! Bạn đã trở thành giáo viên trên Tiger Esport"
+		title = "Hồ sơ đã được duyệt"
+		message = "Chúc mừng bạn! Hồ sơ đăng ký giáo viên đã được phê duyệt. Từ bây giờ bạn có thể tạo khoá học và bài giảng trên Tiger Esport. Vui lòng đăng nhập lại để cập nhật quyền."
+	}
+
+	noteBlock := ""
+	if note != "" {
+		noteBlock = fmt.Sprintf(`
+              <div style="margin-top:20px; padding:15px; background:#f8f9fa; border-radius:3px;">
+                <p style="margin:0 0 10px; font-weight:bold; color:#5f6368;">
+                  Nhận xét của người duyệt
+                </p>
+                <p style="margin:0; color:#5f6368; font-size:13px; white-space:pre-line;">%s</p>
+              </div>
+`, html.EscapeString(note))
+	}
+
+	body := fmt.Sprintf(`
+<!DOCTYPE html>
+<html lang="vi">
+<head>
+  <meta charset="UTF-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1.0">
+</head>
+<body style="margin:0; padding:0; background:#f8f9fa; font-family:-apple-system,BlinkMacSystemFont,'Segoe UI',Roboto,'Helvetica Neue',Arial,sans-serif; color:#202124; font-size:14px; line-height:1.5;">
+  
+  <table width="100%%" cellpadding="0" cellspacing="0" border="0" style="background:#f8f9fa; padding:20px;">
+    <tr>
+      <td align="center">
+        
+        <table width="600" cellpadding="0" cellspacing="0" border="0" style="background:#ffffff; border-radius:3px; overflow:hidden;">
+          
+          <tr>
+            <td style="padding:20px;">
+              
+              <h2 style="margin:0 0 20px; font-size:20px; font-weight:bold;">
+                %s
+              </h2>
+              
+              <p style="margin:0 0 20px;">
+                %s
+              </p>
+%s
+            </td>
+          </tr>
+
+          <tr>
+            <td style="padding:20px; background:#f8f9fa; text-align:center; font-size:12px; color:#5f6368;">
+              Đây là email tự động, vui lòng không trả lời.<br>
+              © 2025 Tiger Esport. Bảo lưu mọi quyền.
+            </td>
+          </tr>
+
+        </table>
+
+      </td>
+    </tr>
+  </table>
+
+</body>
+</html>
+`, title, message, noteBlock)
+
+	return SendEmail(cfg, []string{to}, subject, body)
+}
+
+func SendParentLinkOTP(cfg *config.Config, to, parentName, relationship, otp string, expiresInMinutes int) error {
+	subject := "Xác nhận liên kết tài khoản phụ huynh"
+
+	body := fmt.Sprintf(`
+<!DOCTYPE html>
+<html lang="vi">
+<head>
+  <meta charset="UTF-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1.0">
+</head>
+<body style="margin:0; padding:0; background:#f8f9fa; font-family:-apple-system,BlinkMacSystemFont,'Segoe UI',Roboto,'Helvetica Neue',Arial,sans-serif; color:#202124; font-size:14px; line-height:1.5;">
+  
+  <table width="100%%" cellpadding="0" cellspacing="0" border="0" style="background:#f8f9fa; padding:20px;">
+    <tr>
+      <td align="center">
+        
+        <table width="600" cellpadding="0" cellspacing="0" border="0" style="background:#ffffff; border-radius:3px; overflow:hidden;">
+          
+          <tr>
+            <td style="padding:20px;">
+              
+              <h2 style="margin:0 0 20px; font-size:20px; font-weight:bold;">
+                Xác nhận liên kết tài khoản phụ huynh
+              </h2>
+              
+              <p style="margin:0 0 20px;">
+                <strong>%s</strong> muốn liên kết với tài khoản của bạn với vai trò <strong>%s</strong>. Sau khi liên kết, người này có thể xem tiến độ học tập, điểm kiểm tra, lịch sử đơn hàng và duyệt các khoản mua khóa học của bạn.
+              </p>
+
+              <div style="text-align:center; margin:20px 0; padding:20px; background:#f8f9fa; border-radius:3px;">
+                <p style="margin:0 0 10px; font-size:12px; text-transform:uppercase; font-weight:bold; color:#5f6368;">
+                  Mã xác nhận
+                </p>
+                <div style="font-size:32px; font-weight:bold; letter-spacing:5px;">
+                  %s
+                </div>
+                <p style="margin:10px 0 0; font-size:12px; color:#5f6368;">
+                  Mã này hết hạn sau <strong>%d phút</strong>
+                </p>
+              </div>
+
+              <p style="margin:20px 0 0;">
+                Nhập mã này trong ứng dụng, hoặc đọc mã cho phụ huynh của bạn nếu họ đang ở cạnh bạn. Nếu bạn không biết người này, đừng chia sẻ mã và hãy bỏ qua email này.
+              </p>
+
+            </td>
+          </tr>
+
+          <tr>
+            <td style="padding:20px; background:#f8f9fa; text-align:center; font-size:12px; color:#5f6368;">
+              Đây là email tự động, vui lòng không trả lời.<br>
+              © 2025 Tiger Esport. Bảo lưu mọi quyền.
+            </td>
+          </tr>
+
+        </table>
+
+      </td>
+    </tr>
+  </table>
+
+</body>
+</html>
+`, html.EscapeString(parentName), html.EscapeString(relationship), otp, expiresInMinutes)
+
+	return SendEmail(cfg, []string{to}, subject, body)
+}
+
+// SendCourseReviewDecision tells the instructor whether the course passed review.
+// The reviewer's comment is included when present.
+func SendCourseReviewDecision(cfg *config.Config, to, courseTitle string, approved bool, note string) error {
+	subject := fmt.Sprintf("Khoá học \"%s\" chưa được duyệt", courseTitle)
+	title := "Khoá học chưa được duyệt"
+	message := fmt.Sprintf("Khoá học <strong>%s</strong> chưa đáp ứng yêu cầu xuất bản và đã được chuyển về bản nháp. Bạn có thể chỉnh sửa theo góp ý bên dưới rồi gửi duyệt lại.", html.EscapeString(courseTitle))
+	if approved {
+		subject = fmt.Sprintf("Khoá học \"%s\" đã được xuất bản", courseTitle)
+		title = "Khoá học đã được duyệt"
+		message = fmt.Sprintf("Chúc mừng bạn! Khoá học <strong>%s</strong> đã được phê duyệt và đang hiển thị với học viên trên Tiger Esport.", html.EscapeString(courseTitle))
+	}
+
+	noteBlock := ""
+	if note != "" {
+		noteBlock = fmt.Sprintf(`
+              <div style="margin-top:20px; padding:15px; background:#f8f9fa; border-radius:3px;">
+                <p style="margin:0 0 10px; font-weight:bold; color:#5f6368;">
+                  Nhận xét của người duyệt
+                </p>
+                <p style="margin:0; color:#5f6368; font-size:13px; white-space:pre-line;">%s</p>
+              </div>
+`, html.EscapeString(note))
+	}
+
+	body := fmt.Sprintf(`
+<!DOCTYPE html>
+<html lang="vi">
+<head>
+  <meta charset="UTF-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1.0">
+</head>
+<body style="margin:0; padding:0; background:#f8f9fa; font-family:-apple-system,BlinkMacSystemFont,'Segoe UI',Roboto,'Helvetica Neue',Arial,sans-serif; color:#202124; font-size:14px; line-height:1.5;">
+  
+  <table width="100%%" cellpadding="0" cellspacing="0" border="0" style="background:#f8f9fa; padding:20px;">
+    <tr>
+      <td align="center">
+        
+        <table width="600" cellpadding="0" cellspacing="0" border="0" style="background:#ffffff; border-radius:3px; overflow:hidden;">
+          
+          <tr>
+            <td style="padding:20px;">
+              
+              <h2 style="margin:0 0 20px; font-size:20px; font-weight:bold;">
+                %s
+              </h2>
+              
+              <p style="margin:0 0 20px;">
+                %s
+              </p>
+%s
+            </td>
+          </tr>
+
+          <tr>
+            <td style="padding:20px; background:#f8f9fa; text-align:center; font-size:12px; color:#5f6368;">
+              Đây là email tự động, vui lòng không trả lời.<br>
+              © 2025 Tiger Esport. Bảo lưu mọi quyền.
+            </td>
+          </tr>
+
+        </table>
+
+      </td>
+    </tr>
+  </table>
+
+</body>
+</html>
+`, title, message, noteBlock)
+
+	return SendEmail(cfg, []string{to}, subject, body)
+}
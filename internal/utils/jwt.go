@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"time"
 
@@ -9,64 +11,143 @@ import (
 	"study.com/v1/internal/config"
 )
 
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
+var ErrWrongTokenType = errors.New("unexpected token type")
+
 type Claims struct {
-	UserID   uuid.UUID `json:"user_id"`
-	DeviceID uuid.UUID `json:"device_id"`
-	Version  int64     `json:"version"`
+	UserID    uuid.UUID `json:"user_id"`
+	DeviceID  uuid.UUID `json:"device_id"`
+	Version   int64     `json:"version"`
+	TokenType string    `json:"typ"`
 	jwt.RegisteredClaims
 }
 
 func GenerateTokens(cfg *config.Config, userID uuid.UUID, deviceID uuid.UUID, version int64) (string, string, error) {
-	// Access Token
-	accessClaims := Claims{
-		UserID:   userID,
-		DeviceID: deviceID,
-		Version:  version,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(cfg.JWTAccessExpiration)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
+	accessTokenString, err := signToken(cfg, TokenTypeAccess, userID, deviceID, version)
+	if err != nil {
+		return "", "", err
 	}
-	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims)
-	accessTokenString, err := accessToken.SignedString([]byte(cfg.JWTSecret))
+
+	refreshTokenString, err := signToken(cfg, TokenTypeRefresh, userID, deviceID, version)
 	if err != nil {
 		return "", "", err
 	}
 
-	// Refresh Token
-	refreshClaims := Claims{
-		UserID:   userID,
-		DeviceID: deviceID,
-		Version:  version,
+	return accessTokenString, refreshTokenString, nil
+}
+
+// ParseToken verifies the signature, issuer, audience and expiry of a token and
+// rejects it unless it was issued as expectedType.
+func ParseToken(cfg *config.Config, tokenString string, expectedType string) (*Claims, error) {
+	keyFunc, methods, err := verificationKeys(cfg, expectedType)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, keyFunc,
+		jwt.WithValidMethods(methods),
+		jwt.WithIssuer(cfg.JWTIssuer),
+		jwt.WithAudience(tokenAudience(cfg, expectedType)),
+		jwt.WithExpirationRequired(),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*Claims)
+	if !ok || !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+	if claims.TokenType != expectedType {
+		return nil, ErrWrongTokenType
+	}
+
+	return claims, nil
+}
+
+// HashToken returns the digest stored server-side in place of a raw refresh token.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func signToken(cfg *config.Config, tokenType string, userID, deviceID uuid.UUID, version int64) (string, error) {
+	now := time.Now()
+	expiration := cfg.JWTAccessExpiration
+	if tokenType == TokenTypeRefresh {
+		expiration = cfg.JWTRefreshExpiration
+	}
+
+	claims := Claims{
+		UserID:    userID,
+		DeviceID:  deviceID,
+		Version:   version,
+		TokenType: tokenType,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(cfg.JWTRefreshExpiration)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        uuid.NewString(),
+			Issuer:    cfg.JWTIssuer,
+			Subject:   userID.String(),
+			Audience:  jwt.ClaimStrings{tokenAudience(cfg, tokenType)},
+			ExpiresAt: jwt.NewNumericDate(now.Add(expiration)),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
-	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims)
-	refreshTokenString, err := refreshToken.SignedString([]byte(cfg.JWTSecret))
-	if err != nil {
-		return "", "", err
+
+	// Refresh tokens never leave this API, so they always use the shared secret.
+	// Access tokens switch to the asymmetric key set when one is configured.
+	if tokenType == TokenTypeAccess {
+		set, err := LoadKeySet(cfg)
+		if err != nil {
+			return "", err
+		}
+		if set != nil {
+			token := jwt.NewWithClaims(set.method, claims)
+			token.Header["kid"] = set.active.ID
+			return token.SignedString(set.active.Private)
+		}
 	}
 
-	return accessTokenString, refreshTokenString, nil
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(tokenSecret(cfg, tokenType))
 }
 
-func ParseToken(cfg *config.Config, tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+func verificationKeys(cfg *config.Config, tokenType string) (jwt.Keyfunc, []string, error) {
+	if tokenType == TokenTypeAccess {
+		set, err := LoadKeySet(cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+		if set != nil {
+			return set.lookup, []string{set.method.Alg()}, nil
+		}
+	}
+
+	keyFunc := func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, errors.New("unexpected signing method")
 		}
-		return []byte(cfg.JWTSecret), nil
-	})
-
-	if err != nil {
-		return nil, err
+		return tokenSecret(cfg, tokenType), nil
 	}
+	return keyFunc, []string{jwt.SigningMethodHS256.Alg()}, nil
+}
 
-	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
-		return claims, nil
+func tokenSecret(cfg *config.Config, tokenType string) []byte {
+	if tokenType == TokenTypeRefresh {
+		return []byte(cfg.JWTRefreshSecret)
 	}
+	return []byte(cfg.JWTAccessSecret)
+}
 
-	return nil, errors.New("invalid token")
+// Refresh tokens are only ever presented back to this API, so their audience is
+// the issuer itself rather than the client audience used by access tokens.
+func tokenAudience(cfg *config.Config, tokenType string) string {
+	if tokenType == TokenTypeRefresh {
+		return cfg.JWTIssuer
+	}
+	return cfg.JWTAudience
 }
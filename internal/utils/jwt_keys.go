@@ -0,0 +1,229 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+	"study.com/v1/internal/config"
+)
+
+const (
+	SigningAlgorithmHS256 = "HS256"
+	SigningAlgorithmRS256 = "RS256"
+	SigningAlgorithmEdDSA = "EdDSA"
+)
+
+// verifyKey is one key that access tokens may be verified with. Only the active
+// key also carries a private half and is used for signing.
+type verifyKey struct {
+	ID      string
+	Public  crypto.PublicKey
+	Private crypto.Signer
+}
+
+// KeySet holds the asymmetric keys for access tokens. Every *.pem file in
+// JWT_KEYS_DIR is a key named after the file; the one matching JWT_ACTIVE_KEY_ID
+// signs new tokens and the rest stay valid for verification until removed.
+type KeySet struct {
+	method jwt.SigningMethod
+	active *verifyKey
+	keys   map[string]*verifyKey
+}
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+var keySets sync.Map // *config.Config -> *KeySet
+
+// LoadKeySet reads and caches the key set for cfg. It returns nil in HS256 mode.
+// Call it at startup so a broken key directory fails fast instead of on first login.
+func LoadKeySet(cfg *config.Config) (*KeySet, error) {
+	if cfg.JWTSigningAlgorithm == "" || cfg.JWTSigningAlgorithm == SigningAlgorithmHS256 {
+		// A key id with nothing to load means the deployment expects keys that are not used
+		if cfg.JWTActiveKeyID != "" {
+			return nil, fmt.Errorf("JWT_ACTIVE_KEY_ID is %q but JWT_SIGNING_ALGORITHM is HS256, no key is loaded", cfg.JWTActiveKeyID)
+		}
+		return nil, nil
+	}
+	if cached, ok := keySets.Load(cfg); ok {
+		return cached.(*KeySet), nil
+	}
+
+	set, err := readKeySet(cfg)
+	if err != nil {
+		return nil, err
+	}
+	keySets.Store(cfg, set)
+	return set, nil
+}
+
+// JWKS returns the public keys for access token verification. In HS256 mode there
+// is nothing that can be published, so the set is empty.
+func JWKS(cfg *config.Config) (*JWKSet, error) {
+	set, err := LoadKeySet(cfg)
+	if err != nil {
+		return nil, err
+	}
+	out := &JWKSet{Keys: []JWK{}}
+	if set == nil {
+		return out, nil
+	}
+
+	ids := make([]string, 0, len(set.keys))
+	for id := range set.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		key := set.keys[id]
+		jwk := JWK{Use: "sig", Alg: set.method.Alg(), Kid: key.ID}
+		switch pub := key.Public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		}
+		out.Keys = append(out.Keys, jwk)
+	}
+	return out, nil
+}
+
+func (k *KeySet) lookup(token *jwt.Token) (interface{}, error) {
+	if token.Method.Alg() != k.method.Alg() {
+		return nil, errors.New("unexpected signing method")
+	}
+	kid, _ := token.Header["kid"].(string)
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	return key.Public, nil
+}
+
+func readKeySet(cfg *config.Config) (*KeySet, error) {
+	var method jwt.SigningMethod
+	switch cfg.JWTSigningAlgorithm {
+	case SigningAlgorithmRS256:
+		method = jwt.SigningMethodRS256
+	case SigningAlgorithmEdDSA:
+		method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("unsupported JWT signing algorithm %q", cfg.JWTSigningAlgorithm)
+	}
+
+	if cfg.JWTKeysDir == "" {
+		return nil, errors.New("JWT_KEYS_DIR is required for asymmetric signing")
+	}
+	files, err := filepath.Glob(filepath.Join(cfg.JWTKeysDir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+
+	set := &KeySet{method: method, keys: make(map[string]*verifyKey)}
+	for _, file := range files {
+		key, err := readKeyFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load JWT key %s: %w", file, err)
+		}
+		if !keyMatchesMethod(key.Public, method) {
+			return nil, fmt.Errorf("JWT key %s does not match algorithm %s", file, method.Alg())
+		}
+		if existing, ok := set.keys[key.ID]; ok && existing.Private != nil {
+			continue
+		}
+		set.keys[key.ID] = key
+	}
+
+	active, ok := set.keys[cfg.JWTActiveKeyID]
+	if !ok || active.Private == nil {
+		return nil, fmt.Errorf("active JWT key %q has no private key in %s", cfg.JWTActiveKeyID, cfg.JWTKeysDir)
+	}
+	set.active = active
+	return set, nil
+}
+
+// readKeyFile accepts a private key (PKCS#1 or PKCS#8) or, for retired keys that
+// should only verify, a PKIX public key. "<kid>.pub.pem" and "<kid>.pem" share a kid.
+func readKeyFile(path string) (*verifyKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	id := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(path), ".pem"), ".pub")
+	key := &verifyKey{ID: id}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		private, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		key.Private = private
+		key.Public = private.Public()
+	case "PRIVATE KEY":
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		signer, ok := parsed.(crypto.Signer)
+		if !ok {
+			return nil, errors.New("private key cannot sign")
+		}
+		key.Private = signer
+		key.Public = signer.Public()
+	case "PUBLIC KEY":
+		public, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		key.Public = public
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	return key, nil
+}
+
+func keyMatchesMethod(public crypto.PublicKey, method jwt.SigningMethod) bool {
+	switch public.(type) {
+	case *rsa.PublicKey:
+		return method == jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		return method == jwt.SigningMethodEdDSA
+	}
+	return false
+}
@@ -0,0 +1,277 @@
+package utils_test
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"study.com/v1/internal/config"
+	"study.com/v1/internal/middleware"
+	"study.com/v1/internal/utils"
+)
+
+func testConfig() *config.Config {
+	return &config.Config{
+		JWTSecret:            "test-secret",
+		JWTAccessSecret:      "test-access-secret",
+		JWTRefreshSecret:     "test-refresh-secret",
+		JWTIssuer:            "test-issuer",
+		JWTAudience:          "test-audience",
+		JWTSigningAlgorithm:  utils.SigningAlgorithmHS256,
+		JWTAccessExpiration:  15 * time.Minute,
+		JWTRefreshExpiration: 24 * time.Hour,
+		AuthAllowBearer:      true,
+	}
+}
+
+func generateTokens(t *testing.T, cfg *config.Config) (string, string) {
+	t.Helper()
+	access, refresh, err := utils.GenerateTokens(cfg, uuid.New(), uuid.New(), 1)
+	if err != nil {
+		t.Fatalf("GenerateTokens: %v", err)
+	}
+	return access, refresh
+}
+
+// signClaims signs a token the way signToken does, with the fields a test wants to break.
+func signClaims(t *testing.T, method jwt.SigningMethod, key interface{}, kid, typ, issuer, audience string) string {
+	t.Helper()
+	now := time.Now()
+	token := jwt.NewWithClaims(method, utils.Claims{
+		UserID:    uuid.New(),
+		DeviceID:  uuid.New(),
+		Version:   1,
+		TokenType: typ,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Issuer:    issuer,
+			Audience:  jwt.ClaimStrings{audience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	})
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return signed
+}
+
+func TestParseTokenAcceptsItsOwnType(t *testing.T) {
+	cfg := testConfig()
+	access, refresh := generateTokens(t, cfg)
+
+	if _, err := utils.ParseToken(cfg, access, utils.TokenTypeAccess); err != nil {
+		t.Errorf("access token rejected as access: %v", err)
+	}
+	if _, err := utils.ParseToken(cfg, refresh, utils.TokenTypeRefresh); err != nil {
+		t.Errorf("refresh token rejected as refresh: %v", err)
+	}
+}
+
+func TestParseTokenRejectsRefreshAsAccess(t *testing.T) {
+	cfg := testConfig()
+	_, refresh := generateTokens(t, cfg)
+
+	if _, err := utils.ParseToken(cfg, refresh, utils.TokenTypeAccess); err == nil {
+		t.Fatal("refresh token accepted as an access token")
+	}
+}
+
+func TestParseTokenRejectsAccessAsRefresh(t *testing.T) {
+	cfg := testConfig()
+	access, _ := generateTokens(t, cfg)
+
+	if _, err := utils.ParseToken(cfg, access, utils.TokenTypeRefresh); err == nil {
+		t.Fatal("access token accepted as a refresh token")
+	}
+}
+
+// Even with a single shared secret, the audience and typ claims keep the two apart.
+func TestParseTokenRejectsCrossUseWithSharedSecret(t *testing.T) {
+	cfg := testConfig()
+	cfg.JWTRefreshSecret = cfg.JWTAccessSecret
+	access, refresh := generateTokens(t, cfg)
+
+	if _, err := utils.ParseToken(cfg, refresh, utils.TokenTypeAccess); err == nil {
+		t.Error("refresh token accepted as an access token")
+	}
+	if _, err := utils.ParseToken(cfg, access, utils.TokenTypeRefresh); err == nil {
+		t.Error("access token accepted as a refresh token")
+	}
+}
+
+func TestParseTokenRejectsWrongClaims(t *testing.T) {
+	cfg := testConfig()
+	key := []byte(cfg.JWTAccessSecret)
+
+	tests := []struct {
+		name     string
+		typ      string
+		issuer   string
+		audience string
+	}{
+		{name: "wrong audience", typ: utils.TokenTypeAccess, issuer: cfg.JWTIssuer, audience: "another-app"},
+		{name: "refresh audience", typ: utils.TokenTypeAccess, issuer: cfg.JWTIssuer, audience: cfg.JWTIssuer},
+		{name: "wrong issuer", typ: utils.TokenTypeAccess, issuer: "another-issuer", audience: cfg.JWTAudience},
+		{name: "wrong typ", typ: utils.TokenTypeRefresh, issuer: cfg.JWTIssuer, audience: cfg.JWTAudience},
+		{name: "missing typ", typ: "", issuer: cfg.JWTIssuer, audience: cfg.JWTAudience},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := signClaims(t, jwt.SigningMethodHS256, key, "", tt.typ, tt.issuer, tt.audience)
+			if _, err := utils.ParseToken(cfg, token, utils.TokenTypeAccess); err == nil {
+				t.Fatal("token accepted")
+			}
+		})
+	}
+
+	t.Run("wrong typ error", func(t *testing.T) {
+		token := signClaims(t, jwt.SigningMethodHS256, key, "", utils.TokenTypeRefresh, cfg.JWTIssuer, cfg.JWTAudience)
+		if _, err := utils.ParseToken(cfg, token, utils.TokenTypeAccess); !errors.Is(err, utils.ErrWrongTokenType) {
+			t.Fatalf("got %v, want ErrWrongTokenType", err)
+		}
+	})
+}
+
+func TestParseTokenRejectsUnknownKeyID(t *testing.T) {
+	tests := []struct {
+		name      string
+		algorithm string
+		method    jwt.SigningMethod
+		generate  func() (crypto.Signer, error)
+	}{
+		{
+			name:      "RS256",
+			algorithm: utils.SigningAlgorithmRS256,
+			method:    jwt.SigningMethodRS256,
+			generate:  func() (crypto.Signer, error) { return rsa.GenerateKey(rand.Reader, 2048) },
+		},
+		{
+			name:      "EdDSA",
+			algorithm: utils.SigningAlgorithmEdDSA,
+			method:    jwt.SigningMethodEdDSA,
+			generate: func() (crypto.Signer, error) {
+				_, private, err := ed25519.GenerateKey(rand.Reader)
+				return private, err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			active, err := tt.generate()
+			if err != nil {
+				t.Fatal(err)
+			}
+			other, err := tt.generate()
+			if err != nil {
+				t.Fatal(err)
+			}
+			cfg := testConfig()
+			cfg.JWTSigningAlgorithm = tt.algorithm
+			cfg.JWTKeysDir = t.TempDir()
+			cfg.JWTActiveKeyID = "current"
+			writeKey(t, filepath.Join(cfg.JWTKeysDir, "current.pem"), active)
+
+			access, _ := generateTokens(t, cfg)
+			if _, err := utils.ParseToken(cfg, access, utils.TokenTypeAccess); err != nil {
+				t.Fatalf("token of the active key rejected: %v", err)
+			}
+
+			unknown := signClaims(t, tt.method, other, "unknown", utils.TokenTypeAccess, cfg.JWTIssuer, cfg.JWTAudience)
+			if _, err := utils.ParseToken(cfg, unknown, utils.TokenTypeAccess); err == nil {
+				t.Error("token with an unknown kid accepted")
+			}
+			// A known kid does not help a token signed by another key
+			forged := signClaims(t, tt.method, other, "current", utils.TokenTypeAccess, cfg.JWTIssuer, cfg.JWTAudience)
+			if _, err := utils.ParseToken(cfg, forged, utils.TokenTypeAccess); err == nil {
+				t.Error("token signed by another key accepted")
+			}
+			// Nor does falling back to the shared secret
+			hmac := signClaims(t, jwt.SigningMethodHS256, []byte(cfg.JWTAccessSecret), "current", utils.TokenTypeAccess, cfg.JWTIssuer, cfg.JWTAudience)
+			if _, err := utils.ParseToken(cfg, hmac, utils.TokenTypeAccess); err == nil {
+				t.Error("HS256 token accepted in asymmetric mode")
+			}
+		})
+	}
+}
+
+// Startup fails when JWT_ACTIVE_KEY_ID names a key that is not loaded.
+func TestLoadKeySetRejectsMissingActiveKey(t *testing.T) {
+	cfg := testConfig()
+	cfg.JWTActiveKeyID = "current"
+	if _, err := utils.LoadKeySet(cfg); err == nil {
+		t.Error("active key id accepted in HS256 mode")
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg = testConfig()
+	cfg.JWTSigningAlgorithm = utils.SigningAlgorithmRS256
+	cfg.JWTKeysDir = t.TempDir()
+	cfg.JWTActiveKeyID = "missing"
+	writeKey(t, filepath.Join(cfg.JWTKeysDir, "current.pem"), key)
+	if _, err := utils.LoadKeySet(cfg); err == nil {
+		t.Error("active key id without a key file accepted")
+	}
+}
+
+func writeKey(t *testing.T, path string, key crypto.Signer) {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// The middleware refuses a refresh token before it looks at the session, so no Redis is
+// needed: the client points nowhere.
+func TestAuthMiddlewareRejectsRefreshToken(t *testing.T) {
+	cfg := testConfig()
+	_, refresh := generateTokens(t, cfg)
+
+	rdb := redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"})
+	defer rdb.Close()
+	app := fiber.New()
+	app.Get("/", middleware.AuthMiddleware(cfg, rdb), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	bearer := httptest.NewRequest(fiber.MethodGet, "/", nil)
+	bearer.Header.Set(fiber.HeaderAuthorization, "Bearer "+refresh)
+	cookie := httptest.NewRequest(fiber.MethodGet, "/", nil)
+	cookie.Header.Set(fiber.HeaderCookie, utils.AccessTokenCookie+"="+refresh)
+
+	for name, req := range map[string]*http.Request{"bearer": bearer, "cookie": cookie} {
+		res, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if res.StatusCode != fiber.StatusUnauthorized {
+			t.Errorf("%s: refresh token got status %d, want 401", name, res.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"study.com/v1/internal/config"
+)
+
+// mediaTokenAudience keeps media tokens and access tokens from being accepted as each other.
+const mediaTokenAudience = "media"
+
+// MediaClaims grant one user access to the video of one lesson until they expire. The
+// token travels in the playlist URL, since players do not send the Authorization header.
+type MediaClaims struct {
+	UserID    uuid.UUID `json:"user_id"`
+	LessonID  uuid.UUID `json:"lesson_id"`
+	SessionID string    `json:"sid"` // shown in the watermark, ties a leaked recording to a playback
+	jwt.RegisteredClaims
+}
+
+func SignMediaToken(cfg *config.Config, userID, lessonID uuid.UUID, sessionID string, ttl time.Duration) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+	claims := MediaClaims{
+		UserID:    userID,
+		LessonID:  lessonID,
+		SessionID: sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    cfg.JWTIssuer,
+			Audience:  jwt.ClaimStrings{mediaTokenAudience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(cfg.MediaTokenSecret))
+	return token, expiresAt, err
+}
+
+func ParseMediaToken(cfg *config.Config, tokenString string) (*MediaClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &MediaClaims{}, func(*jwt.Token) (interface{}, error) {
+		return []byte(cfg.MediaTokenSecret), nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(cfg.JWTIssuer),
+		jwt.WithAudience(mediaTokenAudience),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return token.Claims.(*MediaClaims), nil
+}
@@ -2,7 +2,9 @@ package utils
 
 import (
 	"crypto/rand"
+	"crypto/subtle"
 	"math/big"
+	"strings"
 )
 
 func GenerateOTP(length int) (string, error) {
@@ -17,3 +19,8 @@ func GenerateOTP(length int) (string, error) {
 	}
 	return string(otp), nil
 }
+
+// OTPMatches compares the code a user typed with the one that was sent in constant time.
+func OTPMatches(input, expected string) bool {
+	return subtle.ConstantTimeCompare([]byte(strings.TrimSpace(input)), []byte(expected)) == 1
+}
@@ -0,0 +1,200 @@
+// Package worker runs the background jobs queued in Postgres, see model.Job.
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"study.com/v1/internal/model"
+	"study.com/v1/internal/repository"
+)
+
+const (
+	defaultLease        = 2 * time.Minute
+	defaultPollInterval = 5 * time.Second
+	minRetryDelay       = 30 * time.Second
+	maxRetryDelay       = time.Hour
+)
+
+// Handler runs one job. An error retries the job later with a growing delay, unless it is
+// wrapped with Permanent. The context is cancelled when the worker stops or loses the lease.
+type Handler func(ctx context.Context, job *model.Job) error
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks an error that retrying cannot fix, such as a corrupt file. The job fails
+// at once instead of using up its attempts.
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was marked with Permanent.
+func IsPermanent(err error) bool {
+	return errors.As(err, new(*permanentError))
+}
+
+type Worker struct {
+	jobs        repository.JobRepositoryInterface
+	handlers    map[string]Handler
+	id          string
+	concurrency int
+	lease       time.Duration
+	poll        time.Duration
+}
+
+func New(jobs repository.JobRepositoryInterface, concurrency int) *Worker {
+	hostname, _ := os.Hostname()
+	return &Worker{
+		jobs:        jobs,
+		handlers:    map[string]Handler{},
+		id:          fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), uuid.NewString()[:8]),
+		concurrency: max(concurrency, 1),
+		lease:       defaultLease,
+		poll:        defaultPollInterval,
+	}
+}
+
+func (w *Worker) Register(jobType string, handler Handler) {
+	w.handlers[jobType] = handler
+}
+
+// Run processes jobs until ctx is cancelled, then waits for the running jobs to stop.
+func (w *Worker) Run(ctx context.Context) {
+	types := make([]string, 0, len(w.handlers))
+	for t := range w.handlers {
+		types = append(types, t)
+	}
+	log.Printf("Worker %s started with %d slots for %v", w.id, w.concurrency, types)
+
+	var wg sync.WaitGroup
+	for i := 0; i < w.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.loop(ctx, types)
+		}()
+	}
+	wg.Wait()
+	log.Printf("Worker %s stopped", w.id)
+}
+
+func (w *Worker) loop(ctx context.Context, types []string) {
+	for ctx.Err() == nil {
+		job, err := w.jobs.Claim(ctx, types, w.id, w.lease)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("Warning: failed to claim a job: %v", err)
+		}
+		if job == nil {
+			select {
+			case <-ctx.Done():
+			case <-time.After(w.poll):
+			}
+			continue
+		}
+		w.process(ctx, job)
+	}
+}
+
+func (w *Worker) process(ctx context.Context, job *model.Job) {
+	// The job may outlive ctx by a moment; the bookkeeping below must still reach the database
+	bookkeeping := context.Background()
+
+	if job.Attempts > job.MaxAttempts {
+		// Only a job whose worker kept dying gets here: it never returned to record an error
+		w.finish(job, w.jobs.Fail(bookkeeping, job.ID, w.id, "the worker stopped while running the job too many times"))
+		return
+	}
+
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go w.renewLease(jobCtx, cancel, job)
+
+	started := time.Now()
+	err := w.run(jobCtx, job)
+	switch {
+	case err == nil:
+		log.Printf("Job %s (%s) succeeded in %s", job.ID, job.Type, time.Since(started).Round(time.Second))
+		w.finish(job, w.jobs.Complete(bookkeeping, job.ID, w.id))
+	case ctx.Err() != nil:
+		// Shutting down: hand the job back so another worker starts it at once
+		log.Printf("Job %s (%s) interrupted by shutdown", job.ID, job.Type)
+		w.finish(job, w.jobs.Retry(bookkeeping, job.ID, w.id, time.Now(), "interrupted by worker shutdown"))
+	case IsPermanent(err) || job.Attempts >= job.MaxAttempts:
+		log.Printf("Job %s (%s) failed: %v", job.ID, job.Type, err)
+		w.finish(job, w.jobs.Fail(bookkeeping, job.ID, w.id, err.Error()))
+	default:
+		delay := retryDelay(job.Attempts)
+		log.Printf("Job %s (%s) failed, retrying in %s: %v", job.ID, job.Type, delay, err)
+		w.finish(job, w.jobs.Retry(bookkeeping, job.ID, w.id, time.Now().Add(delay), err.Error()))
+	}
+}
+
+// run calls the handler, turning a panic into a permanent failure of the job rather than
+// of the worker.
+func (w *Worker) run(ctx context.Context, job *model.Job) (err error) {
+	handler, ok := w.handlers[job.Type]
+	if !ok {
+		return Permanent(fmt.Errorf("no handler for job type %s", job.Type))
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Job %s (%s) panicked: %v\n%s", job.ID, job.Type, r, debug.Stack())
+			err = Permanent(fmt.Errorf("panic: %v", r))
+		}
+	}()
+	return handler(ctx, job)
+}
+
+// renewLease extends the lease while the job runs. When it cannot, another worker may
+// already have the job, so this one is cancelled.
+func (w *Worker) renewLease(ctx context.Context, cancel context.CancelFunc, job *model.Job) {
+	ticker := time.NewTicker(w.lease / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := w.jobs.ExtendLease(ctx, job.ID, w.id, w.lease)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				log.Printf("Warning: job %s (%s) lost its lease, stopping it", job.ID, job.Type)
+				cancel()
+				return
+			}
+			if err != nil && ctx.Err() == nil {
+				log.Printf("Warning: failed to extend the lease of job %s: %v", job.ID, err)
+			}
+		}
+	}
+}
+
+// finish logs when the outcome of a job could not be recorded.
+func (w *Worker) finish(job *model.Job, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Printf("Warning: job %s (%s) was taken over by another worker, its result is dropped", job.ID, job.Type)
+	} else if err != nil {
+		log.Printf("Warning: failed to record the result of job %s: %v", job.ID, err)
+	}
+}
+
+// retryDelay doubles from 30 seconds up to an hour.
+func retryDelay(attempts int) time.Duration {
+	delay := minRetryDelay
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}